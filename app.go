@@ -2,19 +2,58 @@ package main
 
 import (
 	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
 
+	"flowpulse/pkg/assertions"
+	"flowpulse/pkg/auth"
+	"flowpulse/pkg/backup"
 	"flowpulse/pkg/database"
+	"flowpulse/pkg/diff"
+	"flowpulse/pkg/environments"
+	"flowpulse/pkg/extraction"
+	"flowpulse/pkg/graphql"
+	"flowpulse/pkg/importer"
+	"flowpulse/pkg/jsonpath"
 	"flowpulse/pkg/models"
+	"flowpulse/pkg/notify"
+	"flowpulse/pkg/openapi"
+	"flowpulse/pkg/profiles"
+	"flowpulse/pkg/promrules"
 	"flowpulse/pkg/scheduler"
+	"flowpulse/pkg/secrets"
+	"flowpulse/pkg/specwatch"
+	"flowpulse/pkg/statuspage"
+	"flowpulse/pkg/webhooks"
+	"flowpulse/pkg/workspacesync"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
 )
 
 // App struct
 type App struct {
-	ctx       context.Context
-	db        *database.DBService
-	scheduler *scheduler.SchedulerService
+	ctx         context.Context
+	profile     string // Name of the profile in use; set before startup to override the saved choice
+	headless    bool   // Set before startup when running without the desktop UI, which has no frontend to send events to
+	db          database.Store
+	scheduler   *scheduler.SchedulerService
+	notifier    *notify.Service
+	sync        *workspacesync.SyncService
+	specWatcher *specwatch.Service
+	secrets     *secrets.Service
+	restServer  *http.Server
+	restMutex   sync.Mutex
 }
 
 // NewApp creates a new App application struct
@@ -27,37 +66,221 @@ func NewApp() *App {
 func (a *App) startup(ctx context.Context) {
 	a.ctx = ctx
 
-	// Initialize the database
-	db, err := database.NewDBService()
+	name := a.profile
+	if name == "" {
+		config, err := profiles.Load()
+		if err != nil {
+			log.Fatalf("Failed to load profiles: %v", err)
+		}
+		name = config.Active
+	}
+	if err := a.openProfile(name); err != nil {
+		log.Fatalf("Failed to open profile %q: %v", name, err)
+	}
+
+	log.Println("FlowPulse started successfully!")
+}
+
+// repairDatabase replaces a corrupt database with its latest snapshot and opens it. The desktop app asks
+// first; headless, with nobody to ask, it restores right away. The corrupt file is kept next to the database.
+func (a *App) repairDatabase(dbPath string, corrupt *database.CorruptionError) (*database.DBService, error) {
+	log.Printf("Database integrity check failed: %v", corrupt)
+
+	snapshots, err := database.Snapshots(dbPath)
+	if err != nil {
+		return nil, err
+	}
+	if len(snapshots) == 0 {
+		return nil, fmt.Errorf("%w; no backup of it is available to restore from", corrupt)
+	}
+
+	if !a.headless {
+		answer, err := runtime.MessageDialog(a.ctx, runtime.MessageDialogOptions{
+			Type:  runtime.QuestionDialog,
+			Title: "Database corrupted",
+			Message: fmt.Sprintf("The FlowPulse database at %s is damaged (%s).\n\nRestore the backup taken %s? "+
+				"Changes made since then will be lost. The damaged file is kept next to the database.",
+				dbPath, corrupt.Problems[0], snapshots[0].TakenAt.Format("2006-01-02 15:04")),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("%w; failed to ask about restoring it: %v", corrupt, err)
+		}
+		if answer != "Yes" {
+			return nil, corrupt
+		}
+	}
+
+	restore, err := database.RestoreLatestSnapshot(dbPath)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("Restored database from %s taken %s; the corrupt database was moved to %s",
+		restore.Snapshot.Path, restore.Snapshot.TakenAt.Format(time.RFC3339), restore.CorruptPath)
+	return database.NewDBServiceWithPath(dbPath)
+}
+
+// openProfile opens the database of a profile and starts the services working on it
+func (a *App) openProfile(name string) error {
+	config, err := profiles.Load()
+	if err != nil {
+		return err
+	}
+	profile, ok := config.Find(name)
+	if !ok {
+		return fmt.Errorf("profile %q not found", name)
+	}
+	dbPath, err := profiles.DBPath(profile)
+	if err != nil {
+		return err
+	}
+
+	// Initialize the database, offering to restore the latest snapshot when it is corrupt
+	db, err := database.NewDBServiceWithPath(dbPath)
+	var corrupt *database.CorruptionError
+	if errors.As(err, &corrupt) {
+		db, err = a.repairDatabase(dbPath, corrupt)
+	}
 	if err != nil {
-		log.Fatalf("Failed to initialize database: %v", err)
+		return fmt.Errorf("failed to initialize database: %w", err)
 	}
 
 	a.db = db
+	a.profile = name
 
-	// Initialize the scheduler
+	// Keep a recent copy of the healthy database to restore from
+	if err := a.db.SnapshotIfDue(); err != nil {
+		log.Printf("Failed to snapshot database: %v", err)
+	}
+
+	// Initialize the scheduler, streaming its events to the frontend
 	a.scheduler = scheduler.NewSchedulerService(db)
+	if !a.headless {
+		a.scheduler.SetEventEmitter(func(name string, data interface{}) {
+			runtime.EventsEmit(a.ctx, name, data)
+		})
+	}
+
+	// Initialize the notification service used to test channels
+	a.notifier = notify.NewService(db)
+
+	// Initialize the secrets store
+	a.secrets = secrets.NewService(db)
+
+	// Initialize workspace sync
+	a.sync = workspacesync.NewSyncService(db)
+
+	// Watch OpenAPI specs for new and removed operations
+	a.specWatcher = specwatch.NewService(db)
+	a.specWatcher.Start()
 
 	// Start all active jobs
 	if err := a.scheduler.StartAllJobs(); err != nil {
 		log.Printf("Failed to start jobs: %v", err)
 	}
 
-	log.Println("FlowPulse started successfully!")
+	// Keep execution logs within the retention policy
+	a.db.StartPruning(time.Hour)
+
+	// Serve the REST API when it has been enabled
+	if config, err := a.db.GetRESTServerConfig(); err != nil {
+		log.Printf("Failed to load REST API config: %v", err)
+	} else if config.Enabled {
+		if err := a.startRESTServer(config.Host, config.Port, config.Token); err != nil {
+			log.Printf("Failed to start REST API: %v", err)
+		}
+	}
+
+	return nil
 }
 
 // shutdown is called when the app is about to quit
 func (a *App) shutdown(ctx context.Context) {
 	log.Println("Shutting down FlowPulse...")
+	a.closeProfile()
+}
+
+// closeProfile stops the services of the open profile and closes its database
+func (a *App) closeProfile() {
+	a.stopRESTServer()
+
 	if a.scheduler != nil {
 		a.scheduler.Shutdown()
 	}
 
+	if a.specWatcher != nil {
+		a.specWatcher.Stop()
+	}
+
 	if a.db != nil {
 		a.db.Close()
 	}
 }
 
+// Profile methods
+
+// GetProfiles returns all profiles, marking the one in use
+func (a *App) GetProfiles() ([]models.Profile, error) {
+	config, err := profiles.Load()
+	if err != nil {
+		return nil, err
+	}
+	config.Active = a.profile
+	return config.List(), nil
+}
+
+// CreateProfile adds a profile. Without a database path, its database is kept in the app directory.
+func (a *App) CreateProfile(name string, dbPath string) (models.Profile, error) {
+	config, err := profiles.Load()
+	if err != nil {
+		return models.Profile{}, err
+	}
+	profile, err := config.Add(name, dbPath)
+	if err != nil {
+		return profile, err
+	}
+	return profile, config.Save()
+}
+
+// SwitchProfile closes the current profile and opens another one, remembering it for the next start
+func (a *App) SwitchProfile(name string) error {
+	config, err := profiles.Load()
+	if err != nil {
+		return err
+	}
+	if _, ok := config.Find(name); !ok {
+		return fmt.Errorf("profile %q not found", name)
+	}
+	if name == a.profile {
+		return nil
+	}
+
+	previous := a.profile
+	a.closeProfile()
+	if err := a.openProfile(name); err != nil {
+		// Reopen the previous profile so the app keeps working
+		if reopenErr := a.openProfile(previous); reopenErr != nil {
+			log.Printf("Failed to reopen profile %q: %v", previous, reopenErr)
+		}
+		return fmt.Errorf("failed to open profile %q: %w", name, err)
+	}
+
+	config.Active = name
+	return config.Save()
+}
+
+// DeleteProfile removes a profile from the list. Its database file is kept.
+func (a *App) DeleteProfile(name string) error {
+	config, err := profiles.Load()
+	if err != nil {
+		return err
+	}
+	config.Active = a.profile
+	if err := config.Remove(name); err != nil {
+		return err
+	}
+	return config.Save()
+}
+
 // APIs methods
 
 // GetAllAPIs returns all APIs
@@ -65,6 +288,11 @@ func (a *App) GetAllAPIs() ([]models.API, error) {
 	return a.db.GetAllAPIs()
 }
 
+// GetDeprecationReport returns every API with a deprecation date, soonest sunset first, flagging those past it
+func (a *App) GetDeprecationReport() ([]models.DeprecatedAPI, error) {
+	return a.db.GetDeprecationReport()
+}
+
 // GetAPIByID returns an API by ID
 func (a *App) GetAPIByID(id int) (models.API, error) {
 	return a.db.GetAPIByID(id)
@@ -72,139 +300,1190 @@ func (a *App) GetAPIByID(id int) (models.API, error) {
 
 // CreateAPI creates a new API
 func (a *App) CreateAPI(api models.API) (models.API, error) {
+	if err := validateStatusCodes(api); err != nil {
+		return api, err
+	}
+	if err := validateAPIProxy(&api); err != nil {
+		return api, err
+	}
+	if err := validateRedirectPolicy(&api); err != nil {
+		return api, err
+	}
+	if err := validateOverlapPolicy(&api); err != nil {
+		return api, err
+	}
+	if api.AutoDisable && api.DeprecatedAfter == nil {
+		return api, fmt.Errorf("disabling an API once deprecated needs a deprecation date")
+	}
+	if err := validateRunbook(&api); err != nil {
+		return api, err
+	}
+	if _, err := scheduler.LoadTLSConfig(api.TLS); err != nil {
+		return api, err
+	}
+	if err := validateDNSServer(&api); err != nil {
+		return api, err
+	}
+	if err := validateLatencyThresholds(api); err != nil {
+		return api, err
+	}
+	if api.StepDelayMs < 0 {
+		return api, fmt.Errorf("step delay cannot be negative")
+	}
+	if err := validateContentWatch(api); err != nil {
+		return api, err
+	}
+	if _, err := environments.ParseVariables(api.Variables); err != nil {
+		return api, err
+	}
+	if err := validateBody(api); err != nil {
+		return api, err
+	}
+	if err := validateCheckType(api); err != nil {
+		return api, err
+	}
 	return a.db.CreateAPI(api)
 }
 
 // UpdateAPI updates an existing API
 func (a *App) UpdateAPI(api models.API) (models.API, error) {
+	if err := validateStatusCodes(api); err != nil {
+		return api, err
+	}
+	if err := validateAPIProxy(&api); err != nil {
+		return api, err
+	}
+	if err := validateRedirectPolicy(&api); err != nil {
+		return api, err
+	}
+	if err := validateOverlapPolicy(&api); err != nil {
+		return api, err
+	}
+	if api.AutoDisable && api.DeprecatedAfter == nil {
+		return api, fmt.Errorf("disabling an API once deprecated needs a deprecation date")
+	}
+	if err := validateRunbook(&api); err != nil {
+		return api, err
+	}
+	if _, err := scheduler.LoadTLSConfig(api.TLS); err != nil {
+		return api, err
+	}
+	if err := validateDNSServer(&api); err != nil {
+		return api, err
+	}
+	if err := validateLatencyThresholds(api); err != nil {
+		return api, err
+	}
+	if api.StepDelayMs < 0 {
+		return api, fmt.Errorf("step delay cannot be negative")
+	}
+	if err := validateContentWatch(api); err != nil {
+		return api, err
+	}
+	if _, err := environments.ParseVariables(api.Variables); err != nil {
+		return api, err
+	}
+	if err := validateBody(api); err != nil {
+		return api, err
+	}
+	if err := validateCheckType(api); err != nil {
+		return api, err
+	}
 	return a.db.UpdateAPI(api)
 }
 
-// DeleteAPI deletes an API by ID
-func (a *App) DeleteAPI(id int) error {
-	return a.db.DeleteAPI(id)
+// PreviewReplaceInAPIs returns the URL and body changes a find and replace would make, without making them
+func (a *App) PreviewReplaceInAPIs(request models.FindReplace) (models.ReplaceResult, error) {
+	return a.db.ReplaceInAPIs(request, false)
 }
 
-// Collection methods
+// ReplaceInAPIs finds and replaces text in the URLs and bodies of APIs, e.g. to move them to a new
+// domain, and restarts the jobs of the changed APIs so they use the new values
+func (a *App) ReplaceInAPIs(request models.FindReplace) (models.ReplaceResult, error) {
+	result, err := a.db.ReplaceInAPIs(request, true)
+	if err != nil {
+		return result, err
+	}
 
-// GetAllCollections returns all collections
-func (a *App) GetAllCollections() ([]models.Collection, error) {
-	return a.db.GetAllCollections()
+	refreshed := make(map[int]bool)
+	var scheduleIDs []int
+	for _, match := range result.Matches {
+		if refreshed[match.APIID] {
+			continue
+		}
+		refreshed[match.APIID] = true
+		schedules, err := a.db.GetSchedulesByAPIID(match.APIID)
+		if err != nil {
+			log.Printf("Failed to load schedules of API ID %d: %v", match.APIID, err)
+			continue
+		}
+		for _, schedule := range schedules {
+			scheduleIDs = append(scheduleIDs, schedule.ID)
+		}
+	}
+	a.refreshJobs(scheduleIDs)
+	return result, nil
 }
 
-// GetCollectionByID returns a collection by ID
-func (a *App) GetCollectionByID(id int) (models.Collection, error) {
-	return a.db.GetCollectionByID(id)
+// validateStatusCodes checks the API's success and degraded status code lists, which may be empty
+func validateStatusCodes(api models.API) error {
+	if strings.TrimSpace(api.SuccessCodes) != "" {
+		if _, err := assertions.ParseStatusCodes(api.SuccessCodes); err != nil {
+			return fmt.Errorf("invalid success codes: %w", err)
+		}
+	}
+	if strings.TrimSpace(api.DegradedCodes) != "" {
+		if _, err := assertions.ParseStatusCodes(api.DegradedCodes); err != nil {
+			return fmt.Errorf("invalid degraded codes: %w", err)
+		}
+	}
+	return nil
 }
 
-// CreateCollection creates a new collection
-func (a *App) CreateCollection(collection models.Collection) (models.Collection, error) {
-	return a.db.CreateCollection(collection)
+// validateBody checks the API's body type and that only multipart bodies upload files
+func validateBody(api models.API) error {
+	switch api.BodyType {
+	case "", models.BodyTypeRaw, models.BodyTypeJSON, models.BodyTypeForm, models.BodyTypeMultipart, models.BodyTypeGraphQL:
+	default:
+		return fmt.Errorf("unknown body type %q", api.BodyType)
+	}
+	if api.BodyType == models.BodyTypeGraphQL {
+		if strings.TrimSpace(api.GraphQLQuery) == "" {
+			return fmt.Errorf("GraphQL APIs need a query or mutation document")
+		}
+		if !strings.EqualFold(api.Method, http.MethodPost) {
+			return fmt.Errorf("GraphQL APIs are sent with POST")
+		}
+		// Variables made of environment references are only valid JSON once substituted
+		if variables := strings.TrimSpace(api.GraphQLVariables); variables != "" && !strings.Contains(variables, "{{") {
+			if err := graphql.ValidateVariables(variables); err != nil {
+				return err
+			}
+		}
+	}
+	for _, field := range api.FormFields {
+		if field.File && api.BodyType != models.BodyTypeMultipart {
+			return fmt.Errorf("form field %s uploads a file, which needs a multipart body", field.Key)
+		}
+		if field.File && strings.TrimSpace(field.Value) == "" {
+			return fmt.Errorf("form field %s has no file path", field.Key)
+		}
+	}
+	return nil
 }
 
-// UpdateCollection updates an existing collection
-func (a *App) UpdateCollection(collection models.Collection) (models.Collection, error) {
-	return a.db.UpdateCollection(collection)
+// validateAPIProxy checks an API's proxy mode and, when it sets its own proxy, the proxy's URL
+func validateAPIProxy(api *models.API) error {
+	switch api.ProxyMode {
+	case "":
+		api.ProxyMode = models.ProxyModeGlobal
+	case models.ProxyModeGlobal, models.ProxyModeNone:
+	case models.ProxyModeCustom:
+		if _, err := scheduler.ParseProxyURL(api.Proxy); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unsupported proxy mode: %s", api.ProxyMode)
+	}
+	return nil
 }
 
-// DeleteCollection deletes a collection by ID
-func (a *App) DeleteCollection(id int) error {
-	return a.db.DeleteCollection(id)
+// validateDNSServer checks the DNS server an API resolves host names with, which may be empty for the system's
+func validateDNSServer(api *models.API) error {
+	api.DNSServer = strings.TrimSpace(api.DNSServer)
+	if api.DNSServer == "" {
+		return nil
+	}
+	_, err := scheduler.ParseDNSServer(api.DNSServer)
+	return err
 }
 
-// GetAPIsByCollectionID returns all APIs in a collection
-func (a *App) GetAPIsByCollectionID(collectionID int) ([]models.API, error) {
-	return a.db.GetAPIsByCollectionID(collectionID)
+// validateLatencyThresholds checks the response times above which an API's checks are degraded, which are 0 when unset
+func validateLatencyThresholds(api models.API) error {
+	if api.LatencyWarnMs < 0 || api.LatencyCriticalMs < 0 {
+		return fmt.Errorf("latency thresholds cannot be negative")
+	}
+	if api.LatencyWarnMs > 0 && api.LatencyCriticalMs > 0 && api.LatencyCriticalMs <= api.LatencyWarnMs {
+		return fmt.Errorf("the critical latency threshold must be above the warning threshold")
+	}
+	return nil
 }
 
-// Analytics methods
+// validateContentWatch checks how an API compares its response bodies with its content baseline
+func validateContentWatch(api models.API) error {
+	switch api.ContentWatch {
+	case "", models.ContentWatchHash, models.ContentWatchJSON:
+		return nil
+	default:
+		return fmt.Errorf("unsupported content watch mode: %s", api.ContentWatch)
+	}
+}
 
-// GetAPIAnalytics returns analytics for a specific API
-func (a *App) GetAPIAnalytics(apiID int) (models.AnalyticsSummary, error) {
-	return a.db.GetAPIAnalytics(apiID)
+// validateRedirectPolicy checks an API's redirect policy and limit
+func validateRedirectPolicy(api *models.API) error {
+	switch api.RedirectPolicy {
+	case "":
+		api.RedirectPolicy = models.RedirectPolicyFollow
+	case models.RedirectPolicyFollow, models.RedirectPolicyNone:
+	default:
+		return fmt.Errorf("unsupported redirect policy: %s", api.RedirectPolicy)
+	}
+	if api.MaxRedirects < 0 {
+		return fmt.Errorf("maximum redirects cannot be negative")
+	}
+	return nil
 }
 
-// GetOverallAnalytics returns overall analytics for all APIs
-func (a *App) GetOverallAnalytics() (models.AnalyticsSummary, error) {
-	return a.db.GetOverallAnalytics()
+// validateOverlapPolicy checks what an API's scheduled runs do while its previous check is still going
+func validateOverlapPolicy(api *models.API) error {
+	switch api.OverlapPolicy {
+	case "":
+		api.OverlapPolicy = models.OverlapPolicyQueue
+	case models.OverlapPolicyQueue, models.OverlapPolicySkip:
+	default:
+		return fmt.Errorf("unsupported overlap policy: %s", api.OverlapPolicy)
+	}
+	return nil
 }
 
-// GetExecutionStatusCounts returns counts of different status code ranges for an API
-func (a *App) GetExecutionStatusCounts(apiID int) (map[string]int, error) {
-	logs, err := a.db.GetExecutionLogsByAPIID(apiID, 1000) // Get a large sample
-	if err != nil {
-		return nil, err
+// validateRunbook checks that an API's runbook link is an absolute HTTP(S) URL, so it can be opened from an alert
+func validateRunbook(api *models.API) error {
+	api.RunbookURL = strings.TrimSpace(api.RunbookURL)
+	if api.RunbookURL == "" {
+		return nil
 	}
-	
-	counts := map[string]int{
-		"success": 0,   // 2xx
-		"redirect": 0,  // 3xx
-		"client_error": 0, // 4xx
-		"server_error": 0, // 5xx
-		"other": 0,     // Other codes
+	parsed, err := url.Parse(api.RunbookURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fmt.Errorf("runbook URL must be an http or https URL")
 	}
-	
-	for _, log := range logs {
-		statusCode := log.StatusCode
-		
-		if statusCode >= 200 && statusCode < 300 {
-			counts["success"]++
-		} else if statusCode >= 300 && statusCode < 400 {
-			counts["redirect"]++
-		} else if statusCode >= 400 && statusCode < 500 {
-			counts["client_error"]++
-		} else if statusCode >= 500 {
-			counts["server_error"]++
-		} else {
-			counts["other"]++
+	return nil
+}
+
+// validateCheckType checks the API's check type and that its URL uses a matching scheme
+func validateCheckType(api models.API) error {
+	switch api.CheckType {
+	case "", models.CheckTypeHTTP:
+	case models.CheckTypeWebSocket:
+		// URLs starting with a variable are only known once the environment is applied
+		target := strings.ToLower(strings.TrimSpace(api.URL))
+		if !strings.HasPrefix(target, "ws://") && !strings.HasPrefix(target, "wss://") && !strings.HasPrefix(target, "{{") {
+			return fmt.Errorf("WebSocket checks need a ws:// or wss:// URL")
+		}
+	case models.CheckTypeTCP, models.CheckTypeICMP, models.CheckTypeDNS:
+		if strings.TrimSpace(api.URL) == "" {
+			return fmt.Errorf("%s checks need a host", api.CheckType)
 		}
+		if api.CheckType == models.CheckTypeDNS {
+			switch strings.ToUpper(api.DNSRecordType) {
+			case "", "A", "AAAA", "CNAME", "MX", "NS", "TXT":
+			default:
+				return fmt.Errorf("unsupported DNS record type %q", api.DNSRecordType)
+			}
+		}
+	default:
+		return fmt.Errorf("unknown check type %q", api.CheckType)
 	}
-	
-	return counts, nil
+	return nil
 }
 
-// Schedules methods
+// DeleteAPI moves an API to the trash and stops its schedules
+func (a *App) DeleteAPI(id int) error {
+	schedules, err := a.db.GetSchedulesByAPIID(id)
+	if err != nil {
+		return err
+	}
+	if err := a.db.DeleteAPI(id); err != nil {
+		return err
+	}
 
-// GetAllSchedules returns all schedules
-func (a *App) GetAllSchedules() ([]models.Schedule, error) {
-	return a.db.GetAllSchedules()
+	// The API's schedules stay stopped while it is in the trash
+	for _, schedule := range schedules {
+		a.scheduler.StopJob(schedule.ID)
+	}
+	return nil
 }
 
-// GetSchedulesByAPIID returns all schedules for an API
-func (a *App) GetSchedulesByAPIID(apiID int) ([]models.Schedule, error) {
-	return a.db.GetSchedulesByAPIID(apiID)
+// DuplicateAPI copies an API with its assertions, extractions and tags into the same collection, named
+// with a "copy" suffix. Its schedules aren't copied, so the copy doesn't run until it is scheduled.
+func (a *App) DuplicateAPI(id int) (models.API, error) {
+	api, err := a.db.GetAPIByID(id)
+	if err != nil {
+		return api, err
+	}
+	api.Name += " copy"
+	copied, _, err := a.duplicateAPI(api, api.CollectionID, false)
+	return copied, err
 }
 
-// CreateSchedule creates a new schedule
-func (a *App) CreateSchedule(schedule models.Schedule) (models.Schedule, error) {
-	newSchedule, err := a.db.CreateSchedule(schedule)
+// duplicateAPI copies an API's configuration into a collection, along with its schedules when
+// includeSchedules is set, returning the copy and the IDs of the copied schedules
+func (a *App) duplicateAPI(api models.API, collectionID int, includeSchedules bool) (models.API, []int, error) {
+	assertions, err := a.db.GetAssertionsByAPIID(api.ID)
 	if err != nil {
-		return newSchedule, err
+		return api, nil, err
+	}
+	extractions, err := a.db.GetExtractionsByAPIID(api.ID)
+	if err != nil {
+		return api, nil, err
+	}
+	tags, err := a.db.GetTagsByAPIID(api.ID)
+	if err != nil {
+		return api, nil, err
 	}
 
-	// If the schedule is active, schedule it
-	if newSchedule.IsActive {
-		if err := a.scheduler.ScheduleJob(newSchedule); err != nil {
-			return newSchedule, fmt.Errorf("schedule created but failed to start job: %w", err)
+	source := api.ID
+	api.ID = 0
+	api.UUID = ""
+	api.CollectionID = collectionID
+	copied, err := a.db.CreateAPI(api)
+	if err != nil {
+		return copied, nil, err
+	}
+
+	for _, assertion := range assertions {
+		assertion.APIID = copied.ID
+		if _, err := a.db.CreateAssertion(assertion); err != nil {
+			return copied, nil, err
+		}
+	}
+	for _, e := range extractions {
+		e.APIID = copied.ID
+		if _, err := a.db.CreateExtraction(e); err != nil {
+			return copied, nil, err
+		}
+	}
+	if err := a.db.SetAPITags(copied.ID, tagIDs(tags)); err != nil {
+		return copied, nil, err
+	}
+
+	if !includeSchedules {
+		return copied, nil, nil
+	}
+	schedules, err := a.db.GetSchedulesByAPIID(source)
+	if err != nil {
+		return copied, nil, err
+	}
+	var scheduleIDs []int
+	for _, schedule := range schedules {
+		tags, err := a.db.GetTagsByScheduleID(schedule.ID)
+		if err != nil {
+			return copied, scheduleIDs, err
+		}
+		schedule.ID = 0
+		schedule.UUID = ""
+		schedule.APIID = copied.ID
+		created, err := a.db.CreateSchedule(schedule)
+		if err != nil {
+			return copied, scheduleIDs, err
+		}
+		scheduleIDs = append(scheduleIDs, created.ID)
+		if err := a.db.SetScheduleTags(created.ID, tagIDs(tags)); err != nil {
+			return copied, scheduleIDs, err
 		}
 	}
+	return copied, scheduleIDs, nil
+}
 
-	return newSchedule, nil
+// tagIDs returns the IDs of tags
+func tagIDs(tags []models.Tag) []int {
+	ids := make([]int, len(tags))
+	for i, tag := range tags {
+		ids[i] = tag.ID
+	}
+	return ids
 }
 
-// UpdateSchedule updates an existing schedule
-func (a *App) UpdateSchedule(schedule models.Schedule) error {
-	// Get the current state of the schedule
-	currentSchedule, err := a.db.GetScheduleByID(schedule.ID)
+// RestoreAPI takes an API out of the trash and restarts its active schedules
+func (a *App) RestoreAPI(id int) error {
+	if err := a.db.RestoreAPI(id); err != nil {
+		return err
+	}
+
+	schedules, err := a.db.GetSchedulesByAPIID(id)
 	if err != nil {
 		return err
 	}
+	scheduleIDs := make([]int, len(schedules))
+	for i, schedule := range schedules {
+		scheduleIDs[i] = schedule.ID
+	}
+	a.refreshJobs(scheduleIDs)
+	return nil
+}
 
-	isCurrentlyActive := currentSchedule.IsActive
+// ImportFetch creates an API from a browser DevTools "Copy as fetch" snippet
+func (a *App) ImportFetch(snippet string, collectionID int) (models.API, error) {
+	api, err := importer.ParseFetch(snippet)
+	if err != nil {
+		return api, err
+	}
+	api.CollectionID = collectionID
+	return a.db.CreateAPI(api)
+}
 
-	// Update the schedule in the database
-	if err := a.db.UpdateSchedule(schedule); err != nil {
-		return err
+// ImportCurl creates an API from a cURL command, e.g. copied from the browser or API docs
+func (a *App) ImportCurl(cmd string) (models.API, error) {
+	api, err := importer.ParseCurl(cmd)
+	if err != nil {
+		return api, err
+	}
+	return a.db.CreateAPI(api)
+}
+
+// ExportCurl renders a stored API as a cURL command
+func (a *App) ExportCurl(apiID int) (string, error) {
+	api, err := a.db.GetAPIByID(apiID)
+	if err != nil {
+		return "", err
+	}
+	return importer.FormatCurl(api)
+}
+
+// ImportOpenAPI stores an OpenAPI document (JSON) and creates an API for each of its operations.
+// The APIs stay linked to the spec so their responses can be validated against it.
+func (a *App) ImportOpenAPI(document string, collectionID int) ([]models.API, error) {
+	doc, err := openapi.Parse([]byte(document))
+	if err != nil {
+		return nil, err
+	}
+
+	return a.importSpec(models.APISpec{Document: document, CollectionID: collectionID}, doc)
+}
+
+// PreviewOpenAPIImport returns what ImportOpenAPI would create for a document without storing anything
+func (a *App) PreviewOpenAPIImport(document string) (models.ChangePlan, error) {
+	doc, err := openapi.Parse([]byte(document))
+	if err != nil {
+		return models.ChangePlan{}, err
+	}
+
+	name := doc.Info.Title
+	if name == "" {
+		name = "OpenAPI spec"
+	}
+	plan := models.ChangePlan{Changes: []models.PlannedChange{{Action: models.ChangeActionCreate, Kind: models.ChangeKindSpec, Name: name}}}
+	for _, api := range importer.OpenAPIOperations(doc) {
+		plan.Changes = append(plan.Changes, models.PlannedChange{Action: models.ChangeActionCreate, Kind: models.ChangeKindAPI, Name: api.Name})
+	}
+	return plan, nil
+}
+
+// ImportOpenAPIFromURL fetches an OpenAPI document (JSON) and imports it like ImportOpenAPI.
+// The URL is kept so the spec can be watched for new or removed operations.
+func (a *App) ImportOpenAPIFromURL(url string, collectionID int) ([]models.API, error) {
+	document, doc, err := a.specWatcher.Fetch(url)
+	if err != nil {
+		return nil, err
+	}
+	return a.importSpec(models.APISpec{SourceURL: url, Document: document, CollectionID: collectionID}, doc)
+}
+
+// importSpec stores a parsed spec and creates an API for each of its operations
+func (a *App) importSpec(spec models.APISpec, doc *openapi.Document) ([]models.API, error) {
+	spec.Name = doc.Info.Title
+	if spec.Name == "" {
+		spec.Name = "OpenAPI spec"
+	}
+	spec, err := a.db.CreateAPISpec(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	var created []models.API
+	for _, api := range importer.OpenAPIOperations(doc) {
+		api.CollectionID = spec.CollectionID
+		api.SpecID = spec.ID
+		newAPI, err := a.db.CreateAPI(api)
+		if err != nil {
+			return created, err
+		}
+		created = append(created, newAPI)
+	}
+	return created, nil
+}
+
+// GetAllAPISpecs returns all imported OpenAPI documents
+func (a *App) GetAllAPISpecs() ([]models.APISpec, error) {
+	return a.db.GetAllAPISpecs()
+}
+
+// UpdateAPISpec updates an imported OpenAPI document's settings, such as how often it is watched
+func (a *App) UpdateAPISpec(spec models.APISpec) (models.APISpec, error) {
+	if spec.WatchIntervalMinutes < 0 {
+		return spec, fmt.Errorf("watch interval cannot be negative")
+	}
+	if _, err := openapi.Parse([]byte(spec.Document)); err != nil {
+		return spec, err
+	}
+	return a.db.UpdateAPISpec(spec)
+}
+
+// CheckAPISpec re-fetches a watched spec now and returns its pending proposals
+func (a *App) CheckAPISpec(specID int) ([]models.SpecProposal, error) {
+	return a.specWatcher.Check(specID)
+}
+
+// GetSpecProposals returns the pending proposals of a watched spec
+func (a *App) GetSpecProposals(specID int) ([]models.SpecProposal, error) {
+	return a.db.GetPendingSpecProposals(specID)
+}
+
+// AcceptSpecProposal creates the API for a new operation, or deletes the API of a removed one
+func (a *App) AcceptSpecProposal(proposalID int) error {
+	return a.specWatcher.Accept(proposalID)
+}
+
+// DismissSpecProposal dismisses a proposal so the same change isn't proposed again
+func (a *App) DismissSpecProposal(proposalID int) error {
+	return a.db.SetSpecProposalStatus(proposalID, models.SpecProposalDismissed)
+}
+
+// DeleteAPISpec deletes an imported OpenAPI document and unlinks its APIs
+func (a *App) DeleteAPISpec(id int) error {
+	return a.db.DeleteAPISpec(id)
+}
+
+// Assertion methods
+
+// GetAssertionsByAPIID returns all assertions of an API
+func (a *App) GetAssertionsByAPIID(apiID int) ([]models.Assertion, error) {
+	return a.db.GetAssertionsByAPIID(apiID)
+}
+
+// CreateAssertion creates a new assertion
+func (a *App) CreateAssertion(assertion models.Assertion) (models.Assertion, error) {
+	if err := assertions.Validate(assertion); err != nil {
+		return assertion, err
+	}
+	return a.db.CreateAssertion(assertion)
+}
+
+// UpdateAssertion updates an existing assertion
+func (a *App) UpdateAssertion(assertion models.Assertion) (models.Assertion, error) {
+	if err := assertions.Validate(assertion); err != nil {
+		return assertion, err
+	}
+	return a.db.UpdateAssertion(assertion)
+}
+
+// DeleteAssertion deletes an assertion by ID
+func (a *App) DeleteAssertion(id int) error {
+	return a.db.DeleteAssertion(id)
+}
+
+// Extraction methods
+
+// GetExtractionsByAPIID returns all extractions of an API
+func (a *App) GetExtractionsByAPIID(apiID int) ([]models.Extraction, error) {
+	return a.db.GetExtractionsByAPIID(apiID)
+}
+
+// CreateExtraction creates a new extraction
+func (a *App) CreateExtraction(e models.Extraction) (models.Extraction, error) {
+	if err := extraction.Validate(e); err != nil {
+		return e, err
+	}
+	return a.db.CreateExtraction(e)
+}
+
+// UpdateExtraction updates an existing extraction
+func (a *App) UpdateExtraction(e models.Extraction) (models.Extraction, error) {
+	if err := extraction.Validate(e); err != nil {
+		return e, err
+	}
+	return a.db.UpdateExtraction(e)
+}
+
+// DeleteExtraction deletes an extraction by ID
+func (a *App) DeleteExtraction(id int) error {
+	return a.db.DeleteExtraction(id)
+}
+
+// Collection methods
+
+// GetAllCollections returns all collections
+func (a *App) GetAllCollections() ([]models.Collection, error) {
+	return a.db.GetAllCollections()
+}
+
+// GetCollectionByID returns a collection by ID
+func (a *App) GetCollectionByID(id int) (models.Collection, error) {
+	return a.db.GetCollectionByID(id)
+}
+
+// CreateCollection creates a new collection
+func (a *App) CreateCollection(collection models.Collection) (models.Collection, error) {
+	if err := validateRunThrottle(collection); err != nil {
+		return collection, err
+	}
+	if _, err := environments.ParseVariables(collection.Variables); err != nil {
+		return collection, err
+	}
+	if err := validateLatencyTargets(collection.LatencyTargets); err != nil {
+		return collection, err
+	}
+	if collection.ParentID != 0 {
+		parent, err := a.db.GetCollectionByID(collection.ParentID)
+		if err != nil {
+			return collection, err
+		}
+		if parent.DeletedAt != nil {
+			return collection, fmt.Errorf("collection %s is in the trash", parent.Name)
+		}
+	}
+	return a.db.CreateCollection(collection)
+}
+
+// UpdateCollection updates an existing collection
+func (a *App) UpdateCollection(collection models.Collection) (models.Collection, error) {
+	if err := validateRunThrottle(collection); err != nil {
+		return collection, err
+	}
+	if _, err := environments.ParseVariables(collection.Variables); err != nil {
+		return collection, err
+	}
+	if err := validateLatencyTargets(collection.LatencyTargets); err != nil {
+		return collection, err
+	}
+	return a.db.UpdateCollection(collection)
+}
+
+// validateLatencyTargets checks each latency target has a percentile, threshold and window that make sense
+func validateLatencyTargets(targets []models.LatencyTarget) error {
+	for _, target := range targets {
+		if target.Percentile <= 0 || target.Percentile > 100 {
+			return fmt.Errorf("latency target percentile must be above 0 and at most 100")
+		}
+		if target.ThresholdMs <= 0 {
+			return fmt.Errorf("latency target threshold must be positive")
+		}
+		if target.WindowHours < 0 {
+			return fmt.Errorf("latency target window cannot be negative")
+		}
+	}
+	return nil
+}
+
+// validateRunThrottle checks the parallelism and step delay of a collection's runs
+func validateRunThrottle(collection models.Collection) error {
+	if collection.MaxParallel < 0 {
+		return fmt.Errorf("max parallel requests cannot be negative")
+	}
+	if collection.StepDelayMs < 0 {
+		return fmt.Errorf("step delay cannot be negative")
+	}
+	return nil
+}
+
+// DeleteCollection moves a collection to the trash along with the folders nested in it
+func (a *App) DeleteCollection(id int) error {
+	return a.db.DeleteCollection(id)
+}
+
+// MoveCollection nests a collection, with everything in it, in another collection, or at the top level
+// with parentID 0
+func (a *App) MoveCollection(id, parentID int) error {
+	return a.db.MoveCollection(id, parentID)
+}
+
+// GetCollectionTree returns the collections as a tree of folders with their APIs
+func (a *App) GetCollectionTree() ([]models.CollectionNode, error) {
+	return a.db.GetCollectionTree()
+}
+
+// DuplicateCollection copies a collection, named with a "copy" suffix, along with copies of its APIs in the
+// same order and under the same names. With includeSchedules the APIs' schedules are copied and started too; their alert rules
+// aren't, so the copy doesn't alert anyone twice.
+func (a *App) DuplicateCollection(id int, includeSchedules bool) (models.Collection, error) {
+	collection, err := a.db.GetCollectionByID(id)
+	if err != nil {
+		return collection, err
+	}
+	apis, err := a.db.GetAPIsByCollectionID(id)
+	if err != nil {
+		return collection, err
+	}
+
+	collection.ID = 0
+	collection.UUID = ""
+	collection.Name += " copy"
+	copied, err := a.db.CreateCollection(collection)
+	if err != nil {
+		return copied, err
+	}
+
+	var scheduleIDs []int
+	for _, api := range apis {
+		_, copiedSchedules, err := a.duplicateAPI(api, copied.ID, includeSchedules)
+		scheduleIDs = append(scheduleIDs, copiedSchedules...)
+		if err != nil {
+			a.refreshJobs(scheduleIDs)
+			return copied, err
+		}
+	}
+	a.refreshJobs(scheduleIDs)
+	return copied, nil
+}
+
+// RestoreCollection takes a collection out of the trash
+func (a *App) RestoreCollection(id int) error {
+	return a.db.RestoreCollection(id)
+}
+
+// GetTrash returns the deleted APIs and collections that can still be restored
+func (a *App) GetTrash() (models.Trash, error) {
+	return a.db.GetTrash()
+}
+
+// EmptyTrash permanently deletes everything in the trash
+func (a *App) EmptyTrash() error {
+	return a.db.EmptyTrash()
+}
+
+// GetAPIsByCollectionID returns all APIs in a collection
+func (a *App) GetAPIsByCollectionID(collectionID int) ([]models.API, error) {
+	return a.db.GetAPIsByCollectionID(collectionID)
+}
+
+// SetCollectionEnvironment marks the active environment of a collection (0 to clear it)
+func (a *App) SetCollectionEnvironment(collectionID, environmentID int) error {
+	return a.db.SetCollectionEnvironment(collectionID, environmentID)
+}
+
+// SetCollectionOrder stores the order in which a collection's APIs run; apiIDs lists them first to last
+func (a *App) SetCollectionOrder(collectionID int, apiIDs []int) error {
+	return a.db.SetCollectionOrder(collectionID, apiIDs)
+}
+
+// RunCollection runs every API of a collection in order and returns the recorded collection run
+func (a *App) RunCollection(collectionID int) (models.CollectionRun, error) {
+	return a.scheduler.RunCollection(collectionID)
+}
+
+// GetCollectionRuns returns the most recent runs of a collection
+func (a *App) GetCollectionRuns(collectionID int, limit int) ([]models.CollectionRun, error) {
+	return a.db.GetCollectionRunsByCollectionID(collectionID, limit)
+}
+
+// GetCollectionRun returns a collection run with its executions and latency budget evaluation
+func (a *App) GetCollectionRun(runID int) (models.CollectionRun, error) {
+	run, err := a.db.GetCollectionRunByID(runID)
+	if err != nil {
+		return run, err
+	}
+
+	collection, err := a.db.GetCollectionByID(run.CollectionID)
+	if err != nil {
+		return run, err
+	}
+	apis, err := a.db.GetAPIsByCollectionID(run.CollectionID)
+	if err != nil {
+		return run, err
+	}
+
+	run.Budget = scheduler.RunBudget(collection, apis, run)
+	return run, nil
+}
+
+// GetCollectionRunWaterfall returns the start offsets, durations and dependencies of the steps of a
+// collection run for rendering as a waterfall chart
+func (a *App) GetCollectionRunWaterfall(runID int) (models.Waterfall, error) {
+	run, err := a.db.GetCollectionRunByID(runID)
+	if err != nil {
+		return models.Waterfall{}, err
+	}
+	apis, err := a.db.GetAPIsByCollectionID(run.CollectionID)
+	if err != nil {
+		return models.Waterfall{}, err
+	}
+
+	extractions := make(map[int][]models.Extraction, len(apis))
+	for _, api := range apis {
+		if extractions[api.ID], err = a.db.GetExtractionsByAPIID(api.ID); err != nil {
+			return models.Waterfall{}, err
+		}
+	}
+	return scheduler.RunWaterfall(run, apis, extractions), nil
+}
+
+// GetCollectionBudgetReport evaluates the collection's latency budget against the latest execution of each of its APIs
+func (a *App) GetCollectionBudgetReport(collectionID int) (models.BudgetReport, error) {
+	collection, err := a.db.GetCollectionByID(collectionID)
+	if err != nil {
+		return models.BudgetReport{}, err
+	}
+
+	apis, err := a.db.GetAPIsByCollectionID(collectionID)
+	if err != nil {
+		return models.BudgetReport{}, err
+	}
+
+	apiIDs := make([]int, len(apis))
+	for i, api := range apis {
+		apiIDs[i] = api.ID
+	}
+	latest, err := a.db.GetLatestExecutionForAPIs(apiIDs)
+	if err != nil {
+		return models.BudgetReport{}, err
+	}
+
+	var steps []models.BudgetStep
+	for _, api := range apis {
+		execution, ok := latest[api.ID]
+		if !ok {
+			continue
+		}
+		steps = append(steps, models.BudgetStep{
+			APIID:      api.ID,
+			APIName:    api.Name,
+			LogID:      execution.ID,
+			DurationMs: execution.DurationMs,
+		})
+	}
+
+	return scheduler.EvaluateBudget(collectionID, collection.LatencyBudgetMs, steps), nil
+}
+
+// Auth config methods
+
+// GetAllAuthConfigs returns all auth configs
+func (a *App) GetAllAuthConfigs() ([]models.AuthConfig, error) {
+	return a.db.GetAllAuthConfigs()
+}
+
+// CreateAuthConfig creates a new auth config
+func (a *App) CreateAuthConfig(config models.AuthConfig) (models.AuthConfig, error) {
+	if err := auth.Validate(config); err != nil {
+		return config, err
+	}
+	return a.db.CreateAuthConfig(config)
+}
+
+// UpdateAuthConfig updates an existing auth config
+func (a *App) UpdateAuthConfig(config models.AuthConfig) (models.AuthConfig, error) {
+	if err := auth.Validate(config); err != nil {
+		return config, err
+	}
+	return a.db.UpdateAuthConfig(config)
+}
+
+// DeleteAuthConfig deletes an auth config and detaches it from APIs and collections
+func (a *App) DeleteAuthConfig(id int) error {
+	return a.db.DeleteAuthConfig(id)
+}
+
+// Secret methods
+
+// GetSecrets returns all secrets without their values
+func (a *App) GetSecrets() ([]models.Secret, error) {
+	return a.db.GetAllSecrets()
+}
+
+// SetSecret creates or replaces a secret, referenced in requests as {{secret:name}}
+func (a *App) SetSecret(name, value string) (models.Secret, error) {
+	return a.secrets.Set(strings.TrimSpace(name), value)
+}
+
+// DeleteSecret deletes a secret by ID
+func (a *App) DeleteSecret(id int) error {
+	return a.secrets.Delete(id)
+}
+
+// SetSecretValidationAPI designates the API run to check a secret when it is rotated; 0 clears it
+func (a *App) SetSecretValidationAPI(secretID, apiID int) (models.Secret, error) {
+	if apiID != 0 {
+		if _, err := a.db.GetAPIByID(apiID); err != nil {
+			return models.Secret{}, err
+		}
+	}
+	return a.db.SetSecretValidationAPI(secretID, apiID)
+}
+
+// RotateSecret replaces a secret's value and immediately runs its validation API with it.
+// When the check fails the previous value is restored, so schedules keep the working credential,
+// although checks starting during validation may already see the new one.
+func (a *App) RotateSecret(name, value string) (models.SecretRotation, error) {
+	name = strings.TrimSpace(name)
+	secret, err := a.db.GetSecretByName(name)
+	if err != nil {
+		return models.SecretRotation{}, err
+	}
+	if secret.ValidationAPIID == 0 {
+		return models.SecretRotation{}, fmt.Errorf("secret %q has no validation API", name)
+	}
+	if _, err := a.db.GetAPIByID(secret.ValidationAPIID); err != nil {
+		return models.SecretRotation{}, err
+	}
+	previous, err := a.secrets.Get(name)
+	if err != nil {
+		return models.SecretRotation{}, fmt.Errorf("failed to read current value of secret %q: %w", name, err)
+	}
+
+	rotated, err := a.secrets.Set(name, value)
+	if err != nil {
+		return models.SecretRotation{}, err
+	}
+	validation, err := a.scheduler.RunAPI(secret.ValidationAPIID)
+	if err != nil {
+		return models.SecretRotation{Secret: rotated}, err
+	}
+
+	rotation := models.SecretRotation{
+		Secret:     rotated,
+		Validation: validation,
+		Valid:      validation.Status == models.ExecutionStatusSuccess || validation.Status == models.ExecutionStatusDegraded,
+	}
+	if !rotation.Valid {
+		restored, err := a.secrets.Set(name, previous)
+		if err != nil {
+			return rotation, fmt.Errorf("new value of secret %q failed validation and the previous value could not be restored: %w", name, err)
+		}
+		rotation.Secret = restored
+		rotation.RolledBack = true
+	}
+	return rotation, nil
+}
+
+// Environment methods
+
+// GetAllEnvironments returns all environments
+func (a *App) GetAllEnvironments() ([]models.Environment, error) {
+	return a.db.GetAllEnvironments()
+}
+
+// GetEnvironmentByID returns an environment by ID
+func (a *App) GetEnvironmentByID(id int) (models.Environment, error) {
+	return a.db.GetEnvironmentByID(id)
+}
+
+// CreateEnvironment creates a new environment
+func (a *App) CreateEnvironment(environment models.Environment) (models.Environment, error) {
+	if _, err := environments.ParseVariables(environment.Variables); err != nil {
+		return environment, err
+	}
+	return a.db.CreateEnvironment(environment)
+}
+
+// UpdateEnvironment updates an existing environment
+func (a *App) UpdateEnvironment(environment models.Environment) (models.Environment, error) {
+	if _, err := environments.ParseVariables(environment.Variables); err != nil {
+		return environment, err
+	}
+	return a.db.UpdateEnvironment(environment)
+}
+
+// DeleteEnvironment deletes an environment by ID
+func (a *App) DeleteEnvironment(id int) error {
+	return a.db.DeleteEnvironment(id)
+}
+
+// GetGlobalVariables returns the JSON object of variables available to every API
+func (a *App) GetGlobalVariables() (string, error) {
+	return a.db.GetGlobalVariables()
+}
+
+// SaveGlobalVariables saves the variables available to every API, which environments, collections and APIs can override
+func (a *App) SaveGlobalVariables(variables string) error {
+	if _, err := environments.ParseVariables(variables); err != nil {
+		return err
+	}
+	return a.db.SaveGlobalVariables(variables)
+}
+
+// GetResolvedVariables returns the variables an API's requests are resolved with,
+// reporting which scope supplied each value and which scopes it overrides
+func (a *App) GetResolvedVariables(apiID int) ([]models.ResolvedVariable, error) {
+	api, err := a.db.GetAPIByID(apiID)
+	if err != nil {
+		return nil, err
+	}
+	return environments.NewService(a.db, a.secrets).ResolveVariables(api)
+}
+
+// Analytics methods
+
+// GetAPIAnalytics returns analytics for a specific API
+func (a *App) GetAPIAnalytics(apiID int) (models.AnalyticsSummary, error) {
+	return a.db.GetAPIAnalytics(apiID)
+}
+
+// GetOverallAnalytics returns overall analytics for all APIs
+func (a *App) GetOverallAnalytics() (models.AnalyticsSummary, error) {
+	return a.db.GetOverallAnalytics()
+}
+
+// GetAPITimeSeries returns an API's status counts and latency between from and to, bucketed by "hour" or "day"
+func (a *App) GetAPITimeSeries(apiID int, bucket string, from, to time.Time) (models.TimeSeries, error) {
+	if !to.After(from) {
+		return models.TimeSeries{}, fmt.Errorf("the time series must end after it starts")
+	}
+	return a.db.GetAPITimeSeries(apiID, bucket, from, to)
+}
+
+// GetLatencyHistogram returns an API's latency distribution and percentiles between from and to, read from
+// hourly rollups so long ranges are answered without scanning their execution logs
+func (a *App) GetLatencyHistogram(apiID int, from, to time.Time) (models.LatencyHistogram, error) {
+	if !to.After(from) {
+		return models.LatencyHistogram{}, fmt.Errorf("the histogram must end after it starts")
+	}
+	return a.db.GetLatencyHistogram(apiID, from, to)
+}
+
+// GetPerformanceBreakdown splits an API's checks over the last given hours between availability, whether they
+// answered as expected, and performance, whether they answered within the API's latency thresholds
+func (a *App) GetPerformanceBreakdown(apiID int, hours int) (models.PerformanceBreakdown, error) {
+	api, err := a.db.GetAPIByID(apiID)
+	if err != nil {
+		return models.PerformanceBreakdown{}, err
+	}
+	breakdown, err := a.db.GetPerformanceBreakdown(apiID, time.Now().Add(-time.Duration(hours)*time.Hour))
+	breakdown.LatencyWarnMs, breakdown.LatencyCriticalMs = api.LatencyWarnMs, api.LatencyCriticalMs
+	return breakdown, err
+}
+
+// GetCollectionLatencyCompliance evaluates a collection's latency targets over the executions of its APIs
+func (a *App) GetCollectionLatencyCompliance(collectionID int) (models.LatencyCompliance, error) {
+	collection, err := a.db.GetCollectionByID(collectionID)
+	if err != nil {
+		return models.LatencyCompliance{}, err
+	}
+	return a.scheduler.EvaluateLatencyTargets(collection)
+}
+
+// GetLocationComparison compares an API's availability and latency across locations over the last given hours,
+// distinguishing global outages from failures seen only in some regions
+func (a *App) GetLocationComparison(apiID int, hours int) (models.LocationComparison, error) {
+	return a.db.GetLocationComparison(apiID, time.Now().Add(-time.Duration(hours)*time.Hour))
+}
+
+// GetSchedulerMetrics returns the scheduler's own activity per minute over the last given hours, such as how many
+// checks ran, how long scheduled runs waited to start and how many were skipped
+func (a *App) GetSchedulerMetrics(hours int) (models.SchedulerMetricsReport, error) {
+	report, err := a.db.GetSchedulerMetrics(time.Now().Add(-time.Duration(hours) * time.Hour))
+	if err != nil {
+		return report, err
+	}
+	report.Hosts = a.scheduler.HostLoad()
+	return report, nil
+}
+
+// GetExecutionStatusCounts returns counts of the status codes of an API's recent executions. Codes are scored
+// the way checks score them, so the API's own success and degraded codes count as such, and codes it fails
+// on are grouped by range. Executions without a status code, such as connection errors, count as other.
+func (a *App) GetExecutionStatusCounts(apiID int) (map[string]int, error) {
+	api, err := a.db.GetAPIByID(apiID)
+	if err != nil {
+		return nil, err
+	}
+	logs, err := a.db.GetExecutionLogsByAPIID(apiID, 1000) // Get a large sample
+	if err != nil {
+		return nil, err
+	}
+
+	counts := map[string]int{
+		"success":      0, // Codes the check succeeds with
+		"degraded":     0, // Codes the check reports as degraded
+		"redirect":     0, // Failing 3xx
+		"client_error": 0, // Failing 4xx
+		"server_error": 0, // Failing 5xx
+		"failure":      0, // Other failing codes, e.g. a 2xx the API's success codes leave out
+		"other":        0, // No status code
+	}
+
+	for _, log := range logs {
+		if log.Status == models.ExecutionStatusSkipped {
+			continue // Never ran, so there is no status code
+		}
+		statusCode := log.StatusCode
+		if statusCode == 0 {
+			counts["other"]++
+			continue
+		}
+
+		outcome, err := a.scheduler.ClassifyStatusCode(api, statusCode)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case outcome == models.ExecutionStatusSuccess:
+			counts["success"]++
+		case outcome == models.ExecutionStatusDegraded:
+			counts["degraded"]++
+		case statusCode >= 300 && statusCode < 400:
+			counts["redirect"]++
+		case statusCode >= 400 && statusCode < 500:
+			counts["client_error"]++
+		case statusCode >= 500:
+			counts["server_error"]++
+		default:
+			counts["failure"]++
+		}
+	}
+
+	return counts, nil
+}
+
+// Schedules methods
+
+// GetAllSchedules returns all schedules
+func (a *App) GetAllSchedules() ([]models.Schedule, error) {
+	return a.db.GetAllSchedules()
+}
+
+// GetSchedulesByAPIID returns all schedules for an API
+func (a *App) GetSchedulesByAPIID(apiID int) ([]models.Schedule, error) {
+	return a.db.GetSchedulesByAPIID(apiID)
+}
+
+// CreateSchedule creates a new schedule
+func (a *App) CreateSchedule(schedule models.Schedule) (models.Schedule, error) {
+	if err := validateRetryPolicy(&schedule); err != nil {
+		return schedule, err
+	}
+	if err := validateTimezone(schedule); err != nil {
+		return schedule, err
+	}
+	if err := a.validateScheduleAfter(&schedule); err != nil {
+		return schedule, err
+	}
+	newSchedule, err := a.db.CreateSchedule(schedule)
+	if err != nil {
+		return newSchedule, err
+	}
+
+	// If the schedule is active, schedule it
+	if newSchedule.IsActive {
+		if err := a.scheduler.ScheduleJob(newSchedule); err != nil {
+			return newSchedule, fmt.Errorf("schedule created but failed to start job: %w", err)
+		}
+	}
+
+	return newSchedule, nil
+}
+
+// UpdateSchedule updates an existing schedule
+func (a *App) UpdateSchedule(schedule models.Schedule) error {
+	if err := validateRetryPolicy(&schedule); err != nil {
+		return err
+	}
+	if err := validateTimezone(schedule); err != nil {
+		return err
+	}
+	if err := a.validateScheduleAfter(&schedule); err != nil {
+		return err
+	}
+
+	// Get the current state of the schedule
+	currentSchedule, err := a.db.GetScheduleByID(schedule.ID)
+	if err != nil {
+		return err
+	}
+
+	isCurrentlyActive := currentSchedule.IsActive
+
+	// Update the schedule in the database
+	if err := a.db.UpdateSchedule(schedule); err != nil {
+		return err
 	}
 
 	// Handle job scheduling based on active state changes
@@ -213,82 +1492,1519 @@ func (a *App) UpdateSchedule(schedule models.Schedule) error {
 		if err := a.scheduler.StopJob(schedule.ID); err != nil {
 			return fmt.Errorf("schedule updated but failed to stop job: %w", err)
 		}
-	} else if !isCurrentlyActive && schedule.IsActive {
-		// Start the job
-		if err := a.scheduler.ScheduleJob(schedule); err != nil {
-			return fmt.Errorf("schedule updated but failed to start job: %w", err)
+	} else if !isCurrentlyActive && schedule.IsActive {
+		// Start the job
+		if err := a.scheduler.ScheduleJob(schedule); err != nil {
+			return fmt.Errorf("schedule updated but failed to start job: %w", err)
+		}
+	} else if isCurrentlyActive && schedule.IsActive {
+		// Update the job by stopping and restarting
+		if err := a.scheduler.StopJob(schedule.ID); err != nil {
+			log.Printf("Failed to stop existing job for schedule ID %d: %v", schedule.ID, err)
+		}
+		if err := a.scheduler.ScheduleJob(schedule); err != nil {
+			return fmt.Errorf("schedule updated but failed to restart job: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// maxRetryCount is the most retries a schedule can make, which keeps exponential delays far from overflowing
+const maxRetryCount = 20
+
+// validateRetryPolicy checks a schedule's retries and retry policy, defaulting an empty strategy to fixed delays
+func validateRetryPolicy(schedule *models.Schedule) error {
+	if schedule.RetryCount < 0 || schedule.RetryCount > maxRetryCount {
+		return fmt.Errorf("retry count must be between 0 and %d", maxRetryCount)
+	}
+	if schedule.FallbackDelay < 0 {
+		return fmt.Errorf("retry delay cannot be negative")
+	}
+	policy := &schedule.RetryPolicy
+	switch policy.Strategy {
+	case "":
+		policy.Strategy = models.RetryStrategyFixed
+	case models.RetryStrategyFixed, models.RetryStrategyExponential:
+	default:
+		return fmt.Errorf("unsupported retry strategy: %s", policy.Strategy)
+	}
+	if policy.Multiplier != 0 && policy.Multiplier < 1 {
+		return fmt.Errorf("retry multiplier must be at least 1")
+	}
+	if policy.MaxDelay < 0 {
+		return fmt.Errorf("maximum retry delay cannot be negative")
+	}
+	if policy.Jitter < 0 || policy.Jitter > 1 {
+		return fmt.Errorf("retry jitter must be between 0 and 1")
+	}
+	return nil
+}
+
+// validateTimezone checks that a schedule's timezone is a known IANA zone and that only cron
+// schedules set one, since intervals and RFC3339 timestamps are independent of the zone
+func validateTimezone(schedule models.Schedule) error {
+	if schedule.Timezone == "" {
+		return nil
+	}
+	if schedule.Type != "cron" {
+		return fmt.Errorf("only cron schedules can have a timezone")
+	}
+	_, err := scheduler.LoadTimezone(schedule.Timezone)
+	return err
+}
+
+// validateScheduleAfter checks the schedule an "after" schedule runs after, which must exist and must not
+// run after it in turn, and the outcome it waits for, defaulting to success
+func (a *App) validateScheduleAfter(schedule *models.Schedule) error {
+	if schedule.Type != "after" {
+		if schedule.AfterID != 0 {
+			return fmt.Errorf("only after schedules run after another schedule")
+		}
+		schedule.AfterOutcome = ""
+		return nil
+	}
+
+	switch schedule.AfterOutcome {
+	case "":
+		schedule.AfterOutcome = models.AfterOutcomeSuccess
+	case models.AfterOutcomeSuccess, models.AfterOutcomeFailure, models.AfterOutcomeAny:
+	default:
+		return fmt.Errorf("unsupported outcome to run after: %s", schedule.AfterOutcome)
+	}
+	if schedule.AfterID == 0 {
+		return fmt.Errorf("after schedules need a schedule to run after")
+	}
+	if schedule.AfterID == schedule.ID {
+		return fmt.Errorf("a schedule cannot run after itself")
+	}
+	if _, err := a.db.GetScheduleByID(schedule.AfterID); err != nil {
+		return err
+	}
+	if schedule.ID != 0 {
+		cycle, err := a.db.ScheduleRunsAfter(schedule.AfterID, schedule.ID)
+		if err != nil {
+			return err
+		}
+		if cycle {
+			return fmt.Errorf("schedule %d already runs after this one, which would make a cycle", schedule.AfterID)
+		}
+	}
+	return nil
+}
+
+// GetScheduleGraph returns the schedules that run after one another as a graph, to draw their dependencies
+func (a *App) GetScheduleGraph() (models.ScheduleGraph, error) {
+	return a.db.GetScheduleGraph()
+}
+
+// DeleteSchedule deletes a schedule by ID. Schedules running after it must be deleted or relinked first.
+func (a *App) DeleteSchedule(id int) error {
+	dependents, err := a.db.GetSchedulesAfter(id)
+	if err != nil {
+		return err
+	}
+	if len(dependents) > 0 {
+		return fmt.Errorf("%d schedules run after this one; delete them or run them after another schedule first", len(dependents))
+	}
+
+	// Stop the job first
+	if err := a.scheduler.StopJob(id); err != nil {
+		log.Printf("Failed to stop job for schedule ID %d: %v", id, err)
+	}
+
+	// Delete from database
+	return a.db.DeleteSchedule(id)
+}
+
+// ToggleSchedule toggles the active state of a schedule
+func (a *App) ToggleSchedule(id int, isActive bool) error {
+	schedule, err := a.db.GetScheduleByID(id)
+	if err != nil {
+		return fmt.Errorf("failed to get schedule: %w", err)
+	}
+
+	schedule.IsActive = isActive
+	return a.UpdateSchedule(schedule)
+}
+
+// CancelSchedule cancels a schedule permanently
+func (a *App) CancelSchedule(id int) error {
+	// First get the schedule
+	schedule, err := a.db.GetScheduleByID(id)
+	if err != nil {
+		return fmt.Errorf("failed to get schedule: %w", err)
+	}
+
+	// Stop the job
+	if err := a.scheduler.StopJob(id); err != nil {
+		log.Printf("Failed to stop job for schedule ID %d: %v", id, err)
+	}
+
+	// Update to inactive status
+	schedule.IsActive = false
+	return a.db.UpdateSchedule(schedule)
+}
+
+// maxUpcomingRuns bounds how many upcoming runs of a schedule are computed at once
+const maxUpcomingRuns = 1000
+
+// maxCalendarDays bounds the period a schedule calendar covers
+const maxCalendarDays = 366
+
+// GetUpcomingRuns returns the next count times a schedule fires, whether or not it is active
+func (a *App) GetUpcomingRuns(scheduleID int, count int) ([]time.Time, error) {
+	if count < 1 || count > maxUpcomingRuns {
+		return nil, fmt.Errorf("count must be between 1 and %d", maxUpcomingRuns)
+	}
+	schedule, err := a.db.GetScheduleByID(scheduleID)
+	if err != nil {
+		return nil, err
+	}
+	return a.scheduler.UpcomingRuns(schedule, time.Now(), time.Time{}, count)
+}
+
+// GetUpcomingExecutions returns the next limit runs across every active schedule, to show what will
+// run soon at a glance
+func (a *App) GetUpcomingExecutions(limit int) ([]models.ScheduledRun, error) {
+	if limit < 1 || limit > maxUpcomingRuns {
+		return nil, fmt.Errorf("limit must be between 1 and %d", maxUpcomingRuns)
+	}
+	return a.scheduler.UpcomingExecutions(time.Now(), limit)
+}
+
+// GetScheduleCalendar returns the runs of every active schedule between from and to, for a calendar view
+func (a *App) GetScheduleCalendar(from, to time.Time) (models.ScheduleCalendar, error) {
+	if !to.After(from) {
+		return models.ScheduleCalendar{}, fmt.Errorf("the calendar must end after it starts")
+	}
+	if to.Sub(from) > maxCalendarDays*24*time.Hour {
+		return models.ScheduleCalendar{}, fmt.Errorf("the calendar can cover at most %d days", maxCalendarDays)
+	}
+	return a.scheduler.Calendar(from, to)
+}
+
+// GetStaleSchedules returns the active schedules whose job stopped running, as decided by the stale check policy
+func (a *App) GetStaleSchedules() ([]models.StaleCheck, error) {
+	policy, err := a.db.GetStaleCheckPolicy()
+	if err != nil {
+		return nil, err
+	}
+	return a.scheduler.StaleChecks(policy.Intervals)
+}
+
+// GetStaleCheckPolicy returns when active schedules count as stale and whether that is alerted
+func (a *App) GetStaleCheckPolicy() (models.StaleCheckPolicy, error) {
+	return a.db.GetStaleCheckPolicy()
+}
+
+// SaveStaleCheckPolicy saves when active schedules count as stale and whether that is alerted
+func (a *App) SaveStaleCheckPolicy(policy models.StaleCheckPolicy) error {
+	if policy.Intervals < 1 {
+		return fmt.Errorf("a schedule must miss at least one interval to be stale")
+	}
+	return a.db.SaveStaleCheckPolicy(policy)
+}
+
+// GetSchedulerConcurrency returns the limits on scheduled checks running at once
+func (a *App) GetSchedulerConcurrency() (models.SchedulerConcurrency, error) {
+	return a.db.GetSchedulerConcurrency()
+}
+
+// SaveSchedulerConcurrency saves the limits on scheduled checks running at once and applies them right away
+func (a *App) SaveSchedulerConcurrency(concurrency models.SchedulerConcurrency) error {
+	if concurrency.MaxConcurrent < 0 || concurrency.MaxPerHost < 0 {
+		return fmt.Errorf("concurrency limits cannot be negative")
+	}
+	if err := a.db.SaveSchedulerConcurrency(concurrency); err != nil {
+		return err
+	}
+	a.scheduler.SetConcurrency(concurrency)
+	return nil
+}
+
+// GetIngestionGuard returns the limits on log volume above which successes are sampled
+func (a *App) GetIngestionGuard() (models.IngestionGuard, error) {
+	return a.db.GetIngestionGuard()
+}
+
+// SaveIngestionGuard saves the limits on log volume above which successes are sampled and applies them right away
+func (a *App) SaveIngestionGuard(guard models.IngestionGuard) error {
+	if guard.MaxLogsPerMinute < 0 || guard.MaxBytesPerMinute < 0 || guard.SampleRate < 0 {
+		return fmt.Errorf("ingestion limits cannot be negative")
+	}
+	if guard.AlertChannelID != 0 {
+		if _, err := a.db.GetNotificationChannelByID(guard.AlertChannelID); err != nil {
+			return err
+		}
+	}
+	if err := a.db.SaveIngestionGuard(guard); err != nil {
+		return err
+	}
+	a.scheduler.SetIngestionGuard(guard)
+	return nil
+}
+
+// GetTriggerCoalescing returns how triggers of the same API arriving close together are coalesced
+func (a *App) GetTriggerCoalescing() (models.TriggerCoalescing, error) {
+	return a.db.GetTriggerCoalescing()
+}
+
+// SaveTriggerCoalescing saves how triggers of the same API arriving close together are coalesced and applies
+// it right away
+func (a *App) SaveTriggerCoalescing(coalescing models.TriggerCoalescing) error {
+	if coalescing.WindowSeconds < 0 {
+		return fmt.Errorf("coalescing window cannot be negative")
+	}
+	if err := a.db.SaveTriggerCoalescing(coalescing); err != nil {
+		return err
+	}
+	a.scheduler.SetTriggerCoalescing(coalescing)
+	return nil
+}
+
+// GetIngestionStatus returns the log volume of the current minute and whether successes are being sampled
+func (a *App) GetIngestionStatus() models.IngestionStatus {
+	return a.scheduler.IngestionStatus()
+}
+
+// Logs methods
+
+// GetExecutionLogsByAPIID returns execution logs for an API
+func (a *App) GetExecutionLogsByAPIID(apiID int, limit int) ([]models.ExecutionLog, error) {
+	return a.db.GetExecutionLogsByAPIID(apiID, limit)
+}
+
+// GetLatestExecutionForAPIs returns the most recent execution log of each API, keyed by API ID
+func (a *App) GetLatestExecutionForAPIs(apiIDs []int) (map[int]models.ExecutionLog, error) {
+	return a.db.GetLatestExecutionForAPIs(apiIDs)
+}
+
+// GetAllExecutionLogs returns all execution logs with pagination
+func (a *App) GetAllExecutionLogs(page, pageSize int) ([]models.ExecutionLog, error) {
+	return a.db.GetAllExecutionLogs(page, pageSize)
+}
+
+// GetRecentExecutions returns the most recent execution logs
+func (a *App) GetRecentExecutions(limit int) ([]models.ExecutionLog, error) {
+	return a.db.GetRecentExecutions(limit)
+}
+
+// GetExecutionResponseBody returns the full response of an execution, which its log only keeps a preview of
+// when the response was long
+func (a *App) GetExecutionResponseBody(logID int) (string, error) {
+	return a.db.GetExecutionResponseBody(logID)
+}
+
+// QueryExecutionResponse evaluates a JSONPath against the stored response of an execution, so extraction
+// rules can be previewed while they are written
+func (a *App) QueryExecutionResponse(logID int, jsonPath string) (models.ResponseQuery, error) {
+	query := models.ResponseQuery{Expression: jsonPath, Matches: []interface{}{}}
+	if _, err := jsonpath.Compile(jsonPath); err != nil {
+		return query, err
+	}
+	body, err := a.db.GetExecutionResponseBody(logID)
+	if err != nil {
+		return query, err
+	}
+
+	matches, err := jsonpath.Query(body, jsonPath)
+	if err != nil {
+		if strings.HasSuffix(body, database.TruncatedSuffix) {
+			return query, fmt.Errorf("only a preview of this response was stored; store full responses to query long ones")
+		}
+		return query, err
+	}
+	if len(matches) > 0 {
+		query.Matches = matches
+		query.Value = jsonpath.Stringify(matches[0])
+	}
+	return query, nil
+}
+
+// searchResultLimit is how many results of each type Search returns
+const searchResultLimit = 20
+
+// Search finds APIs, collections and execution logs containing every word of the query, for the universal search bar
+func (a *App) Search(query string) ([]models.SearchResult, error) {
+	return a.db.Search(query, searchResultLimit)
+}
+
+// SearchExecutionLogs returns the newest execution logs matching a filter, at most limit of them
+func (a *App) SearchExecutionLogs(filter models.LogFilter, limit int) ([]models.ExecutionLog, error) {
+	if err := validateLogFilter(filter); err != nil {
+		return nil, err
+	}
+	return a.db.SearchExecutionLogs(filter, limit)
+}
+
+// SearchExecutionLogsWithSavedFilter returns the newest execution logs matching a saved filter, at most limit of them
+func (a *App) SearchExecutionLogsWithSavedFilter(filterID, limit int) ([]models.ExecutionLog, error) {
+	saved, err := a.db.GetSavedLogFilterByID(filterID)
+	if err != nil {
+		return nil, err
+	}
+	return a.db.SearchExecutionLogs(saved.Filter, limit)
+}
+
+// GetSavedLogFilters returns every saved log filter ordered by name
+func (a *App) GetSavedLogFilters() ([]models.SavedLogFilter, error) {
+	return a.db.GetAllSavedLogFilters()
+}
+
+// CreateSavedLogFilter saves a named log filter
+func (a *App) CreateSavedLogFilter(saved models.SavedLogFilter) (models.SavedLogFilter, error) {
+	if err := validateSavedLogFilter(&saved); err != nil {
+		return saved, err
+	}
+	return a.db.CreateSavedLogFilter(saved)
+}
+
+// UpdateSavedLogFilter updates an existing saved log filter
+func (a *App) UpdateSavedLogFilter(saved models.SavedLogFilter) (models.SavedLogFilter, error) {
+	if err := validateSavedLogFilter(&saved); err != nil {
+		return saved, err
+	}
+	return a.db.UpdateSavedLogFilter(saved)
+}
+
+// DeleteSavedLogFilter deletes a saved log filter by ID
+func (a *App) DeleteSavedLogFilter(id int) error {
+	return a.db.DeleteSavedLogFilter(id)
+}
+
+// validateSavedLogFilter checks a saved log filter has a name and a valid filter
+func validateSavedLogFilter(saved *models.SavedLogFilter) error {
+	saved.Name = strings.TrimSpace(saved.Name)
+	if saved.Name == "" {
+		return fmt.Errorf("filter name is required")
+	}
+	return validateLogFilter(saved.Filter)
+}
+
+// validateLogFilter checks a log filter's status code range, status and period
+func validateLogFilter(filter models.LogFilter) error {
+	if filter.StatusCodeMin < 0 || filter.StatusCodeMax < 0 {
+		return fmt.Errorf("status codes cannot be negative")
+	}
+	if filter.StatusCodeMin > 0 && filter.StatusCodeMax > 0 && filter.StatusCodeMin > filter.StatusCodeMax {
+		return fmt.Errorf("the lowest status code cannot be above the highest")
+	}
+	switch filter.Status {
+	case "", models.ExecutionStatusSuccess, models.ExecutionStatusDegraded, models.ExecutionStatusFailure, models.ExecutionStatusSkipped:
+	default:
+		return fmt.Errorf("unsupported status: %s", filter.Status)
+	}
+	if filter.PeriodHours < 0 {
+		return fmt.Errorf("period cannot be negative")
+	}
+	return nil
+}
+
+// DiffExecutions compares the stored responses of two executions, structurally when both are JSON
+func (a *App) DiffExecutions(logID1, logID2 int) (models.ExecutionDiff, error) {
+	var result models.ExecutionDiff
+
+	left, err := a.db.GetExecutionLogByID(logID1)
+	if err != nil {
+		return result, err
+	}
+	right, err := a.db.GetExecutionLogByID(logID2)
+	if err != nil {
+		return result, err
+	}
+
+	result.LeftLog = left
+	result.RightLog = right
+	result.Changes, result.IsJSON = diff.Bodies(left.Response, right.Response)
+	result.Identical = len(result.Changes) == 0 && left.StatusCode == right.StatusCode
+	return result, nil
+}
+
+// maxContentChanges bounds how many content changes of an API are returned at once
+const maxContentChanges = 100
+
+// GetContentBaseline returns the response body an API that watches its content compares its checks against
+func (a *App) GetContentBaseline(apiID int) (models.ContentBaseline, error) {
+	baseline, exists, err := a.db.GetContentBaseline(apiID)
+	if err != nil {
+		return baseline, err
+	}
+	if !exists {
+		return baseline, fmt.Errorf("API %d has no content baseline yet: %w", apiID, sql.ErrNoRows)
+	}
+	return baseline, nil
+}
+
+// ResetContentBaseline drops an API's content baseline, so that its next check captures a new one without
+// counting as a change
+func (a *App) ResetContentBaseline(apiID int) error {
+	return a.db.DeleteContentBaseline(apiID)
+}
+
+// GetContentChanges returns the most recent changes of an API's response body, newest first, with the
+// differences between the bodies before and after each change
+func (a *App) GetContentChanges(apiID int, limit int) ([]models.ContentChange, error) {
+	if limit < 1 || limit > maxContentChanges {
+		return nil, fmt.Errorf("limit must be between 1 and %d", maxContentChanges)
+	}
+	changes, err := a.db.GetContentChanges(apiID, limit)
+	if err != nil {
+		return nil, err
+	}
+	for i := range changes {
+		changes[i].Changes, changes[i].IsJSON = diff.Bodies(changes[i].PreviousBody, changes[i].Body)
+	}
+	return changes, nil
+}
+
+// ExecuteAPIManually executes an API immediately (run now)
+func (a *App) ExecuteAPIManually(apiID int) error {
+	return a.scheduler.ExecuteAPIManually(apiID)
+}
+
+// TestAPI sends an API definition, saved or not, right away and returns the outcome, such as the status,
+// headers, body, timing and assertion results, without writing an execution log. It backs the editor's Send button.
+func (a *App) TestAPI(api models.API) (models.ExecutionLog, error) {
+	if err := validateStatusCodes(api); err != nil {
+		return models.ExecutionLog{}, err
+	}
+	if err := validateAPIProxy(&api); err != nil {
+		return models.ExecutionLog{}, err
+	}
+	if err := validateRedirectPolicy(&api); err != nil {
+		return models.ExecutionLog{}, err
+	}
+	if err := validateDNSServer(&api); err != nil {
+		return models.ExecutionLog{}, err
+	}
+	if err := validateLatencyThresholds(api); err != nil {
+		return models.ExecutionLog{}, err
+	}
+	if err := validateBody(api); err != nil {
+		return models.ExecutionLog{}, err
+	}
+	if err := validateCheckType(api); err != nil {
+		return models.ExecutionLog{}, err
+	}
+	return a.scheduler.TestAPI(api), nil
+}
+
+// ExecuteAPIsManually runs several APIs right away through the scheduler's concurrency limits and returns
+// the batch tracking them, whose progress is sent as batch:progress events
+func (a *App) ExecuteAPIsManually(apiIDs []int) (models.ExecutionBatch, error) {
+	return a.scheduler.ExecuteAPIsManually(apiIDs)
+}
+
+// GetExecutionBatch returns the progress and results of a batch of manual runs
+func (a *App) GetExecutionBatch(batchID int) (models.ExecutionBatch, error) {
+	return a.scheduler.ExecutionBatch(batchID)
+}
+
+// GetRunningExecutions returns the checks in flight, whether scheduled, manual or part of a collection run
+func (a *App) GetRunningExecutions() []models.RunningExecution {
+	return a.scheduler.RunningExecutions()
+}
+
+// CancelExecution aborts a check in flight, such as a hung request, logging it as a failure
+func (a *App) CancelExecution(executionID int) error {
+	return a.scheduler.CancelExecution(executionID)
+}
+
+// GetPendingRetries returns the failed checks waiting to retry, with the attempt they wait to make and when
+func (a *App) GetPendingRetries() []models.PendingRetry {
+	return a.scheduler.PendingRetries()
+}
+
+// RetryNow makes a check waiting to retry do so right away instead of waiting out its delay
+func (a *App) RetryNow(executionID int) error {
+	return a.scheduler.RetryNow(executionID)
+}
+
+// CancelRetry makes a check waiting to retry give up its remaining retries, logging its last failure
+func (a *App) CancelRetry(executionID int) error {
+	return a.scheduler.CancelRetry(executionID)
+}
+
+// Notification methods
+
+// GetAllNotificationChannels returns all notification channels
+func (a *App) GetAllNotificationChannels() ([]models.NotificationChannel, error) {
+	return a.db.GetAllNotificationChannels()
+}
+
+// CreateNotificationChannel creates a new notification channel
+func (a *App) CreateNotificationChannel(channel models.NotificationChannel) (models.NotificationChannel, error) {
+	if _, err := notify.ParseChannelConfig(channel); err != nil {
+		return channel, err
+	}
+	return a.db.CreateNotificationChannel(channel)
+}
+
+// UpdateNotificationChannel updates an existing notification channel
+func (a *App) UpdateNotificationChannel(channel models.NotificationChannel) (models.NotificationChannel, error) {
+	if _, err := notify.ParseChannelConfig(channel); err != nil {
+		return channel, err
+	}
+	return a.db.UpdateNotificationChannel(channel)
+}
+
+// DeleteNotificationChannel deletes a notification channel and its alert rules
+func (a *App) DeleteNotificationChannel(id int) error {
+	return a.db.DeleteNotificationChannel(id)
+}
+
+// TestNotificationChannel sends a sample alert through a notification channel and returns whether it was delivered.
+// The error is only set when the channel can't be loaded; delivery failures are reported in the result.
+func (a *App) TestNotificationChannel(id int) (models.ChannelTestResult, error) {
+	channel, err := a.db.GetNotificationChannelByID(id)
+	if err != nil {
+		return models.ChannelTestResult{}, err
+	}
+	return a.notifier.Test(channel), nil
+}
+
+// GetAllAlertRules returns all alert rules
+func (a *App) GetAllAlertRules() ([]models.AlertRule, error) {
+	return a.db.GetAllAlertRules()
+}
+
+// GetAlertRulesByScheduleID returns the alert rules of a schedule
+func (a *App) GetAlertRulesByScheduleID(scheduleID int) ([]models.AlertRule, error) {
+	return a.db.GetAlertRulesByScheduleID(scheduleID)
+}
+
+// CreateAlertRule creates a new alert rule
+func (a *App) CreateAlertRule(rule models.AlertRule) (models.AlertRule, error) {
+	if err := a.validateAlertRule(rule); err != nil {
+		return rule, err
+	}
+	return a.db.CreateAlertRule(rule)
+}
+
+// UpdateAlertRule updates an existing alert rule
+func (a *App) UpdateAlertRule(rule models.AlertRule) (models.AlertRule, error) {
+	if err := a.validateAlertRule(rule); err != nil {
+		return rule, err
+	}
+	return a.db.UpdateAlertRule(rule)
+}
+
+// validateAlertRule checks that an alert rule fires after at least one failure, watches a schedule of an API
+// that isn't in the trash, and notifies a channel or on-call rotation that exists
+func (a *App) validateAlertRule(rule models.AlertRule) error {
+	if rule.FailureThreshold < 1 {
+		return fmt.Errorf("failure threshold must be at least 1")
+	}
+
+	schedule, err := a.db.GetScheduleByID(rule.ScheduleID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("schedule %d does not exist", rule.ScheduleID)
+	}
+	if err != nil {
+		return err
+	}
+	api, err := a.db.GetAPIByID(schedule.APIID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("API %d of schedule %d does not exist", schedule.APIID, schedule.ID)
+	}
+	if err != nil {
+		return err
+	}
+	if api.DeletedAt != nil {
+		return fmt.Errorf("API %s is in the trash", api.Name)
+	}
+
+	if rule.RotationID != 0 {
+		if _, err := a.db.GetOnCallRotationByID(rule.RotationID); errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("on-call rotation %d does not exist", rule.RotationID)
+		} else if err != nil {
+			return err
+		}
+		return nil
+	}
+	if _, err := a.db.GetNotificationChannelByID(rule.ChannelID); errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("notification channel %d does not exist", rule.ChannelID)
+	} else if err != nil {
+		return err
+	}
+	return nil
+}
+
+// DeleteAlertRule deletes an alert rule by ID
+func (a *App) DeleteAlertRule(id int) error {
+	return a.db.DeleteAlertRule(id)
+}
+
+// GetPrometheusRules renders the active alert rules as a Prometheus rule file
+func (a *App) GetPrometheusRules() (string, error) {
+	return promrules.Generate(a.db, time.Now())
+}
+
+// ExportPrometheusRules writes the active alert rules to a Prometheus rule file, for Alertmanager to take over alerting
+func (a *App) ExportPrometheusRules(path string) error {
+	if strings.TrimSpace(path) == "" {
+		return fmt.Errorf("rule file path is required")
+	}
+	rules, err := a.GetPrometheusRules()
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, []byte(rules), 0644); err != nil {
+		return fmt.Errorf("failed to write rule file: %w", err)
+	}
+	return nil
+}
+
+// Proxy methods
+
+// GetGlobalProxy returns the proxy used by APIs that don't set their own
+func (a *App) GetGlobalProxy() (models.ProxyConfig, error) {
+	return a.db.GetGlobalProxy()
+}
+
+// SaveGlobalProxy saves the proxy used by APIs that don't set their own; an empty URL disables it
+func (a *App) SaveGlobalProxy(proxy models.ProxyConfig) error {
+	proxy.URL = strings.TrimSpace(proxy.URL)
+	if proxy.URL != "" {
+		if _, err := scheduler.ParseProxyURL(proxy); err != nil {
+			return err
+		}
+	}
+	return a.db.SaveGlobalProxy(proxy)
+}
+
+// Retention methods
+
+// GetRetentionPolicy returns the execution log retention policy
+func (a *App) GetRetentionPolicy() (models.RetentionPolicy, error) {
+	return a.db.GetRetentionPolicy()
+}
+
+// SaveRetentionPolicy saves the execution log retention policy
+func (a *App) SaveRetentionPolicy(policy models.RetentionPolicy) error {
+	if policy.MaxAgeDays < 0 || policy.MaxRowsPerAPI < 0 {
+		return fmt.Errorf("retention limits cannot be negative")
+	}
+	return a.db.SaveRetentionPolicy(policy)
+}
+
+// PruneExecutionLogs deletes the execution logs outside the retention policy and returns how many were deleted
+func (a *App) PruneExecutionLogs() (int64, error) {
+	return a.db.PruneExecutionLogs()
+}
+
+// GetWorkspaceReport summarizes what the workspace holds and where its storage goes, such as the largest
+// responses, the noisiest APIs and orphaned records, with the cleanups worth running
+func (a *App) GetWorkspaceReport() (models.WorkspaceReport, error) {
+	report, err := a.db.GetWorkspaceReport()
+	if err != nil {
+		return report, err
+	}
+	report.Counts.ActiveJobs = a.scheduler.ActiveJobs()
+	return report, nil
+}
+
+// RunCleanupAction runs one of the cleanups a workspace report suggests and returns the report afterwards
+func (a *App) RunCleanupAction(action string) (models.WorkspaceReport, error) {
+	var err error
+	switch action {
+	case models.CleanupEmptyTrash:
+		err = a.EmptyTrash()
+	case models.CleanupPruneLogs:
+		_, err = a.PruneExecutionLogs()
+	case models.CleanupDeleteOrphans:
+		_, err = a.db.DeleteOrphanedRecords()
+	case models.CleanupVacuum:
+		err = a.db.Vacuum()
+	default:
+		return models.WorkspaceReport{}, fmt.Errorf("unknown cleanup action %q", action)
+	}
+	if err != nil {
+		return models.WorkspaceReport{}, err
+	}
+	return a.GetWorkspaceReport()
+}
+
+// Incident methods
+
+// GetOpenIncidents returns every unresolved incident
+func (a *App) GetOpenIncidents() ([]models.Incident, error) {
+	return a.db.GetOpenIncidents()
+}
+
+// GetIncidentsByAPIID returns the incidents of an API, newest first
+func (a *App) GetIncidentsByAPIID(apiID int) ([]models.Incident, error) {
+	return a.db.GetIncidentsByAPIID(apiID)
+}
+
+// GetIncidentHistory returns the incidents opened over the last given hours, newest first, for one API or every API when apiID is 0
+func (a *App) GetIncidentHistory(apiID int, hours int) ([]models.Incident, error) {
+	return a.db.GetIncidentHistory(apiID, time.Now().Add(-time.Duration(hours)*time.Hour))
+}
+
+// GetIncidentStats summarizes the incidents opened over the last given hours, such as the mean time to resolve them,
+// for one API or every API when apiID is 0
+func (a *App) GetIncidentStats(apiID int, hours int) (models.IncidentStats, error) {
+	return a.db.GetIncidentStats(apiID, time.Now().Add(-time.Duration(hours)*time.Hour))
+}
+
+// GetIncidentPolicy returns the policy deciding when an API's failures open an incident
+func (a *App) GetIncidentPolicy() (models.IncidentPolicy, error) {
+	return a.db.GetIncidentPolicy()
+}
+
+// SaveIncidentPolicy saves the policy deciding when an API's failures open an incident
+func (a *App) SaveIncidentPolicy(policy models.IncidentPolicy) error {
+	if policy.FailureThreshold < 0 {
+		return fmt.Errorf("incident failure threshold cannot be negative")
+	}
+	return a.db.SaveIncidentPolicy(policy)
+}
+
+// GetIncidentEvents returns the history of an incident
+func (a *App) GetIncidentEvents(incidentID int) ([]models.IncidentEvent, error) {
+	return a.db.GetIncidentEvents(incidentID)
+}
+
+// AcknowledgeIncident acknowledges an open incident, suppressing further failure notifications until it resolves
+func (a *App) AcknowledgeIncident(incidentID int, note string) error {
+	return a.db.AcknowledgeIncident(incidentID, note)
+}
+
+// AnnotateExecution attaches a note to an execution log, shown with it in the execution history
+func (a *App) AnnotateExecution(logID int, note string) (models.Annotation, error) {
+	note = strings.TrimSpace(note)
+	if note == "" {
+		return models.Annotation{}, fmt.Errorf("annotation note is required")
+	}
+	if _, err := a.db.GetExecutionLogByID(logID); err != nil {
+		return models.Annotation{}, err
+	}
+	return a.db.CreateAnnotation(models.Annotation{ExecutionLogID: logID, Note: note})
+}
+
+// AnnotateIncident attaches a note to an incident, shown with it in the incident history
+func (a *App) AnnotateIncident(incidentID int, note string) (models.Annotation, error) {
+	note = strings.TrimSpace(note)
+	if note == "" {
+		return models.Annotation{}, fmt.Errorf("annotation note is required")
+	}
+	if _, err := a.db.GetIncidentByID(incidentID); err != nil {
+		return models.Annotation{}, err
+	}
+	return a.db.CreateAnnotation(models.Annotation{IncidentID: incidentID, Note: note})
+}
+
+// UpdateAnnotation changes the note of an annotation
+func (a *App) UpdateAnnotation(id int, note string) (models.Annotation, error) {
+	note = strings.TrimSpace(note)
+	if note == "" {
+		return models.Annotation{}, fmt.Errorf("annotation note is required")
+	}
+	return a.db.UpdateAnnotationNote(id, note)
+}
+
+// DeleteAnnotation deletes an annotation by ID
+func (a *App) DeleteAnnotation(id int) error {
+	return a.db.DeleteAnnotation(id)
+}
+
+// SnoozeAlerts suppresses all notifications for an API for the given number of hours
+func (a *App) SnoozeAlerts(apiID int, hours int) error {
+	if hours <= 0 {
+		return fmt.Errorf("snooze duration must be at least one hour")
+	}
+	return a.db.SnoozeAlerts(apiID, time.Now().Add(time.Duration(hours)*time.Hour))
+}
+
+// UnsnoozeAlerts ends the snooze of an API
+func (a *App) UnsnoozeAlerts(apiID int) error {
+	return a.db.ClearAlertSnooze(apiID)
+}
+
+// GetAlertSnoozedUntil returns the time an API's alerts are snoozed until (zero when not snoozed)
+func (a *App) GetAlertSnoozedUntil(apiID int) (time.Time, error) {
+	return a.db.GetAlertSnoozedUntil(apiID)
+}
+
+// Maintenance window methods
+
+// GetAllMaintenanceWindows returns every maintenance window
+func (a *App) GetAllMaintenanceWindows() ([]models.MaintenanceWindow, error) {
+	return a.db.GetAllMaintenanceWindows()
+}
+
+// CreateMaintenanceWindow creates a maintenance window, during which the scheduled checks it covers are skipped
+func (a *App) CreateMaintenanceWindow(window models.MaintenanceWindow) (models.MaintenanceWindow, error) {
+	if err := validateMaintenanceWindow(&window); err != nil {
+		return window, err
+	}
+	return a.db.CreateMaintenanceWindow(window)
+}
+
+// UpdateMaintenanceWindow updates an existing maintenance window
+func (a *App) UpdateMaintenanceWindow(window models.MaintenanceWindow) (models.MaintenanceWindow, error) {
+	if err := validateMaintenanceWindow(&window); err != nil {
+		return window, err
+	}
+	return a.db.UpdateMaintenanceWindow(window)
+}
+
+// DeleteMaintenanceWindow deletes a maintenance window by ID
+func (a *App) DeleteMaintenanceWindow(id int) error {
+	return a.db.DeleteMaintenanceWindow(id)
+}
+
+// validateMaintenanceWindow checks a maintenance window's scope and timing, clearing the target IDs its scope doesn't use
+func validateMaintenanceWindow(window *models.MaintenanceWindow) error {
+	window.Name = strings.TrimSpace(window.Name)
+	if window.Name == "" {
+		return fmt.Errorf("maintenance window name is required")
+	}
+
+	switch window.Scope {
+	case models.MaintenanceScopeGlobal:
+		window.APIID, window.CollectionID = 0, 0
+	case models.MaintenanceScopeAPI:
+		if window.APIID == 0 {
+			return fmt.Errorf("an API maintenance window needs an API")
+		}
+		window.CollectionID = 0
+	case models.MaintenanceScopeCollection:
+		if window.CollectionID == 0 {
+			return fmt.Errorf("a collection maintenance window needs a collection")
+		}
+		window.APIID = 0
+	default:
+		return fmt.Errorf("unsupported maintenance window scope: %s", window.Scope)
+	}
+
+	if window.Recurrence == "" {
+		if window.StartsAt.IsZero() || window.EndsAt.IsZero() {
+			return fmt.Errorf("a one-off maintenance window needs a start and an end")
+		}
+		if !window.EndsAt.After(window.StartsAt) {
+			return fmt.Errorf("a maintenance window must end after it starts")
+		}
+		window.DurationMinutes, window.Timezone = 0, ""
+		return nil
+	}
+
+	if window.DurationMinutes < 1 {
+		return fmt.Errorf("a recurring maintenance window must last at least a minute")
+	}
+	if !window.StartsAt.IsZero() && !window.EndsAt.IsZero() && !window.EndsAt.After(window.StartsAt) {
+		return fmt.Errorf("a maintenance window must end after it starts")
+	}
+	if _, err := scheduler.ParseCronIn(window.Recurrence, window.Timezone); err != nil {
+		return fmt.Errorf("invalid recurrence: %w", err)
+	}
+	return nil
+}
+
+// Expected failure window methods
+
+// GetExpectedFailureWindows returns the time ranges of an API marked as expected to fail
+func (a *App) GetExpectedFailureWindows(apiID int) ([]models.ExpectedFailureWindow, error) {
+	return a.db.GetExpectedFailureWindowsByAPIID(apiID)
+}
+
+// CreateExpectedFailureWindow marks a time range of an API as expected to fail, leaving its executions out of uptime and latency statistics
+func (a *App) CreateExpectedFailureWindow(window models.ExpectedFailureWindow) (models.ExpectedFailureWindow, error) {
+	if err := a.validateExpectedFailureWindow(window); err != nil {
+		return window, err
+	}
+	return a.db.CreateExpectedFailureWindow(window)
+}
+
+// UpdateExpectedFailureWindow updates an existing expected failure window
+func (a *App) UpdateExpectedFailureWindow(window models.ExpectedFailureWindow) (models.ExpectedFailureWindow, error) {
+	if err := a.validateExpectedFailureWindow(window); err != nil {
+		return window, err
+	}
+	return a.db.UpdateExpectedFailureWindow(window)
+}
+
+// DeleteExpectedFailureWindow deletes an expected failure window, counting its executions toward statistics again
+func (a *App) DeleteExpectedFailureWindow(id int) error {
+	return a.db.DeleteExpectedFailureWindow(id)
+}
+
+// validateExpectedFailureWindow checks that an expected failure window belongs to an existing API and has a valid range
+func (a *App) validateExpectedFailureWindow(window models.ExpectedFailureWindow) error {
+	if _, err := a.db.GetAPIByID(window.APIID); err != nil {
+		return err
+	}
+	if window.StartsAt.IsZero() || window.EndsAt.IsZero() {
+		return fmt.Errorf("an expected failure window needs a start and an end")
+	}
+	if !window.EndsAt.After(window.StartsAt) {
+		return fmt.Errorf("an expected failure window must end after it starts")
+	}
+	return nil
+}
+
+// Dashboard methods
+
+// GetAllDashboards returns every saved dashboard in display order
+func (a *App) GetAllDashboards() ([]models.Dashboard, error) {
+	return a.db.GetAllDashboards()
+}
+
+// GetDashboardByID returns a dashboard by ID
+func (a *App) GetDashboardByID(id int) (models.Dashboard, error) {
+	return a.db.GetDashboardByID(id)
+}
+
+// CreateDashboard saves a new dashboard layout
+func (a *App) CreateDashboard(dashboard models.Dashboard) (models.Dashboard, error) {
+	if err := validateDashboard(&dashboard); err != nil {
+		return dashboard, err
+	}
+	return a.db.CreateDashboard(dashboard)
+}
+
+// UpdateDashboard updates an existing dashboard
+func (a *App) UpdateDashboard(dashboard models.Dashboard) (models.Dashboard, error) {
+	if err := validateDashboard(&dashboard); err != nil {
+		return dashboard, err
+	}
+	return a.db.UpdateDashboard(dashboard)
+}
+
+// DeleteDashboard deletes a dashboard by ID
+func (a *App) DeleteDashboard(id int) error {
+	return a.db.DeleteDashboard(id)
+}
+
+// validateDashboard checks a dashboard has a name and that its layout, when set, is valid JSON
+func validateDashboard(dashboard *models.Dashboard) error {
+	dashboard.Name = strings.TrimSpace(dashboard.Name)
+	if dashboard.Name == "" {
+		return fmt.Errorf("dashboard name is required")
+	}
+	if dashboard.Layout != "" && !json.Valid([]byte(dashboard.Layout)) {
+		return fmt.Errorf("dashboard layout must be valid JSON")
+	}
+	return nil
+}
+
+// Webhook trigger methods
+
+// GetWebhookTriggers returns every webhook trigger ordered by name
+func (a *App) GetWebhookTriggers() ([]models.WebhookTrigger, error) {
+	return a.db.GetAllWebhookTriggers()
+}
+
+// CreateWebhookTrigger creates a webhook trigger, generating its secret unless one is given
+func (a *App) CreateWebhookTrigger(trigger models.WebhookTrigger) (models.WebhookTrigger, error) {
+	if err := a.validateWebhookTrigger(&trigger); err != nil {
+		return trigger, err
+	}
+	if trigger.Secret == "" {
+		secret, err := webhooks.GenerateSecret()
+		if err != nil {
+			return trigger, err
+		}
+		trigger.Secret = secret
+	}
+	return a.db.CreateWebhookTrigger(trigger)
+}
+
+// UpdateWebhookTrigger updates a webhook trigger, keeping its secret when none is given
+func (a *App) UpdateWebhookTrigger(trigger models.WebhookTrigger) (models.WebhookTrigger, error) {
+	if err := a.validateWebhookTrigger(&trigger); err != nil {
+		return trigger, err
+	}
+	if trigger.Secret == "" {
+		current, err := a.db.GetWebhookTriggerByID(trigger.ID)
+		if err != nil {
+			return trigger, err
 		}
-	} else if isCurrentlyActive && schedule.IsActive {
-		// Update the job by stopping and restarting
-		if err := a.scheduler.StopJob(schedule.ID); err != nil {
-			log.Printf("Failed to stop existing job for schedule ID %d: %v", schedule.ID, err)
+		trigger.Secret = current.Secret
+	}
+	return a.db.UpdateWebhookTrigger(trigger)
+}
+
+// DeleteWebhookTrigger deletes a webhook trigger by ID
+func (a *App) DeleteWebhookTrigger(id int) error {
+	return a.db.DeleteWebhookTrigger(id)
+}
+
+// validateWebhookTrigger checks a webhook trigger has a name, a signature scheme and exactly one existing target
+func (a *App) validateWebhookTrigger(trigger *models.WebhookTrigger) error {
+	trigger.Name = strings.TrimSpace(trigger.Name)
+	if trigger.Name == "" {
+		return fmt.Errorf("trigger name is required")
+	}
+	switch trigger.SignatureScheme {
+	case models.WebhookSignatureGitHub, models.WebhookSignatureStripe, models.WebhookSignatureURL:
+	default:
+		return fmt.Errorf("unsupported signature scheme: %s", trigger.SignatureScheme)
+	}
+	if (trigger.APIID == 0) == (trigger.CollectionID == 0) {
+		return fmt.Errorf("a trigger runs either an API or a collection")
+	}
+	if trigger.APIID != 0 {
+		if _, err := a.db.GetAPIByID(trigger.APIID); err != nil {
+			return err
 		}
-		if err := a.scheduler.ScheduleJob(schedule); err != nil {
-			return fmt.Errorf("schedule updated but failed to restart job: %w", err)
+	} else if _, err := a.db.GetCollectionByID(trigger.CollectionID); err != nil {
+		return err
+	}
+	return nil
+}
+
+// fireWebhookTrigger verifies an inbound request to a webhook trigger, posted to its secret URL when urlSecret
+// is set, and starts the run it triggers. Inactive triggers are reported as missing, so they can't be told apart
+// from unknown ones.
+func (a *App) fireWebhookTrigger(id int, urlSecret string, header http.Header, body []byte) error {
+	trigger, err := a.db.GetWebhookTriggerByID(id)
+	if err != nil {
+		return err
+	}
+	if !trigger.IsActive {
+		return sql.ErrNoRows
+	}
+	if err := webhooks.Verify(trigger, urlSecret, header, body, time.Now()); err != nil {
+		return err
+	}
+	if err := a.db.MarkWebhookTriggered(trigger.ID, time.Now()); err != nil {
+		log.Printf("Failed to record webhook trigger %d firing: %v", trigger.ID, err)
+	}
+
+	if trigger.APIID != 0 {
+		return a.scheduler.ExecuteAPIManually(trigger.APIID)
+	}
+	go func() {
+		if _, err := a.scheduler.RunCollection(trigger.CollectionID); err != nil {
+			log.Printf("Webhook trigger %d failed to run collection %d: %v", trigger.ID, trigger.CollectionID, err)
 		}
+	}()
+	return nil
+}
+
+// Event webhook methods
+
+// GetEventWebhooks returns every event webhook ordered by name
+func (a *App) GetEventWebhooks() ([]models.EventWebhook, error) {
+	return a.db.GetAllEventWebhooks()
+}
+
+// CreateEventWebhook creates an event webhook
+func (a *App) CreateEventWebhook(webhook models.EventWebhook) (models.EventWebhook, error) {
+	if err := validateEventWebhook(&webhook); err != nil {
+		return webhook, err
+	}
+	return a.db.CreateEventWebhook(webhook)
+}
+
+// UpdateEventWebhook updates an existing event webhook
+func (a *App) UpdateEventWebhook(webhook models.EventWebhook) (models.EventWebhook, error) {
+	if err := validateEventWebhook(&webhook); err != nil {
+		return webhook, err
 	}
+	return a.db.UpdateEventWebhook(webhook)
+}
+
+// DeleteEventWebhook deletes an event webhook by ID
+func (a *App) DeleteEventWebhook(id int) error {
+	return a.db.DeleteEventWebhook(id)
+}
 
+// validateEventWebhook checks an event webhook has a name, an http(s) URL and rules that can be evaluated
+func validateEventWebhook(webhook *models.EventWebhook) error {
+	webhook.Name = strings.TrimSpace(webhook.Name)
+	if webhook.Name == "" {
+		return fmt.Errorf("event webhook name is required")
+	}
+	webhook.URL = strings.TrimSpace(webhook.URL)
+	parsed, err := url.Parse(webhook.URL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fmt.Errorf("event webhook URL must be an http or https URL")
+	}
+	for _, rule := range webhook.Rules {
+		if err := webhooks.ValidateRule(rule); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
-// DeleteSchedule deletes a schedule by ID
-func (a *App) DeleteSchedule(id int) error {
-	// Stop the job first
-	if err := a.scheduler.StopJob(id); err != nil {
-		log.Printf("Failed to stop job for schedule ID %d: %v", id, err)
+// Status page methods
+
+// GetStatusPages returns every status page ordered by name
+func (a *App) GetStatusPages() ([]models.StatusPage, error) {
+	return a.db.GetAllStatusPages()
+}
+
+// CreateStatusPage creates a status page
+func (a *App) CreateStatusPage(page models.StatusPage) (models.StatusPage, error) {
+	if err := a.validateStatusPage(&page); err != nil {
+		return page, err
 	}
+	return a.db.CreateStatusPage(page)
+}
 
-	// Delete from database
-	return a.db.DeleteSchedule(id)
+// UpdateStatusPage updates an existing status page
+func (a *App) UpdateStatusPage(page models.StatusPage) (models.StatusPage, error) {
+	if err := a.validateStatusPage(&page); err != nil {
+		return page, err
+	}
+	return a.db.UpdateStatusPage(page)
 }
 
-// ToggleSchedule toggles the active state of a schedule
-func (a *App) ToggleSchedule(id int, isActive bool) error {
-	schedule, err := a.db.GetScheduleByID(id)
+// DeleteStatusPage deletes a status page by ID
+func (a *App) DeleteStatusPage(id int) error {
+	return a.db.DeleteStatusPage(id)
+}
+
+// GenerateStatusPage returns the current content of a status page
+func (a *App) GenerateStatusPage(id int) (models.StatusPageReport, error) {
+	page, err := a.db.GetStatusPageByID(id)
 	if err != nil {
-		return fmt.Errorf("failed to get schedule: %w", err)
+		return models.StatusPageReport{}, err
 	}
+	return statuspage.Build(a.db, page, time.Now())
+}
 
-	schedule.IsActive = isActive
-	return a.UpdateSchedule(schedule)
+// ExportStatusPage writes a status page as index.html and status.json to its output directory and returns the directory
+func (a *App) ExportStatusPage(id int) (string, error) {
+	page, err := a.db.GetStatusPageByID(id)
+	if err != nil {
+		return "", err
+	}
+	if page.OutputDir == "" {
+		return "", fmt.Errorf("status page %s has no output directory", page.Name)
+	}
+	report, err := statuspage.Build(a.db, page, time.Now())
+	if err != nil {
+		return "", err
+	}
+	return page.OutputDir, statuspage.Write(report, page.OutputDir)
 }
 
-// CancelSchedule cancels a schedule permanently
-func (a *App) CancelSchedule(id int) error {
-	// First get the schedule
-	schedule, err := a.db.GetScheduleByID(id)
+// publicStatusPage returns the content of the status page served at a slug. Pages that aren't public are
+// reported as missing, so they can't be told apart from unknown ones.
+func (a *App) publicStatusPage(slug string) (models.StatusPageReport, error) {
+	page, err := a.db.GetStatusPageBySlug(slug)
 	if err != nil {
-		return fmt.Errorf("failed to get schedule: %w", err)
+		return models.StatusPageReport{}, err
+	}
+	if !page.Public {
+		return models.StatusPageReport{}, sql.ErrNoRows
 	}
+	return statuspage.Build(a.db, page, time.Now())
+}
 
-	// Stop the job
-	if err := a.scheduler.StopJob(id); err != nil {
-		log.Printf("Failed to stop job for schedule ID %d: %v", id, err)
+// validateStatusPage checks a status page has a name, a URL-safe slug and existing APIs
+func (a *App) validateStatusPage(page *models.StatusPage) error {
+	page.Name = strings.TrimSpace(page.Name)
+	if page.Name == "" {
+		return fmt.Errorf("status page name is required")
 	}
+	page.Slug = strings.ToLower(strings.TrimSpace(page.Slug))
+	if page.Slug == "" {
+		return fmt.Errorf("status page slug is required")
+	}
+	for _, c := range page.Slug {
+		if (c < 'a' || c > 'z') && (c < '0' || c > '9') && c != '-' {
+			return fmt.Errorf("status page slug may only contain letters, digits and dashes")
+		}
+	}
+	if len(page.APIIDs) == 0 {
+		return fmt.Errorf("a status page needs at least one API")
+	}
+	for _, apiID := range page.APIIDs {
+		if _, err := a.db.GetAPIByID(apiID); err != nil {
+			return err
+		}
+	}
+	if page.WindowDays < 0 {
+		return fmt.Errorf("status page window cannot be negative")
+	}
+	page.Title = strings.TrimSpace(page.Title)
+	page.OutputDir = strings.TrimSpace(page.OutputDir)
+	return nil
+}
 
-	// Update to inactive status
-	schedule.IsActive = false
-	return a.db.UpdateSchedule(schedule)
+// GetTags returns every tag ordered by name
+func (a *App) GetTags() ([]models.Tag, error) {
+	return a.db.GetAllTags()
 }
 
-// Logs methods
+// CreateTag creates a tag
+func (a *App) CreateTag(tag models.Tag) (models.Tag, error) {
+	if err := validateTag(&tag); err != nil {
+		return tag, err
+	}
+	return a.db.CreateTag(tag)
+}
 
-// GetExecutionLogsByAPIID returns execution logs for an API
-func (a *App) GetExecutionLogsByAPIID(apiID int, limit int) ([]models.ExecutionLog, error) {
-	return a.db.GetExecutionLogsByAPIID(apiID, limit)
+// UpdateTag renames or recolors an existing tag
+func (a *App) UpdateTag(tag models.Tag) (models.Tag, error) {
+	if err := validateTag(&tag); err != nil {
+		return tag, err
+	}
+	return a.db.UpdateTag(tag)
 }
 
-// GetAllExecutionLogs returns all execution logs with pagination
-func (a *App) GetAllExecutionLogs(page, pageSize int) ([]models.ExecutionLog, error) {
-	return a.db.GetAllExecutionLogs(page, pageSize)
+// DeleteTag deletes a tag and removes it from every API and schedule
+func (a *App) DeleteTag(id int) error {
+	return a.db.DeleteTag(id)
 }
 
-// GetRecentExecutions returns the most recent execution logs
-func (a *App) GetRecentExecutions(limit int) ([]models.ExecutionLog, error) {
-	return a.db.GetRecentExecutions(limit)
+// GetAPITags returns the tags of an API
+func (a *App) GetAPITags(apiID int) ([]models.Tag, error) {
+	return a.db.GetTagsByAPIID(apiID)
 }
 
-// ExecuteAPIManually executes an API immediately (run now)
-func (a *App) ExecuteAPIManually(apiID int) error {
-	return a.scheduler.ExecuteAPIManually(apiID)
+// SetAPITags replaces the tags of an API
+func (a *App) SetAPITags(apiID int, tagIDs []int) error {
+	if _, err := a.db.GetAPIByID(apiID); err != nil {
+		return err
+	}
+	if err := a.checkTags(tagIDs); err != nil {
+		return err
+	}
+	return a.db.SetAPITags(apiID, tagIDs)
+}
+
+// GetScheduleTags returns the tags of a schedule
+func (a *App) GetScheduleTags(scheduleID int) ([]models.Tag, error) {
+	return a.db.GetTagsByScheduleID(scheduleID)
+}
+
+// SetScheduleTags replaces the tags of a schedule
+func (a *App) SetScheduleTags(scheduleID int, tagIDs []int) error {
+	if _, err := a.db.GetScheduleByID(scheduleID); err != nil {
+		return err
+	}
+	if err := a.checkTags(tagIDs); err != nil {
+		return err
+	}
+	return a.db.SetScheduleTags(scheduleID, tagIDs)
+}
+
+// GetAPIsByTag returns the APIs carrying a tag
+func (a *App) GetAPIsByTag(tagID int) ([]models.API, error) {
+	return a.db.GetAPIsByTag(tagID)
+}
+
+// GetSchedulesByTag returns the schedules carrying a tag
+func (a *App) GetSchedulesByTag(tagID int) ([]models.Schedule, error) {
+	return a.db.GetSchedulesByTag(tagID)
+}
+
+// GetExecutionLogsByTag returns the newest execution logs of the APIs and schedules carrying a tag
+func (a *App) GetExecutionLogsByTag(tagID int, limit int) ([]models.ExecutionLog, error) {
+	if limit <= 0 {
+		limit = 100
+	}
+	return a.db.GetExecutionLogsByTag(tagID, limit)
+}
+
+// validateTag checks a tag has a name and its color, if any, is a hex color
+func validateTag(tag *models.Tag) error {
+	tag.Name = strings.TrimSpace(tag.Name)
+	if tag.Name == "" {
+		return fmt.Errorf("tag name is required")
+	}
+	tag.Color = strings.TrimSpace(tag.Color)
+	if tag.Color == "" {
+		return nil
+	}
+	if len(tag.Color) != 7 || tag.Color[0] != '#' {
+		return fmt.Errorf("tag color must be a hex color like #3b82f6")
+	}
+	for _, c := range strings.ToLower(tag.Color[1:]) {
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return fmt.Errorf("tag color must be a hex color like #3b82f6")
+		}
+	}
+	return nil
+}
+
+// checkTags checks every tag exists
+func (a *App) checkTags(tagIDs []int) error {
+	for _, tagID := range tagIDs {
+		if _, err := a.db.GetTagByID(tagID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// REST API methods
+
+// GetRESTServerConfig returns the embedded REST API configuration
+func (a *App) GetRESTServerConfig() (models.RESTServerConfig, error) {
+	return a.db.GetRESTServerConfig()
+}
+
+// SaveRESTServerConfig saves the REST API configuration and starts or stops the server to match.
+// A token is generated when none is given so the API is never served unauthenticated by accident.
+func (a *App) SaveRESTServerConfig(config models.RESTServerConfig) (models.RESTServerConfig, error) {
+	if config.Port < 1 || config.Port > 65535 {
+		return config, fmt.Errorf("port must be between 1 and 65535")
+	}
+	if strings.TrimSpace(config.Host) == "" {
+		config.Host = "127.0.0.1"
+	}
+	if config.Token == "" {
+		token := make([]byte, 24)
+		if _, err := rand.Read(token); err != nil {
+			return config, fmt.Errorf("failed to generate token: %w", err)
+		}
+		config.Token = hex.EncodeToString(token)
+	}
+
+	if config.Enabled {
+		if err := a.startRESTServer(config.Host, config.Port, config.Token); err != nil {
+			return config, err
+		}
+	} else {
+		a.stopRESTServer()
+	}
+	return config, a.db.SaveRESTServerConfig(config)
+}
+
+// On-call methods
+
+// GetAllOnCallUsers returns all on-call users
+func (a *App) GetAllOnCallUsers() ([]models.OnCallUser, error) {
+	return a.db.GetAllOnCallUsers()
+}
+
+// CreateOnCallUser creates a new on-call user
+func (a *App) CreateOnCallUser(user models.OnCallUser) (models.OnCallUser, error) {
+	if _, err := a.db.GetNotificationChannelByID(user.ChannelID); err != nil {
+		return user, err
+	}
+	return a.db.CreateOnCallUser(user)
+}
+
+// UpdateOnCallUser updates an existing on-call user
+func (a *App) UpdateOnCallUser(user models.OnCallUser) (models.OnCallUser, error) {
+	if _, err := a.db.GetNotificationChannelByID(user.ChannelID); err != nil {
+		return user, err
+	}
+	return a.db.UpdateOnCallUser(user)
+}
+
+// DeleteOnCallUser deletes an on-call user and removes them from every rotation
+func (a *App) DeleteOnCallUser(id int) error {
+	return a.db.DeleteOnCallUser(id)
+}
+
+// GetAllOnCallRotations returns all on-call rotations
+func (a *App) GetAllOnCallRotations() ([]models.OnCallRotation, error) {
+	return a.db.GetAllOnCallRotations()
+}
+
+// CreateOnCallRotation creates a new weekly on-call rotation
+func (a *App) CreateOnCallRotation(rotation models.OnCallRotation) (models.OnCallRotation, error) {
+	if rotation.StartsAt.IsZero() {
+		rotation.StartsAt = time.Now()
+	}
+	return a.db.CreateOnCallRotation(rotation)
+}
+
+// UpdateOnCallRotation updates an existing on-call rotation
+func (a *App) UpdateOnCallRotation(rotation models.OnCallRotation) (models.OnCallRotation, error) {
+	return a.db.UpdateOnCallRotation(rotation)
+}
+
+// DeleteOnCallRotation deletes an on-call rotation and the alert rules routed through it
+func (a *App) DeleteOnCallRotation(id int) error {
+	return a.db.DeleteOnCallRotation(id)
+}
+
+// GetCurrentOnCall returns the user currently on call in a rotation
+func (a *App) GetCurrentOnCall(rotationID int) (models.OnCallUser, error) {
+	rotation, err := a.db.GetOnCallRotationByID(rotationID)
+	if err != nil {
+		return models.OnCallUser{}, err
+	}
+	userID, err := notify.CurrentOnCall(rotation, time.Now())
+	if err != nil {
+		return models.OnCallUser{}, err
+	}
+	return a.db.GetOnCallUserByID(userID)
+}
+
+// Sync methods
+
+// GetSyncConfig returns the workspace sync configuration
+func (a *App) GetSyncConfig() (models.SyncConfig, error) {
+	return a.db.GetSyncConfig()
+}
+
+// SaveSyncConfig saves the workspace sync configuration
+func (a *App) SaveSyncConfig(config models.SyncConfig) error {
+	return a.db.SaveSyncConfig(config)
+}
+
+// SyncWorkspace syncs APIs, collections and schedules with the configured remote
+func (a *App) SyncWorkspace() (models.SyncResult, error) {
+	result, changedSchedules, err := a.sync.Sync()
+	a.refreshJobs(changedSchedules)
+	return result, err
+}
+
+// PreviewSyncWorkspace returns the local changes SyncWorkspace would make without applying them or uploading anything
+func (a *App) PreviewSyncWorkspace() (models.ChangePlan, error) {
+	return a.sync.Preview()
+}
+
+// ExportWorkspace writes a backup of the environments, collections, APIs and schedules, and of every
+// execution log when includeLogs is set, to a single gzip-compressed JSON file
+func (a *App) ExportWorkspace(path string, includeLogs bool) error {
+	if strings.TrimSpace(path) == "" {
+		return fmt.Errorf("backup path is required")
+	}
+	workspace, err := a.backupWorkspace(includeLogs)
+	if err != nil {
+		return err
+	}
+	return backup.Write(workspace, path)
+}
+
+// backupWorkspace reads a backup of the workspace, with every execution log when includeLogs is set
+func (a *App) backupWorkspace(includeLogs bool) (*backup.Backup, error) {
+	return backup.Export(a.db, includeLogs)
+}
+
+// ImportWorkspace restores a backup written by ExportWorkspace, keeping the records that already exist,
+// and starts the jobs of the schedules it restored
+func (a *App) ImportWorkspace(path string) (models.BackupResult, error) {
+	workspace, err := backup.Read(path)
+	if err != nil {
+		return models.BackupResult{}, err
+	}
+	return a.restoreWorkspace(workspace)
+}
+
+// restoreWorkspace restores a backup and starts the jobs of the schedules it created
+func (a *App) restoreWorkspace(workspace *backup.Backup) (models.BackupResult, error) {
+	result, createdSchedules, err := backup.Restore(a.db, workspace)
+	a.refreshJobs(createdSchedules)
+	return result, err
+}
+
+// refreshJobs restarts the jobs of schedules changed outside of UpdateSchedule
+func (a *App) refreshJobs(scheduleIDs []int) {
+	for _, id := range scheduleIDs {
+		// The job may not be running, so a failed stop is expected
+		a.scheduler.StopJob(id)
+
+		schedule, err := a.db.GetScheduleByID(id)
+		if err != nil || !schedule.IsActive {
+			continue
+		}
+		if err := a.scheduler.ScheduleJob(schedule); err != nil {
+			log.Printf("Failed to start job for schedule ID %d: %v", id, err)
+		}
+	}
 }
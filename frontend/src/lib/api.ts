@@ -62,6 +62,10 @@ export const GetExecutionLogsByAPIID = async (apiId: number, limit: number): Pro
   return callBackend<ExecutionLog[]>('GetExecutionLogsByAPIID', [apiId, limit]);
 };
 
+export const GetLatestExecutionForAPIs = async (apiIds: number[]): Promise<Record<number, ExecutionLog>> => {
+  return callBackend<Record<number, ExecutionLog>>('GetLatestExecutionForAPIs', [apiIds]);
+};
+
 export const GetRecentExecutions = async (limit: number): Promise<ExecutionLog[]> => {
   return callBackend<ExecutionLog[]>('GetRecentExecutions', [limit]);
 }; 
\ No newline at end of file
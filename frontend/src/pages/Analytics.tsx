@@ -86,7 +86,7 @@ export function Analytics() {
           setAnalyticsData(apiAnalytics || emptyAnalytics);
           
           const counts = await window.go.main.App.GetExecutionStatusCounts(selectedApiId);
-          setStatusCounts(counts || { success: 0, redirect: 0, client_error: 0, server_error: 0, other: 0 });
+          setStatusCounts(counts || { success: 0, degraded: 0, redirect: 0, client_error: 0, server_error: 0, failure: 0, other: 0 });
         } else {
           setAnalyticsData(emptyAnalytics);
           setStatusCounts(null);
@@ -110,21 +110,25 @@ export function Analytics() {
     if (!statusCounts) return null;
     
     return {
-      labels: ['Success (2xx)', 'Redirect (3xx)', 'Client Error (4xx)', 'Server Error (5xx)', 'Other'],
+      labels: ['Success', 'Degraded', 'Redirect (3xx)', 'Client Error (4xx)', 'Server Error (5xx)', 'Failed (other codes)', 'No Status Code'],
       datasets: [
         {
           data: [
             statusCounts.success || 0,
+            statusCounts.degraded || 0,
             statusCounts.redirect || 0,
             statusCounts.client_error || 0,
             statusCounts.server_error || 0,
+            statusCounts.failure || 0,
             statusCounts.other || 0,
           ],
           backgroundColor: [
             theme.colors.green[6],
+            theme.colors.yellow[6],
             theme.colors.blue[6],
             theme.colors.orange[6],
             theme.colors.red[6],
+            theme.colors.grape[6],
             theme.colors.gray[6],
           ],
           borderColor: theme.colorScheme === 'dark' ? theme.colors.dark[6] : theme.white,
@@ -36,9 +36,11 @@ export interface BaseSchedule {
 // Analytics types
 export interface StatusCounts {
   success: number;
+  degraded: number;
   redirect: number;
   client_error: number;
   server_error: number;
+  failure: number;
   other: number;
 }
 
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"os/signal"
+	"strconv"
+	"syscall"
+)
+
+// runHeadless starts the database and scheduler without the desktop UI and blocks until
+// SIGINT or SIGTERM. When listenAddr is set, the REST API is served on it regardless of
+// whether it is enabled in the settings, using the configured token. An empty profile
+// opens the last used one.
+func runHeadless(listenAddr, profile string) error {
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	app := NewApp()
+	app.profile = profile
+	app.headless = true
+	app.startup(ctx)
+	defer app.shutdown(context.Background())
+
+	if listenAddr != "" {
+		host, port, err := localAddr(listenAddr)
+		if err != nil {
+			return err
+		}
+		config, err := app.db.GetRESTServerConfig()
+		if err != nil {
+			return err
+		}
+		if err := app.startRESTServer(host, port, config.Token); err != nil {
+			return err
+		}
+	}
+
+	log.Println("FlowPulse is running headless, press Ctrl+C to stop")
+	<-ctx.Done()
+	log.Println("Received shutdown signal")
+	return nil
+}
+
+// localAddr splits a listen address, binding addresses without a host, such as ":7070",
+// to the loopback interface so the API isn't exposed to the network by accident
+func localAddr(addr string) (string, int, error) {
+	host, rawPort, err := net.SplitHostPort(addr)
+	if err != nil {
+		return "", 0, fmt.Errorf("invalid listen address %q: %w", addr, err)
+	}
+	port, err := strconv.Atoi(rawPort)
+	if err != nil {
+		return "", 0, fmt.Errorf("invalid port in listen address %q", addr)
+	}
+	if host == "" {
+		host = "127.0.0.1"
+	}
+	return host, port, nil
+}
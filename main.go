@@ -2,7 +2,12 @@ package main
 
 import (
 	"embed"
+	"flag"
+	"io"
 	"log"
+	"os"
+
+	"flowpulse/pkg/database"
 
 	"github.com/wailsapp/wails/v2"
 	"github.com/wailsapp/wails/v2/pkg/options"
@@ -16,8 +21,31 @@ import (
 var assets embed.FS
 
 func main() {
+	// Run without the desktop UI when started with --headless, e.g. on a server or under systemd.
+	// Unknown arguments are left to Wails, which may receive platform-specific ones.
+	flags := flag.NewFlagSet("flowpulse", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	headless := flags.Bool("headless", false, "run the scheduler without the desktop UI")
+	listen := flags.String("listen", "", "address of the REST control API in headless mode, e.g. 127.0.0.1:7070")
+	profile := flags.String("profile", "", "profile to open instead of the last used one")
+	dataDir := flags.String("data-dir", "", "directory holding FlowPulse's data instead of ~/.flowpulse")
+	parseErr := flags.Parse(os.Args[1:])
+	if parseErr == nil && *dataDir != "" {
+		// Passed on through the environment, where database.AppDir looks for it
+		os.Setenv(database.DataDirEnv, *dataDir)
+	}
+	if parseErr == nil && *headless {
+		if err := runHeadless(*listen, *profile); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
 	// Create an instance of the app structure
 	app := NewApp()
+	if parseErr == nil {
+		app.profile = *profile
+	}
 
 	// Create application with options
 	err := wails.Run(&options.App{
@@ -0,0 +1,281 @@
+package assertions
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"flowpulse/pkg/graphql"
+	"flowpulse/pkg/jsonpath"
+	"flowpulse/pkg/models"
+	"flowpulse/pkg/openapi"
+)
+
+// Response is the part of an execution result that assertions can inspect
+type Response struct {
+	StatusCode int
+	Headers    http.Header
+	Body       string
+	DurationMs int64
+}
+
+// Validate checks that an assertion definition can be evaluated
+func Validate(assertion models.Assertion) error {
+	switch assertion.Type {
+	case models.AssertionStatusCode:
+		_, err := ParseStatusCodes(assertion.Expected)
+		return err
+	case models.AssertionJSONPath:
+		if _, err := jsonpath.Compile(assertion.Target); err != nil {
+			return err
+		}
+		return validateOperator(assertion)
+	case models.AssertionBodyRegex:
+		_, err := regexp.Compile(assertion.Expected)
+		return err
+	case models.AssertionHeader:
+		if assertion.Target == "" {
+			return fmt.Errorf("header assertion requires a header name")
+		}
+		if assertion.Operator == "" || assertion.Operator == models.OperatorExists {
+			return nil
+		}
+		return validateOperator(assertion)
+	case models.AssertionLatency:
+		if _, err := strconv.ParseInt(assertion.Expected, 10, 64); err != nil {
+			return fmt.Errorf("latency assertion requires a number of milliseconds: %w", err)
+		}
+		return nil
+	case models.AssertionGraphQLErrors:
+		return nil
+	default:
+		return fmt.Errorf("unsupported assertion type: %s", assertion.Type)
+	}
+}
+
+func validateOperator(assertion models.Assertion) error {
+	switch assertion.Operator {
+	case models.OperatorEquals, models.OperatorNotEquals, models.OperatorContains, models.OperatorExists:
+		return nil
+	case models.OperatorMatches:
+		_, err := regexp.Compile(assertion.Expected)
+		return err
+	default:
+		return fmt.Errorf("unsupported assertion operator: %s", assertion.Operator)
+	}
+}
+
+// Evaluate runs every assertion against the response
+func Evaluate(assertions []models.Assertion, resp Response) []models.AssertionResult {
+	results := make([]models.AssertionResult, 0, len(assertions))
+	for _, assertion := range assertions {
+		result := evaluate(assertion, resp)
+		result.AssertionID = assertion.ID
+		result.Type = assertion.Type
+		result.Target = assertion.Target
+		results = append(results, result)
+	}
+	return results
+}
+
+// Contract validates a response against an operation of an OpenAPI spec
+func Contract(spec *openapi.Document, operation string, resp Response) models.AssertionResult {
+	result := models.AssertionResult{Type: models.AssertionContract, Target: operation, Actual: strconv.Itoa(resp.StatusCode)}
+	violations := spec.ValidateResponse(operation, resp.StatusCode, resp.Body)
+	if len(violations) == 0 {
+		result.Passed = true
+		return result
+	}
+	result.Message = "contract violation: " + strings.Join(violations, "; ")
+	return result
+}
+
+// AllPassed reports whether every assertion result passed
+func AllPassed(results []models.AssertionResult) bool {
+	for _, result := range results {
+		if !result.Passed {
+			return false
+		}
+	}
+	return true
+}
+
+// FailureMessage summarizes the failed assertions for the execution log error
+func FailureMessage(results []models.AssertionResult) string {
+	var failures []string
+	for _, result := range results {
+		if !result.Passed {
+			failures = append(failures, result.Message)
+		}
+	}
+	return fmt.Sprintf("%d assertion(s) failed: %s", len(failures), strings.Join(failures, "; "))
+}
+
+func evaluate(assertion models.Assertion, resp Response) models.AssertionResult {
+	switch assertion.Type {
+	case models.AssertionStatusCode:
+		actual := strconv.Itoa(resp.StatusCode)
+		codes, err := ParseStatusCodes(assertion.Expected)
+		if err != nil {
+			return fail(actual, err.Error())
+		}
+		if codes.Contains(resp.StatusCode) {
+			return pass(actual)
+		}
+		return fail(actual, fmt.Sprintf("status code %d not in %s", resp.StatusCode, assertion.Expected))
+
+	case models.AssertionJSONPath:
+		matches, err := jsonpath.Query(resp.Body, assertion.Target)
+		if err != nil {
+			return fail("", err.Error())
+		}
+		if len(matches) == 0 {
+			if assertion.Operator == models.OperatorNotEquals {
+				return pass("")
+			}
+			return fail("", fmt.Sprintf("%s matched nothing", assertion.Target))
+		}
+		actual := jsonpath.Stringify(matches[0])
+		return compare(assertion, assertion.Target, actual)
+
+	case models.AssertionBodyRegex:
+		re, err := regexp.Compile(assertion.Expected)
+		if err != nil {
+			return fail("", err.Error())
+		}
+		if re.MatchString(resp.Body) {
+			return pass("")
+		}
+		return fail("", fmt.Sprintf("body does not match /%s/", assertion.Expected))
+
+	case models.AssertionHeader:
+		values, present := resp.Headers[http.CanonicalHeaderKey(assertion.Target)]
+		if !present {
+			return fail("", fmt.Sprintf("header %s is missing", assertion.Target))
+		}
+		actual := strings.Join(values, ", ")
+		if assertion.Operator == "" || assertion.Operator == models.OperatorExists {
+			return pass(actual)
+		}
+		return compare(assertion, "header "+assertion.Target, actual)
+
+	case models.AssertionLatency:
+		actual := strconv.FormatInt(resp.DurationMs, 10)
+		limit, err := strconv.ParseInt(assertion.Expected, 10, 64)
+		if err != nil {
+			return fail(actual, err.Error())
+		}
+		if resp.DurationMs < limit {
+			return pass(actual)
+		}
+		return fail(actual, fmt.Sprintf("latency %dms is not under %dms", resp.DurationMs, limit))
+
+	case models.AssertionGraphQLErrors:
+		messages, err := graphql.Errors(resp.Body)
+		if err != nil {
+			return fail("", err.Error())
+		}
+		actual := strings.Join(messages, "; ")
+		if assertion.Expected == "" {
+			if len(messages) == 0 {
+				return pass("")
+			}
+			return fail(actual, fmt.Sprintf("GraphQL response has %d error(s): %s", len(messages), actual))
+		}
+		// An expected error, e.g. for a check that a protected field stays protected
+		for _, message := range messages {
+			if strings.Contains(message, assertion.Expected) {
+				return pass(actual)
+			}
+		}
+		return fail(actual, fmt.Sprintf("GraphQL response has no error containing %q", assertion.Expected))
+
+	default:
+		return fail("", fmt.Sprintf("unsupported assertion type: %s", assertion.Type))
+	}
+}
+
+// compare applies the assertion operator to an actual value
+func compare(assertion models.Assertion, subject, actual string) models.AssertionResult {
+	switch assertion.Operator {
+	case models.OperatorExists:
+		return pass(actual)
+	case models.OperatorEquals:
+		if actual == assertion.Expected {
+			return pass(actual)
+		}
+		return fail(actual, fmt.Sprintf("%s is %q, expected %q", subject, actual, assertion.Expected))
+	case models.OperatorNotEquals:
+		if actual != assertion.Expected {
+			return pass(actual)
+		}
+		return fail(actual, fmt.Sprintf("%s should not be %q", subject, assertion.Expected))
+	case models.OperatorContains:
+		if strings.Contains(actual, assertion.Expected) {
+			return pass(actual)
+		}
+		return fail(actual, fmt.Sprintf("%s does not contain %q", subject, assertion.Expected))
+	case models.OperatorMatches:
+		re, err := regexp.Compile(assertion.Expected)
+		if err != nil {
+			return fail(actual, err.Error())
+		}
+		if re.MatchString(actual) {
+			return pass(actual)
+		}
+		return fail(actual, fmt.Sprintf("%s does not match /%s/", subject, assertion.Expected))
+	default:
+		return fail(actual, fmt.Sprintf("unsupported assertion operator: %s", assertion.Operator))
+	}
+}
+
+func pass(actual string) models.AssertionResult {
+	return models.AssertionResult{Passed: true, Actual: actual}
+}
+
+func fail(actual, message string) models.AssertionResult {
+	return models.AssertionResult{Passed: false, Actual: actual, Message: message}
+}
+
+// StatusCodes is a parsed list of status codes and ranges such as "200,201,300-399"
+type StatusCodes [][2]int
+
+// ParseStatusCodes parses a comma-separated list of codes and inclusive ranges
+func ParseStatusCodes(expression string) (StatusCodes, error) {
+	var codes StatusCodes
+	for _, part := range strings.Split(expression, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		low, high := part, part
+		if i := strings.Index(part, "-"); i > 0 {
+			low, high = part[:i], part[i+1:]
+		}
+		from, err := strconv.Atoi(strings.TrimSpace(low))
+		if err != nil {
+			return nil, fmt.Errorf("invalid status code %q", part)
+		}
+		to, err := strconv.Atoi(strings.TrimSpace(high))
+		if err != nil || to < from {
+			return nil, fmt.Errorf("invalid status code range %q", part)
+		}
+		codes = append(codes, [2]int{from, to})
+	}
+	if len(codes) == 0 {
+		return nil, fmt.Errorf("no status codes given")
+	}
+	return codes, nil
+}
+
+// Contains reports whether the status code is in the list
+func (c StatusCodes) Contains(statusCode int) bool {
+	for _, r := range c {
+		if statusCode >= r[0] && statusCode <= r[1] {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,180 @@
+package auth
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"flowpulse/pkg/database"
+	"flowpulse/pkg/models"
+)
+
+// refreshBefore is how long before expiry a cached token is replaced
+const refreshBefore = time.Minute
+
+// defaultTokenLifetime is assumed when the token response doesn't include expires_in
+const defaultTokenLifetime = 5 * time.Minute
+
+// Validate checks that an auth config has the settings its type needs
+func Validate(config models.AuthConfig) error {
+	switch config.Type {
+	case models.AuthOAuth2ClientCredentials:
+		if config.TokenURL == "" || config.ClientID == "" || config.ClientSecret == "" {
+			return fmt.Errorf("client credentials auth requires a token URL, client ID and client secret")
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported auth type: %s", config.Type)
+	}
+}
+
+// cachedToken is an access token with its expiry. updatedAt ties it to the config version it was fetched with.
+type cachedToken struct {
+	accessToken string
+	expiresAt   time.Time
+	updatedAt   time.Time
+}
+
+// Service resolves the auth config of an API and fetches and caches its access tokens
+type Service struct {
+	db     database.Store
+	client *http.Client
+	tokens map[int]cachedToken
+	mu     sync.Mutex
+}
+
+// NewService creates a new auth service
+func NewService(db database.Store) *Service {
+	return &Service{
+		db: db,
+		client: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+		tokens: make(map[int]cachedToken),
+	}
+}
+
+// ConfigForAPI returns the auth config of an API, falling back to its collection's. It returns nil when neither has one.
+func (s *Service) ConfigForAPI(api models.API) (*models.AuthConfig, error) {
+	configID := api.AuthConfigID
+	if configID == 0 && api.CollectionID != 0 {
+		collection, err := s.db.GetCollectionByID(api.CollectionID)
+		if err != nil {
+			return nil, err
+		}
+		configID = collection.AuthConfigID
+	}
+	if configID == 0 {
+		return nil, nil
+	}
+
+	config, err := s.db.GetAuthConfigByID(configID)
+	if err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+// Authorize sets the Authorization header of a request from the auth config
+func (s *Service) Authorize(req *http.Request, config models.AuthConfig) error {
+	token, err := s.Token(config)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	return nil
+}
+
+// Token returns a cached access token, fetching a new one when it is missing, about to expire,
+// or was fetched with an older version of the config
+func (s *Service) Token(config models.AuthConfig) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if cached, ok := s.tokens[config.ID]; ok && cached.updatedAt.Equal(config.UpdatedAt) &&
+		time.Now().Add(refreshBefore).Before(cached.expiresAt) {
+		return cached.accessToken, nil
+	}
+
+	token, err := s.fetchToken(config)
+	if err != nil {
+		return "", err
+	}
+	s.tokens[config.ID] = token
+	return token.accessToken, nil
+}
+
+// Invalidate drops the cached token of a config, e.g. after the API rejected it
+func (s *Service) Invalidate(configID int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.tokens, configID)
+}
+
+// tokenResponse is the token endpoint response of RFC 6749 section 5.1
+type tokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int64  `json:"expires_in"`
+}
+
+// fetchToken requests an access token with the client credentials grant
+func (s *Service) fetchToken(config models.AuthConfig) (cachedToken, error) {
+	if err := Validate(config); err != nil {
+		return cachedToken{}, err
+	}
+
+	form := url.Values{"grant_type": {"client_credentials"}}
+	if config.Scopes != "" {
+		form.Set("scope", config.Scopes)
+	}
+	if config.Audience != "" {
+		form.Set("audience", config.Audience)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, config.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return cachedToken{}, fmt.Errorf("failed to create token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	req.SetBasicAuth(url.QueryEscape(config.ClientID), url.QueryEscape(config.ClientSecret))
+
+	fetchedAt := time.Now()
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return cachedToken{}, fmt.Errorf("token request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return cachedToken{}, fmt.Errorf("failed to read token response: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return cachedToken{}, fmt.Errorf("token endpoint returned %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	var token tokenResponse
+	if err := json.Unmarshal(body, &token); err != nil {
+		return cachedToken{}, fmt.Errorf("failed to parse token response: %w", err)
+	}
+	if token.AccessToken == "" {
+		return cachedToken{}, fmt.Errorf("token response has no access_token")
+	}
+
+	lifetime := defaultTokenLifetime
+	if token.ExpiresIn > 0 {
+		lifetime = time.Duration(token.ExpiresIn) * time.Second
+	}
+	return cachedToken{
+		accessToken: token.AccessToken,
+		expiresAt:   fetchedAt.Add(lifetime),
+		updatedAt:   config.UpdatedAt,
+	}, nil
+}
@@ -0,0 +1,454 @@
+// Package backup exports the whole workspace to a single archive and restores it, to migrate to another
+// machine or recover after data loss.
+package backup
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"runtime/debug"
+	"time"
+
+	"flowpulse/pkg/database"
+	"flowpulse/pkg/models"
+)
+
+// Format identifies a FlowPulse backup
+const Format = "flowpulse-backup"
+
+// SchemaVersion is the current version of the Backup format. Restore refuses backups of later versions
+// and migrates those of earlier ones.
+const SchemaVersion = 2
+
+// AppVersion is the FlowPulse version recorded in backups. Release builds set it with
+// -ldflags "-X flowpulse/pkg/backup.AppVersion=<version>"; other builds record the module version.
+var AppVersion = ""
+
+// migrations upgrade a backup document from the version of their key to the next one. Every change to the
+// Backup format bumps SchemaVersion and adds a migration here, so that older backups stay restorable.
+var migrations = map[int]func(document map[string]any) error{
+	// Version 2 added the app version and checksum, which version 1 backups go without
+	1: func(document map[string]any) error { return nil },
+}
+
+// logPageSize is how many execution logs are read or restored at once
+const logPageSize = 1000
+
+// Backup is a full copy of the workspace. Records keep the IDs they had when exported, which Restore
+// maps to the IDs they get in the restored database.
+type Backup struct {
+	Format        string                `json:"format"`
+	SchemaVersion int                   `json:"schemaVersion"`
+	AppVersion    string                `json:"appVersion,omitempty"`
+	CreatedAt     time.Time             `json:"createdAt"`
+	Checksum      string                `json:"checksum,omitempty"` // SHA-256 of the backup without the checksum
+	Environments  []models.Environment  `json:"environments"`
+	Collections   []models.Collection   `json:"collections"`
+	APIs          []models.API          `json:"apis"`
+	Assertions    []models.Assertion    `json:"assertions"`
+	Extractions   []models.Extraction   `json:"extractions"`
+	Schedules     []models.Schedule     `json:"schedules"`
+	Logs          []models.ExecutionLog `json:"logs,omitempty"` // Only when exported with logs
+}
+
+// Export reads the workspace from the database, with every execution log when includeLogs is set
+func Export(db database.Store, includeLogs bool) (*Backup, error) {
+	backup := &Backup{
+		Format:        Format,
+		SchemaVersion: SchemaVersion,
+		AppVersion:    appVersion(),
+		CreatedAt:     time.Now(),
+	}
+
+	var err error
+	if backup.Environments, err = db.GetAllEnvironments(); err != nil {
+		return nil, err
+	}
+	if backup.Collections, err = db.GetAllCollections(); err != nil {
+		return nil, err
+	}
+	if backup.APIs, err = db.GetAllAPIs(); err != nil {
+		return nil, err
+	}
+	for _, api := range backup.APIs {
+		assertions, err := db.GetAssertionsByAPIID(api.ID)
+		if err != nil {
+			return nil, err
+		}
+		backup.Assertions = append(backup.Assertions, assertions...)
+
+		extractions, err := db.GetExtractionsByAPIID(api.ID)
+		if err != nil {
+			return nil, err
+		}
+		backup.Extractions = append(backup.Extractions, extractions...)
+	}
+	if backup.Schedules, err = db.GetAllSchedules(); err != nil {
+		return nil, err
+	}
+
+	if includeLogs {
+		lastID := 0
+		for {
+			logs, err := db.GetExecutionLogsAfterID(lastID, logPageSize)
+			if err != nil {
+				return nil, err
+			}
+			backup.Logs = append(backup.Logs, logs...)
+			if len(logs) < logPageSize {
+				break
+			}
+			lastID = logs[len(logs)-1].ID
+		}
+	}
+
+	if backup.Checksum, err = checksum(backup); err != nil {
+		return nil, err
+	}
+	return backup, nil
+}
+
+// appVersion is the FlowPulse version recorded in new backups
+func appVersion() string {
+	if AppVersion != "" {
+		return AppVersion
+	}
+	if info, ok := debug.ReadBuildInfo(); ok && info.Main.Version != "" {
+		return info.Main.Version
+	}
+	return "unknown"
+}
+
+// checksum computes the checksum of a backup from its canonical JSON, with sorted keys and without the
+// checksum itself, so that it doesn't depend on how the backup was written or which fields the reader knows
+func checksum(v any) (string, error) {
+	encoded, err := json.Marshal(v)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode backup: %w", err)
+	}
+	document, err := decodeDocument(bytes.NewReader(encoded))
+	if err != nil {
+		return "", err
+	}
+	delete(document, "checksum")
+	canonical, err := json.Marshal(document)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode backup: %w", err)
+	}
+	sum := sha256.Sum256(canonical)
+	return "sha256:" + hex.EncodeToString(sum[:]), nil
+}
+
+// decodeDocument parses a backup into generic JSON values, keeping numbers as they were written
+func decodeDocument(r io.Reader) (map[string]any, error) {
+	decoder := json.NewDecoder(r)
+	decoder.UseNumber()
+	var document map[string]any
+	if err := decoder.Decode(&document); err != nil {
+		return nil, fmt.Errorf("failed to parse backup: %w", err)
+	}
+	if document == nil {
+		return nil, fmt.Errorf("not a FlowPulse backup")
+	}
+	return document, nil
+}
+
+// Write saves a backup to a file as gzip-compressed JSON
+func Write(backup *Backup, path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create backup file: %w", err)
+	}
+	defer file.Close()
+
+	compressed := gzip.NewWriter(file)
+	if err := json.NewEncoder(compressed).Encode(backup); err != nil {
+		return fmt.Errorf("failed to write backup: %w", err)
+	}
+	if err := compressed.Close(); err != nil {
+		return fmt.Errorf("failed to write backup: %w", err)
+	}
+	return file.Close()
+}
+
+// Read loads a backup written by Write. Uncompressed JSON is accepted too, e.g. after editing a backup by hand.
+func Read(path string) (*Backup, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open backup file: %w", err)
+	}
+	defer file.Close()
+
+	return Decode(file)
+}
+
+// Decode parses a backup from gzip-compressed or plain JSON, checks it can be restored and migrates it
+// to the current version. Backups with a checksum must match it; one edited by hand can drop the
+// checksum to be restored.
+func Decode(r io.Reader) (*Backup, error) {
+	buffered := bufio.NewReader(r)
+	var source io.Reader = buffered
+	if magic, err := buffered.Peek(2); err == nil && magic[0] == 0x1f && magic[1] == 0x8b {
+		decompressed, err := gzip.NewReader(buffered)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decompress backup: %w", err)
+		}
+		defer decompressed.Close()
+		source = decompressed
+	}
+
+	document, err := decodeDocument(source)
+	if err != nil {
+		return nil, err
+	}
+	if format, _ := document["format"].(string); format != Format {
+		return nil, fmt.Errorf("not a FlowPulse backup")
+	}
+	number, _ := document["schemaVersion"].(json.Number)
+	version, err := number.Int64()
+	if err != nil || version < 1 || version > SchemaVersion {
+		return nil, fmt.Errorf("unsupported backup version %s; this version of FlowPulse reads up to version %d",
+			number, SchemaVersion)
+	}
+
+	if expected, _ := document["checksum"].(string); expected != "" {
+		actual, err := checksum(document)
+		if err != nil {
+			return nil, err
+		}
+		if actual != expected {
+			return nil, fmt.Errorf("backup checksum mismatch: the file is corrupt or was modified")
+		}
+	}
+
+	for ; version < SchemaVersion; version++ {
+		if err := migrations[int(version)](document); err != nil {
+			return nil, fmt.Errorf("failed to migrate backup from version %d: %w", version, err)
+		}
+	}
+	document["schemaVersion"] = SchemaVersion
+
+	migrated, err := json.Marshal(document)
+	if err != nil {
+		return nil, fmt.Errorf("failed to migrate backup: %w", err)
+	}
+	var backup Backup
+	if err := json.Unmarshal(migrated, &backup); err != nil {
+		return nil, fmt.Errorf("failed to parse backup: %w", err)
+	}
+	return &backup, nil
+}
+
+// Restore adds the records of a backup to the database. Collections, APIs and schedules that already exist,
+// recognized by their UUID, and environments with the same name are kept as they are, so restoring the same
+// backup twice changes nothing. Records in the trash count as existing, since their UUIDs stay taken.
+// Assertions, extractions and logs are only restored for the APIs it creates.
+// Auth configs and OpenAPI specs aren't part of backups, so restored collections and APIs don't reference any.
+// It returns the IDs of the schedules it created, so their jobs can be started.
+func Restore(db database.Store, backup *Backup) (models.BackupResult, []int, error) {
+	var result models.BackupResult
+	var createdSchedules []int
+
+	environments, err := db.GetAllEnvironments()
+	if err != nil {
+		return result, nil, err
+	}
+	environmentIDs := make(map[int]int)
+	existingEnvironments := make(map[string]int)
+	for _, environment := range environments {
+		existingEnvironments[environment.Name] = environment.ID
+	}
+	for _, environment := range backup.Environments {
+		if id, ok := existingEnvironments[environment.Name]; ok {
+			environmentIDs[environment.ID] = id
+			result.Skipped++
+			continue
+		}
+		created, err := db.CreateEnvironment(environment)
+		if err != nil {
+			return result, createdSchedules, err
+		}
+		environmentIDs[environment.ID] = created.ID
+		existingEnvironments[created.Name] = created.ID
+		result.Environments++
+	}
+
+	trash, err := db.GetTrash()
+	if err != nil {
+		return result, createdSchedules, err
+	}
+
+	collections, err := db.GetAllCollections()
+	if err != nil {
+		return result, createdSchedules, err
+	}
+	collections = append(collections, trash.Collections...)
+	collectionIDs := make(map[int]int)
+	createdCollections := make(map[int]bool) // By ID in the backup
+	existingCollections := make(map[string]int)
+	for _, collection := range collections {
+		existingCollections[collection.UUID] = collection.ID
+	}
+	for _, collection := range backup.Collections {
+		if id, ok := existingCollections[collection.UUID]; ok && collection.UUID != "" {
+			collectionIDs[collection.ID] = id
+			result.Skipped++
+			continue
+		}
+		oldID := collection.ID
+		collection.ParentID = 0 // Nested once every collection has its new ID
+		collection.EnvironmentID = environmentIDs[collection.EnvironmentID]
+		collection.AuthConfigID = 0
+		created, err := db.CreateCollection(collection)
+		if err != nil {
+			return result, createdSchedules, err
+		}
+		collectionIDs[oldID] = created.ID
+		createdCollections[oldID] = true
+		result.Collections++
+	}
+	// Collections nested in one that is in the trash here stay at the top level
+	trashedCollections := make(map[int]bool)
+	for _, collection := range trash.Collections {
+		trashedCollections[collection.ID] = true
+	}
+	for _, collection := range backup.Collections {
+		parentID, ok := collectionIDs[collection.ParentID]
+		if ok && createdCollections[collection.ID] && !trashedCollections[parentID] {
+			if err := db.MoveCollection(collectionIDs[collection.ID], parentID); err != nil {
+				return result, createdSchedules, err
+			}
+		}
+	}
+
+	apis, err := db.GetAllAPIs()
+	if err != nil {
+		return result, createdSchedules, err
+	}
+	apis = append(apis, trash.APIs...)
+	apiIDs := make(map[int]int)
+	createdAPIs := make(map[int]bool) // By ID in the backup
+	existingAPIs := make(map[string]int)
+	for _, api := range apis {
+		existingAPIs[api.UUID] = api.ID
+	}
+	for _, api := range backup.APIs {
+		if id, ok := existingAPIs[api.UUID]; ok && api.UUID != "" {
+			apiIDs[api.ID] = id
+			result.Skipped++
+			continue
+		}
+		oldID := api.ID
+		api.CollectionID = collectionIDs[api.CollectionID]
+		api.AuthConfigID = 0
+		api.SpecID = 0
+		created, err := db.CreateAPI(api)
+		if err != nil {
+			return result, createdSchedules, err
+		}
+		apiIDs[oldID] = created.ID
+		createdAPIs[oldID] = true
+		result.APIs++
+	}
+
+	for _, assertion := range backup.Assertions {
+		if createdAPIs[assertion.APIID] {
+			assertion.APIID = apiIDs[assertion.APIID]
+			if _, err := db.CreateAssertion(assertion); err != nil {
+				return result, createdSchedules, err
+			}
+		}
+	}
+	for _, extraction := range backup.Extractions {
+		if createdAPIs[extraction.APIID] {
+			extraction.APIID = apiIDs[extraction.APIID]
+			if _, err := db.CreateExtraction(extraction); err != nil {
+				return result, createdSchedules, err
+			}
+		}
+	}
+
+	schedules, err := db.GetAllSchedules()
+	if err != nil {
+		return result, createdSchedules, err
+	}
+	for _, api := range trash.APIs {
+		trashed, err := db.GetSchedulesByAPIID(api.ID)
+		if err != nil {
+			return result, createdSchedules, err
+		}
+		schedules = append(schedules, trashed...)
+	}
+	scheduleIDs := make(map[int]int)
+	var linked []models.Schedule
+	existingSchedules := make(map[string]int)
+	for _, schedule := range schedules {
+		existingSchedules[schedule.UUID] = schedule.ID
+	}
+	for _, schedule := range backup.Schedules {
+		if id, ok := existingSchedules[schedule.UUID]; ok && schedule.UUID != "" {
+			scheduleIDs[schedule.ID] = id
+			result.Skipped++
+			continue
+		}
+		apiID, ok := apiIDs[schedule.APIID]
+		if !ok {
+			continue // The backup is missing the scheduled API
+		}
+		oldID := schedule.ID
+		schedule.APIID = apiID
+		created, err := db.CreateSchedule(schedule)
+		if err != nil {
+			return result, createdSchedules, err
+		}
+		scheduleIDs[oldID] = created.ID
+		createdSchedules = append(createdSchedules, created.ID)
+		if created.AfterID != 0 {
+			linked = append(linked, created)
+		}
+		result.Schedules++
+	}
+	// Links between schedules wait until every schedule exists, since one may run after a later one
+	for _, schedule := range linked {
+		schedule.AfterID = scheduleIDs[schedule.AfterID]
+		if err := db.UpdateSchedule(schedule); err != nil {
+			return result, createdSchedules, err
+		}
+	}
+
+	var logs []models.ExecutionLog
+	for _, log := range backup.Logs {
+		if !createdAPIs[log.APIID] {
+			continue
+		}
+		log.APIID = apiIDs[log.APIID]
+		log.ScheduleID = scheduleIDs[log.ScheduleID]
+		log.Triggers = append([]models.ExecutionTrigger(nil), log.Triggers...)
+		for i := range log.Triggers {
+			log.Triggers[i].ScheduleID = scheduleIDs[log.Triggers[i].ScheduleID]
+		}
+		log.CollectionRunID = 0 // Collection runs aren't part of backups
+		logs = append(logs, log)
+		if len(logs) == logPageSize {
+			if err := db.RestoreExecutionLogs(logs); err != nil {
+				return result, createdSchedules, err
+			}
+			result.Logs += len(logs)
+			logs = logs[:0]
+		}
+	}
+	if len(logs) > 0 {
+		if err := db.RestoreExecutionLogs(logs); err != nil {
+			return result, createdSchedules, err
+		}
+		result.Logs += len(logs)
+	}
+
+	return result, createdSchedules, nil
+}
@@ -0,0 +1,280 @@
+package database
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"flowpulse/pkg/models"
+)
+
+// GetLocationComparison compares an API's availability and latency across the locations that checked it since the given time.
+// A location counts as failing when its most recent execution failed.
+func (s *DBService) GetLocationComparison(apiID int, since time.Time) (models.LocationComparison, error) {
+	comparison := models.LocationComparison{
+		APIID:       apiID,
+		Locations:   []models.LocationStats{},
+		OutageScope: models.OutageScopeNone,
+	}
+
+	rows, err := s.db.Query(`
+		SELECT
+			COALESCE(location, 'local') AS loc,
+			COUNT(*),
+			SUM(CASE WHEN status = ? THEN 1 ELSE 0 END),
+			SUM(CASE WHEN status = ? THEN 1 ELSE 0 END),
+			COALESCE(AVG(duration_ms), 0)
+		FROM execution_logs
+		WHERE api_id = ? AND executed_at >= ? AND `+uptimeExecutions+`
+		GROUP BY loc
+		ORDER BY loc`,
+		models.ExecutionStatusSuccess, models.ExecutionStatusDegraded, apiID, since,
+	)
+	if err != nil {
+		return comparison, fmt.Errorf("failed to query location statistics: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var stats models.LocationStats
+		var degradedCount int
+		if err := rows.Scan(&stats.Location, &stats.TotalExecutions, &stats.SuccessCount, &degradedCount, &stats.AverageTimeMs); err != nil {
+			return comparison, fmt.Errorf("failed to scan location statistics: %w", err)
+		}
+		if stats.TotalExecutions > 0 {
+			stats.Availability = float64(stats.SuccessCount+degradedCount) / float64(stats.TotalExecutions) * 100
+		}
+		comparison.Locations = append(comparison.Locations, stats)
+	}
+	if err := rows.Err(); err != nil {
+		return comparison, fmt.Errorf("failed to read location statistics: %w", err)
+	}
+
+	// Look up the latest result of each location to decide which ones are currently failing
+	for i := range comparison.Locations {
+		stats := &comparison.Locations[i]
+		var executedAt time.Time
+		err := s.db.QueryRow(`
+			SELECT COALESCE(status, ''), executed_at FROM execution_logs
+			WHERE api_id = ? AND COALESCE(location, 'local') = ? AND `+measuredExecutions+`
+			ORDER BY executed_at DESC LIMIT 1`,
+			apiID, stats.Location,
+		).Scan(&stats.LastStatus, &executedAt)
+		if err != nil {
+			return comparison, fmt.Errorf("failed to get latest execution for location %s: %w", stats.Location, err)
+		}
+		stats.LastExecutedAt = executedAt.Format(time.RFC3339)
+		if stats.LastStatus == models.ExecutionStatusFailure {
+			comparison.FailingLocations = append(comparison.FailingLocations, stats.Location)
+		}
+	}
+
+	switch {
+	case len(comparison.FailingLocations) == 0:
+		comparison.OutageScope = models.OutageScopeNone
+	case len(comparison.FailingLocations) == len(comparison.Locations):
+		comparison.OutageScope = models.OutageScopeGlobal
+	default:
+		comparison.OutageScope = models.OutageScopeRegional
+	}
+
+	return comparison, nil
+}
+
+// timeSeriesBuckets maps bucket sizes to the length of the executed_at prefix grouped on and its layout.
+// Execution times are stored in local time, so buckets follow local hours and days.
+var timeSeriesBuckets = map[string]struct {
+	length int
+	layout string
+}{
+	models.TimeSeriesBucketHour: {13, "2006-01-02 15"},
+	models.TimeSeriesBucketDay:  {10, "2006-01-02"},
+}
+
+// GetAPITimeSeries buckets the executions of an API between from and to by hour or day, counting each status and
+// computing the average and nearest-rank percentile latencies in SQL. Buckets without executions are left out.
+func (s *DBService) GetAPITimeSeries(apiID int, bucket string, from, to time.Time) (models.TimeSeries, error) {
+	series := models.TimeSeries{APIID: apiID, Bucket: bucket, From: from, To: to, Buckets: []models.TimeSeriesBucket{}}
+	size, ok := timeSeriesBuckets[bucket]
+	if !ok {
+		return series, fmt.Errorf("unsupported time series bucket: %s", bucket)
+	}
+
+	rows, err := s.db.Query(`
+		WITH bucketed AS (
+			SELECT substr(executed_at, 1, ?) AS bucket, status, duration_ms
+			FROM execution_logs
+			WHERE api_id = ? AND executed_at >= ? AND executed_at < ? AND `+uptimeExecutions+`
+		), ranked AS (
+			SELECT bucket, duration_ms,
+				ROW_NUMBER() OVER (PARTITION BY bucket ORDER BY duration_ms) AS rank,
+				COUNT(*) OVER (PARTITION BY bucket) AS measured
+			FROM bucketed
+			WHERE duration_ms IS NOT NULL
+		)
+		SELECT
+			b.bucket,
+			COUNT(*),
+			SUM(CASE WHEN b.status = ? THEN 1 ELSE 0 END),
+			SUM(CASE WHEN b.status = ? THEN 1 ELSE 0 END),
+			SUM(CASE WHEN b.status = ? THEN 1 ELSE 0 END),
+			COALESCE(AVG(b.duration_ms), 0),
+			COALESCE((SELECT duration_ms FROM ranked r WHERE r.bucket = b.bucket AND r.rank = (r.measured * 50 + 99) / 100), 0),
+			COALESCE((SELECT duration_ms FROM ranked r WHERE r.bucket = b.bucket AND r.rank = (r.measured * 95 + 99) / 100), 0),
+			COALESCE((SELECT duration_ms FROM ranked r WHERE r.bucket = b.bucket AND r.rank = (r.measured * 99 + 99) / 100), 0)
+		FROM bucketed b
+		GROUP BY b.bucket
+		ORDER BY b.bucket`,
+		size.length, apiID, from.Local(), to.Local(),
+		models.ExecutionStatusSuccess, models.ExecutionStatusDegraded, models.ExecutionStatusFailure,
+	)
+	if err != nil {
+		return series, fmt.Errorf("failed to query time series: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var point models.TimeSeriesBucket
+		var start string
+		if err := rows.Scan(&start, &point.Executions, &point.SuccessCount, &point.DegradedCount, &point.FailureCount,
+			&point.AverageTimeMs, &point.P50TimeMs, &point.P95TimeMs, &point.P99TimeMs); err != nil {
+			return series, fmt.Errorf("failed to scan time series bucket: %w", err)
+		}
+		if point.Start, err = time.ParseInLocation(size.layout, start, time.Local); err != nil {
+			return series, fmt.Errorf("failed to parse time series bucket %q: %w", start, err)
+		}
+		series.Buckets = append(series.Buckets, point)
+	}
+	if err := rows.Err(); err != nil {
+		return series, fmt.Errorf("failed to read time series: %w", err)
+	}
+	return series, nil
+}
+
+// GetCollectionLatencyPercentile returns the nearest-rank latency at a percentile of the measured executions of a
+// collection's APIs since the given time, along with how many executions there were
+func (s *DBService) GetCollectionLatencyPercentile(collectionID int, percentile float64, since time.Time) (int64, int, error) {
+	const collectionExecutions = `FROM execution_logs
+		WHERE api_id IN (SELECT id FROM apis WHERE collection_id = ? AND deleted_at IS NULL) AND executed_at >= ? AND duration_ms IS NOT NULL AND ` + uptimeExecutions
+
+	var measured int
+	if err := s.db.QueryRow("SELECT COUNT(*) "+collectionExecutions, collectionID, since.Local()).Scan(&measured); err != nil {
+		return 0, 0, fmt.Errorf("failed to count collection executions: %w", err)
+	}
+	if measured == 0 {
+		return 0, 0, nil
+	}
+
+	rank := int(math.Ceil(float64(measured) * percentile / 100))
+	if rank < 1 {
+		rank = 1
+	}
+	var latency int64
+	err := s.db.QueryRow("SELECT duration_ms "+collectionExecutions+" ORDER BY duration_ms LIMIT 1 OFFSET ?",
+		collectionID, since.Local(), rank-1).Scan(&latency)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to query collection latency percentile: %w", err)
+	}
+	return latency, measured, nil
+}
+
+// GetIncidentHistory gets the incidents opened since the given time, newest first, for one API or every API when apiID is 0
+func (s *DBService) GetIncidentHistory(apiID int, since time.Time) ([]models.Incident, error) {
+	if apiID == 0 {
+		return s.queryIncidents("SELECT "+incidentColumns+" FROM incidents WHERE opened_at >= ? ORDER BY opened_at DESC", since.Local())
+	}
+	return s.queryIncidents(
+		"SELECT "+incidentColumns+" FROM incidents WHERE api_id = ? AND opened_at >= ? ORDER BY opened_at DESC",
+		apiID, since.Local(),
+	)
+}
+
+// GetIncidentStats summarizes the incidents opened since the given time, including the mean time to resolve (MTTR)
+// and acknowledge them, for one API or every API when apiID is 0
+func (s *DBService) GetIncidentStats(apiID int, since time.Time) (models.IncidentStats, error) {
+	stats := models.IncidentStats{APIID: apiID, Since: since}
+
+	incidents, err := s.GetIncidentHistory(apiID, since)
+	if err != nil {
+		return stats, err
+	}
+
+	var resolving, acknowledging float64
+	var acknowledged int
+	for _, incident := range incidents {
+		stats.Incidents++
+		stats.DowntimeSeconds += incident.DurationSeconds
+		if incident.AcknowledgedAt != nil {
+			acknowledged++
+			acknowledging += incident.AcknowledgedAt.Sub(incident.OpenedAt).Seconds()
+		}
+		if incident.Status != models.IncidentResolved {
+			stats.Open++
+			continue
+		}
+		stats.Resolved++
+		resolving += float64(incident.DurationSeconds)
+		stats.LongestSeconds = max(stats.LongestSeconds, incident.DurationSeconds)
+	}
+	if stats.Resolved > 0 {
+		stats.MTTRSeconds = resolving / float64(stats.Resolved)
+	}
+	if acknowledged > 0 {
+		stats.MTTASeconds = acknowledging / float64(acknowledged)
+	}
+	return stats, nil
+}
+
+// GetAPIUptime returns the percentage of an API's checks since the given time that succeeded or were degraded,
+// along with how many checks there were. Executions that don't count toward uptime, such as expected failures, are left out.
+func (s *DBService) GetAPIUptime(apiID int, since time.Time) (float64, int, error) {
+	var total, healthy int
+	err := s.db.QueryRow(`
+		SELECT COUNT(*), COALESCE(SUM(CASE WHEN status IN (?, ?) THEN 1 ELSE 0 END), 0)
+		FROM execution_logs
+		WHERE api_id = ? AND executed_at >= ? AND `+uptimeExecutions,
+		models.ExecutionStatusSuccess, models.ExecutionStatusDegraded, apiID, since.Local(),
+	).Scan(&total, &healthy)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to query API uptime: %w", err)
+	}
+	if total == 0 {
+		return 0, 0, nil
+	}
+	return float64(healthy) / float64(total) * 100, total, nil
+}
+
+// GetPerformanceBreakdown splits an API's checks since the given time between availability and performance:
+// how many answered as expected, and how many of those came within the latency thresholds the API had when they
+// ran. Executions that don't count toward uptime, such as expected failures, are left out.
+func (s *DBService) GetPerformanceBreakdown(apiID int, since time.Time) (models.PerformanceBreakdown, error) {
+	breakdown := models.PerformanceBreakdown{APIID: apiID}
+	err := s.db.QueryRow(`
+		SELECT
+			COUNT(*),
+			COALESCE(SUM(CASE WHEN status IN (?, ?) THEN 1 ELSE 0 END), 0),
+			COALESCE(SUM(CASE WHEN status IN (?, ?) AND COALESCE(latency_breach, '') = ? THEN 1 ELSE 0 END), 0),
+			COALESCE(SUM(CASE WHEN status IN (?, ?) AND COALESCE(latency_breach, '') = ? THEN 1 ELSE 0 END), 0),
+			COALESCE(AVG(CASE WHEN status IN (?, ?) THEN duration_ms END), 0)
+		FROM execution_logs
+		WHERE api_id = ? AND executed_at >= ? AND `+uptimeExecutions,
+		models.ExecutionStatusSuccess, models.ExecutionStatusDegraded,
+		models.ExecutionStatusSuccess, models.ExecutionStatusDegraded, models.LatencyBreachWarn,
+		models.ExecutionStatusSuccess, models.ExecutionStatusDegraded, models.LatencyBreachCritical,
+		models.ExecutionStatusSuccess, models.ExecutionStatusDegraded,
+		apiID, since.Local(),
+	).Scan(&breakdown.TotalExecutions, &breakdown.Available, &breakdown.OverWarn, &breakdown.OverCritical, &breakdown.AverageTimeMs)
+	if err != nil {
+		return breakdown, fmt.Errorf("failed to query performance breakdown: %w", err)
+	}
+
+	breakdown.Failed = breakdown.TotalExecutions - breakdown.Available
+	breakdown.WithinThresholds = breakdown.Available - breakdown.OverWarn - breakdown.OverCritical
+	if breakdown.TotalExecutions > 0 {
+		breakdown.Availability = float64(breakdown.Available) / float64(breakdown.TotalExecutions) * 100
+	}
+	if breakdown.Available > 0 {
+		breakdown.Performance = float64(breakdown.WithinThresholds) / float64(breakdown.Available) * 100
+	}
+	return breakdown, nil
+}
@@ -0,0 +1,173 @@
+package database
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"flowpulse/pkg/models"
+)
+
+// initAnnotationTables creates the table of notes attached to execution logs and incidents
+func (s *DBService) initAnnotationTables() error {
+	_, err := s.db.Exec(`
+		CREATE TABLE IF NOT EXISTS annotations (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			execution_log_id INTEGER NOT NULL DEFAULT 0,
+			incident_id INTEGER NOT NULL DEFAULT 0,
+			note TEXT NOT NULL,
+			created_at TIMESTAMP NOT NULL,
+			updated_at TIMESTAMP NOT NULL
+		)
+	`)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.db.Exec(`CREATE INDEX IF NOT EXISTS idx_annotations_execution_log ON annotations (execution_log_id)`)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.Exec(`CREATE INDEX IF NOT EXISTS idx_annotations_incident ON annotations (incident_id)`)
+	return err
+}
+
+// annotationColumns is the column list matching scanAnnotation
+const annotationColumns = `id, execution_log_id, incident_id, note, created_at, updated_at`
+
+// scanAnnotation scans a single annotation selected with annotationColumns
+func scanAnnotation(row rowScanner) (models.Annotation, error) {
+	var annotation models.Annotation
+	err := row.Scan(&annotation.ID, &annotation.ExecutionLogID, &annotation.IncidentID, &annotation.Note,
+		&annotation.CreatedAt, &annotation.UpdatedAt)
+	return annotation, err
+}
+
+// Annotation Operations
+
+// CreateAnnotation attaches a note to the execution log or incident it names
+func (s *DBService) CreateAnnotation(annotation models.Annotation) (models.Annotation, error) {
+	now := time.Now()
+	annotation.CreatedAt = now
+	annotation.UpdatedAt = now
+
+	result, err := s.db.Exec(
+		"INSERT INTO annotations (execution_log_id, incident_id, note, created_at, updated_at) VALUES (?, ?, ?, ?, ?)",
+		annotation.ExecutionLogID, annotation.IncidentID, annotation.Note, annotation.CreatedAt, annotation.UpdatedAt,
+	)
+	if err != nil {
+		return annotation, fmt.Errorf("failed to create annotation: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return annotation, fmt.Errorf("failed to get last insert ID: %w", err)
+	}
+	annotation.ID = int(id)
+	return annotation, nil
+}
+
+// UpdateAnnotationNote changes the note of an annotation
+func (s *DBService) UpdateAnnotationNote(id int, note string) (models.Annotation, error) {
+	result, err := s.db.Exec("UPDATE annotations SET note = ?, updated_at = ? WHERE id = ?", note, time.Now(), id)
+	if err != nil {
+		return models.Annotation{}, fmt.Errorf("failed to update annotation: %w", err)
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return models.Annotation{}, fmt.Errorf("annotation %d does not exist", id)
+	}
+
+	annotation, err := scanAnnotation(s.db.QueryRow("SELECT "+annotationColumns+" FROM annotations WHERE id = ?", id))
+	if err != nil {
+		return annotation, fmt.Errorf("failed to get annotation by ID: %w", err)
+	}
+	return annotation, nil
+}
+
+// DeleteAnnotation deletes an annotation by ID
+func (s *DBService) DeleteAnnotation(id int) error {
+	_, err := s.db.Exec("DELETE FROM annotations WHERE id = ?", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete annotation: %w", err)
+	}
+	return nil
+}
+
+// queryAnnotations gets the annotations whose column is one of ids, grouped by that column, oldest first
+func (s *DBService) queryAnnotations(column string, ids []int) (map[int][]models.Annotation, error) {
+	grouped := make(map[int][]models.Annotation)
+	if len(ids) == 0 {
+		return grouped, nil
+	}
+
+	args := make([]interface{}, len(ids))
+	for i, id := range ids {
+		args[i] = id
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(ids)), ", ")
+
+	rows, err := s.db.Query(
+		"SELECT "+annotationColumns+" FROM annotations WHERE "+column+" IN ("+placeholders+") ORDER BY created_at, id",
+		args...,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query annotations: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		annotation, err := scanAnnotation(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan annotation row: %w", err)
+		}
+		key := annotation.ExecutionLogID
+		if column == "incident_id" {
+			key = annotation.IncidentID
+		}
+		grouped[key] = append(grouped[key], annotation)
+	}
+	return grouped, nil
+}
+
+// attachLogAnnotations fills in the annotations of execution logs
+func (s *DBService) attachLogAnnotations(logs []models.ExecutionLog) error {
+	ids := make([]int, len(logs))
+	for i, log := range logs {
+		ids[i] = log.ID
+	}
+	annotations, err := s.queryAnnotations("execution_log_id", ids)
+	if err != nil {
+		return err
+	}
+	for i := range logs {
+		logs[i].Annotations = annotations[logs[i].ID]
+	}
+	return nil
+}
+
+// attachIncidentAnnotations fills in the annotations of incidents
+func (s *DBService) attachIncidentAnnotations(incidents []models.Incident) error {
+	ids := make([]int, len(incidents))
+	for i, incident := range incidents {
+		ids[i] = incident.ID
+	}
+	annotations, err := s.queryAnnotations("incident_id", ids)
+	if err != nil {
+		return err
+	}
+	for i := range incidents {
+		incidents[i].Annotations = annotations[incidents[i].ID]
+	}
+	return nil
+}
+
+// pruneOrphanedAnnotations deletes the annotations of execution logs that no longer exist
+func (s *DBService) pruneOrphanedAnnotations() error {
+	_, err := s.db.Exec(
+		"DELETE FROM annotations WHERE execution_log_id != 0 AND execution_log_id NOT IN (SELECT id FROM execution_logs)",
+	)
+	if err != nil {
+		return fmt.Errorf("failed to prune orphaned annotations: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package database
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"flowpulse/pkg/models"
+)
+
+// logArchive writes pruned execution logs as gzip-compressed NDJSON, one log per line.
+// The file is only created once the first log is written, so prunes that delete nothing leave no file.
+type logArchive struct {
+	path    string
+	file    *os.File
+	gzip    *gzip.Writer
+	encoder *json.Encoder
+}
+
+// ArchiveDir returns the directory pruned execution logs are archived to, creating it if needed
+func ArchiveDir() (string, error) {
+	appDir, err := AppDir()
+	if err != nil {
+		return "", err
+	}
+
+	archiveDir := filepath.Join(appDir, "archive")
+	if err := os.MkdirAll(archiveDir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create archive directory: %w", err)
+	}
+	return archiveDir, nil
+}
+
+// newLogArchive prepares an archive named after the current time in the archive directory
+func newLogArchive() (*logArchive, error) {
+	archiveDir, err := ArchiveDir()
+	if err != nil {
+		return nil, err
+	}
+	name := "execution-logs-" + time.Now().Format("20060102-150405") + ".ndjson.gz"
+	return &logArchive{path: filepath.Join(archiveDir, name)}, nil
+}
+
+// write appends execution logs to the archive
+func (a *logArchive) write(logs []models.ExecutionLog) error {
+	if a.file == nil {
+		file, err := os.OpenFile(a.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+		if err != nil {
+			return fmt.Errorf("failed to create log archive: %w", err)
+		}
+		a.file = file
+		a.gzip = gzip.NewWriter(file)
+		a.encoder = json.NewEncoder(a.gzip)
+	}
+
+	for _, log := range logs {
+		if err := a.encoder.Encode(log); err != nil {
+			return fmt.Errorf("failed to write log archive: %w", err)
+		}
+	}
+	// Flush so the logs are on disk before they are deleted from the database
+	if err := a.gzip.Flush(); err != nil {
+		return fmt.Errorf("failed to write log archive: %w", err)
+	}
+	return nil
+}
+
+// close finishes the archive file, if one was created
+func (a *logArchive) close() error {
+	if a.file == nil {
+		return nil
+	}
+	if err := a.gzip.Close(); err != nil {
+		a.file.Close()
+		return fmt.Errorf("failed to finish log archive: %w", err)
+	}
+	if err := a.file.Close(); err != nil {
+		return fmt.Errorf("failed to finish log archive: %w", err)
+	}
+	return nil
+}
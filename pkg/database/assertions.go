@@ -0,0 +1,98 @@
+package database
+
+import (
+	"fmt"
+	"time"
+
+	"flowpulse/pkg/models"
+)
+
+// initAssertionsTables creates the assertions table
+func (s *DBService) initAssertionsTables() error {
+	_, err := s.db.Exec(`
+		CREATE TABLE IF NOT EXISTS assertions (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			api_id INTEGER NOT NULL,
+			type TEXT NOT NULL,
+			target TEXT NOT NULL DEFAULT '',
+			operator TEXT NOT NULL DEFAULT '',
+			expected TEXT NOT NULL DEFAULT '',
+			created_at TIMESTAMP NOT NULL,
+			updated_at TIMESTAMP NOT NULL,
+			FOREIGN KEY (api_id) REFERENCES apis (id) ON DELETE CASCADE
+		)
+	`)
+	return err
+}
+
+// Assertion Operations
+
+// CreateAssertion creates a new assertion
+func (s *DBService) CreateAssertion(assertion models.Assertion) (models.Assertion, error) {
+	now := time.Now()
+	assertion.CreatedAt = now
+	assertion.UpdatedAt = now
+
+	result, err := s.db.Exec(
+		"INSERT INTO assertions (api_id, type, target, operator, expected, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?)",
+		assertion.APIID, assertion.Type, assertion.Target, assertion.Operator, assertion.Expected, assertion.CreatedAt, assertion.UpdatedAt,
+	)
+	if err != nil {
+		return assertion, fmt.Errorf("failed to create assertion: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return assertion, fmt.Errorf("failed to get last insert ID: %w", err)
+	}
+
+	assertion.ID = int(id)
+	return assertion, nil
+}
+
+// UpdateAssertion updates an existing assertion
+func (s *DBService) UpdateAssertion(assertion models.Assertion) (models.Assertion, error) {
+	assertion.UpdatedAt = time.Now()
+
+	_, err := s.db.Exec(
+		"UPDATE assertions SET type = ?, target = ?, operator = ?, expected = ?, updated_at = ? WHERE id = ?",
+		assertion.Type, assertion.Target, assertion.Operator, assertion.Expected, assertion.UpdatedAt, assertion.ID,
+	)
+	if err != nil {
+		return assertion, fmt.Errorf("failed to update assertion: %w", err)
+	}
+	return assertion, nil
+}
+
+// DeleteAssertion deletes an assertion by ID
+func (s *DBService) DeleteAssertion(id int) error {
+	_, err := s.db.Exec("DELETE FROM assertions WHERE id = ?", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete assertion: %w", err)
+	}
+	return nil
+}
+
+// GetAssertionsByAPIID gets all assertions of an API
+func (s *DBService) GetAssertionsByAPIID(apiID int) ([]models.Assertion, error) {
+	rows, err := s.db.Query(
+		"SELECT id, api_id, type, target, operator, expected, created_at, updated_at FROM assertions WHERE api_id = ? ORDER BY id",
+		apiID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query assertions: %w", err)
+	}
+	defer rows.Close()
+
+	var assertions []models.Assertion
+	for rows.Next() {
+		var assertion models.Assertion
+		if err := rows.Scan(&assertion.ID, &assertion.APIID, &assertion.Type, &assertion.Target, &assertion.Operator,
+			&assertion.Expected, &assertion.CreatedAt, &assertion.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan assertion row: %w", err)
+		}
+		assertions = append(assertions, assertion)
+	}
+
+	return assertions, nil
+}
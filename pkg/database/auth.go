@@ -0,0 +1,122 @@
+package database
+
+import (
+	"fmt"
+	"time"
+
+	"flowpulse/pkg/models"
+)
+
+// initAuthTables creates the auth configuration table
+func (s *DBService) initAuthTables() error {
+	_, err := s.db.Exec(`
+		CREATE TABLE IF NOT EXISTS auth_configs (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT NOT NULL,
+			type TEXT NOT NULL,
+			token_url TEXT NOT NULL DEFAULT '',
+			client_id TEXT NOT NULL DEFAULT '',
+			client_secret TEXT NOT NULL DEFAULT '',
+			scopes TEXT NOT NULL DEFAULT '',
+			audience TEXT NOT NULL DEFAULT '',
+			created_at TIMESTAMP NOT NULL,
+			updated_at TIMESTAMP NOT NULL
+		)
+	`)
+	return err
+}
+
+// Auth Config Operations
+
+// authConfigColumns is the column list matching scanAuthConfig
+const authConfigColumns = "id, name, type, token_url, client_id, client_secret, scopes, audience, created_at, updated_at"
+
+// scanAuthConfig scans a single auth config selected with authConfigColumns
+func scanAuthConfig(row rowScanner) (models.AuthConfig, error) {
+	var config models.AuthConfig
+	err := row.Scan(&config.ID, &config.Name, &config.Type, &config.TokenURL, &config.ClientID, &config.ClientSecret,
+		&config.Scopes, &config.Audience, &config.CreatedAt, &config.UpdatedAt)
+	return config, err
+}
+
+// CreateAuthConfig creates a new auth config
+func (s *DBService) CreateAuthConfig(config models.AuthConfig) (models.AuthConfig, error) {
+	now := time.Now()
+	config.CreatedAt = now
+	config.UpdatedAt = now
+
+	result, err := s.db.Exec(
+		"INSERT INTO auth_configs (name, type, token_url, client_id, client_secret, scopes, audience, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)",
+		config.Name, config.Type, config.TokenURL, config.ClientID, config.ClientSecret, config.Scopes, config.Audience, config.CreatedAt, config.UpdatedAt,
+	)
+	if err != nil {
+		return config, fmt.Errorf("failed to create auth config: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return config, fmt.Errorf("failed to get last insert ID: %w", err)
+	}
+
+	config.ID = int(id)
+	return config, nil
+}
+
+// UpdateAuthConfig updates an existing auth config
+func (s *DBService) UpdateAuthConfig(config models.AuthConfig) (models.AuthConfig, error) {
+	config.UpdatedAt = time.Now()
+
+	_, err := s.db.Exec(
+		"UPDATE auth_configs SET name = ?, type = ?, token_url = ?, client_id = ?, client_secret = ?, scopes = ?, audience = ?, updated_at = ? WHERE id = ?",
+		config.Name, config.Type, config.TokenURL, config.ClientID, config.ClientSecret, config.Scopes, config.Audience, config.UpdatedAt, config.ID,
+	)
+	if err != nil {
+		return config, fmt.Errorf("failed to update auth config: %w", err)
+	}
+	return s.GetAuthConfigByID(config.ID)
+}
+
+// DeleteAuthConfig deletes an auth config and detaches it from APIs and collections
+func (s *DBService) DeleteAuthConfig(id int) error {
+	if _, err := s.db.Exec("UPDATE apis SET auth_config_id = 0 WHERE auth_config_id = ?", id); err != nil {
+		return fmt.Errorf("failed to detach auth config from APIs: %w", err)
+	}
+	if _, err := s.db.Exec("UPDATE collections SET auth_config_id = 0 WHERE auth_config_id = ?", id); err != nil {
+		return fmt.Errorf("failed to detach auth config from collections: %w", err)
+	}
+
+	_, err := s.db.Exec("DELETE FROM auth_configs WHERE id = ?", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete auth config: %w", err)
+	}
+	return nil
+}
+
+// GetAuthConfigByID gets an auth config by ID
+func (s *DBService) GetAuthConfigByID(id int) (models.AuthConfig, error) {
+	config, err := scanAuthConfig(s.db.QueryRow("SELECT "+authConfigColumns+" FROM auth_configs WHERE id = ?", id))
+	if err != nil {
+		return config, fmt.Errorf("failed to get auth config by ID: %w", err)
+	}
+	return config, nil
+}
+
+// GetAllAuthConfigs gets all auth configs
+func (s *DBService) GetAllAuthConfigs() ([]models.AuthConfig, error) {
+	rows, err := s.db.Query("SELECT " + authConfigColumns + " FROM auth_configs ORDER BY name")
+	if err != nil {
+		return nil, fmt.Errorf("failed to query auth configs: %w", err)
+	}
+	defer rows.Close()
+
+	var configs []models.AuthConfig
+	for rows.Next() {
+		config, err := scanAuthConfig(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan auth config row: %w", err)
+		}
+		configs = append(configs, config)
+	}
+
+	return configs, nil
+}
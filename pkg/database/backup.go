@@ -0,0 +1,64 @@
+package database
+
+import (
+	"fmt"
+
+	"flowpulse/pkg/models"
+)
+
+// GetExecutionLogsAfterID gets up to limit execution logs with an ID above afterID, oldest first,
+// to page through every log without holding them all in memory
+func (s *DBService) GetExecutionLogsAfterID(afterID, limit int) ([]models.ExecutionLog, error) {
+	rows, err := s.db.Query("SELECT "+executionLogColumns+" FROM execution_logs WHERE id > ? ORDER BY id LIMIT ?", afterID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query execution logs: %w", err)
+	}
+	defer rows.Close()
+
+	return scanExecutionLogs(rows)
+}
+
+// RestoreExecutionLogs inserts execution logs from a backup in one transaction, keeping when they ran.
+// Full responses aren't part of backups, so only the stored previews are restored.
+func (s *DBService) RestoreExecutionLogs(logs []models.ExecutionLog) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, log := range logs {
+		assertionResults, err := encodeJSONList(log.AssertionResults, "assertion results")
+		if err != nil {
+			return err
+		}
+		redirectChain, err := encodeJSONList(log.RedirectChain, "redirect chain")
+		if err != nil {
+			return err
+		}
+		triggers, err := encodeJSONList(log.Triggers, "triggers")
+		if err != nil {
+			return err
+		}
+		requestHeaders, responseHeaders, err := encodeLogHeaders(log)
+		if err != nil {
+			return err
+		}
+		if log.Location == "" {
+			log.Location = models.LocationLocal
+		}
+
+		_, err = tx.Exec(
+			"INSERT INTO execution_logs (api_id, schedule_id, status_code, status, response, error, duration_ms, location, assertion_results, collection_run_id, failure_phase, timed_out, latency_breach, content_changed, full_response, final_url, redirect_chain, triggers, request_headers, response_headers, executed_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, 0, ?, ?, ?, ?, ?, ?)",
+			log.APIID, log.ScheduleID, log.StatusCode, log.Status, log.Response, log.Error, log.DurationMs, log.Location, assertionResults, log.CollectionRunID, log.FailurePhase, log.TimedOut, log.LatencyBreach, log.ContentChanged, log.FinalURL, redirectChain, triggers, requestHeaders, responseHeaders, log.ExecutedAt,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to restore execution log: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit restored execution logs: %w", err)
+	}
+	return nil
+}
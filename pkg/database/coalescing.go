@@ -0,0 +1,36 @@
+package database
+
+import (
+	"fmt"
+	"strconv"
+
+	"flowpulse/pkg/models"
+)
+
+// settingCoalesceWindow is the settings key of the window in which triggers of the same API are coalesced
+const settingCoalesceWindow = "scheduler.coalesce_window_seconds"
+
+// GetTriggerCoalescing gets how triggers of the same API arriving close together are coalesced
+func (s *DBService) GetTriggerCoalescing() (models.TriggerCoalescing, error) {
+	var coalescing models.TriggerCoalescing
+	var err error
+	coalescing.WindowSeconds, err = s.getIntSetting(settingCoalesceWindow)
+	return coalescing, err
+}
+
+// SaveTriggerCoalescing saves how triggers of the same API arriving close together are coalesced
+func (s *DBService) SaveTriggerCoalescing(coalescing models.TriggerCoalescing) error {
+	return s.SetSetting(settingCoalesceWindow, strconv.Itoa(coalescing.WindowSeconds))
+}
+
+// SetExecutionTriggers records the triggers that shared an execution on its log
+func (s *DBService) SetExecutionTriggers(logID int, triggers []models.ExecutionTrigger) error {
+	encoded, err := encodeJSONList(triggers, "triggers")
+	if err != nil {
+		return err
+	}
+	if _, err := s.db.Exec("UPDATE execution_logs SET triggers = ? WHERE id = ?", encoded, logID); err != nil {
+		return fmt.Errorf("failed to record execution triggers: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,136 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+
+	"flowpulse/pkg/models"
+)
+
+// initCollectionRunTables creates the table of collection runs
+func (s *DBService) initCollectionRunTables() error {
+	_, err := s.db.Exec(`
+		CREATE TABLE IF NOT EXISTS collection_runs (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			collection_id INTEGER NOT NULL,
+			status TEXT NOT NULL,
+			total INTEGER NOT NULL DEFAULT 0,
+			success_count INTEGER NOT NULL DEFAULT 0,
+			failure_count INTEGER NOT NULL DEFAULT 0,
+			skipped_count INTEGER NOT NULL DEFAULT 0,
+			duration_ms INTEGER NOT NULL DEFAULT 0,
+			started_at TIMESTAMP NOT NULL,
+			finished_at TIMESTAMP,
+			FOREIGN KEY (collection_id) REFERENCES collections (id) ON DELETE CASCADE
+		)
+	`)
+	return err
+}
+
+// Collection Run Operations
+
+// collectionRunColumns is the column list matching scanCollectionRun
+const collectionRunColumns = "id, collection_id, status, total, success_count, failure_count, skipped_count, duration_ms, started_at, finished_at"
+
+// scanCollectionRun scans a single collection run selected with collectionRunColumns
+func scanCollectionRun(row rowScanner) (models.CollectionRun, error) {
+	var run models.CollectionRun
+	var finishedAt sql.NullTime
+	err := row.Scan(&run.ID, &run.CollectionID, &run.Status, &run.Total, &run.SuccessCount, &run.FailureCount,
+		&run.SkippedCount, &run.DurationMs, &run.StartedAt, &finishedAt)
+	if finishedAt.Valid {
+		run.FinishedAt = finishedAt.Time
+	}
+	return run, err
+}
+
+// CreateCollectionRun records the start of a collection run
+func (s *DBService) CreateCollectionRun(run models.CollectionRun) (models.CollectionRun, error) {
+	result, err := s.db.Exec(
+		"INSERT INTO collection_runs (collection_id, status, total, started_at) VALUES (?, ?, ?, ?)",
+		run.CollectionID, run.Status, run.Total, run.StartedAt,
+	)
+	if err != nil {
+		return run, fmt.Errorf("failed to create collection run: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return run, fmt.Errorf("failed to get last insert ID: %w", err)
+	}
+
+	run.ID = int(id)
+	return run, nil
+}
+
+// FinishCollectionRun stores the aggregated results of a completed collection run
+func (s *DBService) FinishCollectionRun(run models.CollectionRun) error {
+	_, err := s.db.Exec(
+		"UPDATE collection_runs SET status = ?, success_count = ?, failure_count = ?, skipped_count = ?, duration_ms = ?, finished_at = ? WHERE id = ?",
+		run.Status, run.SuccessCount, run.FailureCount, run.SkippedCount, run.DurationMs, run.FinishedAt, run.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to finish collection run: %w", err)
+	}
+	return nil
+}
+
+// GetCollectionRunByID gets a collection run by ID, including its execution logs in order
+func (s *DBService) GetCollectionRunByID(id int) (models.CollectionRun, error) {
+	run, err := scanCollectionRun(s.db.QueryRow("SELECT "+collectionRunColumns+" FROM collection_runs WHERE id = ?", id))
+	if err != nil {
+		return run, fmt.Errorf("failed to get collection run by ID: %w", err)
+	}
+
+	rows, err := s.db.Query("SELECT "+executionLogColumns+" FROM execution_logs WHERE collection_run_id = ? ORDER BY id", id)
+	if err != nil {
+		return run, fmt.Errorf("failed to query collection run executions: %w", err)
+	}
+	defer rows.Close()
+
+	run.Executions, err = s.scanAnnotatedExecutionLogs(rows)
+	return run, err
+}
+
+// GetCollectionRunsByCollectionID gets the most recent runs of a collection, without their execution logs
+func (s *DBService) GetCollectionRunsByCollectionID(collectionID int, limit int) ([]models.CollectionRun, error) {
+	rows, err := s.db.Query(
+		"SELECT "+collectionRunColumns+" FROM collection_runs WHERE collection_id = ? ORDER BY started_at DESC LIMIT ?",
+		collectionID, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query collection runs: %w", err)
+	}
+	defer rows.Close()
+
+	var runs []models.CollectionRun
+	for rows.Next() {
+		run, err := scanCollectionRun(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan collection run row: %w", err)
+		}
+		runs = append(runs, run)
+	}
+
+	return runs, nil
+}
+
+// SetCollectionOrder stores the run order of a collection's APIs; apiIDs lists them first to last
+func (s *DBService) SetCollectionOrder(collectionID int, apiIDs []int) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for i, apiID := range apiIDs {
+		if _, err := tx.Exec("UPDATE apis SET sort_order = ? WHERE id = ? AND collection_id = ?", i+1, apiID, collectionID); err != nil {
+			return fmt.Errorf("failed to update API order: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit API order: %w", err)
+	}
+	return nil
+}
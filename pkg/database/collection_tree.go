@@ -0,0 +1,114 @@
+package database
+
+import (
+	"fmt"
+	"time"
+
+	"flowpulse/pkg/models"
+)
+
+// collectionSubtree builds a recursive CTE named subtree holding the IDs of the collection given as its first
+// parameter and of the collections nested in it, descending only into those matching condition. UNION rather
+// than UNION ALL keeps a cycle from recursing forever.
+func collectionSubtree(condition string) string {
+	return `subtree(id) AS (
+		SELECT ?
+		UNION
+		SELECT collections.id FROM collections JOIN subtree ON collections.parent_id = subtree.id WHERE ` + condition + `
+	)`
+}
+
+// liveCollectionSubtree is the subtree of a collection through the nested collections not in the trash
+var liveCollectionSubtree = collectionSubtree("collections.deleted_at IS NULL")
+
+// MoveCollection nests a collection in another one, or moves it to the top level with parentID 0. The folders
+// and APIs nested in it move along. Moving a collection into itself or one of its own folders is refused.
+func (s *DBService) MoveCollection(id, parentID int) error {
+	collection, err := s.GetCollectionByID(id)
+	if err != nil {
+		return err
+	}
+	if collection.DeletedAt != nil {
+		return fmt.Errorf("collection %s is in the trash", collection.Name)
+	}
+
+	if parentID != 0 {
+		parent, err := s.GetCollectionByID(parentID)
+		if err != nil {
+			return err
+		}
+		if parent.DeletedAt != nil {
+			return fmt.Errorf("collection %s is in the trash", parent.Name)
+		}
+		// Folders in the trash count too, since restoring them would close the cycle
+		var nested bool
+		err = s.db.QueryRow("WITH RECURSIVE "+collectionSubtree("1")+" SELECT COUNT(*) FROM subtree WHERE id = ?", id, parentID).Scan(&nested)
+		if err != nil {
+			return fmt.Errorf("failed to check collection nesting: %w", err)
+		}
+		if nested {
+			return fmt.Errorf("cannot move collection %s into %s, which is nested in it", collection.Name, parent.Name)
+		}
+	}
+
+	_, err = s.db.Exec("UPDATE collections SET parent_id = ?, updated_at = ? WHERE id = ?", parentID, time.Now(), id)
+	if err != nil {
+		return fmt.Errorf("failed to move collection: %w", err)
+	}
+	return nil
+}
+
+// GetCollectionTree gets the collections not in the trash as a tree of folders, each with its APIs.
+// Collections whose parent is in the trash are at the top level.
+func (s *DBService) GetCollectionTree() ([]models.CollectionNode, error) {
+	// Starting from the top level leaves out any collections nested in a cycle, which would never end
+	const tree = `WITH RECURSIVE tree(id) AS (
+		SELECT id FROM collections
+		WHERE deleted_at IS NULL AND COALESCE(parent_id, 0) NOT IN (SELECT id FROM collections WHERE deleted_at IS NULL)
+		UNION
+		SELECT collections.id FROM collections JOIN tree ON collections.parent_id = tree.id WHERE collections.deleted_at IS NULL
+	) `
+
+	rows, err := s.db.Query(tree + "SELECT " + collectionColumns + " FROM collections WHERE id IN (SELECT id FROM tree) ORDER BY name")
+	if err != nil {
+		return nil, fmt.Errorf("failed to query collection tree: %w", err)
+	}
+	children := make(map[int][]models.Collection)
+	for rows.Next() {
+		collection, err := scanCollection(rows)
+		if err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan collection row: %w", err)
+		}
+		children[collection.ParentID] = append(children[collection.ParentID], collection)
+	}
+	rows.Close()
+
+	rows, err = s.db.Query(tree + "SELECT " + apiColumns + " FROM apis WHERE collection_id IN (SELECT id FROM tree) AND deleted_at IS NULL ORDER BY COALESCE(sort_order, 0), name")
+	if err != nil {
+		return nil, fmt.Errorf("failed to query collection tree APIs: %w", err)
+	}
+	defer rows.Close()
+	apis := make(map[int][]models.API)
+	for rows.Next() {
+		api, err := scanAPI(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan API row: %w", err)
+		}
+		apis[api.CollectionID] = append(apis[api.CollectionID], api)
+	}
+
+	var build func(parentID int) []models.CollectionNode
+	build = func(parentID int) []models.CollectionNode {
+		nodes := []models.CollectionNode{}
+		for _, collection := range children[parentID] {
+			node := models.CollectionNode{Collection: collection, Children: build(collection.ID), APIs: apis[collection.ID]}
+			if node.APIs == nil {
+				node.APIs = []models.API{}
+			}
+			nodes = append(nodes, node)
+		}
+		return nodes
+	}
+	return build(0), nil
+}
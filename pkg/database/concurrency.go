@@ -0,0 +1,35 @@
+package database
+
+import (
+	"strconv"
+
+	"flowpulse/pkg/models"
+)
+
+// Settings keys of the scheduler's concurrency limits
+const (
+	settingMaxConcurrent = "scheduler.max_concurrent"
+	settingMaxPerHost    = "scheduler.max_per_host"
+)
+
+// GetSchedulerConcurrency gets the limits on scheduled checks running at once
+func (s *DBService) GetSchedulerConcurrency() (models.SchedulerConcurrency, error) {
+	var concurrency models.SchedulerConcurrency
+	maxConcurrent, err := s.getIntSetting(settingMaxConcurrent)
+	if err != nil {
+		return concurrency, err
+	}
+	concurrency.MaxConcurrent = maxConcurrent
+	if concurrency.MaxPerHost, err = s.getIntSetting(settingMaxPerHost); err != nil {
+		return concurrency, err
+	}
+	return concurrency, nil
+}
+
+// SaveSchedulerConcurrency saves the limits on scheduled checks running at once
+func (s *DBService) SaveSchedulerConcurrency(concurrency models.SchedulerConcurrency) error {
+	if err := s.SetSetting(settingMaxConcurrent, strconv.Itoa(concurrency.MaxConcurrent)); err != nil {
+		return err
+	}
+	return s.SetSetting(settingMaxPerHost, strconv.Itoa(concurrency.MaxPerHost))
+}
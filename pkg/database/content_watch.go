@@ -0,0 +1,148 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"flowpulse/pkg/models"
+)
+
+// maxContentChanges is how many content changes are kept per API, so a page that changes on every check
+// doesn't fill the database
+const maxContentChanges = 100
+
+// initContentWatchTables creates the tables of content baselines and the changes detected against them
+func (s *DBService) initContentWatchTables() error {
+	_, err := s.db.Exec(`
+		CREATE TABLE IF NOT EXISTS content_baselines (
+			api_id INTEGER PRIMARY KEY,
+			mode TEXT NOT NULL,
+			fingerprint TEXT NOT NULL,
+			body TEXT NOT NULL,
+			captured_at TIMESTAMP NOT NULL,
+			FOREIGN KEY (api_id) REFERENCES apis (id) ON DELETE CASCADE
+		)
+	`)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.db.Exec(`
+		CREATE TABLE IF NOT EXISTS content_changes (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			api_id INTEGER NOT NULL,
+			execution_log_id INTEGER NOT NULL DEFAULT 0,
+			previous_fingerprint TEXT NOT NULL,
+			fingerprint TEXT NOT NULL,
+			previous_body TEXT NOT NULL,
+			body TEXT NOT NULL,
+			detected_at TIMESTAMP NOT NULL,
+			FOREIGN KEY (api_id) REFERENCES apis (id) ON DELETE CASCADE
+		)
+	`)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.db.Exec(`CREATE INDEX IF NOT EXISTS idx_content_changes_api ON content_changes (api_id, detected_at)`)
+	return err
+}
+
+// GetContentBaseline gets the content baseline of an API, reporting whether it has one
+func (s *DBService) GetContentBaseline(apiID int) (models.ContentBaseline, bool, error) {
+	var baseline models.ContentBaseline
+	err := s.db.QueryRow(
+		"SELECT api_id, mode, fingerprint, body, captured_at FROM content_baselines WHERE api_id = ?", apiID,
+	).Scan(&baseline.APIID, &baseline.Mode, &baseline.Fingerprint, &baseline.Body, &baseline.CapturedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return baseline, false, nil
+	}
+	if err != nil {
+		return baseline, false, fmt.Errorf("failed to get content baseline: %w", err)
+	}
+	return baseline, true, nil
+}
+
+// SetContentBaseline stores the content baseline of an API, replacing any it had
+func (s *DBService) SetContentBaseline(baseline models.ContentBaseline) error {
+	_, err := s.db.Exec(
+		`INSERT INTO content_baselines (api_id, mode, fingerprint, body, captured_at) VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT (api_id) DO UPDATE SET mode = excluded.mode, fingerprint = excluded.fingerprint, body = excluded.body, captured_at = excluded.captured_at`,
+		baseline.APIID, baseline.Mode, baseline.Fingerprint, baseline.Body, baseline.CapturedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to store content baseline: %w", err)
+	}
+	return nil
+}
+
+// DeleteContentBaseline deletes the content baseline of an API, so that its next check captures a new one
+func (s *DBService) DeleteContentBaseline(apiID int) error {
+	if _, err := s.db.Exec("DELETE FROM content_baselines WHERE api_id = ?", apiID); err != nil {
+		return fmt.Errorf("failed to delete content baseline: %w", err)
+	}
+	return nil
+}
+
+// CreateContentChange records a content change of an API, dropping its oldest changes beyond maxContentChanges
+func (s *DBService) CreateContentChange(change models.ContentChange) (models.ContentChange, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return change, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(
+		"INSERT INTO content_changes (api_id, execution_log_id, previous_fingerprint, fingerprint, previous_body, body, detected_at) VALUES (?, ?, ?, ?, ?, ?, ?)",
+		change.APIID, change.ExecutionLogID, change.PreviousFingerprint, change.Fingerprint, change.PreviousBody, change.Body, change.DetectedAt,
+	)
+	if err != nil {
+		return change, fmt.Errorf("failed to create content change: %w", err)
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return change, fmt.Errorf("failed to get last insert ID: %w", err)
+	}
+
+	_, err = tx.Exec(
+		`DELETE FROM content_changes WHERE api_id = ? AND id NOT IN (
+			SELECT id FROM content_changes WHERE api_id = ? ORDER BY detected_at DESC, id DESC LIMIT ?
+		)`,
+		change.APIID, change.APIID, maxContentChanges,
+	)
+	if err != nil {
+		return change, fmt.Errorf("failed to prune content changes: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return change, fmt.Errorf("failed to commit content change: %w", err)
+	}
+
+	change.ID = int(id)
+	return change, nil
+}
+
+// GetContentChanges gets the most recent content changes of an API, newest first
+func (s *DBService) GetContentChanges(apiID int, limit int) ([]models.ContentChange, error) {
+	rows, err := s.db.Query(
+		`SELECT id, api_id, execution_log_id, previous_fingerprint, fingerprint, previous_body, body, detected_at
+		FROM content_changes WHERE api_id = ? ORDER BY detected_at DESC, id DESC LIMIT ?`,
+		apiID, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query content changes: %w", err)
+	}
+	defer rows.Close()
+
+	changes := []models.ContentChange{}
+	for rows.Next() {
+		var change models.ContentChange
+		err := rows.Scan(&change.ID, &change.APIID, &change.ExecutionLogID, &change.PreviousFingerprint, &change.Fingerprint,
+			&change.PreviousBody, &change.Body, &change.DetectedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan content change row: %w", err)
+		}
+		changes = append(changes, change)
+	}
+	return changes, rows.Err()
+}
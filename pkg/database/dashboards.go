@@ -0,0 +1,112 @@
+package database
+
+import (
+	"fmt"
+	"time"
+
+	"flowpulse/pkg/models"
+)
+
+// initDashboardTables creates the dashboards table
+func (s *DBService) initDashboardTables() error {
+	_, err := s.db.Exec(`
+		CREATE TABLE IF NOT EXISTS dashboards (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT NOT NULL,
+			description TEXT NOT NULL DEFAULT '',
+			layout TEXT NOT NULL DEFAULT '',
+			sort_order INTEGER NOT NULL DEFAULT 0,
+			created_at TIMESTAMP NOT NULL,
+			updated_at TIMESTAMP NOT NULL
+		)
+	`)
+	return err
+}
+
+// dashboardColumns is the column list matching scanDashboard
+const dashboardColumns = "id, name, description, layout, sort_order, created_at, updated_at"
+
+// scanDashboard scans a single dashboard selected with dashboardColumns
+func scanDashboard(row rowScanner) (models.Dashboard, error) {
+	var dashboard models.Dashboard
+	err := row.Scan(&dashboard.ID, &dashboard.Name, &dashboard.Description, &dashboard.Layout, &dashboard.SortOrder,
+		&dashboard.CreatedAt, &dashboard.UpdatedAt)
+	return dashboard, err
+}
+
+// Dashboard Operations
+
+// CreateDashboard creates a new dashboard
+func (s *DBService) CreateDashboard(dashboard models.Dashboard) (models.Dashboard, error) {
+	now := time.Now()
+	dashboard.CreatedAt = now
+	dashboard.UpdatedAt = now
+
+	result, err := s.db.Exec(
+		"INSERT INTO dashboards (name, description, layout, sort_order, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?)",
+		dashboard.Name, dashboard.Description, dashboard.Layout, dashboard.SortOrder, dashboard.CreatedAt, dashboard.UpdatedAt,
+	)
+	if err != nil {
+		return dashboard, fmt.Errorf("failed to create dashboard: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return dashboard, fmt.Errorf("failed to get last insert ID: %w", err)
+	}
+
+	dashboard.ID = int(id)
+	return dashboard, nil
+}
+
+// UpdateDashboard updates an existing dashboard
+func (s *DBService) UpdateDashboard(dashboard models.Dashboard) (models.Dashboard, error) {
+	dashboard.UpdatedAt = time.Now()
+
+	_, err := s.db.Exec(
+		"UPDATE dashboards SET name = ?, description = ?, layout = ?, sort_order = ?, updated_at = ? WHERE id = ?",
+		dashboard.Name, dashboard.Description, dashboard.Layout, dashboard.SortOrder, dashboard.UpdatedAt, dashboard.ID,
+	)
+	if err != nil {
+		return dashboard, fmt.Errorf("failed to update dashboard: %w", err)
+	}
+	return s.GetDashboardByID(dashboard.ID)
+}
+
+// DeleteDashboard deletes a dashboard by ID
+func (s *DBService) DeleteDashboard(id int) error {
+	_, err := s.db.Exec("DELETE FROM dashboards WHERE id = ?", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete dashboard: %w", err)
+	}
+	return nil
+}
+
+// GetDashboardByID gets a dashboard by ID
+func (s *DBService) GetDashboardByID(id int) (models.Dashboard, error) {
+	dashboard, err := scanDashboard(s.db.QueryRow("SELECT "+dashboardColumns+" FROM dashboards WHERE id = ?", id))
+	if err != nil {
+		return dashboard, fmt.Errorf("failed to get dashboard by ID: %w", err)
+	}
+	return dashboard, nil
+}
+
+// GetAllDashboards gets all dashboards in their display order
+func (s *DBService) GetAllDashboards() ([]models.Dashboard, error) {
+	rows, err := s.db.Query("SELECT " + dashboardColumns + " FROM dashboards ORDER BY sort_order, id")
+	if err != nil {
+		return nil, fmt.Errorf("failed to query dashboards: %w", err)
+	}
+	defer rows.Close()
+
+	var dashboards []models.Dashboard
+	for rows.Next() {
+		dashboard, err := scanDashboard(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan dashboard row: %w", err)
+		}
+		dashboards = append(dashboards, dashboard)
+	}
+
+	return dashboards, nil
+}
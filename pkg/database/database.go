@@ -2,9 +2,12 @@ package database
 
 import (
 	"database/sql"
+	"encoding/json"
 	"fmt"
+	"math"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	_ "github.com/mattn/go-sqlite3"
@@ -14,29 +17,38 @@ import (
 
 // DBService handles all database operations
 type DBService struct {
-	db *sql.DB
+	db          *sql.DB
+	path        string
+	stopPruning chan struct{} // Closed to stop the background pruning job
 }
 
-// NewDBService creates a new database service
+// NewDBService creates a new database service using the default database in the app directory
 func NewDBService() (*DBService, error) {
-	// Get application directory
-	homeDir, err := os.UserHomeDir()
+	dbPath, err := DefaultDBPath()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get user home directory: %w", err)
+		return nil, err
 	}
+	return NewDBServiceWithPath(dbPath)
+}
 
-	appDir := filepath.Join(homeDir, ".flowpulse")
-	if err := os.MkdirAll(appDir, 0755); err != nil {
-		return nil, fmt.Errorf("failed to create app directory: %w", err)
+// NewDBServiceWithPath creates a new database service using the database at the given path,
+// creating the file and its directory if needed. It returns a *CorruptionError when the
+// database fails its integrity check.
+func NewDBServiceWithPath(dbPath string) (*DBService, error) {
+	if err := os.MkdirAll(filepath.Dir(dbPath), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create database directory: %w", err)
 	}
 
-	dbPath := filepath.Join(appDir, "flowpulse.db")
 	db, err := sql.Open("sqlite3", dbPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
 
-	service := &DBService{db: db}
+	service := &DBService{db: db, path: dbPath}
+	if err := service.checkIntegrity(); err != nil {
+		db.Close()
+		return nil, err
+	}
 	if err := service.initDB(); err != nil {
 		db.Close()
 		return nil, fmt.Errorf("failed to initialize database: %w", err)
@@ -45,8 +57,75 @@ func NewDBService() (*DBService, error) {
 	return service, nil
 }
 
+// DefaultDBPath returns the path of the database used when no other one is configured
+func DefaultDBPath() (string, error) {
+	appDir, err := AppDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(appDir, "flowpulse.db"), nil
+}
+
+// DataDirEnv is the environment variable overriding the directory holding FlowPulse's data
+const DataDirEnv = "FLOWPULSE_HOME"
+
+// PortableDataDir is the directory next to the executable that, when it exists, holds FlowPulse's data,
+// e.g. for an install on a USB stick
+const PortableDataDir = "flowpulse-data"
+
+// AppDir returns the directory holding FlowPulse's data, creating it if needed. It is the directory
+// named by FLOWPULSE_HOME when set, otherwise the portable data directory next to the executable when
+// it exists, otherwise ~/.flowpulse.
+func AppDir() (string, error) {
+	appDir, err := dataDir()
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(appDir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create app directory: %w", err)
+	}
+	return appDir, nil
+}
+
+// dataDir resolves the directory holding FlowPulse's data without creating it
+func dataDir() (string, error) {
+	if dir := os.Getenv(DataDirEnv); dir != "" {
+		if rest, ok := strings.CutPrefix(dir, "~/"); ok {
+			homeDir, err := os.UserHomeDir()
+			if err != nil {
+				return "", fmt.Errorf("failed to get user home directory: %w", err)
+			}
+			dir = filepath.Join(homeDir, rest)
+		}
+		abs, err := filepath.Abs(dir)
+		if err != nil {
+			return "", fmt.Errorf("invalid %s: %w", DataDirEnv, err)
+		}
+		return abs, nil
+	}
+
+	if exe, err := os.Executable(); err == nil {
+		if exe, err = filepath.EvalSymlinks(exe); err == nil {
+			portable := filepath.Join(filepath.Dir(exe), PortableDataDir)
+			if info, err := os.Stat(portable); err == nil && info.IsDir() {
+				return portable, nil
+			}
+		}
+	}
+
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get user home directory: %w", err)
+	}
+	return filepath.Join(homeDir, ".flowpulse"), nil
+}
+
 // Close closes the database connection
 func (s *DBService) Close() error {
+	if s.stopPruning != nil {
+		close(s.stopPruning)
+		s.stopPruning = nil
+	}
 	return s.db.Close()
 }
 
@@ -69,22 +148,167 @@ func (s *DBService) initDB() error {
 	if err != nil {
 		return err
 	}
-	
-	// Check if collection_id column exists in apis table, and add it if not
-	var columnExists bool
-	err = s.db.QueryRow("SELECT COUNT(*) FROM pragma_table_info('apis') WHERE name = 'collection_id'").Scan(&columnExists)
-	if err != nil {
-		return fmt.Errorf("failed to check for collection_id column: %w", err)
+
+	// Add collection_id column to apis table if it doesn't exist yet
+	if err := s.addColumnIfMissing("apis", "collection_id", "INTEGER DEFAULT 0"); err != nil {
+		return err
 	}
-	
-	if !columnExists {
-		// Add collection_id column to apis table
-		_, err = s.db.Exec("ALTER TABLE apis ADD COLUMN collection_id INTEGER DEFAULT 0")
-		if err != nil {
-			return fmt.Errorf("failed to add collection_id column: %w", err)
-		}
+
+	// Add expected_outcome column for negative monitoring
+	if err := s.addColumnIfMissing("apis", "expected_outcome", "TEXT DEFAULT 'success'"); err != nil {
+		return err
+	}
+
+	// Add status code columns overriding the default 2xx success rule
+	if err := s.addColumnIfMissing("apis", "success_codes", "TEXT DEFAULT ''"); err != nil {
+		return err
+	}
+	if err := s.addColumnIfMissing("apis", "degraded_codes", "TEXT DEFAULT ''"); err != nil {
+		return err
+	}
+
+	// Add structured query parameter and path variable columns
+	if err := s.addColumnIfMissing("apis", "query_params", "TEXT DEFAULT ''"); err != nil {
+		return err
+	}
+	if err := s.addColumnIfMissing("apis", "path_params", "TEXT DEFAULT ''"); err != nil {
+		return err
+	}
+
+	// Add body type columns for form and multipart bodies
+	if err := s.addColumnIfMissing("apis", "body_type", "TEXT DEFAULT 'raw'"); err != nil {
+		return err
+	}
+	if err := s.addColumnIfMissing("apis", "form_fields", "TEXT DEFAULT ''"); err != nil {
+		return err
+	}
+
+	// Add GraphQL columns, stored apart from the body they are composed into
+	if err := s.addColumnIfMissing("apis", "graphql_query", "TEXT DEFAULT ''"); err != nil {
+		return err
+	}
+	if err := s.addColumnIfMissing("apis", "graphql_variables", "TEXT DEFAULT ''"); err != nil {
+		return err
+	}
+	if err := s.addColumnIfMissing("apis", "graphql_operation_name", "TEXT DEFAULT ''"); err != nil {
+		return err
+	}
+
+	// Add check_type column for checks over other protocols than HTTP
+	if err := s.addColumnIfMissing("apis", "check_type", "TEXT DEFAULT 'http'"); err != nil {
+		return err
+	}
+	if err := s.addColumnIfMissing("apis", "dns_record_type", "TEXT DEFAULT ''"); err != nil {
+		return err
 	}
-	
+	if err := s.addColumnIfMissing("apis", "dns_expected", "TEXT DEFAULT ''"); err != nil {
+		return err
+	}
+
+	// Add variables column for variables scoped to an API
+	if err := s.addColumnIfMissing("apis", "variables", "TEXT DEFAULT ''"); err != nil {
+		return err
+	}
+
+	// Add proxy columns choosing how an API's requests are proxied
+	if err := s.addColumnIfMissing("apis", "proxy_mode", "TEXT DEFAULT 'global'"); err != nil {
+		return err
+	}
+	if err := s.addColumnIfMissing("apis", "proxy", "TEXT DEFAULT ''"); err != nil {
+		return err
+	}
+
+	// Add tls column holding an API's TLS options
+	if err := s.addColumnIfMissing("apis", "tls", "TEXT DEFAULT ''"); err != nil {
+		return err
+	}
+
+	// Add redirect columns choosing whether and how far an API's redirects are followed
+	if err := s.addColumnIfMissing("apis", "redirect_policy", "TEXT DEFAULT 'follow'"); err != nil {
+		return err
+	}
+	if err := s.addColumnIfMissing("apis", "max_redirects", "INTEGER DEFAULT 0"); err != nil {
+		return err
+	}
+
+	// Add overlap_policy column choosing what happens to scheduled runs of an API whose check is still going
+	if err := s.addColumnIfMissing("apis", "overlap_policy", "TEXT DEFAULT 'queue'"); err != nil {
+		return err
+	}
+
+	// Add deprecation columns flagging APIs whose endpoint is sunset after a date
+	if err := s.addColumnIfMissing("apis", "deprecated_after", "TIMESTAMP"); err != nil {
+		return err
+	}
+	if err := s.addColumnIfMissing("apis", "auto_disable", "BOOLEAN DEFAULT 0"); err != nil {
+		return err
+	}
+
+	// Add runbook columns giving whoever is alerted about an API its remediation steps
+	if err := s.addColumnIfMissing("apis", "runbook_url", "TEXT DEFAULT ''"); err != nil {
+		return err
+	}
+	if err := s.addColumnIfMissing("apis", "notes", "TEXT DEFAULT ''"); err != nil {
+		return err
+	}
+
+	// Add log_request_headers column keeping the headers an API's requests were sent with in its logs
+	if err := s.addColumnIfMissing("apis", "log_request_headers", "BOOLEAN DEFAULT 0"); err != nil {
+		return err
+	}
+
+	// Add dns_server column holding the DNS server an API's checks resolve host names with
+	if err := s.addColumnIfMissing("apis", "dns_server", "TEXT DEFAULT ''"); err != nil {
+		return err
+	}
+
+	// Add latency threshold columns above which an API's checks are degraded
+	if err := s.addColumnIfMissing("apis", "latency_warn_ms", "INTEGER DEFAULT 0"); err != nil {
+		return err
+	}
+	if err := s.addColumnIfMissing("apis", "latency_critical_ms", "INTEGER DEFAULT 0"); err != nil {
+		return err
+	}
+
+	// Add log_policy column to control which executions are stored
+	if err := s.addColumnIfMissing("apis", "log_policy", "TEXT DEFAULT 'all'"); err != nil {
+		return err
+	}
+
+	// Add OpenAPI spec columns for contract validation
+	if err := s.addColumnIfMissing("apis", "spec_id", "INTEGER DEFAULT 0"); err != nil {
+		return err
+	}
+	if err := s.addColumnIfMissing("apis", "spec_operation", "TEXT DEFAULT ''"); err != nil {
+		return err
+	}
+	if err := s.addColumnIfMissing("apis", "validate_contract", "BOOLEAN DEFAULT 0"); err != nil {
+		return err
+	}
+	if err := s.initSpecTables(); err != nil {
+		return err
+	}
+
+	// Add sort_order column so collections can run as an ordered sequence
+	if err := s.addColumnIfMissing("apis", "sort_order", "INTEGER DEFAULT 0"); err != nil {
+		return err
+	}
+
+	// Add step_delay_ms column delaying an API's step in collection runs
+	if err := s.addColumnIfMissing("apis", "step_delay_ms", "INTEGER DEFAULT 0"); err != nil {
+		return err
+	}
+
+	// Add content_watch column comparing an API's response bodies with a baseline
+	if err := s.addColumnIfMissing("apis", "content_watch", "TEXT DEFAULT ''"); err != nil {
+		return err
+	}
+
+	// Add auth_config_id column so APIs can authenticate their requests
+	if err := s.addColumnIfMissing("apis", "auth_config_id", "INTEGER DEFAULT 0"); err != nil {
+		return err
+	}
+
 	// Create Collections table
 	_, err = s.db.Exec(`
 		CREATE TABLE IF NOT EXISTS collections (
@@ -99,6 +323,64 @@ func (s *DBService) initDB() error {
 		return err
 	}
 
+	// Add environment_id column to collections table if it doesn't exist yet
+	if err := s.addColumnIfMissing("collections", "environment_id", "INTEGER DEFAULT 0"); err != nil {
+		return err
+	}
+
+	// Add latency_budget_ms column to collections table if it doesn't exist yet
+	if err := s.addColumnIfMissing("collections", "latency_budget_ms", "INTEGER DEFAULT 0"); err != nil {
+		return err
+	}
+
+	// Add stop_on_failure column to collections table if it doesn't exist yet
+	if err := s.addColumnIfMissing("collections", "stop_on_failure", "BOOLEAN DEFAULT 0"); err != nil {
+		return err
+	}
+
+	// Add throttling columns for collection runs
+	if err := s.addColumnIfMissing("collections", "max_parallel", "INTEGER DEFAULT 0"); err != nil {
+		return err
+	}
+	if err := s.addColumnIfMissing("collections", "step_delay_ms", "INTEGER DEFAULT 0"); err != nil {
+		return err
+	}
+
+	// Add auth_config_id column to collections table if it doesn't exist yet
+	if err := s.addColumnIfMissing("collections", "auth_config_id", "INTEGER DEFAULT 0"); err != nil {
+		return err
+	}
+
+	// Add variables column for variables scoped to a collection
+	if err := s.addColumnIfMissing("collections", "variables", "TEXT DEFAULT ''"); err != nil {
+		return err
+	}
+
+	// Add latency_targets column holding a collection's latency SLAs
+	if err := s.addColumnIfMissing("collections", "latency_targets", "TEXT DEFAULT ''"); err != nil {
+		return err
+	}
+
+	// Add deleted_at columns keeping deleted APIs and collections in the trash until it is emptied
+	for _, table := range []string{"apis", "collections"} {
+		if err := s.addColumnIfMissing(table, "deleted_at", "TIMESTAMP"); err != nil {
+			return err
+		}
+	}
+
+	// Add parent_id column nesting collections in folders (0 for top level)
+	if err := s.addColumnIfMissing("collections", "parent_id", "INTEGER DEFAULT 0"); err != nil {
+		return err
+	}
+	if err := s.initAuthTables(); err != nil {
+		return err
+	}
+
+	// Create Environments table
+	if err := s.initEnvironmentsTables(); err != nil {
+		return err
+	}
+
 	// Create Schedules table
 	_, err = s.db.Exec(`
 		CREATE TABLE IF NOT EXISTS schedules (
@@ -118,6 +400,31 @@ func (s *DBService) initDB() error {
 		return err
 	}
 
+	// Add retry policy columns controlling how retry delays grow
+	if err := s.addColumnIfMissing("schedules", "retry_strategy", "TEXT DEFAULT 'fixed'"); err != nil {
+		return err
+	}
+	if err := s.addColumnIfMissing("schedules", "retry_multiplier", "REAL DEFAULT 0"); err != nil {
+		return err
+	}
+	if err := s.addColumnIfMissing("schedules", "retry_max_delay", "INTEGER DEFAULT 0"); err != nil {
+		return err
+	}
+	if err := s.addColumnIfMissing("schedules", "retry_jitter", "REAL DEFAULT 0"); err != nil {
+		return err
+	}
+	if err := s.addColumnIfMissing("schedules", "timezone", "TEXT DEFAULT ''"); err != nil {
+		return err
+	}
+
+	// Add after columns linking an "after" schedule to the schedule whose runs fire it
+	if err := s.addColumnIfMissing("schedules", "after_id", "INTEGER DEFAULT 0"); err != nil {
+		return err
+	}
+	if err := s.addColumnIfMissing("schedules", "after_outcome", "TEXT DEFAULT ''"); err != nil {
+		return err
+	}
+
 	// Create Execution Logs table
 	_, err = s.db.Exec(`
 		CREATE TABLE IF NOT EXISTS execution_logs (
@@ -133,9 +440,218 @@ func (s *DBService) initDB() error {
 		)
 	`)
 	if err != nil {
-		return err
+		return err
+	}
+
+	// Round-trip duration is left NULL for logs written before it was tracked
+	if err := s.addColumnIfMissing("execution_logs", "duration_ms", "INTEGER"); err != nil {
+		return err
+	}
+
+	// The evaluated outcome of each execution, backfilled from the status code for older logs
+	if err := s.addColumnIfMissing("execution_logs", "status", "TEXT"); err != nil {
+		return err
+	}
+	// Location of the machine or agent that ran the check
+	if err := s.addColumnIfMissing("execution_logs", "location", "TEXT DEFAULT 'local'"); err != nil {
+		return err
+	}
+
+	// Per-assertion results stored as JSON
+	if err := s.addColumnIfMissing("execution_logs", "assertion_results", "TEXT"); err != nil {
+		return err
+	}
+
+	// Add collection_run_id column to group the executions of a collection run
+	if err := s.addColumnIfMissing("execution_logs", "collection_run_id", "INTEGER DEFAULT 0"); err != nil {
+		return err
+	}
+
+	// Add columns recording where a failed request stopped and whether it timed out
+	if err := s.addColumnIfMissing("execution_logs", "failure_phase", "TEXT DEFAULT ''"); err != nil {
+		return err
+	}
+	if err := s.addColumnIfMissing("execution_logs", "timed_out", "BOOLEAN DEFAULT 0"); err != nil {
+		return err
+	}
+
+	// Add latency_breach column recording which latency threshold of the API a response exceeded
+	if err := s.addColumnIfMissing("execution_logs", "latency_breach", "TEXT DEFAULT ''"); err != nil {
+		return err
+	}
+
+	// Add content_changed column flagging responses that differed from their API's content baseline
+	if err := s.addColumnIfMissing("execution_logs", "content_changed", "BOOLEAN DEFAULT 0"); err != nil {
+		return err
+	}
+
+	// Add full_response column marking logs whose response is stored in full apart from the row
+	if err := s.addColumnIfMissing("execution_logs", "full_response", "BOOLEAN DEFAULT 0"); err != nil {
+		return err
+	}
+
+	// Add redirect columns recording where redirects led a request
+	if err := s.addColumnIfMissing("execution_logs", "final_url", "TEXT DEFAULT ''"); err != nil {
+		return err
+	}
+	if err := s.addColumnIfMissing("execution_logs", "redirect_chain", "TEXT DEFAULT ''"); err != nil {
+		return err
+	}
+
+	// Add triggers column listing the triggers coalesced into one execution
+	if err := s.addColumnIfMissing("execution_logs", "triggers", "TEXT DEFAULT ''"); err != nil {
+		return err
+	}
+
+	// Add header columns holding the headers of the request and response as JSON
+	for _, column := range []string{"request_headers", "response_headers"} {
+		if err := s.addColumnIfMissing("execution_logs", column, "TEXT DEFAULT ''"); err != nil {
+			return err
+		}
+	}
+	if err := s.initResponseBodyTables(); err != nil {
+		return err
+	}
+	if err := s.initCollectionRunTables(); err != nil {
+		return err
+	}
+
+	// Create Assertions table
+	if err := s.initAssertionsTables(); err != nil {
+		return err
+	}
+
+	// Create extractions table for request chaining
+	if err := s.initExtractionsTables(); err != nil {
+		return err
+	}
+
+	_, err = s.db.Exec(`
+		UPDATE execution_logs
+		SET status = CASE WHEN status_code >= 200 AND status_code < 300 THEN 'success' ELSE 'failure' END
+		WHERE status IS NULL
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to backfill execution log status: %w", err)
+	}
+
+	// Create notification tables
+	if err := s.initNotifyTables(); err != nil {
+		return err
+	}
+
+	// Create on-call rotation tables
+	if err := s.initOnCallTables(); err != nil {
+		return err
+	}
+
+	// Create incident tracking tables
+	if err := s.initIncidentTables(); err != nil {
+		return err
+	}
+
+	// Create settings table
+	if err := s.initSettingsTables(); err != nil {
+		return err
+	}
+
+	// Create sync configuration table
+	if err := s.initSyncTables(); err != nil {
+		return err
+	}
+
+	// Create secrets table
+	if err := s.initSecretsTables(); err != nil {
+		return err
+	}
+
+	// Add validation_api_id column naming the API that checks a rotated secret
+	if err := s.addColumnIfMissing("secrets", "validation_api_id", "INTEGER DEFAULT 0"); err != nil {
+		return err
+	}
+
+	// Create scheduler metrics table
+	if err := s.initSchedulerMetricsTables(); err != nil {
+		return err
+	}
+
+	// Create execution log and incident annotations table
+	if err := s.initAnnotationTables(); err != nil {
+		return err
+	}
+
+	// Create maintenance windows table
+	if err := s.initMaintenanceTables(); err != nil {
+		return err
+	}
+
+	// Create expected failure windows table
+	if err := s.initExpectedFailureTables(); err != nil {
+		return err
+	}
+
+	// Create dashboards table
+	if err := s.initDashboardTables(); err != nil {
+		return err
+	}
+
+	// Create saved log filters table
+	if err := s.initLogFilterTables(); err != nil {
+		return err
+	}
+
+	// Create webhook triggers table
+	if err := s.initWebhookTables(); err != nil {
+		return err
+	}
+
+	// Create status pages table
+	if err := s.initStatusPageTables(); err != nil {
+		return err
+	}
+
+	// Create tags tables
+	if err := s.initTagTables(); err != nil {
+		return err
+	}
+
+	// Create full-text search indexes over APIs, collections and execution logs
+	if err := s.initSearchTables(); err != nil {
+		return err
+	}
+
+	// Create hourly latency rollups of each API
+	if err := s.initLatencyRollupTables(); err != nil {
+		return err
+	}
+
+	// Create content baselines and the changes detected against them
+	if err := s.initContentWatchTables(); err != nil {
+		return err
+	}
+
+	// Add UUIDs identifying collections, APIs, schedules, notification channels and alert rules across devices
+	if err := s.initUUIDColumns(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// addColumnIfMissing adds a column to an existing table when an older database doesn't have it yet
+func (s *DBService) addColumnIfMissing(table, column, definition string) error {
+	var columnExists bool
+	err := s.db.QueryRow("SELECT COUNT(*) FROM pragma_table_info(?) WHERE name = ?", table, column).Scan(&columnExists)
+	if err != nil {
+		return fmt.Errorf("failed to check for %s column: %w", column, err)
 	}
 
+	if !columnExists {
+		_, err = s.db.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", table, column, definition))
+		if err != nil {
+			return fmt.Errorf("failed to add %s column: %w", column, err)
+		}
+	}
 	return nil
 }
 
@@ -146,10 +662,18 @@ func (s *DBService) CreateAPI(api models.API) (models.API, error) {
 	now := time.Now()
 	api.CreatedAt = now
 	api.UpdatedAt = now
+	if api.UUID == "" {
+		api.UUID = NewUUID()
+	}
+	lists, err := encodeAPILists(api)
+	if err != nil {
+		return api, err
+	}
 
 	result, err := s.db.Exec(
-		"INSERT INTO apis (name, method, url, headers, body, description, collection_id, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)",
-		api.Name, api.Method, api.URL, api.Headers, api.Body, api.Description, api.CollectionID, api.CreatedAt, api.UpdatedAt,
+		`INSERT INTO apis (uuid, name, method, url, headers, body, description, collection_id, expected_outcome, log_policy, spec_id, spec_operation, validate_contract, auth_config_id, success_codes, degraded_codes, query_params, path_params, body_type, form_fields, check_type, graphql_query, graphql_variables, graphql_operation_name, dns_record_type, dns_expected, variables, proxy_mode, proxy, tls, redirect_policy, max_redirects, overlap_policy, deprecated_after, auto_disable, runbook_url, notes, log_request_headers, dns_server, latency_warn_ms, latency_critical_ms, step_delay_ms, content_watch, sort_order, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, (SELECT COALESCE(MAX(sort_order), 0) + 1 FROM apis WHERE collection_id = ?), ?, ?)`,
+		api.UUID, api.Name, api.Method, api.URL, api.Headers, api.Body, api.Description, api.CollectionID, api.ExpectedOutcome, api.LogPolicy, api.SpecID, api.SpecOperation, api.ValidateContract, api.AuthConfigID, api.SuccessCodes, api.DegradedCodes, lists.queryParams, lists.pathParams, api.BodyType, lists.formFields, api.CheckType, api.GraphQLQuery, api.GraphQLVariables, api.GraphQLOperationName, api.DNSRecordType, api.DNSExpected, api.Variables, api.ProxyMode, lists.proxy, lists.tls, api.RedirectPolicy, api.MaxRedirects, api.OverlapPolicy, api.DeprecatedAfter, api.AutoDisable, api.RunbookURL, api.Notes, api.LogRequestHeaders, api.DNSServer, api.LatencyWarnMs, api.LatencyCriticalMs, api.StepDelayMs, api.ContentWatch, api.CollectionID, api.CreatedAt, api.UpdatedAt,
 	)
 	if err != nil {
 		return api, fmt.Errorf("failed to create API: %w", err)
@@ -167,10 +691,14 @@ func (s *DBService) CreateAPI(api models.API) (models.API, error) {
 // UpdateAPI updates an existing API
 func (s *DBService) UpdateAPI(api models.API) (models.API, error) {
 	api.UpdatedAt = time.Now()
+	lists, err := encodeAPILists(api)
+	if err != nil {
+		return api, err
+	}
 
-	_, err := s.db.Exec(
-		"UPDATE apis SET name = ?, method = ?, url = ?, headers = ?, body = ?, description = ?, collection_id = ?, updated_at = ? WHERE id = ?",
-		api.Name, api.Method, api.URL, api.Headers, api.Body, api.Description, api.CollectionID, api.UpdatedAt, api.ID,
+	_, err = s.db.Exec(
+		"UPDATE apis SET name = ?, method = ?, url = ?, headers = ?, body = ?, description = ?, collection_id = ?, expected_outcome = ?, log_policy = ?, spec_id = ?, spec_operation = ?, validate_contract = ?, auth_config_id = ?, success_codes = ?, degraded_codes = ?, query_params = ?, path_params = ?, body_type = ?, form_fields = ?, check_type = ?, graphql_query = ?, graphql_variables = ?, graphql_operation_name = ?, dns_record_type = ?, dns_expected = ?, variables = ?, proxy_mode = ?, proxy = ?, tls = ?, redirect_policy = ?, max_redirects = ?, overlap_policy = ?, deprecated_after = ?, auto_disable = ?, runbook_url = ?, notes = ?, log_request_headers = ?, dns_server = ?, latency_warn_ms = ?, latency_critical_ms = ?, step_delay_ms = ?, content_watch = ?, updated_at = ? WHERE id = ?",
+		api.Name, api.Method, api.URL, api.Headers, api.Body, api.Description, api.CollectionID, api.ExpectedOutcome, api.LogPolicy, api.SpecID, api.SpecOperation, api.ValidateContract, api.AuthConfigID, api.SuccessCodes, api.DegradedCodes, lists.queryParams, lists.pathParams, api.BodyType, lists.formFields, api.CheckType, api.GraphQLQuery, api.GraphQLVariables, api.GraphQLOperationName, api.DNSRecordType, api.DNSExpected, api.Variables, api.ProxyMode, lists.proxy, lists.tls, api.RedirectPolicy, api.MaxRedirects, api.OverlapPolicy, api.DeprecatedAfter, api.AutoDisable, api.RunbookURL, api.Notes, api.LogRequestHeaders, api.DNSServer, api.LatencyWarnMs, api.LatencyCriticalMs, api.StepDelayMs, api.ContentWatch, api.UpdatedAt, api.ID,
 	)
 	if err != nil {
 		return api, fmt.Errorf("failed to update API: %w", err)
@@ -185,57 +713,147 @@ func (s *DBService) UpdateAPI(api models.API) (models.API, error) {
 	return updatedAPI, nil
 }
 
-// DeleteAPI deletes an API by ID
+// DeleteAPI moves an API to the trash, hiding it and its schedules until it is restored or the trash is emptied
 func (s *DBService) DeleteAPI(id int) error {
-	_, err := s.db.Exec("DELETE FROM apis WHERE id = ?", id)
-	if err != nil {
+	if err := s.trashRow("apis", id); err != nil {
 		return fmt.Errorf("failed to delete API: %w", err)
 	}
 	return nil
 }
 
-// GetAPIByID gets an API by ID
-func (s *DBService) GetAPIByID(id int) (models.API, error) {
+// apiCollectionID is the collection of an API, 0 when it is ungrouped or its collection is in the trash
+const apiCollectionID = "COALESCE((SELECT collections.id FROM collections WHERE collections.id = apis.collection_id AND collections.deleted_at IS NULL), 0)"
+
+// apiColumns is the column list matching scanAPI, selected from the apis table.
+// COALESCE keeps the queries resilient for columns added to older databases.
+// APIs of a collection in the trash read as ungrouped until it is restored.
+const apiColumns = `id, COALESCE(uuid, ''), name, method, url, headers, body, description,
+	` + apiCollectionID + `, COALESCE(expected_outcome, ''), COALESCE(log_policy, ''),
+	COALESCE(spec_id, 0), COALESCE(spec_operation, ''), COALESCE(validate_contract, 0),
+	COALESCE(sort_order, 0), COALESCE(auth_config_id, 0),
+	COALESCE(success_codes, ''), COALESCE(degraded_codes, ''),
+	COALESCE(query_params, ''), COALESCE(path_params, ''),
+	COALESCE(body_type, ''), COALESCE(form_fields, ''), COALESCE(check_type, ''),
+	COALESCE(graphql_query, ''), COALESCE(graphql_variables, ''), COALESCE(graphql_operation_name, ''),
+	COALESCE(dns_record_type, ''), COALESCE(dns_expected, ''), COALESCE(variables, ''),
+	COALESCE(proxy_mode, ''), COALESCE(proxy, ''), COALESCE(tls, ''),
+	COALESCE(redirect_policy, ''), COALESCE(max_redirects, 0), COALESCE(overlap_policy, ''),
+	deprecated_after, COALESCE(auto_disable, 0), COALESCE(runbook_url, ''), COALESCE(notes, ''), COALESCE(log_request_headers, 0), COALESCE(dns_server, ''),
+	COALESCE(latency_warn_ms, 0), COALESCE(latency_critical_ms, 0), COALESCE(step_delay_ms, 0), COALESCE(content_watch, ''), deleted_at, created_at, updated_at`
+
+// scanAPI scans a single API selected with apiColumns
+func scanAPI(row rowScanner) (models.API, error) {
 	var api models.API
-	
-	// Use a more resilient query that handles potential missing collection_id column
-	err := s.db.QueryRow(`
-		SELECT 
-			id, name, method, url, headers, body, description, 
-			COALESCE(collection_id, 0) as collection_id, 
-			created_at, updated_at 
-		FROM apis WHERE id = ?`,
-		id,
-	).Scan(
-		&api.ID, &api.Name, &api.Method, &api.URL, &api.Headers, &api.Body, 
-		&api.Description, &api.CollectionID, &api.CreatedAt, &api.UpdatedAt,
+	var lists apiLists
+	err := row.Scan(
+		&api.ID, &api.UUID, &api.Name, &api.Method, &api.URL, &api.Headers, &api.Body, &api.Description,
+		&api.CollectionID, &api.ExpectedOutcome, &api.LogPolicy,
+		&api.SpecID, &api.SpecOperation, &api.ValidateContract,
+		&api.SortOrder, &api.AuthConfigID,
+		&api.SuccessCodes, &api.DegradedCodes,
+		&lists.queryParams, &lists.pathParams,
+		&api.BodyType, &lists.formFields, &api.CheckType,
+		&api.GraphQLQuery, &api.GraphQLVariables, &api.GraphQLOperationName,
+		&api.DNSRecordType, &api.DNSExpected, &api.Variables,
+		&api.ProxyMode, &lists.proxy, &lists.tls,
+		&api.RedirectPolicy, &api.MaxRedirects, &api.OverlapPolicy,
+		&api.DeprecatedAfter, &api.AutoDisable, &api.RunbookURL, &api.Notes, &api.LogRequestHeaders, &api.DNSServer,
+		&api.LatencyWarnMs, &api.LatencyCriticalMs, &api.StepDelayMs, &api.ContentWatch, &api.DeletedAt, &api.CreatedAt, &api.UpdatedAt,
 	)
 	if err != nil {
-		return api, fmt.Errorf("failed to get API by ID: %w", err)
+		return api, err
+	}
+	api.Deprecated = api.DeprecatedAfter != nil && time.Now().After(*api.DeprecatedAfter)
+	if err := decodeJSONList(lists.queryParams, &api.QueryParams, "query parameters"); err != nil {
+		return api, err
+	}
+	if err := decodeJSONList(lists.pathParams, &api.PathParams, "path variables"); err != nil {
+		return api, err
+	}
+	if err := decodeJSONList(lists.formFields, &api.FormFields, "form fields"); err != nil {
+		return api, err
+	}
+	if lists.proxy != "" {
+		if err := json.Unmarshal([]byte(lists.proxy), &api.Proxy); err != nil {
+			return api, fmt.Errorf("failed to parse proxy: %w", err)
+		}
+	}
+	if lists.tls != "" {
+		if err := json.Unmarshal([]byte(lists.tls), &api.TLS); err != nil {
+			return api, fmt.Errorf("failed to parse TLS options: %w", err)
+		}
 	}
 	return api, nil
 }
 
-// GetAllAPIs gets all APIs
-func (s *DBService) GetAllAPIs() ([]models.API, error) {
-	// Use a more resilient query that handles potential missing collection_id column
-	rows, err := s.db.Query(`
-		SELECT 
-			id, name, method, url, headers, body, description, 
-			COALESCE(collection_id, 0) as collection_id, 
-			created_at, updated_at 
-		FROM apis ORDER BY name
-	`)
+// apiLists holds the list and object fields of an API as stored, encoded as JSON
+type apiLists struct {
+	queryParams string
+	pathParams  string
+	formFields  string
+	proxy       string
+	tls         string
+}
+
+// encodeAPILists encodes the list and object fields of an API for storage
+func encodeAPILists(api models.API) (apiLists, error) {
+	var lists apiLists
+	var err error
+	if lists.queryParams, err = encodeJSONList(api.QueryParams, "query parameters"); err != nil {
+		return lists, err
+	}
+	if lists.pathParams, err = encodeJSONList(api.PathParams, "path variables"); err != nil {
+		return lists, err
+	}
+	if lists.formFields, err = encodeJSONList(api.FormFields, "form fields"); err != nil {
+		return lists, err
+	}
+	if api.Proxy != (models.ProxyConfig{}) {
+		encoded, err := json.Marshal(api.Proxy)
+		if err != nil {
+			return lists, fmt.Errorf("failed to encode proxy: %w", err)
+		}
+		lists.proxy = string(encoded)
+	}
+	if api.TLS != (models.TLSConfig{}) {
+		encoded, err := json.Marshal(api.TLS)
+		if err != nil {
+			return lists, fmt.Errorf("failed to encode TLS options: %w", err)
+		}
+		lists.tls = string(encoded)
+	}
+	return lists, nil
+}
+
+// encodeJSONList encodes a list for a JSON column, as an empty string when there is nothing in it
+func encodeJSONList[T any](list []T, what string) (string, error) {
+	if len(list) == 0 {
+		return "", nil
+	}
+	encoded, err := json.Marshal(list)
 	if err != nil {
-		return nil, fmt.Errorf("failed to query APIs: %w", err)
+		return "", fmt.Errorf("failed to encode %s: %w", what, err)
 	}
-	defer rows.Close()
+	return string(encoded), nil
+}
+
+// decodeJSONList decodes a list stored with encodeJSONList
+func decodeJSONList[T any](raw string, list *[]T, what string) error {
+	if raw == "" {
+		return nil
+	}
+	if err := json.Unmarshal([]byte(raw), list); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", what, err)
+	}
+	return nil
+}
 
+// scanAPIs scans all rows of an API query
+func scanAPIs(rows *sql.Rows) ([]models.API, error) {
 	var apis []models.API
 	for rows.Next() {
-		var api models.API
-		if err := rows.Scan(&api.ID, &api.Name, &api.Method, &api.URL, &api.Headers, 
-			&api.Body, &api.Description, &api.CollectionID, &api.CreatedAt, &api.UpdatedAt); err != nil {
+		api, err := scanAPI(rows)
+		if err != nil {
 			return nil, fmt.Errorf("failed to scan API row: %w", err)
 		}
 		apis = append(apis, api)
@@ -244,6 +862,26 @@ func (s *DBService) GetAllAPIs() ([]models.API, error) {
 	return apis, nil
 }
 
+// GetAPIByID gets an API by ID
+func (s *DBService) GetAPIByID(id int) (models.API, error) {
+	api, err := scanAPI(s.db.QueryRow("SELECT "+apiColumns+" FROM apis WHERE id = ?", id))
+	if err != nil {
+		return api, fmt.Errorf("failed to get API by ID: %w", err)
+	}
+	return api, nil
+}
+
+// GetAllAPIs gets all APIs
+func (s *DBService) GetAllAPIs() ([]models.API, error) {
+	rows, err := s.db.Query("SELECT " + apiColumns + " FROM apis WHERE deleted_at IS NULL ORDER BY name")
+	if err != nil {
+		return nil, fmt.Errorf("failed to query APIs: %w", err)
+	}
+	defer rows.Close()
+
+	return scanAPIs(rows)
+}
+
 // Schedule Operations
 
 // CreateSchedule creates a new schedule
@@ -251,10 +889,14 @@ func (s *DBService) CreateSchedule(schedule models.Schedule) (models.Schedule, e
 	now := time.Now()
 	schedule.CreatedAt = now
 	schedule.UpdatedAt = now
+	if schedule.UUID == "" {
+		schedule.UUID = NewUUID()
+	}
 
 	result, err := s.db.Exec(
-		"INSERT INTO schedules (api_id, type, expression, is_active, retry_count, fallback_delay, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?)",
-		schedule.APIID, schedule.Type, schedule.Expression, schedule.IsActive, schedule.RetryCount, schedule.FallbackDelay, schedule.CreatedAt, schedule.UpdatedAt,
+		"INSERT INTO schedules (uuid, api_id, type, expression, is_active, retry_count, fallback_delay, retry_strategy, retry_multiplier, retry_max_delay, retry_jitter, timezone, after_id, after_outcome, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)",
+		schedule.UUID, schedule.APIID, schedule.Type, schedule.Expression, schedule.IsActive, schedule.RetryCount, schedule.FallbackDelay,
+		schedule.RetryPolicy.Strategy, schedule.RetryPolicy.Multiplier, schedule.RetryPolicy.MaxDelay, schedule.RetryPolicy.Jitter, schedule.Timezone, schedule.AfterID, schedule.AfterOutcome, schedule.CreatedAt, schedule.UpdatedAt,
 	)
 	if err != nil {
 		return schedule, fmt.Errorf("failed to create schedule: %w", err)
@@ -274,8 +916,9 @@ func (s *DBService) UpdateSchedule(schedule models.Schedule) error {
 	schedule.UpdatedAt = time.Now()
 
 	_, err := s.db.Exec(
-		"UPDATE schedules SET api_id = ?, type = ?, expression = ?, is_active = ?, retry_count = ?, fallback_delay = ?, updated_at = ? WHERE id = ?",
-		schedule.APIID, schedule.Type, schedule.Expression, schedule.IsActive, schedule.RetryCount, schedule.FallbackDelay, schedule.UpdatedAt, schedule.ID,
+		"UPDATE schedules SET api_id = ?, type = ?, expression = ?, is_active = ?, retry_count = ?, fallback_delay = ?, retry_strategy = ?, retry_multiplier = ?, retry_max_delay = ?, retry_jitter = ?, timezone = ?, after_id = ?, after_outcome = ?, updated_at = ? WHERE id = ?",
+		schedule.APIID, schedule.Type, schedule.Expression, schedule.IsActive, schedule.RetryCount, schedule.FallbackDelay,
+		schedule.RetryPolicy.Strategy, schedule.RetryPolicy.Multiplier, schedule.RetryPolicy.MaxDelay, schedule.RetryPolicy.Jitter, schedule.Timezone, schedule.AfterID, schedule.AfterOutcome, schedule.UpdatedAt, schedule.ID,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to update schedule: %w", err)
@@ -292,15 +935,24 @@ func (s *DBService) DeleteSchedule(id int) error {
 	return nil
 }
 
+// scheduleColumns is the column list matching scanSchedule
+const scheduleColumns = "id, COALESCE(uuid, ''), api_id, type, expression, is_active, retry_count, fallback_delay, " +
+	"COALESCE(retry_strategy, 'fixed'), COALESCE(retry_multiplier, 0), COALESCE(retry_max_delay, 0), COALESCE(retry_jitter, 0), COALESCE(timezone, ''), " +
+	"COALESCE(after_id, 0), COALESCE(after_outcome, ''), created_at, updated_at"
+
+// scanSchedule scans a single schedule selected with scheduleColumns
+func scanSchedule(row rowScanner) (models.Schedule, error) {
+	var schedule models.Schedule
+	err := row.Scan(&schedule.ID, &schedule.UUID, &schedule.APIID, &schedule.Type, &schedule.Expression, &schedule.IsActive,
+		&schedule.RetryCount, &schedule.FallbackDelay, &schedule.RetryPolicy.Strategy, &schedule.RetryPolicy.Multiplier,
+		&schedule.RetryPolicy.MaxDelay, &schedule.RetryPolicy.Jitter, &schedule.Timezone, &schedule.AfterID, &schedule.AfterOutcome,
+		&schedule.CreatedAt, &schedule.UpdatedAt)
+	return schedule, err
+}
+
 // GetScheduleByID gets a schedule by ID
 func (s *DBService) GetScheduleByID(id int) (models.Schedule, error) {
-	var schedule models.Schedule
-	err := s.db.QueryRow(
-		"SELECT id, api_id, type, expression, is_active, retry_count, fallback_delay, created_at, updated_at FROM schedules WHERE id = ?",
-		id,
-	).Scan(
-		&schedule.ID, &schedule.APIID, &schedule.Type, &schedule.Expression, &schedule.IsActive, &schedule.RetryCount, &schedule.FallbackDelay, &schedule.CreatedAt, &schedule.UpdatedAt,
-	)
+	schedule, err := scanSchedule(s.db.QueryRow("SELECT "+scheduleColumns+" FROM schedules WHERE id = ?", id))
 	if err != nil {
 		return schedule, fmt.Errorf("failed to get schedule by ID: %w", err)
 	}
@@ -309,7 +961,7 @@ func (s *DBService) GetScheduleByID(id int) (models.Schedule, error) {
 
 // GetAllSchedules gets all schedules
 func (s *DBService) GetAllSchedules() ([]models.Schedule, error) {
-	rows, err := s.db.Query("SELECT id, api_id, type, expression, is_active, retry_count, fallback_delay, created_at, updated_at FROM schedules ORDER BY created_at DESC")
+	rows, err := s.db.Query("SELECT " + scheduleColumns + " FROM schedules WHERE " + liveSchedules + " ORDER BY created_at DESC")
 	if err != nil {
 		return nil, fmt.Errorf("failed to query schedules: %w", err)
 	}
@@ -317,8 +969,8 @@ func (s *DBService) GetAllSchedules() ([]models.Schedule, error) {
 
 	var schedules []models.Schedule
 	for rows.Next() {
-		var schedule models.Schedule
-		if err := rows.Scan(&schedule.ID, &schedule.APIID, &schedule.Type, &schedule.Expression, &schedule.IsActive, &schedule.RetryCount, &schedule.FallbackDelay, &schedule.CreatedAt, &schedule.UpdatedAt); err != nil {
+		schedule, err := scanSchedule(rows)
+		if err != nil {
 			return nil, fmt.Errorf("failed to scan schedule row: %w", err)
 		}
 		schedules = append(schedules, schedule)
@@ -329,7 +981,7 @@ func (s *DBService) GetAllSchedules() ([]models.Schedule, error) {
 
 // GetSchedulesByAPIID gets all schedules for an API
 func (s *DBService) GetSchedulesByAPIID(apiID int) ([]models.Schedule, error) {
-	rows, err := s.db.Query("SELECT id, api_id, type, expression, is_active, retry_count, fallback_delay, created_at, updated_at FROM schedules WHERE api_id = ? ORDER BY created_at DESC", apiID)
+	rows, err := s.db.Query("SELECT "+scheduleColumns+" FROM schedules WHERE api_id = ? ORDER BY created_at DESC", apiID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query schedules by API ID: %w", err)
 	}
@@ -337,8 +989,8 @@ func (s *DBService) GetSchedulesByAPIID(apiID int) ([]models.Schedule, error) {
 
 	var schedules []models.Schedule
 	for rows.Next() {
-		var schedule models.Schedule
-		if err := rows.Scan(&schedule.ID, &schedule.APIID, &schedule.Type, &schedule.Expression, &schedule.IsActive, &schedule.RetryCount, &schedule.FallbackDelay, &schedule.CreatedAt, &schedule.UpdatedAt); err != nil {
+		schedule, err := scanSchedule(rows)
+		if err != nil {
 			return nil, fmt.Errorf("failed to scan schedule row: %w", err)
 		}
 		schedules = append(schedules, schedule)
@@ -349,7 +1001,7 @@ func (s *DBService) GetSchedulesByAPIID(apiID int) ([]models.Schedule, error) {
 
 // GetAllActiveSchedules gets all active schedules
 func (s *DBService) GetAllActiveSchedules() ([]models.Schedule, error) {
-	rows, err := s.db.Query("SELECT id, api_id, type, expression, is_active, retry_count, fallback_delay, created_at, updated_at FROM schedules WHERE is_active = 1")
+	rows, err := s.db.Query("SELECT " + scheduleColumns + " FROM schedules WHERE is_active = 1 AND " + liveSchedules)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query active schedules: %w", err)
 	}
@@ -357,8 +1009,8 @@ func (s *DBService) GetAllActiveSchedules() ([]models.Schedule, error) {
 
 	var schedules []models.Schedule
 	for rows.Next() {
-		var schedule models.Schedule
-		if err := rows.Scan(&schedule.ID, &schedule.APIID, &schedule.Type, &schedule.Expression, &schedule.IsActive, &schedule.RetryCount, &schedule.FallbackDelay, &schedule.CreatedAt, &schedule.UpdatedAt); err != nil {
+		schedule, err := scanSchedule(rows)
+		if err != nil {
 			return nil, fmt.Errorf("failed to scan schedule row: %w", err)
 		}
 		schedules = append(schedules, schedule)
@@ -369,22 +1021,68 @@ func (s *DBService) GetAllActiveSchedules() ([]models.Schedule, error) {
 
 // Execution Log Operations
 
-// CreateExecutionLog creates a new execution log
+// CreateExecutionLog creates a new execution log. A response longer than the preview kept in the row is
+// stored in full as well when the retention policy asks for it.
 func (s *DBService) CreateExecutionLog(log models.ExecutionLog) (models.ExecutionLog, error) {
 	// Truncate response and error if they are too large for SQLite
-	if len(log.Response) > 10000 {
-		log.Response = log.Response[:10000] + "... (truncated)"
+	var fullResponse []byte
+	if len(log.Response) > responsePreviewLength {
+		storeFull, err := s.GetSetting(settingStoreFullResponses)
+		if err != nil {
+			return log, err
+		}
+		if storeFull == "true" {
+			if fullResponse, err = compressResponse(log.Response); err != nil {
+				return log, err
+			}
+			log.FullResponseStored = true
+		}
+		log.Response = log.Response[:responsePreviewLength] + TruncatedSuffix
 	}
-	
+
 	if len(log.Error) > 5000 {
-		log.Error = log.Error[:5000] + "... (truncated)"
+		log.Error = log.Error[:5000] + TruncatedSuffix
 	}
 
 	log.ExecutedAt = time.Now()
+	if log.Location == "" {
+		log.Location = models.LocationLocal
+	}
 
-	result, err := s.db.Exec(
-		"INSERT INTO execution_logs (api_id, schedule_id, status_code, response, error, executed_at) VALUES (?, ?, ?, ?, ?, ?)",
-		log.APIID, log.ScheduleID, log.StatusCode, log.Response, log.Error, log.ExecutedAt,
+	var assertionResults string
+	if len(log.AssertionResults) > 0 {
+		encoded, err := json.Marshal(log.AssertionResults)
+		if err != nil {
+			return log, fmt.Errorf("failed to encode assertion results: %w", err)
+		}
+		assertionResults = string(encoded)
+	}
+	var redirectChain string
+	if len(log.RedirectChain) > 0 {
+		encoded, err := json.Marshal(log.RedirectChain)
+		if err != nil {
+			return log, fmt.Errorf("failed to encode redirect chain: %w", err)
+		}
+		redirectChain = string(encoded)
+	}
+	triggers, err := encodeJSONList(log.Triggers, "triggers")
+	if err != nil {
+		return log, err
+	}
+	requestHeaders, responseHeaders, err := encodeLogHeaders(log)
+	if err != nil {
+		return log, err
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return log, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(
+		"INSERT INTO execution_logs (api_id, schedule_id, status_code, status, response, error, duration_ms, location, assertion_results, collection_run_id, failure_phase, timed_out, latency_breach, content_changed, full_response, final_url, redirect_chain, triggers, request_headers, response_headers, executed_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)",
+		log.APIID, log.ScheduleID, log.StatusCode, log.Status, log.Response, log.Error, log.DurationMs, log.Location, assertionResults, log.CollectionRunID, log.FailurePhase, log.TimedOut, log.LatencyBreach, log.ContentChanged, log.FullResponseStored, log.FinalURL, redirectChain, triggers, requestHeaders, responseHeaders, log.ExecutedAt,
 	)
 	if err != nil {
 		return log, fmt.Errorf("failed to create execution log: %w", err)
@@ -395,36 +1093,176 @@ func (s *DBService) CreateExecutionLog(log models.ExecutionLog) (models.Executio
 		return log, fmt.Errorf("failed to get last insert ID: %w", err)
 	}
 
+	if fullResponse != nil {
+		if _, err := tx.Exec("INSERT INTO response_bodies (execution_log_id, body) VALUES (?, ?)", id, fullResponse); err != nil {
+			return log, fmt.Errorf("failed to store response body: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return log, fmt.Errorf("failed to commit execution log: %w", err)
+	}
+
 	log.ID = int(id)
 	return log, nil
 }
 
+// executionLogColumns is the column list matching scanExecutionLog
+const executionLogColumns = "id, api_id, schedule_id, status_code, COALESCE(status, ''), response, error, COALESCE(duration_ms, 0), COALESCE(location, 'local'), COALESCE(assertion_results, ''), COALESCE(collection_run_id, 0), COALESCE(failure_phase, ''), COALESCE(timed_out, 0), COALESCE(latency_breach, ''), COALESCE(content_changed, 0), COALESCE(full_response, 0), COALESCE(final_url, ''), COALESCE(redirect_chain, ''), COALESCE(triggers, ''), COALESCE(request_headers, ''), COALESCE(response_headers, ''), executed_at"
+
+// rowScanner is implemented by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanExecutionLog scans a single execution log selected with executionLogColumns
+func scanExecutionLog(row rowScanner) (models.ExecutionLog, error) {
+	var log models.ExecutionLog
+	var assertionResults, redirectChain, triggers, requestHeaders, responseHeaders string
+	err := row.Scan(&log.ID, &log.APIID, &log.ScheduleID, &log.StatusCode, &log.Status, &log.Response, &log.Error, &log.DurationMs, &log.Location, &assertionResults, &log.CollectionRunID, &log.FailurePhase, &log.TimedOut, &log.LatencyBreach, &log.ContentChanged, &log.FullResponseStored, &log.FinalURL, &redirectChain, &triggers, &requestHeaders, &responseHeaders, &log.ExecutedAt)
+	if err != nil {
+		return log, err
+	}
+	if assertionResults != "" {
+		if err := json.Unmarshal([]byte(assertionResults), &log.AssertionResults); err != nil {
+			return log, fmt.Errorf("failed to parse assertion results: %w", err)
+		}
+	}
+	if redirectChain != "" {
+		if err := json.Unmarshal([]byte(redirectChain), &log.RedirectChain); err != nil {
+			return log, fmt.Errorf("failed to parse redirect chain: %w", err)
+		}
+	}
+	if err := decodeJSONList(triggers, &log.Triggers, "triggers"); err != nil {
+		return log, err
+	}
+	return log, decodeLogHeaders(&log, requestHeaders, responseHeaders)
+}
+
+// encodeLogHeaders encodes the request and response headers of an execution log as JSON, empty for none
+func encodeLogHeaders(log models.ExecutionLog) (string, string, error) {
+	var encoded [2]string
+	for i, headers := range []map[string][]string{log.RequestHeaders, log.ResponseHeaders} {
+		if headers == nil {
+			continue
+		}
+		raw, err := json.Marshal(headers)
+		if err != nil {
+			return "", "", fmt.Errorf("failed to encode headers: %w", err)
+		}
+		encoded[i] = string(raw)
+	}
+	return encoded[0], encoded[1], nil
+}
+
+// decodeLogHeaders decodes headers stored with encodeLogHeaders onto an execution log
+func decodeLogHeaders(log *models.ExecutionLog, requestHeaders, responseHeaders string) error {
+	for _, stored := range []struct {
+		raw     string
+		headers *map[string][]string
+	}{{requestHeaders, &log.RequestHeaders}, {responseHeaders, &log.ResponseHeaders}} {
+		if stored.raw == "" {
+			continue
+		}
+		if err := json.Unmarshal([]byte(stored.raw), stored.headers); err != nil {
+			return fmt.Errorf("failed to parse headers: %w", err)
+		}
+	}
+	return nil
+}
+
+// scanExecutionLogs scans all rows of an execution log query
+func scanExecutionLogs(rows *sql.Rows) ([]models.ExecutionLog, error) {
+	var logs []models.ExecutionLog
+	for rows.Next() {
+		log, err := scanExecutionLog(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan execution log row: %w", err)
+		}
+		logs = append(logs, log)
+	}
+
+	return logs, nil
+}
+
+// scanAnnotatedExecutionLogs scans all rows of an execution log query along with the annotations of each log
+func (s *DBService) scanAnnotatedExecutionLogs(rows *sql.Rows) ([]models.ExecutionLog, error) {
+	logs, err := scanExecutionLogs(rows)
+	if err != nil {
+		return nil, err
+	}
+	return logs, s.attachLogAnnotations(logs)
+}
+
+// GetExecutionLogByID gets an execution log by ID
+func (s *DBService) GetExecutionLogByID(id int) (models.ExecutionLog, error) {
+	log, err := scanExecutionLog(s.db.QueryRow("SELECT "+executionLogColumns+" FROM execution_logs WHERE id = ?", id))
+	if err != nil {
+		return log, fmt.Errorf("failed to get execution log by ID: %w", err)
+	}
+	logs := []models.ExecutionLog{log}
+	if err := s.attachLogAnnotations(logs); err != nil {
+		return log, err
+	}
+	return logs[0], nil
+}
+
 // GetExecutionLogsByAPIID gets execution logs for an API
 func (s *DBService) GetExecutionLogsByAPIID(apiID int, limit int) ([]models.ExecutionLog, error) {
 	query := `
-		SELECT id, api_id, schedule_id, status_code, response, error, executed_at 
+		SELECT ` + executionLogColumns + `
 		FROM execution_logs 
 		WHERE api_id = ? 
 		ORDER BY executed_at DESC 
 		LIMIT ?
 	`
-	
+
 	rows, err := s.db.Query(query, apiID, limit)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query execution logs by API ID: %w", err)
 	}
 	defer rows.Close()
 
-	var logs []models.ExecutionLog
-	for rows.Next() {
-		var log models.ExecutionLog
-		if err := rows.Scan(&log.ID, &log.APIID, &log.ScheduleID, &log.StatusCode, &log.Response, &log.Error, &log.ExecutedAt); err != nil {
-			return nil, fmt.Errorf("failed to scan execution log row: %w", err)
-		}
-		logs = append(logs, log)
+	return s.scanAnnotatedExecutionLogs(rows)
+}
+
+// GetLatestExecutionForAPIs gets the most recent execution log of each API in one query.
+// APIs that haven't been executed yet are missing from the result.
+func (s *DBService) GetLatestExecutionForAPIs(apiIDs []int) (map[int]models.ExecutionLog, error) {
+	latest := make(map[int]models.ExecutionLog, len(apiIDs))
+	if len(apiIDs) == 0 {
+		return latest, nil
 	}
 
-	return logs, nil
+	args := make([]interface{}, len(apiIDs))
+	for i, id := range apiIDs {
+		args[i] = id
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(apiIDs)), ", ")
+
+	query := `
+		SELECT ` + executionLogColumns + `
+		FROM (
+			SELECT *, ROW_NUMBER() OVER (PARTITION BY api_id ORDER BY executed_at DESC, id DESC) AS position
+			FROM execution_logs
+			WHERE api_id IN (` + placeholders + `)
+		)
+		WHERE position = 1
+	`
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query latest executions: %w", err)
+	}
+	defer rows.Close()
+
+	logs, err := scanExecutionLogs(rows)
+	if err != nil {
+		return nil, err
+	}
+	for _, log := range logs {
+		latest[log.APIID] = log
+	}
+	return latest, nil
 }
 
 // GetAllExecutionLogs gets all execution logs with pagination
@@ -435,7 +1273,7 @@ func (s *DBService) GetAllExecutionLogs(page, pageSize int) ([]models.ExecutionL
 	}
 
 	query := `
-		SELECT id, api_id, schedule_id, status_code, response, error, executed_at
+		SELECT ` + executionLogColumns + `
 		FROM execution_logs
 		ORDER BY executed_at DESC
 		LIMIT ? OFFSET ?
@@ -447,22 +1285,13 @@ func (s *DBService) GetAllExecutionLogs(page, pageSize int) ([]models.ExecutionL
 	}
 	defer rows.Close()
 
-	var logs []models.ExecutionLog
-	for rows.Next() {
-		var log models.ExecutionLog
-		if err := rows.Scan(&log.ID, &log.APIID, &log.ScheduleID, &log.StatusCode, &log.Response, &log.Error, &log.ExecutedAt); err != nil {
-			return nil, fmt.Errorf("failed to scan execution log row: %w", err)
-		}
-		logs = append(logs, log)
-	}
-
-	return logs, nil
+	return s.scanAnnotatedExecutionLogs(rows)
 }
 
 // GetRecentExecutions gets the most recent execution logs
 func (s *DBService) GetRecentExecutions(limit int) ([]models.ExecutionLog, error) {
 	query := `
-		SELECT id, api_id, schedule_id, status_code, response, error, executed_at
+		SELECT ` + executionLogColumns + `
 		FROM execution_logs
 		ORDER BY executed_at DESC
 		LIMIT ?
@@ -474,29 +1303,42 @@ func (s *DBService) GetRecentExecutions(limit int) ([]models.ExecutionLog, error
 	}
 	defer rows.Close()
 
-	var logs []models.ExecutionLog
-	for rows.Next() {
-		var log models.ExecutionLog
-		if err := rows.Scan(&log.ID, &log.APIID, &log.ScheduleID, &log.StatusCode, &log.Response, &log.Error, &log.ExecutedAt); err != nil {
-			return nil, fmt.Errorf("failed to scan execution log row: %w", err)
-		}
-		logs = append(logs, log)
-	}
-
-	return logs, nil
+	return s.scanAnnotatedExecutionLogs(rows)
 }
 
 // Collection Operations
 
+// collectionColumns is the column list matching scanCollection
+// The parent reads as 0 while it is in the trash, so the collection shows at the top level until the parent is restored
+const collectionColumns = "id, COALESCE(uuid, ''), name, description, COALESCE((SELECT parent.id FROM collections AS parent WHERE parent.id = collections.parent_id AND parent.deleted_at IS NULL), 0), COALESCE(environment_id, 0), COALESCE(latency_budget_ms, 0), COALESCE(stop_on_failure, 0), COALESCE(max_parallel, 0), COALESCE(step_delay_ms, 0), COALESCE(auth_config_id, 0), COALESCE(variables, ''), COALESCE(latency_targets, ''), deleted_at, created_at, updated_at"
+
+// scanCollection scans a single collection selected with collectionColumns
+func scanCollection(row rowScanner) (models.Collection, error) {
+	var collection models.Collection
+	var latencyTargets string
+	err := row.Scan(&collection.ID, &collection.UUID, &collection.Name, &collection.Description, &collection.ParentID, &collection.EnvironmentID, &collection.LatencyBudgetMs, &collection.StopOnFailure, &collection.MaxParallel, &collection.StepDelayMs, &collection.AuthConfigID, &collection.Variables, &latencyTargets, &collection.DeletedAt, &collection.CreatedAt, &collection.UpdatedAt)
+	if err != nil {
+		return collection, err
+	}
+	return collection, decodeJSONList(latencyTargets, &collection.LatencyTargets, "latency targets")
+}
+
 // CreateCollection creates a new collection
 func (s *DBService) CreateCollection(collection models.Collection) (models.Collection, error) {
 	now := time.Now()
 	collection.CreatedAt = now
 	collection.UpdatedAt = now
+	if collection.UUID == "" {
+		collection.UUID = NewUUID()
+	}
+	latencyTargets, err := encodeJSONList(collection.LatencyTargets, "latency targets")
+	if err != nil {
+		return collection, err
+	}
 
 	result, err := s.db.Exec(
-		"INSERT INTO collections (name, description, created_at, updated_at) VALUES (?, ?, ?, ?)",
-		collection.Name, collection.Description, collection.CreatedAt, collection.UpdatedAt,
+		"INSERT INTO collections (uuid, name, description, parent_id, environment_id, latency_budget_ms, stop_on_failure, max_parallel, step_delay_ms, auth_config_id, variables, latency_targets, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)",
+		collection.UUID, collection.Name, collection.Description, collection.ParentID, collection.EnvironmentID, collection.LatencyBudgetMs, collection.StopOnFailure, collection.MaxParallel, collection.StepDelayMs, collection.AuthConfigID, collection.Variables, latencyTargets, collection.CreatedAt, collection.UpdatedAt,
 	)
 	if err != nil {
 		return collection, fmt.Errorf("failed to create collection: %w", err)
@@ -511,13 +1353,17 @@ func (s *DBService) CreateCollection(collection models.Collection) (models.Colle
 	return collection, nil
 }
 
-// UpdateCollection updates an existing collection
+// UpdateCollection updates an existing collection. It is moved to another folder with MoveCollection instead.
 func (s *DBService) UpdateCollection(collection models.Collection) (models.Collection, error) {
 	collection.UpdatedAt = time.Now()
+	latencyTargets, err := encodeJSONList(collection.LatencyTargets, "latency targets")
+	if err != nil {
+		return collection, err
+	}
 
-	_, err := s.db.Exec(
-		"UPDATE collections SET name = ?, description = ?, updated_at = ? WHERE id = ?",
-		collection.Name, collection.Description, collection.UpdatedAt, collection.ID,
+	_, err = s.db.Exec(
+		"UPDATE collections SET name = ?, description = ?, environment_id = ?, latency_budget_ms = ?, stop_on_failure = ?, max_parallel = ?, step_delay_ms = ?, auth_config_id = ?, variables = ?, latency_targets = ?, updated_at = ? WHERE id = ?",
+		collection.Name, collection.Description, collection.EnvironmentID, collection.LatencyBudgetMs, collection.StopOnFailure, collection.MaxParallel, collection.StepDelayMs, collection.AuthConfigID, collection.Variables, latencyTargets, collection.UpdatedAt, collection.ID,
 	)
 	if err != nil {
 		return collection, fmt.Errorf("failed to update collection: %w", err)
@@ -532,16 +1378,13 @@ func (s *DBService) UpdateCollection(collection models.Collection) (models.Colle
 	return updatedCollection, nil
 }
 
-// DeleteCollection deletes a collection by ID
+// DeleteCollection moves a collection to the trash along with the folders nested in it. Their APIs read as
+// ungrouped until they are restored.
 func (s *DBService) DeleteCollection(id int) error {
-	// First, update all APIs to remove them from this collection
-	_, err := s.db.Exec("UPDATE apis SET collection_id = 0 WHERE collection_id = ?", id)
-	if err != nil {
-		return fmt.Errorf("failed to update APIs: %w", err)
-	}
-
-	// Then delete the collection
-	_, err = s.db.Exec("DELETE FROM collections WHERE id = ?", id)
+	_, err := s.db.Exec(
+		"WITH RECURSIVE "+liveCollectionSubtree+" UPDATE collections SET deleted_at = ? WHERE id IN (SELECT id FROM subtree) AND deleted_at IS NULL",
+		id, time.Now(),
+	)
 	if err != nil {
 		return fmt.Errorf("failed to delete collection: %w", err)
 	}
@@ -550,13 +1393,10 @@ func (s *DBService) DeleteCollection(id int) error {
 
 // GetCollectionByID gets a collection by ID
 func (s *DBService) GetCollectionByID(id int) (models.Collection, error) {
-	var collection models.Collection
-	err := s.db.QueryRow(
-		"SELECT id, name, description, created_at, updated_at FROM collections WHERE id = ?",
+	collection, err := scanCollection(s.db.QueryRow(
+		"SELECT "+collectionColumns+" FROM collections WHERE id = ?",
 		id,
-	).Scan(
-		&collection.ID, &collection.Name, &collection.Description, &collection.CreatedAt, &collection.UpdatedAt,
-	)
+	))
 	if err != nil {
 		return collection, fmt.Errorf("failed to get collection by ID: %w", err)
 	}
@@ -565,7 +1405,7 @@ func (s *DBService) GetCollectionByID(id int) (models.Collection, error) {
 
 // GetAllCollections gets all collections
 func (s *DBService) GetAllCollections() ([]models.Collection, error) {
-	rows, err := s.db.Query("SELECT id, name, description, created_at, updated_at FROM collections ORDER BY name")
+	rows, err := s.db.Query("SELECT " + collectionColumns + " FROM collections WHERE deleted_at IS NULL ORDER BY name")
 	if err != nil {
 		return nil, fmt.Errorf("failed to query collections: %w", err)
 	}
@@ -573,8 +1413,8 @@ func (s *DBService) GetAllCollections() ([]models.Collection, error) {
 
 	var collections []models.Collection
 	for rows.Next() {
-		var collection models.Collection
-		if err := rows.Scan(&collection.ID, &collection.Name, &collection.Description, &collection.CreatedAt, &collection.UpdatedAt); err != nil {
+		collection, err := scanCollection(rows)
+		if err != nil {
 			return nil, fmt.Errorf("failed to scan collection row: %w", err)
 		}
 		collections = append(collections, collection)
@@ -583,17 +1423,11 @@ func (s *DBService) GetAllCollections() ([]models.Collection, error) {
 	return collections, nil
 }
 
-// GetAPIsByCollectionID gets all APIs in a collection
+// GetAPIsByCollectionID gets all APIs in a collection. APIs of a collection in the trash count as ungrouped,
+// so they are listed for collection 0 rather than for the trashed collection.
 func (s *DBService) GetAPIsByCollectionID(collectionID int) ([]models.API, error) {
-	// Use a more resilient query with COALESCE
-	rows, err := s.db.Query(`
-		SELECT 
-			id, name, method, url, headers, body, description, 
-			COALESCE(collection_id, 0) as collection_id, 
-			created_at, updated_at 
-		FROM apis 
-		WHERE COALESCE(collection_id, 0) = ? 
-		ORDER BY name`,
+	rows, err := s.db.Query(
+		"SELECT "+apiColumns+" FROM apis WHERE "+apiCollectionID+" = ? AND deleted_at IS NULL ORDER BY COALESCE(sort_order, 0), name",
 		collectionID,
 	)
 	if err != nil {
@@ -601,51 +1435,52 @@ func (s *DBService) GetAPIsByCollectionID(collectionID int) ([]models.API, error
 	}
 	defer rows.Close()
 
-	var apis []models.API
-	for rows.Next() {
-		var api models.API
-		if err := rows.Scan(&api.ID, &api.Name, &api.Method, &api.URL, &api.Headers, 
-			&api.Body, &api.Description, &api.CollectionID, &api.CreatedAt, &api.UpdatedAt); err != nil {
-			return nil, fmt.Errorf("failed to scan API row: %w", err)
-		}
-		apis = append(apis, api)
-	}
-
-	return apis, nil
+	return scanAPIs(rows)
 }
 
 // GetAPIAnalytics provides analytics for a specific API
 func (s *DBService) GetAPIAnalytics(apiID int) (models.AnalyticsSummary, error) {
 	var analytics models.AnalyticsSummary
-	
+
 	// Get total executions
 	var totalCount int
-	err := s.db.QueryRow("SELECT COUNT(*) FROM execution_logs WHERE api_id = ?", apiID).Scan(&totalCount)
+	err := s.db.QueryRow("SELECT COUNT(*) FROM execution_logs WHERE api_id = ? AND "+uptimeExecutions, apiID).Scan(&totalCount)
 	if err != nil {
 		return analytics, fmt.Errorf("failed to get execution count: %w", err)
 	}
 	analytics.TotalExecutions = totalCount
-	
-	// Get success count (status code 2xx)
+
+	// Get success count (executions whose outcome matched the API's expectation)
 	var successCount int
-	err = s.db.QueryRow("SELECT COUNT(*) FROM execution_logs WHERE api_id = ? AND status_code >= 200 AND status_code < 300", apiID).Scan(&successCount)
+	err = s.db.QueryRow("SELECT COUNT(*) FROM execution_logs WHERE api_id = ? AND status = ? AND "+uptimeExecutions, apiID, models.ExecutionStatusSuccess).Scan(&successCount)
 	if err != nil {
 		return analytics, fmt.Errorf("failed to get success count: %w", err)
 	}
 	analytics.SuccessCount = successCount
-	
+
+	// Degraded executions count toward uptime but not toward the success rate
+	var degradedCount int
+	err = s.db.QueryRow("SELECT COUNT(*) FROM execution_logs WHERE api_id = ? AND status = ? AND "+uptimeExecutions, apiID, models.ExecutionStatusDegraded).Scan(&degradedCount)
+	if err != nil {
+		return analytics, fmt.Errorf("failed to get degraded count: %w", err)
+	}
+	analytics.DegradedCount = degradedCount
+
 	// Calculate failure count
-	analytics.FailureCount = totalCount - successCount
-	
-	// Calculate success rate and error rate
+	analytics.FailureCount = totalCount - successCount - degradedCount
+
+	// Calculate success rate, error rate and uptime
 	if totalCount > 0 {
 		analytics.SuccessRate = float64(successCount) / float64(totalCount) * 100
-		analytics.ErrorRate = 100 - analytics.SuccessRate
+		analytics.ErrorRate = float64(analytics.FailureCount) / float64(totalCount) * 100
+		analytics.Uptime = float64(successCount+degradedCount) / float64(totalCount) * 100
+	}
+
+	// Fill in latency statistics from tracked durations
+	if err := s.fillLatencyStats(&analytics, "api_id = ?", apiID); err != nil {
+		return analytics, err
 	}
-	
-	// Calculate an estimated uptime (simplistic approach based on success rate)
-	analytics.Uptime = analytics.SuccessRate
-	
+
 	// Get most recent execution time
 	var lastExecutionTime sql.NullTime
 	err = s.db.QueryRow("SELECT executed_at FROM execution_logs WHERE api_id = ? ORDER BY executed_at DESC LIMIT 1", apiID).Scan(&lastExecutionTime)
@@ -655,42 +1490,53 @@ func (s *DBService) GetAPIAnalytics(apiID int) (models.AnalyticsSummary, error)
 	if lastExecutionTime.Valid {
 		analytics.LastExecutionTime = lastExecutionTime.Time.Format(time.RFC3339)
 	}
-	
+
 	return analytics, nil
 }
 
 // GetOverallAnalytics provides aggregated analytics for all APIs
 func (s *DBService) GetOverallAnalytics() (models.AnalyticsSummary, error) {
 	var analytics models.AnalyticsSummary
-	
+
 	// Get total executions
 	var totalCount int
-	err := s.db.QueryRow("SELECT COUNT(*) FROM execution_logs").Scan(&totalCount)
+	err := s.db.QueryRow("SELECT COUNT(*) FROM execution_logs WHERE " + uptimeExecutions).Scan(&totalCount)
 	if err != nil {
 		return analytics, fmt.Errorf("failed to get execution count: %w", err)
 	}
 	analytics.TotalExecutions = totalCount
-	
-	// Get success count (status code 2xx)
+
+	// Get success count (executions whose outcome matched the API's expectation)
 	var successCount int
-	err = s.db.QueryRow("SELECT COUNT(*) FROM execution_logs WHERE status_code >= 200 AND status_code < 300").Scan(&successCount)
+	err = s.db.QueryRow("SELECT COUNT(*) FROM execution_logs WHERE status = ? AND "+uptimeExecutions, models.ExecutionStatusSuccess).Scan(&successCount)
 	if err != nil {
 		return analytics, fmt.Errorf("failed to get success count: %w", err)
 	}
 	analytics.SuccessCount = successCount
-	
+
+	// Degraded executions count toward uptime but not toward the success rate
+	var degradedCount int
+	err = s.db.QueryRow("SELECT COUNT(*) FROM execution_logs WHERE status = ? AND "+uptimeExecutions, models.ExecutionStatusDegraded).Scan(&degradedCount)
+	if err != nil {
+		return analytics, fmt.Errorf("failed to get degraded count: %w", err)
+	}
+	analytics.DegradedCount = degradedCount
+
 	// Calculate failure count
-	analytics.FailureCount = totalCount - successCount
-	
-	// Calculate success rate and error rate
+	analytics.FailureCount = totalCount - successCount - degradedCount
+
+	// Calculate success rate, error rate and uptime
 	if totalCount > 0 {
 		analytics.SuccessRate = float64(successCount) / float64(totalCount) * 100
-		analytics.ErrorRate = 100 - analytics.SuccessRate
+		analytics.ErrorRate = float64(analytics.FailureCount) / float64(totalCount) * 100
+		analytics.Uptime = float64(successCount+degradedCount) / float64(totalCount) * 100
+	}
+
+	// Fill in latency statistics from tracked durations
+	if err := s.fillLatencyStats(&analytics, "1 = 1"); err != nil {
+		return analytics, err
 	}
-	
-	// Calculate an estimated uptime (simplistic approach based on success rate)
-	analytics.Uptime = analytics.SuccessRate
-	
+
 	// Get most recent execution time
 	var lastExecutionTime sql.NullTime
 	err = s.db.QueryRow("SELECT executed_at FROM execution_logs ORDER BY executed_at DESC LIMIT 1").Scan(&lastExecutionTime)
@@ -700,6 +1546,51 @@ func (s *DBService) GetOverallAnalytics() (models.AnalyticsSummary, error) {
 	if lastExecutionTime.Valid {
 		analytics.LastExecutionTime = lastExecutionTime.Time.Format(time.RFC3339)
 	}
-	
+
 	return analytics, nil
-} 
\ No newline at end of file
+}
+
+// fillLatencyStats computes the average and percentile durations for the execution logs matching the condition
+func (s *DBService) fillLatencyStats(analytics *models.AnalyticsSummary, condition string, args ...interface{}) error {
+	rows, err := s.db.Query(
+		"SELECT duration_ms FROM execution_logs WHERE duration_ms IS NOT NULL AND "+uptimeExecutions+" AND "+condition+" ORDER BY duration_ms",
+		args...,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to query execution durations: %w", err)
+	}
+	defer rows.Close()
+
+	var durations []int64
+	var total int64
+	for rows.Next() {
+		var duration int64
+		if err := rows.Scan(&duration); err != nil {
+			return fmt.Errorf("failed to scan execution duration: %w", err)
+		}
+		durations = append(durations, duration)
+		total += duration
+	}
+
+	if len(durations) == 0 {
+		return nil
+	}
+
+	analytics.AverageTimeMs = float64(total) / float64(len(durations))
+	analytics.P50TimeMs = percentile(durations, 50)
+	analytics.P95TimeMs = percentile(durations, 95)
+	analytics.P99TimeMs = percentile(durations, 99)
+	return nil
+}
+
+// percentile returns the nearest-rank percentile of an ascending slice of durations
+func percentile(sorted []int64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return float64(sorted[rank-1])
+}
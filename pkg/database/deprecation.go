@@ -0,0 +1,52 @@
+package database
+
+import (
+	"fmt"
+	"time"
+
+	"flowpulse/pkg/models"
+)
+
+// GetDeprecationReport gets every API with a deprecation date, soonest sunset first, along with how many
+// of its schedules are still active
+func (s *DBService) GetDeprecationReport() ([]models.DeprecatedAPI, error) {
+	rows, err := s.db.Query(`
+		SELECT apis.id, apis.name, apis.url, apis.deprecated_after, COALESCE(apis.auto_disable, 0),
+			(SELECT COUNT(*) FROM schedules WHERE schedules.api_id = apis.id AND schedules.is_active = 1)
+		FROM apis
+		WHERE apis.deprecated_after IS NOT NULL AND apis.deleted_at IS NULL
+		ORDER BY apis.deprecated_after, apis.name`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query deprecated APIs: %w", err)
+	}
+	defer rows.Close()
+
+	now := time.Now()
+	report := []models.DeprecatedAPI{}
+	for rows.Next() {
+		var api models.DeprecatedAPI
+		if err := rows.Scan(&api.APIID, &api.APIName, &api.URL, &api.DeprecatedAfter, &api.AutoDisable, &api.ActiveSchedules); err != nil {
+			return nil, fmt.Errorf("failed to scan deprecated API row: %w", err)
+		}
+		api.Deprecated = now.After(api.DeprecatedAfter)
+		report = append(report, api)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read deprecated APIs: %w", err)
+	}
+	return report, nil
+}
+
+// GetAPIsToRetire gets the APIs deprecated by the given time that deactivate their schedules once deprecated
+func (s *DBService) GetAPIsToRetire(now time.Time) ([]models.API, error) {
+	rows, err := s.db.Query(
+		"SELECT "+apiColumns+" FROM apis WHERE deprecated_after IS NOT NULL AND deprecated_after <= ? AND auto_disable = 1 AND deleted_at IS NULL ORDER BY name",
+		now.Local(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query deprecated APIs: %w", err)
+	}
+	defer rows.Close()
+
+	return scanAPIs(rows)
+}
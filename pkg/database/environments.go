@@ -0,0 +1,135 @@
+package database
+
+import (
+	"fmt"
+	"time"
+
+	"flowpulse/pkg/models"
+)
+
+// initEnvironmentsTables creates the environments table
+func (s *DBService) initEnvironmentsTables() error {
+	_, err := s.db.Exec(`
+		CREATE TABLE IF NOT EXISTS environments (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT NOT NULL,
+			variables TEXT,
+			created_at TIMESTAMP NOT NULL,
+			updated_at TIMESTAMP NOT NULL
+		)
+	`)
+	return err
+}
+
+// Environment Operations
+
+// CreateEnvironment creates a new environment
+func (s *DBService) CreateEnvironment(environment models.Environment) (models.Environment, error) {
+	now := time.Now()
+	environment.CreatedAt = now
+	environment.UpdatedAt = now
+
+	result, err := s.db.Exec(
+		"INSERT INTO environments (name, variables, created_at, updated_at) VALUES (?, ?, ?, ?)",
+		environment.Name, environment.Variables, environment.CreatedAt, environment.UpdatedAt,
+	)
+	if err != nil {
+		return environment, fmt.Errorf("failed to create environment: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return environment, fmt.Errorf("failed to get last insert ID: %w", err)
+	}
+
+	environment.ID = int(id)
+	return environment, nil
+}
+
+// UpdateEnvironment updates an existing environment
+func (s *DBService) UpdateEnvironment(environment models.Environment) (models.Environment, error) {
+	environment.UpdatedAt = time.Now()
+
+	_, err := s.db.Exec(
+		"UPDATE environments SET name = ?, variables = ?, updated_at = ? WHERE id = ?",
+		environment.Name, environment.Variables, environment.UpdatedAt, environment.ID,
+	)
+	if err != nil {
+		return environment, fmt.Errorf("failed to update environment: %w", err)
+	}
+
+	return s.GetEnvironmentByID(environment.ID)
+}
+
+// DeleteEnvironment deletes an environment by ID
+func (s *DBService) DeleteEnvironment(id int) error {
+	// First, detach the environment from any collection using it
+	_, err := s.db.Exec("UPDATE collections SET environment_id = 0 WHERE environment_id = ?", id)
+	if err != nil {
+		return fmt.Errorf("failed to update collections: %w", err)
+	}
+
+	_, err = s.db.Exec("DELETE FROM environments WHERE id = ?", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete environment: %w", err)
+	}
+	return nil
+}
+
+// GetEnvironmentByID gets an environment by ID
+func (s *DBService) GetEnvironmentByID(id int) (models.Environment, error) {
+	var environment models.Environment
+	err := s.db.QueryRow(
+		"SELECT id, name, COALESCE(variables, ''), created_at, updated_at FROM environments WHERE id = ?",
+		id,
+	).Scan(&environment.ID, &environment.Name, &environment.Variables, &environment.CreatedAt, &environment.UpdatedAt)
+	if err != nil {
+		return environment, fmt.Errorf("failed to get environment by ID: %w", err)
+	}
+	return environment, nil
+}
+
+// GetAllEnvironments gets all environments
+func (s *DBService) GetAllEnvironments() ([]models.Environment, error) {
+	rows, err := s.db.Query("SELECT id, name, COALESCE(variables, ''), created_at, updated_at FROM environments ORDER BY name")
+	if err != nil {
+		return nil, fmt.Errorf("failed to query environments: %w", err)
+	}
+	defer rows.Close()
+
+	var environments []models.Environment
+	for rows.Next() {
+		var environment models.Environment
+		if err := rows.Scan(&environment.ID, &environment.Name, &environment.Variables, &environment.CreatedAt, &environment.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan environment row: %w", err)
+		}
+		environments = append(environments, environment)
+	}
+
+	return environments, nil
+}
+
+// SetCollectionEnvironment marks the active environment of a collection (0 to clear it)
+func (s *DBService) SetCollectionEnvironment(collectionID, environmentID int) error {
+	_, err := s.db.Exec(
+		"UPDATE collections SET environment_id = ?, updated_at = ? WHERE id = ?",
+		environmentID, time.Now(), collectionID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to set collection environment: %w", err)
+	}
+	return nil
+}
+
+// globalVariablesSetting is the setting holding the variables available to every API, as a JSON object
+const globalVariablesSetting = "variables.global"
+
+// GetGlobalVariables returns the JSON object of variables available to every API
+func (s *DBService) GetGlobalVariables() (string, error) {
+	return s.GetSetting(globalVariablesSetting)
+}
+
+// SaveGlobalVariables saves the JSON object of variables available to every API
+func (s *DBService) SaveGlobalVariables(variables string) error {
+	return s.SetSetting(globalVariablesSetting, variables)
+}
@@ -0,0 +1,136 @@
+package database
+
+import (
+	"fmt"
+	"time"
+
+	"flowpulse/pkg/models"
+)
+
+// uptimeExecutions is the condition selecting the execution logs that count toward uptime and latency
+// statistics: measured runs outside every expected failure window of their API
+const uptimeExecutions = measuredExecutions + ` AND NOT EXISTS (
+	SELECT 1 FROM expected_failure_windows
+	WHERE expected_failure_windows.api_id = execution_logs.api_id
+		AND execution_logs.executed_at >= expected_failure_windows.starts_at
+		AND execution_logs.executed_at < expected_failure_windows.ends_at
+)`
+
+// initExpectedFailureTables creates the expected failure window table
+func (s *DBService) initExpectedFailureTables() error {
+	_, err := s.db.Exec(`
+		CREATE TABLE IF NOT EXISTS expected_failure_windows (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			api_id INTEGER NOT NULL,
+			starts_at TIMESTAMP NOT NULL,
+			ends_at TIMESTAMP NOT NULL,
+			reason TEXT NOT NULL DEFAULT '',
+			created_at TIMESTAMP NOT NULL,
+			updated_at TIMESTAMP NOT NULL,
+			FOREIGN KEY (api_id) REFERENCES apis (id) ON DELETE CASCADE
+		)
+	`)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.db.Exec(`CREATE INDEX IF NOT EXISTS idx_expected_failure_windows_api ON expected_failure_windows (api_id)`)
+	return err
+}
+
+// expectedFailureWindowColumns is the column list matching scanExpectedFailureWindow
+const expectedFailureWindowColumns = "id, api_id, starts_at, ends_at, reason, created_at, updated_at"
+
+// scanExpectedFailureWindow scans a single window selected with expectedFailureWindowColumns
+func scanExpectedFailureWindow(row rowScanner) (models.ExpectedFailureWindow, error) {
+	var window models.ExpectedFailureWindow
+	err := row.Scan(&window.ID, &window.APIID, &window.StartsAt, &window.EndsAt, &window.Reason, &window.CreatedAt, &window.UpdatedAt)
+	return window, err
+}
+
+// Expected Failure Window Operations
+
+// CreateExpectedFailureWindow creates a new expected failure window.
+// Its bounds are stored in the local zone, like execution times, so the two compare as stored.
+func (s *DBService) CreateExpectedFailureWindow(window models.ExpectedFailureWindow) (models.ExpectedFailureWindow, error) {
+	now := time.Now()
+	window.CreatedAt = now
+	window.UpdatedAt = now
+	window.StartsAt = window.StartsAt.Local()
+	window.EndsAt = window.EndsAt.Local()
+
+	result, err := s.db.Exec(
+		"INSERT INTO expected_failure_windows (api_id, starts_at, ends_at, reason, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?)",
+		window.APIID, window.StartsAt, window.EndsAt, window.Reason, window.CreatedAt, window.UpdatedAt,
+	)
+	if err != nil {
+		return window, fmt.Errorf("failed to create expected failure window: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return window, fmt.Errorf("failed to get last insert ID: %w", err)
+	}
+
+	window.ID = int(id)
+	return window, nil
+}
+
+// UpdateExpectedFailureWindow updates an existing expected failure window
+func (s *DBService) UpdateExpectedFailureWindow(window models.ExpectedFailureWindow) (models.ExpectedFailureWindow, error) {
+	window.UpdatedAt = time.Now()
+	window.StartsAt = window.StartsAt.Local()
+	window.EndsAt = window.EndsAt.Local()
+
+	_, err := s.db.Exec(
+		"UPDATE expected_failure_windows SET api_id = ?, starts_at = ?, ends_at = ?, reason = ?, updated_at = ? WHERE id = ?",
+		window.APIID, window.StartsAt, window.EndsAt, window.Reason, window.UpdatedAt, window.ID,
+	)
+	if err != nil {
+		return window, fmt.Errorf("failed to update expected failure window: %w", err)
+	}
+	return s.GetExpectedFailureWindowByID(window.ID)
+}
+
+// DeleteExpectedFailureWindow deletes an expected failure window by ID
+func (s *DBService) DeleteExpectedFailureWindow(id int) error {
+	_, err := s.db.Exec("DELETE FROM expected_failure_windows WHERE id = ?", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete expected failure window: %w", err)
+	}
+	return nil
+}
+
+// GetExpectedFailureWindowByID gets an expected failure window by ID
+func (s *DBService) GetExpectedFailureWindowByID(id int) (models.ExpectedFailureWindow, error) {
+	window, err := scanExpectedFailureWindow(s.db.QueryRow(
+		"SELECT "+expectedFailureWindowColumns+" FROM expected_failure_windows WHERE id = ?", id,
+	))
+	if err != nil {
+		return window, fmt.Errorf("failed to get expected failure window by ID: %w", err)
+	}
+	return window, nil
+}
+
+// GetExpectedFailureWindowsByAPIID gets the expected failure windows of an API, newest first
+func (s *DBService) GetExpectedFailureWindowsByAPIID(apiID int) ([]models.ExpectedFailureWindow, error) {
+	rows, err := s.db.Query(
+		"SELECT "+expectedFailureWindowColumns+" FROM expected_failure_windows WHERE api_id = ? ORDER BY starts_at DESC, id DESC",
+		apiID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query expected failure windows: %w", err)
+	}
+	defer rows.Close()
+
+	var windows []models.ExpectedFailureWindow
+	for rows.Next() {
+		window, err := scanExpectedFailureWindow(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan expected failure window row: %w", err)
+		}
+		windows = append(windows, window)
+	}
+
+	return windows, nil
+}
@@ -0,0 +1,97 @@
+package database
+
+import (
+	"fmt"
+	"time"
+
+	"flowpulse/pkg/models"
+)
+
+// initExtractionsTables creates the table of response extractions used for request chaining
+func (s *DBService) initExtractionsTables() error {
+	_, err := s.db.Exec(`
+		CREATE TABLE IF NOT EXISTS extractions (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			api_id INTEGER NOT NULL,
+			variable TEXT NOT NULL,
+			source TEXT NOT NULL,
+			expression TEXT NOT NULL,
+			created_at TIMESTAMP NOT NULL,
+			updated_at TIMESTAMP NOT NULL,
+			FOREIGN KEY (api_id) REFERENCES apis (id) ON DELETE CASCADE
+		)
+	`)
+	return err
+}
+
+// Extraction Operations
+
+// CreateExtraction creates a new extraction
+func (s *DBService) CreateExtraction(extraction models.Extraction) (models.Extraction, error) {
+	now := time.Now()
+	extraction.CreatedAt = now
+	extraction.UpdatedAt = now
+
+	result, err := s.db.Exec(
+		"INSERT INTO extractions (api_id, variable, source, expression, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?)",
+		extraction.APIID, extraction.Variable, extraction.Source, extraction.Expression, extraction.CreatedAt, extraction.UpdatedAt,
+	)
+	if err != nil {
+		return extraction, fmt.Errorf("failed to create extraction: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return extraction, fmt.Errorf("failed to get last insert ID: %w", err)
+	}
+
+	extraction.ID = int(id)
+	return extraction, nil
+}
+
+// UpdateExtraction updates an existing extraction
+func (s *DBService) UpdateExtraction(extraction models.Extraction) (models.Extraction, error) {
+	extraction.UpdatedAt = time.Now()
+
+	_, err := s.db.Exec(
+		"UPDATE extractions SET variable = ?, source = ?, expression = ?, updated_at = ? WHERE id = ?",
+		extraction.Variable, extraction.Source, extraction.Expression, extraction.UpdatedAt, extraction.ID,
+	)
+	if err != nil {
+		return extraction, fmt.Errorf("failed to update extraction: %w", err)
+	}
+	return extraction, nil
+}
+
+// DeleteExtraction deletes an extraction by ID
+func (s *DBService) DeleteExtraction(id int) error {
+	_, err := s.db.Exec("DELETE FROM extractions WHERE id = ?", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete extraction: %w", err)
+	}
+	return nil
+}
+
+// GetExtractionsByAPIID gets all extractions of an API
+func (s *DBService) GetExtractionsByAPIID(apiID int) ([]models.Extraction, error) {
+	rows, err := s.db.Query(
+		"SELECT id, api_id, variable, source, expression, created_at, updated_at FROM extractions WHERE api_id = ? ORDER BY id",
+		apiID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query extractions: %w", err)
+	}
+	defer rows.Close()
+
+	var extractions []models.Extraction
+	for rows.Next() {
+		var extraction models.Extraction
+		if err := rows.Scan(&extraction.ID, &extraction.APIID, &extraction.Variable, &extraction.Source,
+			&extraction.Expression, &extraction.CreatedAt, &extraction.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan extraction row: %w", err)
+		}
+		extractions = append(extractions, extraction)
+	}
+
+	return extractions, nil
+}
@@ -0,0 +1,108 @@
+package database
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"flowpulse/pkg/models"
+)
+
+// ReplaceInAPIs finds and replaces text in the URLs and bodies of APIs. With apply unset it only
+// previews the changes; otherwise every API is updated in one transaction, so a failure leaves
+// all of them untouched.
+func (s *DBService) ReplaceInAPIs(request models.FindReplace, apply bool) (models.ReplaceResult, error) {
+	result := models.ReplaceResult{Matches: []models.ReplaceMatch{}}
+
+	replace, err := newReplacer(request)
+	if err != nil {
+		return result, err
+	}
+
+	var apis []models.API
+	if request.CollectionID != 0 {
+		apis, err = s.GetAPIsByCollectionID(request.CollectionID)
+	} else {
+		apis, err = s.GetAllAPIs()
+	}
+	if err != nil {
+		return result, err
+	}
+
+	var changed []models.API
+	for _, api := range apis {
+		before := api
+		if request.InURLs {
+			api.URL = replace(api.URL)
+			if api.URL != before.URL {
+				result.Matches = append(result.Matches, models.ReplaceMatch{APIID: api.ID, APIName: api.Name, Field: "url", Before: before.URL, After: api.URL})
+			}
+		}
+		if request.InBodies {
+			api.Body = replace(api.Body)
+			if api.Body != before.Body {
+				result.Matches = append(result.Matches, models.ReplaceMatch{APIID: api.ID, APIName: api.Name, Field: "body", Before: before.Body, After: api.Body})
+			}
+		}
+		if api.URL != before.URL || api.Body != before.Body {
+			changed = append(changed, api)
+		}
+	}
+	result.APIsChanged = len(changed)
+	if !apply || len(changed) == 0 {
+		return result, nil
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return result, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	for _, api := range changed {
+		if _, err := tx.Exec("UPDATE apis SET url = ?, body = ?, updated_at = ? WHERE id = ?", api.URL, api.Body, now, api.ID); err != nil {
+			return result, fmt.Errorf("failed to update API %s: %w", api.Name, err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return result, fmt.Errorf("failed to commit replacement: %w", err)
+	}
+	result.Applied = true
+	return result, nil
+}
+
+// newReplacer compiles a find and replace into a function applying it to a text
+func newReplacer(request models.FindReplace) (func(string) string, error) {
+	if request.Find == "" {
+		return nil, fmt.Errorf("text to find is required")
+	}
+	if !request.InURLs && !request.InBodies {
+		return nil, fmt.Errorf("select URLs, bodies or both to replace in")
+	}
+
+	if !request.Regex && !request.IgnoreCase {
+		return func(text string) string {
+			return strings.ReplaceAll(text, request.Find, request.Replace)
+		}, nil
+	}
+
+	pattern := request.Find
+	replacement := request.Replace
+	if !request.Regex {
+		// A literal replacement must not expand $ references
+		pattern = regexp.QuoteMeta(pattern)
+		replacement = strings.ReplaceAll(replacement, "$", "$$")
+	}
+	if request.IgnoreCase {
+		pattern = "(?i)" + pattern
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid regular expression: %w", err)
+	}
+	return func(text string) string {
+		return re.ReplaceAllString(text, replacement)
+	}, nil
+}
@@ -0,0 +1,294 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"flowpulse/pkg/models"
+)
+
+// initIncidentTables creates the incident, incident history and alert snooze tables
+func (s *DBService) initIncidentTables() error {
+	_, err := s.db.Exec(`
+		CREATE TABLE IF NOT EXISTS incidents (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			api_id INTEGER NOT NULL,
+			status TEXT NOT NULL,
+			opened_at TIMESTAMP NOT NULL,
+			acknowledged_at TIMESTAMP,
+			resolved_at TIMESTAMP,
+			FOREIGN KEY (api_id) REFERENCES apis (id) ON DELETE CASCADE
+		)
+	`)
+	if err != nil {
+		return err
+	}
+	if err := s.addColumnIfMissing("incidents", "duration_seconds", "INTEGER"); err != nil {
+		return err
+	}
+
+	_, err = s.db.Exec(`
+		CREATE TABLE IF NOT EXISTS incident_events (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			incident_id INTEGER NOT NULL,
+			type TEXT NOT NULL,
+			note TEXT,
+			created_at TIMESTAMP NOT NULL,
+			FOREIGN KEY (incident_id) REFERENCES incidents (id) ON DELETE CASCADE
+		)
+	`)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.db.Exec(`
+		CREATE TABLE IF NOT EXISTS alert_snoozes (
+			api_id INTEGER PRIMARY KEY,
+			snoozed_until TIMESTAMP NOT NULL,
+			FOREIGN KEY (api_id) REFERENCES apis (id) ON DELETE CASCADE
+		)
+	`)
+	return err
+}
+
+// settingIncidentFailureThreshold is the settings key of the consecutive failures that open an incident
+const settingIncidentFailureThreshold = "incidents.failure_threshold"
+
+// incidentColumns is the column list matching scanIncident
+const incidentColumns = `id, api_id, status, opened_at, acknowledged_at, resolved_at, duration_seconds`
+
+// scanIncident scans a single incident selected with incidentColumns
+func scanIncident(row rowScanner) (models.Incident, error) {
+	var incident models.Incident
+	var duration sql.NullInt64
+	err := row.Scan(&incident.ID, &incident.APIID, &incident.Status, &incident.OpenedAt, &incident.AcknowledgedAt, &incident.ResolvedAt, &duration)
+	if err != nil {
+		return incident, err
+	}
+	incident.DurationSeconds = incidentDuration(incident, duration, time.Now())
+	return incident, nil
+}
+
+// incidentDuration returns how long an incident lasted: the recorded duration once it's resolved, the time since
+// it opened while it isn't, and the time between opening and resolving for incidents resolved before durations were recorded
+func incidentDuration(incident models.Incident, recorded sql.NullInt64, now time.Time) int64 {
+	switch {
+	case recorded.Valid:
+		return recorded.Int64
+	case incident.ResolvedAt != nil:
+		return int64(incident.ResolvedAt.Sub(incident.OpenedAt).Seconds())
+	default:
+		return int64(now.Sub(incident.OpenedAt).Seconds())
+	}
+}
+
+// queryIncidents runs an incident query and scans every row
+func (s *DBService) queryIncidents(query string, args ...interface{}) ([]models.Incident, error) {
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query incidents: %w", err)
+	}
+	defer rows.Close()
+
+	var incidents []models.Incident
+	for rows.Next() {
+		incident, err := scanIncident(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan incident row: %w", err)
+		}
+		incidents = append(incidents, incident)
+	}
+
+	return incidents, s.attachIncidentAnnotations(incidents)
+}
+
+// Incident Operations
+
+// CreateIncident opens a new incident for an API and records it in the incident history
+func (s *DBService) CreateIncident(apiID int) (models.Incident, error) {
+	incident := models.Incident{APIID: apiID, Status: models.IncidentOpen, OpenedAt: time.Now()}
+
+	result, err := s.db.Exec(
+		"INSERT INTO incidents (api_id, status, opened_at) VALUES (?, ?, ?)",
+		incident.APIID, incident.Status, incident.OpenedAt,
+	)
+	if err != nil {
+		return incident, fmt.Errorf("failed to create incident: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return incident, fmt.Errorf("failed to get last insert ID: %w", err)
+	}
+	incident.ID = int(id)
+
+	return incident, s.AddIncidentEvent(incident.ID, models.IncidentEventOpened, "")
+}
+
+// GetIncidentByID gets an incident by ID
+func (s *DBService) GetIncidentByID(id int) (models.Incident, error) {
+	incident, err := scanIncident(s.db.QueryRow("SELECT "+incidentColumns+" FROM incidents WHERE id = ?", id))
+	if err != nil {
+		return incident, fmt.Errorf("failed to get incident by ID: %w", err)
+	}
+	incidents := []models.Incident{incident}
+	if err := s.attachIncidentAnnotations(incidents); err != nil {
+		return incident, err
+	}
+	return incidents[0], nil
+}
+
+// GetOpenIncidentByAPIID gets the unresolved incident of an API, reporting whether there is one
+func (s *DBService) GetOpenIncidentByAPIID(apiID int) (models.Incident, bool, error) {
+	incident, err := scanIncident(s.db.QueryRow(
+		"SELECT "+incidentColumns+" FROM incidents WHERE api_id = ? AND status != ? ORDER BY opened_at DESC LIMIT 1",
+		apiID, models.IncidentResolved,
+	))
+	if errors.Is(err, sql.ErrNoRows) {
+		return incident, false, nil
+	}
+	if err != nil {
+		return incident, false, fmt.Errorf("failed to get open incident: %w", err)
+	}
+	return incident, true, nil
+}
+
+// GetOpenIncidents gets every unresolved incident
+func (s *DBService) GetOpenIncidents() ([]models.Incident, error) {
+	return s.queryIncidents(
+		"SELECT "+incidentColumns+" FROM incidents WHERE status != ? ORDER BY opened_at DESC",
+		models.IncidentResolved,
+	)
+}
+
+// GetIncidentPolicy gets the policy deciding when an API's failures open an incident
+func (s *DBService) GetIncidentPolicy() (models.IncidentPolicy, error) {
+	threshold, err := s.getIntSetting(settingIncidentFailureThreshold)
+	if err != nil {
+		return models.IncidentPolicy{}, err
+	}
+	return models.IncidentPolicy{FailureThreshold: threshold}, nil
+}
+
+// SaveIncidentPolicy saves the policy deciding when an API's failures open an incident
+func (s *DBService) SaveIncidentPolicy(policy models.IncidentPolicy) error {
+	return s.SetSetting(settingIncidentFailureThreshold, strconv.Itoa(policy.FailureThreshold))
+}
+
+// GetIncidentsByAPIID gets the incidents of an API, newest first
+func (s *DBService) GetIncidentsByAPIID(apiID int) ([]models.Incident, error) {
+	return s.queryIncidents("SELECT "+incidentColumns+" FROM incidents WHERE api_id = ? ORDER BY opened_at DESC", apiID)
+}
+
+// AcknowledgeIncident marks an open incident as acknowledged and records it in the incident history
+func (s *DBService) AcknowledgeIncident(id int, note string) error {
+	result, err := s.db.Exec(
+		"UPDATE incidents SET status = ?, acknowledged_at = ? WHERE id = ? AND status = ?",
+		models.IncidentAcknowledged, time.Now(), id, models.IncidentOpen,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to acknowledge incident: %w", err)
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return fmt.Errorf("incident %d is not open", id)
+	}
+	return s.AddIncidentEvent(id, models.IncidentEventAcknowledged, note)
+}
+
+// ResolveIncident closes an incident, recording how long it lasted, and records it in the incident history
+func (s *DBService) ResolveIncident(id int) error {
+	var openedAt time.Time
+	if err := s.db.QueryRow("SELECT opened_at FROM incidents WHERE id = ?", id).Scan(&openedAt); err != nil {
+		return fmt.Errorf("failed to get incident by ID: %w", err)
+	}
+
+	resolvedAt := time.Now()
+	_, err := s.db.Exec(
+		"UPDATE incidents SET status = ?, resolved_at = ?, duration_seconds = ? WHERE id = ?",
+		models.IncidentResolved, resolvedAt, int64(resolvedAt.Sub(openedAt).Seconds()), id,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to resolve incident: %w", err)
+	}
+	return s.AddIncidentEvent(id, models.IncidentEventResolved, "")
+}
+
+// AddIncidentEvent appends an entry to the history of an incident
+func (s *DBService) AddIncidentEvent(incidentID int, eventType, note string) error {
+	_, err := s.db.Exec(
+		"INSERT INTO incident_events (incident_id, type, note, created_at) VALUES (?, ?, ?, ?)",
+		incidentID, eventType, note, time.Now(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to add incident event: %w", err)
+	}
+	return nil
+}
+
+// GetIncidentEvents gets the history of an incident, oldest first
+func (s *DBService) GetIncidentEvents(incidentID int) ([]models.IncidentEvent, error) {
+	rows, err := s.db.Query(
+		"SELECT id, incident_id, type, COALESCE(note, ''), created_at FROM incident_events WHERE incident_id = ? ORDER BY created_at, id",
+		incidentID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query incident events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []models.IncidentEvent
+	for rows.Next() {
+		var event models.IncidentEvent
+		if err := rows.Scan(&event.ID, &event.IncidentID, &event.Type, &event.Note, &event.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan incident event row: %w", err)
+		}
+		events = append(events, event)
+	}
+
+	return events, nil
+}
+
+// Alert Snooze Operations
+
+// SnoozeAlerts suppresses notifications for an API until the given time.
+// The snooze is recorded in the history of the API's open incident, if any.
+func (s *DBService) SnoozeAlerts(apiID int, until time.Time) error {
+	_, err := s.db.Exec(
+		"INSERT INTO alert_snoozes (api_id, snoozed_until) VALUES (?, ?) ON CONFLICT(api_id) DO UPDATE SET snoozed_until = excluded.snoozed_until",
+		apiID, until,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to snooze alerts: %w", err)
+	}
+
+	incident, open, err := s.GetOpenIncidentByAPIID(apiID)
+	if err != nil || !open {
+		return err
+	}
+	return s.AddIncidentEvent(incident.ID, models.IncidentEventSnoozed, "Alerts snoozed until "+until.Format(time.RFC3339))
+}
+
+// ClearAlertSnooze removes the snooze of an API
+func (s *DBService) ClearAlertSnooze(apiID int) error {
+	_, err := s.db.Exec("DELETE FROM alert_snoozes WHERE api_id = ?", apiID)
+	if err != nil {
+		return fmt.Errorf("failed to clear alert snooze: %w", err)
+	}
+	return nil
+}
+
+// GetAlertSnoozedUntil gets the time an API's alerts are snoozed until (zero when not snoozed)
+func (s *DBService) GetAlertSnoozedUntil(apiID int) (time.Time, error) {
+	var until time.Time
+	err := s.db.QueryRow("SELECT snoozed_until FROM alert_snoozes WHERE api_id = ?", apiID).Scan(&until)
+	if errors.Is(err, sql.ErrNoRows) {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return until, fmt.Errorf("failed to get alert snooze: %w", err)
+	}
+	return until, nil
+}
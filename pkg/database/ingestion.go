@@ -0,0 +1,48 @@
+package database
+
+import (
+	"strconv"
+
+	"flowpulse/pkg/models"
+)
+
+// Settings keys of the log ingestion guard
+const (
+	settingIngestionMaxLogs        = "ingestion.max_logs_per_minute"
+	settingIngestionMaxBytes       = "ingestion.max_bytes_per_minute"
+	settingIngestionSampleRate     = "ingestion.sample_rate"
+	settingIngestionAlertChannelID = "ingestion.alert_channel_id"
+)
+
+// GetIngestionGuard gets the limits on log volume above which successes are sampled
+func (s *DBService) GetIngestionGuard() (models.IngestionGuard, error) {
+	var guard models.IngestionGuard
+	var err error
+	if guard.MaxLogsPerMinute, err = s.getIntSetting(settingIngestionMaxLogs); err != nil {
+		return guard, err
+	}
+	if guard.MaxBytesPerMinute, err = s.getIntSetting(settingIngestionMaxBytes); err != nil {
+		return guard, err
+	}
+	if guard.SampleRate, err = s.getIntSetting(settingIngestionSampleRate); err != nil {
+		return guard, err
+	}
+	if guard.AlertChannelID, err = s.getIntSetting(settingIngestionAlertChannelID); err != nil {
+		return guard, err
+	}
+	return guard, nil
+}
+
+// SaveIngestionGuard saves the limits on log volume above which successes are sampled
+func (s *DBService) SaveIngestionGuard(guard models.IngestionGuard) error {
+	if err := s.SetSetting(settingIngestionMaxLogs, strconv.Itoa(guard.MaxLogsPerMinute)); err != nil {
+		return err
+	}
+	if err := s.SetSetting(settingIngestionMaxBytes, strconv.Itoa(guard.MaxBytesPerMinute)); err != nil {
+		return err
+	}
+	if err := s.SetSetting(settingIngestionSampleRate, strconv.Itoa(guard.SampleRate)); err != nil {
+		return err
+	}
+	return s.SetSetting(settingIngestionAlertChannelID, strconv.Itoa(guard.AlertChannelID))
+}
@@ -0,0 +1,198 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mattn/go-sqlite3"
+
+	"flowpulse/pkg/models"
+)
+
+// snapshotInterval is how often a copy of a healthy database is kept to restore from after corruption
+const snapshotInterval = 24 * time.Hour
+
+// snapshotsKept is how many database snapshots are kept; older ones are removed
+const snapshotsKept = 3
+
+// snapshotTimeLayout formats the time a snapshot was taken in its file name, so names sort by age
+const snapshotTimeLayout = "20060102-150405"
+
+// integrityProblemsReported is how many problems PRAGMA integrity_check reports at most
+const integrityProblemsReported = 10
+
+// CorruptionError reports a database file that fails SQLite's integrity check or isn't a database at all
+type CorruptionError struct {
+	Path     string
+	Problems []string
+}
+
+func (e *CorruptionError) Error() string {
+	return fmt.Sprintf("database %s is corrupt: %s", e.Path, strings.Join(e.Problems, "; "))
+}
+
+// checkIntegrity runs PRAGMA integrity_check, returning a CorruptionError listing the problems found
+func (s *DBService) checkIntegrity() error {
+	rows, err := s.db.Query(fmt.Sprintf("PRAGMA integrity_check(%d)", integrityProblemsReported))
+	if err != nil {
+		return s.corruption(err)
+	}
+	defer rows.Close()
+
+	var problems []string
+	for rows.Next() {
+		var problem string
+		if err := rows.Scan(&problem); err != nil {
+			return s.corruption(err)
+		}
+		if problem != "ok" {
+			problems = append(problems, problem)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return s.corruption(err)
+	}
+	if len(problems) > 0 {
+		return &CorruptionError{Path: s.path, Problems: problems}
+	}
+	return nil
+}
+
+// corruption turns an error reading the database into a CorruptionError when SQLite found the file
+// damaged or not a database, and wraps it otherwise
+func (s *DBService) corruption(err error) error {
+	var sqliteErr sqlite3.Error
+	if errors.As(err, &sqliteErr) && (sqliteErr.Code == sqlite3.ErrCorrupt || sqliteErr.Code == sqlite3.ErrNotADB) {
+		return &CorruptionError{Path: s.path, Problems: []string{sqliteErr.Error()}}
+	}
+	return fmt.Errorf("failed to check database integrity: %w", err)
+}
+
+// SnapshotDir returns the directory holding the snapshots of the database at dbPath
+func SnapshotDir(dbPath string) string {
+	return filepath.Join(filepath.Dir(dbPath), "backups")
+}
+
+// Snapshots returns the snapshots of the database at dbPath, newest first
+func Snapshots(dbPath string) ([]models.DatabaseSnapshot, error) {
+	entries, err := os.ReadDir(SnapshotDir(dbPath))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list database snapshots: %w", err)
+	}
+
+	prefix := strings.TrimSuffix(filepath.Base(dbPath), filepath.Ext(dbPath)) + "-"
+	var snapshots []models.DatabaseSnapshot
+	for _, entry := range entries {
+		stamp, ok := strings.CutPrefix(entry.Name(), prefix)
+		if !ok || entry.IsDir() {
+			continue
+		}
+		// Requiring a timestamp after the prefix leaves out the snapshots of other profiles sharing the prefix
+		takenAt, err := time.ParseInLocation(snapshotTimeLayout, strings.TrimSuffix(stamp, ".db"), time.Local)
+		if err != nil || !strings.HasSuffix(stamp, ".db") {
+			continue
+		}
+		snapshots = append(snapshots, models.DatabaseSnapshot{
+			Path:    filepath.Join(SnapshotDir(dbPath), entry.Name()),
+			TakenAt: takenAt,
+		})
+	}
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].TakenAt.After(snapshots[j].TakenAt)
+	})
+	return snapshots, nil
+}
+
+// SnapshotIfDue copies the database to its snapshot directory when the latest snapshot is older than
+// snapshotInterval, removing all but the newest snapshotsKept. Databases are checked on opening, so
+// only healthy ones are copied.
+func (s *DBService) SnapshotIfDue() error {
+	snapshots, err := Snapshots(s.path)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	if len(snapshots) > 0 && now.Sub(snapshots[0].TakenAt) < snapshotInterval {
+		return nil
+	}
+
+	if err := os.MkdirAll(SnapshotDir(s.path), 0755); err != nil {
+		return fmt.Errorf("failed to create snapshot directory: %w", err)
+	}
+	name := strings.TrimSuffix(filepath.Base(s.path), filepath.Ext(s.path)) + "-" + now.Format(snapshotTimeLayout) + ".db"
+	path := filepath.Join(SnapshotDir(s.path), name)
+
+	// Written under another name first, so an interrupted snapshot is never taken for the latest one
+	partial := path + ".partial"
+	os.Remove(partial)
+	if _, err := s.db.Exec("VACUUM INTO ?", partial); err != nil {
+		os.Remove(partial)
+		return fmt.Errorf("failed to snapshot database: %w", err)
+	}
+	if err := os.Rename(partial, path); err != nil {
+		return fmt.Errorf("failed to snapshot database: %w", err)
+	}
+
+	snapshots = append([]models.DatabaseSnapshot{{Path: path, TakenAt: now}}, snapshots...)
+	for _, old := range snapshots[min(len(snapshots), snapshotsKept):] {
+		if err := os.Remove(old.Path); err != nil {
+			return fmt.Errorf("failed to remove old snapshot: %w", err)
+		}
+	}
+	return nil
+}
+
+// RestoreLatestSnapshot replaces the database at dbPath with its latest snapshot. The database is moved
+// aside rather than removed, along with its journal files so SQLite doesn't apply them to the snapshot.
+func RestoreLatestSnapshot(dbPath string) (models.DatabaseRestore, error) {
+	var restore models.DatabaseRestore
+	snapshots, err := Snapshots(dbPath)
+	if err != nil {
+		return restore, err
+	}
+	if len(snapshots) == 0 {
+		return restore, fmt.Errorf("no snapshot of %s to restore from", dbPath)
+	}
+	restore.Snapshot = snapshots[0]
+	restore.CorruptPath = dbPath + ".corrupt-" + time.Now().Format(snapshotTimeLayout)
+
+	for _, suffix := range []string{"", "-wal", "-shm", "-journal"} {
+		err := os.Rename(dbPath+suffix, restore.CorruptPath+suffix)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return restore, fmt.Errorf("failed to move corrupt database aside: %w", err)
+		}
+	}
+
+	if err := copyFile(restore.Snapshot.Path, dbPath); err != nil {
+		return restore, fmt.Errorf("failed to restore snapshot: %w", err)
+	}
+	return restore, nil
+}
+
+// copyFile copies the file at src to dst, replacing it
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,179 @@
+package database
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"flowpulse/pkg/models"
+)
+
+// latencyBucketBoundsMs are the upper bounds, inclusive, of the fixed latency histogram buckets. A last bucket
+// holds everything slower. Changing them would mix up the counts already stored, so only append to them
+// along with a migration adding the new column.
+var latencyBucketBoundsMs = []int64{
+	5, 10, 25, 50, 75, 100, 150, 200, 250, 300, 400, 500, 750,
+	1000, 1500, 2000, 3000, 5000, 7500, 10000, 20000, 30000, 60000,
+}
+
+// latencyBucketColumn is the rollup column counting the executions of a histogram bucket
+func latencyBucketColumn(bucket int) string {
+	return fmt.Sprintf("bucket_%d", bucket)
+}
+
+// latencyBucketCondition is the SQL condition selecting the durations of a histogram bucket
+func latencyBucketCondition(duration string, bucket int) string {
+	var conditions []string
+	if bucket > 0 {
+		conditions = append(conditions, fmt.Sprintf("%s > %d", duration, latencyBucketBoundsMs[bucket-1]))
+	}
+	if bucket < len(latencyBucketBoundsMs) {
+		conditions = append(conditions, fmt.Sprintf("%s <= %d", duration, latencyBucketBoundsMs[bucket]))
+	}
+	return "(" + strings.Join(conditions, " AND ") + ")"
+}
+
+// initLatencyRollupTables creates the hourly latency rollups of each API and the trigger adding every
+// measured execution to them, filling a new table from the execution logs kept so far. Rollups aren't
+// removed along with old logs, so percentiles stay available long after log retention has pruned them.
+func (s *DBService) initLatencyRollupTables() error {
+	var exists bool
+	if err := s.db.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE name = 'latency_rollups'").Scan(&exists); err != nil {
+		return fmt.Errorf("failed to check for latency rollups table: %w", err)
+	}
+
+	buckets := len(latencyBucketBoundsMs) + 1
+	var definitions, columns, counts, sums, updates []string
+	for bucket := 0; bucket < buckets; bucket++ {
+		column := latencyBucketColumn(bucket)
+		definitions = append(definitions, column+" INTEGER NOT NULL DEFAULT 0")
+		columns = append(columns, column)
+		counts = append(counts, latencyBucketCondition("new.duration_ms", bucket))
+		sums = append(sums, fmt.Sprintf("SUM(%s)", latencyBucketCondition("duration_ms", bucket)))
+		updates = append(updates, fmt.Sprintf("%[1]s = %[1]s + excluded.%[1]s", column))
+	}
+
+	if !exists {
+		_, err := s.db.Exec(fmt.Sprintf(`
+			CREATE TABLE latency_rollups (
+				api_id INTEGER NOT NULL,
+				hour TEXT NOT NULL,
+				executions INTEGER NOT NULL DEFAULT 0,
+				total_ms INTEGER NOT NULL DEFAULT 0,
+				max_ms INTEGER NOT NULL DEFAULT 0,
+				%s,
+				PRIMARY KEY (api_id, hour)
+			)
+		`, strings.Join(definitions, ",\n")))
+		if err != nil {
+			return fmt.Errorf("failed to create latency rollups table: %w", err)
+		}
+
+		_, err = s.db.Exec(fmt.Sprintf(`
+			INSERT INTO latency_rollups (api_id, hour, executions, total_ms, max_ms, %s)
+			SELECT api_id, substr(executed_at, 1, 13), COUNT(*), SUM(duration_ms), MAX(duration_ms), %s
+			FROM execution_logs
+			WHERE %s AND duration_ms IS NOT NULL
+			GROUP BY api_id, substr(executed_at, 1, 13)
+		`, strings.Join(columns, ", "), strings.Join(sums, ", "), measuredExecutions))
+		if err != nil {
+			return fmt.Errorf("failed to fill latency rollups: %w", err)
+		}
+	}
+
+	// The WHERE clause also keeps SQLite from reading ON CONFLICT as part of the SELECT
+	_, err := s.db.Exec(fmt.Sprintf(`
+		CREATE TRIGGER IF NOT EXISTS latency_rollups_insert AFTER INSERT ON execution_logs BEGIN
+			INSERT INTO latency_rollups (api_id, hour, executions, total_ms, max_ms, %s)
+			SELECT new.api_id, substr(new.executed_at, 1, 13), 1, new.duration_ms, new.duration_ms, %s
+			WHERE COALESCE(new.status, '') != '%s' AND new.duration_ms IS NOT NULL
+			ON CONFLICT (api_id, hour) DO UPDATE SET
+				executions = executions + 1,
+				total_ms = total_ms + excluded.total_ms,
+				max_ms = MAX(max_ms, excluded.max_ms),
+				%s;
+		END
+	`, strings.Join(columns, ", "), strings.Join(counts, ", "), models.ExecutionStatusSkipped, strings.Join(updates, ",\n")))
+	if err != nil {
+		return fmt.Errorf("failed to create latency rollups trigger: %w", err)
+	}
+	return nil
+}
+
+// startOfHour returns the start of the local hour containing t
+func startOfHour(t time.Time) time.Time {
+	t = t.Local()
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, time.Local)
+}
+
+// GetLatencyHistogram adds up an API's hourly latency rollups over the hours overlapping from until to,
+// and estimates percentiles from the resulting histogram
+func (s *DBService) GetLatencyHistogram(apiID int, from, to time.Time) (models.LatencyHistogram, error) {
+	from = startOfHour(from)
+	if end := startOfHour(to); end.Before(to) {
+		to = end.Add(time.Hour)
+	}
+	histogram := models.LatencyHistogram{
+		APIID:          apiID,
+		From:           from,
+		To:             to,
+		BucketBoundsMs: latencyBucketBoundsMs,
+		Counts:         make([]int64, len(latencyBucketBoundsMs)+1),
+	}
+
+	sums := make([]string, len(histogram.Counts))
+	for bucket := range sums {
+		sums[bucket] = fmt.Sprintf("COALESCE(SUM(%s), 0)", latencyBucketColumn(bucket))
+	}
+	query := fmt.Sprintf(`
+		SELECT COALESCE(SUM(executions), 0), COALESCE(SUM(total_ms), 0), COALESCE(MAX(max_ms), 0), %s
+		FROM latency_rollups
+		WHERE api_id = ? AND hour >= ? AND hour < ?
+	`, strings.Join(sums, ", "))
+
+	var totalMs int64
+	dest := []interface{}{&histogram.Executions, &totalMs, &histogram.MaxTimeMs}
+	for bucket := range histogram.Counts {
+		dest = append(dest, &histogram.Counts[bucket])
+	}
+	err := s.db.QueryRow(query, apiID, from.Format("2006-01-02 15"), to.Local().Format("2006-01-02 15")).Scan(dest...)
+	if err != nil {
+		return histogram, fmt.Errorf("failed to get latency histogram: %w", err)
+	}
+
+	if histogram.Executions > 0 {
+		histogram.AverageTimeMs = float64(totalMs) / float64(histogram.Executions)
+		histogram.P50TimeMs = histogramPercentile(histogram, 50)
+		histogram.P95TimeMs = histogramPercentile(histogram, 95)
+		histogram.P99TimeMs = histogramPercentile(histogram, 99)
+	}
+	return histogram, nil
+}
+
+// histogramPercentile estimates a percentile by interpolating within the bucket holding it, taking the
+// slowest execution as the upper bound of the last bucket
+func histogramPercentile(histogram models.LatencyHistogram, percentile float64) int64 {
+	rank := int64(math.Ceil(float64(histogram.Executions) * percentile / 100))
+	var below int64
+	for bucket, count := range histogram.Counts {
+		if count == 0 || below+count < rank {
+			below += count
+			continue
+		}
+
+		var lower int64
+		if bucket > 0 {
+			lower = histogram.BucketBoundsMs[bucket-1]
+		}
+		upper := histogram.MaxTimeMs
+		if bucket < len(histogram.BucketBoundsMs) && histogram.BucketBoundsMs[bucket] < upper {
+			upper = histogram.BucketBoundsMs[bucket]
+		}
+		if upper < lower {
+			return upper
+		}
+		return lower + int64(math.Round(float64(upper-lower)*float64(rank-below)/float64(count)))
+	}
+	return histogram.MaxTimeMs
+}
@@ -0,0 +1,179 @@
+package database
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"flowpulse/pkg/models"
+)
+
+// initLogFilterTables creates the saved log filters table
+func (s *DBService) initLogFilterTables() error {
+	_, err := s.db.Exec(`
+		CREATE TABLE IF NOT EXISTS saved_log_filters (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT NOT NULL,
+			filter TEXT NOT NULL,
+			created_at TIMESTAMP NOT NULL,
+			updated_at TIMESTAMP NOT NULL
+		)
+	`)
+	return err
+}
+
+// likeEscaper escapes the wildcards of a LIKE pattern, using \ as the escape character
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// SearchExecutionLogs gets the newest execution logs matching a filter. The text query is matched
+// case-insensitively against the error and the response kept in the log row.
+func (s *DBService) SearchExecutionLogs(filter models.LogFilter, limit int) ([]models.ExecutionLog, error) {
+	var conditions []string
+	var args []interface{}
+
+	if len(filter.APIIDs) > 0 {
+		placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(filter.APIIDs)), ", ")
+		conditions = append(conditions, "api_id IN ("+placeholders+")")
+		for _, apiID := range filter.APIIDs {
+			args = append(args, apiID)
+		}
+	}
+	if filter.StatusCodeMin > 0 {
+		conditions = append(conditions, "status_code >= ?")
+		args = append(args, filter.StatusCodeMin)
+	}
+	if filter.StatusCodeMax > 0 {
+		conditions = append(conditions, "status_code <= ?")
+		args = append(args, filter.StatusCodeMax)
+	}
+	if filter.Status != "" {
+		conditions = append(conditions, "COALESCE(status, '') = ?")
+		args = append(args, filter.Status)
+	}
+	if query := strings.TrimSpace(filter.Query); query != "" {
+		pattern := "%" + likeEscaper.Replace(query) + "%"
+		conditions = append(conditions, `(response LIKE ? ESCAPE '\' OR error LIKE ? ESCAPE '\')`)
+		args = append(args, pattern, pattern)
+	}
+	if filter.PeriodHours > 0 {
+		conditions = append(conditions, "executed_at >= ?")
+		args = append(args, time.Now().Add(-time.Duration(filter.PeriodHours)*time.Hour))
+	}
+
+	query := "SELECT " + executionLogColumns + " FROM execution_logs"
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += " ORDER BY executed_at DESC LIMIT ?"
+	args = append(args, limit)
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search execution logs: %w", err)
+	}
+	defer rows.Close()
+
+	return s.scanAnnotatedExecutionLogs(rows)
+}
+
+// savedLogFilterColumns is the column list matching scanSavedLogFilter
+const savedLogFilterColumns = "id, name, filter, created_at, updated_at"
+
+// scanSavedLogFilter scans a single saved log filter selected with savedLogFilterColumns
+func scanSavedLogFilter(row rowScanner) (models.SavedLogFilter, error) {
+	var saved models.SavedLogFilter
+	var filter string
+	if err := row.Scan(&saved.ID, &saved.Name, &filter, &saved.CreatedAt, &saved.UpdatedAt); err != nil {
+		return saved, err
+	}
+	if err := json.Unmarshal([]byte(filter), &saved.Filter); err != nil {
+		return saved, fmt.Errorf("failed to parse log filter: %w", err)
+	}
+	return saved, nil
+}
+
+// Saved Log Filter Operations
+
+// CreateSavedLogFilter creates a new saved log filter
+func (s *DBService) CreateSavedLogFilter(saved models.SavedLogFilter) (models.SavedLogFilter, error) {
+	now := time.Now()
+	saved.CreatedAt = now
+	saved.UpdatedAt = now
+	filter, err := json.Marshal(saved.Filter)
+	if err != nil {
+		return saved, fmt.Errorf("failed to encode log filter: %w", err)
+	}
+
+	result, err := s.db.Exec(
+		"INSERT INTO saved_log_filters (name, filter, created_at, updated_at) VALUES (?, ?, ?, ?)",
+		saved.Name, string(filter), saved.CreatedAt, saved.UpdatedAt,
+	)
+	if err != nil {
+		return saved, fmt.Errorf("failed to create saved log filter: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return saved, fmt.Errorf("failed to get last insert ID: %w", err)
+	}
+
+	saved.ID = int(id)
+	return saved, nil
+}
+
+// UpdateSavedLogFilter updates an existing saved log filter
+func (s *DBService) UpdateSavedLogFilter(saved models.SavedLogFilter) (models.SavedLogFilter, error) {
+	saved.UpdatedAt = time.Now()
+	filter, err := json.Marshal(saved.Filter)
+	if err != nil {
+		return saved, fmt.Errorf("failed to encode log filter: %w", err)
+	}
+
+	_, err = s.db.Exec(
+		"UPDATE saved_log_filters SET name = ?, filter = ?, updated_at = ? WHERE id = ?",
+		saved.Name, string(filter), saved.UpdatedAt, saved.ID,
+	)
+	if err != nil {
+		return saved, fmt.Errorf("failed to update saved log filter: %w", err)
+	}
+	return s.GetSavedLogFilterByID(saved.ID)
+}
+
+// DeleteSavedLogFilter deletes a saved log filter by ID
+func (s *DBService) DeleteSavedLogFilter(id int) error {
+	_, err := s.db.Exec("DELETE FROM saved_log_filters WHERE id = ?", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete saved log filter: %w", err)
+	}
+	return nil
+}
+
+// GetSavedLogFilterByID gets a saved log filter by ID
+func (s *DBService) GetSavedLogFilterByID(id int) (models.SavedLogFilter, error) {
+	saved, err := scanSavedLogFilter(s.db.QueryRow("SELECT "+savedLogFilterColumns+" FROM saved_log_filters WHERE id = ?", id))
+	if err != nil {
+		return saved, fmt.Errorf("failed to get saved log filter by ID: %w", err)
+	}
+	return saved, nil
+}
+
+// GetAllSavedLogFilters gets all saved log filters ordered by name
+func (s *DBService) GetAllSavedLogFilters() ([]models.SavedLogFilter, error) {
+	rows, err := s.db.Query("SELECT " + savedLogFilterColumns + " FROM saved_log_filters ORDER BY name, id")
+	if err != nil {
+		return nil, fmt.Errorf("failed to query saved log filters: %w", err)
+	}
+	defer rows.Close()
+
+	var filters []models.SavedLogFilter
+	for rows.Next() {
+		saved, err := scanSavedLogFilter(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan saved log filter row: %w", err)
+		}
+		filters = append(filters, saved)
+	}
+
+	return filters, nil
+}
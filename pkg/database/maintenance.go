@@ -0,0 +1,127 @@
+package database
+
+import (
+	"fmt"
+	"time"
+
+	"flowpulse/pkg/models"
+)
+
+// measuredExecutions is the condition selecting the execution logs of checks that actually ran,
+// which leaves out runs skipped during maintenance windows
+const measuredExecutions = "COALESCE(status, '') != '" + models.ExecutionStatusSkipped + "'"
+
+// initMaintenanceTables creates the maintenance window table
+func (s *DBService) initMaintenanceTables() error {
+	_, err := s.db.Exec(`
+		CREATE TABLE IF NOT EXISTS maintenance_windows (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT NOT NULL,
+			scope TEXT NOT NULL,
+			api_id INTEGER NOT NULL DEFAULT 0,
+			collection_id INTEGER NOT NULL DEFAULT 0,
+			starts_at TIMESTAMP NOT NULL,
+			ends_at TIMESTAMP NOT NULL,
+			recurrence TEXT NOT NULL DEFAULT '',
+			duration_minutes INTEGER NOT NULL DEFAULT 0,
+			timezone TEXT NOT NULL DEFAULT '',
+			created_at TIMESTAMP NOT NULL,
+			updated_at TIMESTAMP NOT NULL
+		)
+	`)
+	return err
+}
+
+// maintenanceWindowColumns is the column list matching scanMaintenanceWindow
+const maintenanceWindowColumns = `id, name, scope, api_id, collection_id, starts_at, ends_at, recurrence, duration_minutes,
+	timezone, created_at, updated_at`
+
+// scanMaintenanceWindow scans a single maintenance window selected with maintenanceWindowColumns
+func scanMaintenanceWindow(row rowScanner) (models.MaintenanceWindow, error) {
+	var window models.MaintenanceWindow
+	err := row.Scan(&window.ID, &window.Name, &window.Scope, &window.APIID, &window.CollectionID,
+		&window.StartsAt, &window.EndsAt, &window.Recurrence, &window.DurationMinutes,
+		&window.Timezone, &window.CreatedAt, &window.UpdatedAt)
+	return window, err
+}
+
+// Maintenance Window Operations
+
+// CreateMaintenanceWindow creates a new maintenance window
+func (s *DBService) CreateMaintenanceWindow(window models.MaintenanceWindow) (models.MaintenanceWindow, error) {
+	now := time.Now()
+	window.CreatedAt = now
+	window.UpdatedAt = now
+
+	result, err := s.db.Exec(
+		`INSERT INTO maintenance_windows (name, scope, api_id, collection_id, starts_at, ends_at, recurrence, duration_minutes,
+			timezone, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		window.Name, window.Scope, window.APIID, window.CollectionID, window.StartsAt, window.EndsAt, window.Recurrence,
+		window.DurationMinutes, window.Timezone, window.CreatedAt, window.UpdatedAt,
+	)
+	if err != nil {
+		return window, fmt.Errorf("failed to create maintenance window: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return window, fmt.Errorf("failed to get last insert ID: %w", err)
+	}
+
+	window.ID = int(id)
+	return window, nil
+}
+
+// UpdateMaintenanceWindow updates an existing maintenance window
+func (s *DBService) UpdateMaintenanceWindow(window models.MaintenanceWindow) (models.MaintenanceWindow, error) {
+	window.UpdatedAt = time.Now()
+
+	_, err := s.db.Exec(
+		`UPDATE maintenance_windows SET name = ?, scope = ?, api_id = ?, collection_id = ?, starts_at = ?, ends_at = ?,
+			recurrence = ?, duration_minutes = ?, timezone = ?, updated_at = ? WHERE id = ?`,
+		window.Name, window.Scope, window.APIID, window.CollectionID, window.StartsAt, window.EndsAt,
+		window.Recurrence, window.DurationMinutes, window.Timezone, window.UpdatedAt, window.ID,
+	)
+	if err != nil {
+		return window, fmt.Errorf("failed to update maintenance window: %w", err)
+	}
+	return s.GetMaintenanceWindowByID(window.ID)
+}
+
+// DeleteMaintenanceWindow deletes a maintenance window by ID
+func (s *DBService) DeleteMaintenanceWindow(id int) error {
+	_, err := s.db.Exec("DELETE FROM maintenance_windows WHERE id = ?", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete maintenance window: %w", err)
+	}
+	return nil
+}
+
+// GetMaintenanceWindowByID gets a maintenance window by ID
+func (s *DBService) GetMaintenanceWindowByID(id int) (models.MaintenanceWindow, error) {
+	window, err := scanMaintenanceWindow(s.db.QueryRow("SELECT "+maintenanceWindowColumns+" FROM maintenance_windows WHERE id = ?", id))
+	if err != nil {
+		return window, fmt.Errorf("failed to get maintenance window by ID: %w", err)
+	}
+	return window, nil
+}
+
+// GetAllMaintenanceWindows gets all maintenance windows, one-off windows ordered by when they start
+func (s *DBService) GetAllMaintenanceWindows() ([]models.MaintenanceWindow, error) {
+	rows, err := s.db.Query("SELECT " + maintenanceWindowColumns + " FROM maintenance_windows ORDER BY starts_at, id")
+	if err != nil {
+		return nil, fmt.Errorf("failed to query maintenance windows: %w", err)
+	}
+	defer rows.Close()
+
+	var windows []models.MaintenanceWindow
+	for rows.Next() {
+		window, err := scanMaintenanceWindow(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan maintenance window row: %w", err)
+		}
+		windows = append(windows, window)
+	}
+
+	return windows, nil
+}
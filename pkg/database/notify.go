@@ -0,0 +1,330 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"flowpulse/pkg/models"
+)
+
+// initNotifyTables creates the notification channel and alert rule tables
+func (s *DBService) initNotifyTables() error {
+	_, err := s.db.Exec(`
+		CREATE TABLE IF NOT EXISTS notification_channels (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT NOT NULL,
+			type TEXT NOT NULL,
+			config TEXT,
+			created_at TIMESTAMP NOT NULL,
+			updated_at TIMESTAMP NOT NULL
+		)
+	`)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.db.Exec(`
+		CREATE TABLE IF NOT EXISTS alert_rules (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			schedule_id INTEGER NOT NULL,
+			channel_id INTEGER NOT NULL,
+			failure_threshold INTEGER NOT NULL DEFAULT 1,
+			notify_on_recovery BOOLEAN NOT NULL DEFAULT 1,
+			is_active BOOLEAN NOT NULL DEFAULT 1,
+			created_at TIMESTAMP NOT NULL,
+			updated_at TIMESTAMP NOT NULL,
+			FOREIGN KEY (schedule_id) REFERENCES schedules (id) ON DELETE CASCADE,
+			FOREIGN KEY (channel_id) REFERENCES notification_channels (id) ON DELETE CASCADE
+		)
+	`)
+	return err
+}
+
+// Notification Channel Operations
+
+// CreateNotificationChannel creates a new notification channel
+func (s *DBService) CreateNotificationChannel(channel models.NotificationChannel) (models.NotificationChannel, error) {
+	now := time.Now()
+	channel.CreatedAt = now
+	channel.UpdatedAt = now
+	if channel.UUID == "" {
+		channel.UUID = NewUUID()
+	}
+
+	result, err := s.db.Exec(
+		"INSERT INTO notification_channels (uuid, name, type, config, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?)",
+		channel.UUID, channel.Name, channel.Type, channel.Config, channel.CreatedAt, channel.UpdatedAt,
+	)
+	if err != nil {
+		return channel, fmt.Errorf("failed to create notification channel: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return channel, fmt.Errorf("failed to get last insert ID: %w", err)
+	}
+
+	channel.ID = int(id)
+	return channel, nil
+}
+
+// UpdateNotificationChannel updates an existing notification channel
+func (s *DBService) UpdateNotificationChannel(channel models.NotificationChannel) (models.NotificationChannel, error) {
+	channel.UpdatedAt = time.Now()
+
+	_, err := s.db.Exec(
+		"UPDATE notification_channels SET name = ?, type = ?, config = ?, updated_at = ? WHERE id = ?",
+		channel.Name, channel.Type, channel.Config, channel.UpdatedAt, channel.ID,
+	)
+	if err != nil {
+		return channel, fmt.Errorf("failed to update notification channel: %w", err)
+	}
+	return s.GetNotificationChannelByID(channel.ID)
+}
+
+// DeleteNotificationChannel deletes a notification channel and the alert rules using it
+func (s *DBService) DeleteNotificationChannel(id int) error {
+	_, err := s.db.Exec("DELETE FROM alert_rules WHERE channel_id = ?", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete alert rules: %w", err)
+	}
+
+	_, err = s.db.Exec("DELETE FROM notification_channels WHERE id = ?", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete notification channel: %w", err)
+	}
+	return nil
+}
+
+// GetNotificationChannelByID gets a notification channel by ID
+func (s *DBService) GetNotificationChannelByID(id int) (models.NotificationChannel, error) {
+	var channel models.NotificationChannel
+	err := s.db.QueryRow(
+		"SELECT id, uuid, name, type, COALESCE(config, ''), created_at, updated_at FROM notification_channels WHERE id = ?",
+		id,
+	).Scan(&channel.ID, &channel.UUID, &channel.Name, &channel.Type, &channel.Config, &channel.CreatedAt, &channel.UpdatedAt)
+	if err != nil {
+		return channel, fmt.Errorf("failed to get notification channel by ID: %w", err)
+	}
+	return channel, nil
+}
+
+// GetAllNotificationChannels gets all notification channels
+func (s *DBService) GetAllNotificationChannels() ([]models.NotificationChannel, error) {
+	rows, err := s.db.Query("SELECT id, uuid, name, type, COALESCE(config, ''), created_at, updated_at FROM notification_channels ORDER BY name")
+	if err != nil {
+		return nil, fmt.Errorf("failed to query notification channels: %w", err)
+	}
+	defer rows.Close()
+
+	var channels []models.NotificationChannel
+	for rows.Next() {
+		var channel models.NotificationChannel
+		if err := rows.Scan(&channel.ID, &channel.UUID, &channel.Name, &channel.Type, &channel.Config, &channel.CreatedAt, &channel.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan notification channel row: %w", err)
+		}
+		channels = append(channels, channel)
+	}
+
+	return channels, nil
+}
+
+// Alert Rule Operations
+
+// alertRuleColumns is the column list matching the scan in queryAlertRules
+const alertRuleColumns = "id, uuid, schedule_id, channel_id, COALESCE(rotation_id, 0), failure_threshold, notify_on_recovery, is_active, created_at, updated_at"
+
+// CreateAlertRule creates a new alert rule
+func (s *DBService) CreateAlertRule(rule models.AlertRule) (models.AlertRule, error) {
+	now := time.Now()
+	rule.CreatedAt = now
+	rule.UpdatedAt = now
+	if rule.UUID == "" {
+		rule.UUID = NewUUID()
+	}
+
+	result, err := s.db.Exec(
+		"INSERT INTO alert_rules (uuid, schedule_id, channel_id, rotation_id, failure_threshold, notify_on_recovery, is_active, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)",
+		rule.UUID, rule.ScheduleID, rule.ChannelID, rule.RotationID, rule.FailureThreshold, rule.NotifyOnRecovery, rule.IsActive, rule.CreatedAt, rule.UpdatedAt,
+	)
+	if err != nil {
+		return rule, fmt.Errorf("failed to create alert rule: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return rule, fmt.Errorf("failed to get last insert ID: %w", err)
+	}
+
+	rule.ID = int(id)
+	return rule, nil
+}
+
+// UpdateAlertRule updates an existing alert rule
+func (s *DBService) UpdateAlertRule(rule models.AlertRule) (models.AlertRule, error) {
+	rule.UpdatedAt = time.Now()
+
+	_, err := s.db.Exec(
+		"UPDATE alert_rules SET schedule_id = ?, channel_id = ?, rotation_id = ?, failure_threshold = ?, notify_on_recovery = ?, is_active = ?, updated_at = ? WHERE id = ?",
+		rule.ScheduleID, rule.ChannelID, rule.RotationID, rule.FailureThreshold, rule.NotifyOnRecovery, rule.IsActive, rule.UpdatedAt, rule.ID,
+	)
+	if err != nil {
+		return rule, fmt.Errorf("failed to update alert rule: %w", err)
+	}
+	return rule, nil
+}
+
+// DeleteAlertRule deletes an alert rule by ID
+func (s *DBService) DeleteAlertRule(id int) error {
+	_, err := s.db.Exec("DELETE FROM alert_rules WHERE id = ?", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete alert rule: %w", err)
+	}
+	return nil
+}
+
+// GetAllAlertRules gets all alert rules
+func (s *DBService) GetAllAlertRules() ([]models.AlertRule, error) {
+	return s.queryAlertRules("SELECT " + alertRuleColumns + " FROM alert_rules ORDER BY id")
+}
+
+// GetAlertRuleByID gets an alert rule by ID
+func (s *DBService) GetAlertRuleByID(id int) (models.AlertRule, error) {
+	rules, err := s.queryAlertRules("SELECT "+alertRuleColumns+" FROM alert_rules WHERE id = ?", id)
+	if err != nil {
+		return models.AlertRule{}, err
+	}
+	if len(rules) == 0 {
+		return models.AlertRule{}, fmt.Errorf("failed to get alert rule by ID: %w", sql.ErrNoRows)
+	}
+	return rules[0], nil
+}
+
+// GetAlertRulesByScheduleID gets the alert rules of a schedule
+func (s *DBService) GetAlertRulesByScheduleID(scheduleID int) ([]models.AlertRule, error) {
+	return s.queryAlertRules("SELECT "+alertRuleColumns+" FROM alert_rules WHERE schedule_id = ? ORDER BY id", scheduleID)
+}
+
+// GetAlertRulesByCollectionID gets the alert rules of the schedules of every API in a collection
+func (s *DBService) GetAlertRulesByCollectionID(collectionID int) ([]models.AlertRule, error) {
+	return s.queryAlertRules(`SELECT `+alertRuleColumns+` FROM alert_rules
+		WHERE schedule_id IN (SELECT schedules.id FROM schedules JOIN apis ON apis.id = schedules.api_id WHERE apis.collection_id = ?)
+		ORDER BY id`, collectionID)
+}
+
+func (s *DBService) queryAlertRules(query string, args ...interface{}) ([]models.AlertRule, error) {
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query alert rules: %w", err)
+	}
+	defer rows.Close()
+
+	var rules []models.AlertRule
+	for rows.Next() {
+		var rule models.AlertRule
+		if err := rows.Scan(&rule.ID, &rule.UUID, &rule.ScheduleID, &rule.ChannelID, &rule.RotationID, &rule.FailureThreshold, &rule.NotifyOnRecovery,
+			&rule.IsActive, &rule.CreatedAt, &rule.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan alert rule row: %w", err)
+		}
+		rules = append(rules, rule)
+	}
+
+	return rules, nil
+}
+
+// GetRecentStatusesByScheduleID gets the statuses of a schedule's most recent executions, newest first.
+// Runs skipped during maintenance windows are left out.
+func (s *DBService) GetRecentStatusesByScheduleID(scheduleID int, limit int) ([]string, error) {
+	rows, err := s.db.Query(
+		"SELECT COALESCE(status, '') FROM execution_logs WHERE schedule_id = ? AND "+measuredExecutions+" ORDER BY executed_at DESC, id DESC LIMIT ?",
+		scheduleID, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query recent statuses: %w", err)
+	}
+	defer rows.Close()
+
+	var statuses []string
+	for rows.Next() {
+		var status string
+		if err := rows.Scan(&status); err != nil {
+			return nil, fmt.Errorf("failed to scan status: %w", err)
+		}
+		statuses = append(statuses, status)
+	}
+
+	return statuses, nil
+}
+
+// GetRecentLatencyBreachesByScheduleID gets which latency threshold the most recent measured executions of a
+// schedule exceeded, newest first, with an empty string for those that exceeded none
+func (s *DBService) GetRecentLatencyBreachesByScheduleID(scheduleID int, limit int) ([]string, error) {
+	rows, err := s.db.Query(
+		"SELECT COALESCE(latency_breach, '') FROM execution_logs WHERE schedule_id = ? AND "+measuredExecutions+" ORDER BY executed_at DESC, id DESC LIMIT ?",
+		scheduleID, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query recent latency breaches: %w", err)
+	}
+	defer rows.Close()
+
+	var breaches []string
+	for rows.Next() {
+		var breach string
+		if err := rows.Scan(&breach); err != nil {
+			return nil, fmt.Errorf("failed to scan latency breach: %w", err)
+		}
+		breaches = append(breaches, breach)
+	}
+
+	return breaches, rows.Err()
+}
+
+// GetRecentScheduledStatusesByAPIID gets the statuses of the most recent measured scheduled executions of an API,
+// newest first, across all of its schedules
+func (s *DBService) GetRecentScheduledStatusesByAPIID(apiID int, limit int) ([]string, error) {
+	rows, err := s.db.Query(
+		"SELECT COALESCE(status, '') FROM execution_logs WHERE api_id = ? AND COALESCE(schedule_id, 0) != 0 AND "+measuredExecutions+" ORDER BY executed_at DESC, id DESC LIMIT ?",
+		apiID, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query recent statuses: %w", err)
+	}
+	defer rows.Close()
+
+	var statuses []string
+	for rows.Next() {
+		var status string
+		if err := rows.Scan(&status); err != nil {
+			return nil, fmt.Errorf("failed to scan status: %w", err)
+		}
+		statuses = append(statuses, status)
+	}
+
+	return statuses, nil
+}
+
+// GetRecentScheduledStatusesByLocation gets the statuses of an API's most recent scheduled executions from one
+// location, newest first, ignoring skipped runs
+func (s *DBService) GetRecentScheduledStatusesByLocation(apiID int, location string, limit int) ([]string, error) {
+	rows, err := s.db.Query(
+		"SELECT COALESCE(status, '') FROM execution_logs WHERE api_id = ? AND COALESCE(location, 'local') = ? AND COALESCE(schedule_id, 0) != 0 AND "+measuredExecutions+" ORDER BY executed_at DESC, id DESC LIMIT ?",
+		apiID, location, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query recent statuses by location: %w", err)
+	}
+	defer rows.Close()
+
+	var statuses []string
+	for rows.Next() {
+		var status string
+		if err := rows.Scan(&status); err != nil {
+			return nil, fmt.Errorf("failed to scan status: %w", err)
+		}
+		statuses = append(statuses, status)
+	}
+
+	return statuses, nil
+}
@@ -0,0 +1,263 @@
+package database
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"flowpulse/pkg/models"
+)
+
+// initOnCallTables creates the on-call user and rotation tables
+func (s *DBService) initOnCallTables() error {
+	_, err := s.db.Exec(`
+		CREATE TABLE IF NOT EXISTS oncall_users (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT NOT NULL,
+			channel_id INTEGER NOT NULL,
+			created_at TIMESTAMP NOT NULL,
+			updated_at TIMESTAMP NOT NULL,
+			FOREIGN KEY (channel_id) REFERENCES notification_channels (id)
+		)
+	`)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.db.Exec(`
+		CREATE TABLE IF NOT EXISTS oncall_rotations (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT NOT NULL,
+			user_ids TEXT NOT NULL DEFAULT '[]',
+			starts_at TIMESTAMP NOT NULL,
+			created_at TIMESTAMP NOT NULL,
+			updated_at TIMESTAMP NOT NULL
+		)
+	`)
+	if err != nil {
+		return err
+	}
+
+	// Add rotation_id column so alert rules can route to whoever is on call
+	return s.addColumnIfMissing("alert_rules", "rotation_id", "INTEGER DEFAULT 0")
+}
+
+// On-Call User Operations
+
+// CreateOnCallUser creates a new on-call user
+func (s *DBService) CreateOnCallUser(user models.OnCallUser) (models.OnCallUser, error) {
+	now := time.Now()
+	user.CreatedAt = now
+	user.UpdatedAt = now
+
+	result, err := s.db.Exec(
+		"INSERT INTO oncall_users (name, channel_id, created_at, updated_at) VALUES (?, ?, ?, ?)",
+		user.Name, user.ChannelID, user.CreatedAt, user.UpdatedAt,
+	)
+	if err != nil {
+		return user, fmt.Errorf("failed to create on-call user: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return user, fmt.Errorf("failed to get last insert ID: %w", err)
+	}
+
+	user.ID = int(id)
+	return user, nil
+}
+
+// UpdateOnCallUser updates an existing on-call user
+func (s *DBService) UpdateOnCallUser(user models.OnCallUser) (models.OnCallUser, error) {
+	user.UpdatedAt = time.Now()
+
+	_, err := s.db.Exec(
+		"UPDATE oncall_users SET name = ?, channel_id = ?, updated_at = ? WHERE id = ?",
+		user.Name, user.ChannelID, user.UpdatedAt, user.ID,
+	)
+	if err != nil {
+		return user, fmt.Errorf("failed to update on-call user: %w", err)
+	}
+	return s.GetOnCallUserByID(user.ID)
+}
+
+// DeleteOnCallUser deletes an on-call user and removes them from every rotation
+func (s *DBService) DeleteOnCallUser(id int) error {
+	rotations, err := s.GetAllOnCallRotations()
+	if err != nil {
+		return err
+	}
+	for _, rotation := range rotations {
+		userIDs := make([]int, 0, len(rotation.UserIDs))
+		for _, userID := range rotation.UserIDs {
+			if userID != id {
+				userIDs = append(userIDs, userID)
+			}
+		}
+		if len(userIDs) != len(rotation.UserIDs) {
+			rotation.UserIDs = userIDs
+			if _, err := s.UpdateOnCallRotation(rotation); err != nil {
+				return err
+			}
+		}
+	}
+
+	_, err = s.db.Exec("DELETE FROM oncall_users WHERE id = ?", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete on-call user: %w", err)
+	}
+	return nil
+}
+
+// GetOnCallUserByID gets an on-call user by ID
+func (s *DBService) GetOnCallUserByID(id int) (models.OnCallUser, error) {
+	var user models.OnCallUser
+	err := s.db.QueryRow(
+		"SELECT id, name, channel_id, created_at, updated_at FROM oncall_users WHERE id = ?",
+		id,
+	).Scan(&user.ID, &user.Name, &user.ChannelID, &user.CreatedAt, &user.UpdatedAt)
+	if err != nil {
+		return user, fmt.Errorf("failed to get on-call user by ID: %w", err)
+	}
+	return user, nil
+}
+
+// GetAllOnCallUsers gets all on-call users
+func (s *DBService) GetAllOnCallUsers() ([]models.OnCallUser, error) {
+	rows, err := s.db.Query("SELECT id, name, channel_id, created_at, updated_at FROM oncall_users ORDER BY name")
+	if err != nil {
+		return nil, fmt.Errorf("failed to query on-call users: %w", err)
+	}
+	defer rows.Close()
+
+	var users []models.OnCallUser
+	for rows.Next() {
+		var user models.OnCallUser
+		if err := rows.Scan(&user.ID, &user.Name, &user.ChannelID, &user.CreatedAt, &user.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan on-call user row: %w", err)
+		}
+		users = append(users, user)
+	}
+
+	return users, nil
+}
+
+// On-Call Rotation Operations
+
+// onCallRotationColumns is the column list matching scanOnCallRotation
+const onCallRotationColumns = "id, name, user_ids, starts_at, created_at, updated_at"
+
+// scanOnCallRotation scans a single rotation selected with onCallRotationColumns
+func scanOnCallRotation(row rowScanner) (models.OnCallRotation, error) {
+	var rotation models.OnCallRotation
+	var userIDs string
+	if err := row.Scan(&rotation.ID, &rotation.Name, &userIDs, &rotation.StartsAt, &rotation.CreatedAt, &rotation.UpdatedAt); err != nil {
+		return rotation, err
+	}
+	if err := json.Unmarshal([]byte(userIDs), &rotation.UserIDs); err != nil {
+		return rotation, fmt.Errorf("failed to parse rotation users: %w", err)
+	}
+	return rotation, nil
+}
+
+// encodeUserIDs encodes a rotation's user order for storage
+func encodeUserIDs(userIDs []int) (string, error) {
+	if userIDs == nil {
+		userIDs = []int{}
+	}
+	encoded, err := json.Marshal(userIDs)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode rotation users: %w", err)
+	}
+	return string(encoded), nil
+}
+
+// CreateOnCallRotation creates a new on-call rotation
+func (s *DBService) CreateOnCallRotation(rotation models.OnCallRotation) (models.OnCallRotation, error) {
+	now := time.Now()
+	rotation.CreatedAt = now
+	rotation.UpdatedAt = now
+
+	userIDs, err := encodeUserIDs(rotation.UserIDs)
+	if err != nil {
+		return rotation, err
+	}
+
+	result, err := s.db.Exec(
+		"INSERT INTO oncall_rotations (name, user_ids, starts_at, created_at, updated_at) VALUES (?, ?, ?, ?, ?)",
+		rotation.Name, userIDs, rotation.StartsAt, rotation.CreatedAt, rotation.UpdatedAt,
+	)
+	if err != nil {
+		return rotation, fmt.Errorf("failed to create on-call rotation: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return rotation, fmt.Errorf("failed to get last insert ID: %w", err)
+	}
+
+	rotation.ID = int(id)
+	return rotation, nil
+}
+
+// UpdateOnCallRotation updates an existing on-call rotation
+func (s *DBService) UpdateOnCallRotation(rotation models.OnCallRotation) (models.OnCallRotation, error) {
+	rotation.UpdatedAt = time.Now()
+
+	userIDs, err := encodeUserIDs(rotation.UserIDs)
+	if err != nil {
+		return rotation, err
+	}
+
+	_, err = s.db.Exec(
+		"UPDATE oncall_rotations SET name = ?, user_ids = ?, starts_at = ?, updated_at = ? WHERE id = ?",
+		rotation.Name, userIDs, rotation.StartsAt, rotation.UpdatedAt, rotation.ID,
+	)
+	if err != nil {
+		return rotation, fmt.Errorf("failed to update on-call rotation: %w", err)
+	}
+	return s.GetOnCallRotationByID(rotation.ID)
+}
+
+// DeleteOnCallRotation deletes an on-call rotation and the alert rules routed through it
+func (s *DBService) DeleteOnCallRotation(id int) error {
+	_, err := s.db.Exec("DELETE FROM alert_rules WHERE rotation_id = ?", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete alert rules: %w", err)
+	}
+
+	_, err = s.db.Exec("DELETE FROM oncall_rotations WHERE id = ?", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete on-call rotation: %w", err)
+	}
+	return nil
+}
+
+// GetOnCallRotationByID gets an on-call rotation by ID
+func (s *DBService) GetOnCallRotationByID(id int) (models.OnCallRotation, error) {
+	rotation, err := scanOnCallRotation(s.db.QueryRow("SELECT "+onCallRotationColumns+" FROM oncall_rotations WHERE id = ?", id))
+	if err != nil {
+		return rotation, fmt.Errorf("failed to get on-call rotation by ID: %w", err)
+	}
+	return rotation, nil
+}
+
+// GetAllOnCallRotations gets all on-call rotations
+func (s *DBService) GetAllOnCallRotations() ([]models.OnCallRotation, error) {
+	rows, err := s.db.Query("SELECT " + onCallRotationColumns + " FROM oncall_rotations ORDER BY name")
+	if err != nil {
+		return nil, fmt.Errorf("failed to query on-call rotations: %w", err)
+	}
+	defer rows.Close()
+
+	var rotations []models.OnCallRotation
+	for rows.Next() {
+		rotation, err := scanOnCallRotation(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan on-call rotation row: %w", err)
+		}
+		rotations = append(rotations, rotation)
+	}
+
+	return rotations, nil
+}
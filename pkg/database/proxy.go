@@ -0,0 +1,33 @@
+package database
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"flowpulse/pkg/models"
+)
+
+// settingGlobalProxy is the settings key of the proxy used by APIs that don't set their own, as JSON
+const settingGlobalProxy = "proxy.global"
+
+// GetGlobalProxy gets the proxy used by APIs that don't set their own
+func (s *DBService) GetGlobalProxy() (models.ProxyConfig, error) {
+	var proxy models.ProxyConfig
+	raw, err := s.GetSetting(settingGlobalProxy)
+	if err != nil || raw == "" {
+		return proxy, err
+	}
+	if err := json.Unmarshal([]byte(raw), &proxy); err != nil {
+		return proxy, fmt.Errorf("failed to parse global proxy: %w", err)
+	}
+	return proxy, nil
+}
+
+// SaveGlobalProxy saves the proxy used by APIs that don't set their own
+func (s *DBService) SaveGlobalProxy(proxy models.ProxyConfig) error {
+	encoded, err := json.Marshal(proxy)
+	if err != nil {
+		return fmt.Errorf("failed to encode global proxy: %w", err)
+	}
+	return s.SetSetting(settingGlobalProxy, string(encoded))
+}
@@ -0,0 +1,81 @@
+package database
+
+import (
+	"bytes"
+	"compress/gzip"
+	"database/sql"
+	"fmt"
+	"io"
+)
+
+// responsePreviewLength is how much of a response is kept in its execution log row
+const responsePreviewLength = 10000
+
+// TruncatedSuffix ends the responses and errors of execution logs that were cut short
+const TruncatedSuffix = "... (truncated)"
+
+// settingStoreFullResponses is the settings key enabling full storage of responses longer than the preview
+const settingStoreFullResponses = "responses.store_full"
+
+// initResponseBodyTables creates the table of full response bodies, gzip-compressed, kept apart from their
+// execution logs so the log table stays small
+func (s *DBService) initResponseBodyTables() error {
+	_, err := s.db.Exec(`
+		CREATE TABLE IF NOT EXISTS response_bodies (
+			execution_log_id INTEGER PRIMARY KEY,
+			body BLOB NOT NULL,
+			FOREIGN KEY (execution_log_id) REFERENCES execution_logs (id) ON DELETE CASCADE
+		)
+	`)
+	return err
+}
+
+// compressResponse gzips a response body for storage
+func compressResponse(body string) ([]byte, error) {
+	var buffer bytes.Buffer
+	writer := gzip.NewWriter(&buffer)
+	if _, err := writer.Write([]byte(body)); err != nil {
+		return nil, fmt.Errorf("failed to compress response body: %w", err)
+	}
+	if err := writer.Close(); err != nil {
+		return nil, fmt.Errorf("failed to compress response body: %w", err)
+	}
+	return buffer.Bytes(), nil
+}
+
+// GetExecutionResponseBody returns the full response of an execution log. Logs whose response wasn't stored
+// in full return the response kept in the log row.
+func (s *DBService) GetExecutionResponseBody(logID int) (string, error) {
+	var compressed []byte
+	err := s.db.QueryRow("SELECT body FROM response_bodies WHERE execution_log_id = ?", logID).Scan(&compressed)
+	if err == sql.ErrNoRows {
+		var response string
+		if err := s.db.QueryRow("SELECT response FROM execution_logs WHERE id = ?", logID).Scan(&response); err != nil {
+			return "", fmt.Errorf("failed to get response of execution log %d: %w", logID, err)
+		}
+		return response, nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get response body of execution log %d: %w", logID, err)
+	}
+
+	reader, err := gzip.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		return "", fmt.Errorf("failed to read response body of execution log %d: %w", logID, err)
+	}
+	defer reader.Close()
+	body, err := io.ReadAll(reader)
+	if err != nil {
+		return "", fmt.Errorf("failed to read response body of execution log %d: %w", logID, err)
+	}
+	return string(body), nil
+}
+
+// pruneOrphanedResponseBodies deletes the full response bodies of execution logs that no longer exist
+func (s *DBService) pruneOrphanedResponseBodies() error {
+	_, err := s.db.Exec("DELETE FROM response_bodies WHERE execution_log_id NOT IN (SELECT id FROM execution_logs)")
+	if err != nil {
+		return fmt.Errorf("failed to prune orphaned response bodies: %w", err)
+	}
+	return nil
+}
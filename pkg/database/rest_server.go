@@ -0,0 +1,65 @@
+package database
+
+import (
+	"strconv"
+
+	"flowpulse/pkg/models"
+)
+
+// Settings keys of the embedded REST server
+const (
+	settingRESTEnabled = "rest.enabled"
+	settingRESTHost    = "rest.host"
+	settingRESTPort    = "rest.port"
+	settingRESTToken   = "rest.token"
+)
+
+// Defaults used until the REST server is configured
+const (
+	defaultRESTHost = "127.0.0.1"
+	defaultRESTPort = 7070
+)
+
+// GetRESTServerConfig gets the embedded REST server configuration
+func (s *DBService) GetRESTServerConfig() (models.RESTServerConfig, error) {
+	config := models.RESTServerConfig{Host: defaultRESTHost, Port: defaultRESTPort}
+
+	enabled, err := s.GetSetting(settingRESTEnabled)
+	if err != nil {
+		return config, err
+	}
+	config.Enabled = enabled == "true"
+
+	if host, err := s.GetSetting(settingRESTHost); err != nil {
+		return config, err
+	} else if host != "" {
+		config.Host = host
+	}
+
+	if port, err := s.getIntSetting(settingRESTPort); err != nil {
+		return config, err
+	} else if port != 0 {
+		config.Port = port
+	}
+
+	if config.Token, err = s.GetSetting(settingRESTToken); err != nil {
+		return config, err
+	}
+	return config, nil
+}
+
+// SaveRESTServerConfig saves the embedded REST server configuration
+func (s *DBService) SaveRESTServerConfig(config models.RESTServerConfig) error {
+	settings := map[string]string{
+		settingRESTEnabled: strconv.FormatBool(config.Enabled),
+		settingRESTHost:    config.Host,
+		settingRESTPort:    strconv.Itoa(config.Port),
+		settingRESTToken:   config.Token,
+	}
+	for key, value := range settings {
+		if err := s.SetSetting(key, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
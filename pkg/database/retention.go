@@ -0,0 +1,211 @@
+package database
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"flowpulse/pkg/models"
+)
+
+// Settings keys of the execution log retention policy
+const (
+	settingRetentionMaxAgeDays    = "retention.max_age_days"
+	settingRetentionMaxRowsPerAPI = "retention.max_rows_per_api"
+	settingRetentionArchive       = "retention.archive"
+)
+
+// archiveBatchSize is how many execution logs are archived and deleted at a time
+const archiveBatchSize = 500
+
+// GetRetentionPolicy gets the execution log retention policy
+func (s *DBService) GetRetentionPolicy() (models.RetentionPolicy, error) {
+	var policy models.RetentionPolicy
+	var err error
+	if policy.MaxAgeDays, err = s.getIntSetting(settingRetentionMaxAgeDays); err != nil {
+		return policy, err
+	}
+	if policy.MaxRowsPerAPI, err = s.getIntSetting(settingRetentionMaxRowsPerAPI); err != nil {
+		return policy, err
+	}
+	archive, err := s.GetSetting(settingRetentionArchive)
+	if err != nil {
+		return policy, err
+	}
+	policy.Archive = archive == "true"
+	storeFull, err := s.GetSetting(settingStoreFullResponses)
+	if err != nil {
+		return policy, err
+	}
+	policy.StoreFullResponses = storeFull == "true"
+	return policy, nil
+}
+
+// SaveRetentionPolicy saves the execution log retention policy
+func (s *DBService) SaveRetentionPolicy(policy models.RetentionPolicy) error {
+	if err := s.SetSetting(settingRetentionMaxAgeDays, strconv.Itoa(policy.MaxAgeDays)); err != nil {
+		return err
+	}
+	if err := s.SetSetting(settingRetentionMaxRowsPerAPI, strconv.Itoa(policy.MaxRowsPerAPI)); err != nil {
+		return err
+	}
+	if err := s.SetSetting(settingRetentionArchive, strconv.FormatBool(policy.Archive)); err != nil {
+		return err
+	}
+	return s.SetSetting(settingStoreFullResponses, strconv.FormatBool(policy.StoreFullResponses))
+}
+
+// getIntSetting gets a numeric setting, treating a missing value as 0
+func (s *DBService) getIntSetting(key string) (int, error) {
+	value, err := s.GetSetting(key)
+	if err != nil || value == "" {
+		return 0, err
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value for setting %s: %w", key, err)
+	}
+	return n, nil
+}
+
+// PruneExecutionLogs deletes the execution logs falling outside the retention policy and returns how many were deleted.
+// Scheduler metrics older than the maximum age are deleted along with them. When the policy archives logs,
+// each log is only deleted once it was written to the archive.
+func (s *DBService) PruneExecutionLogs() (deleted int64, err error) {
+	policy, err := s.GetRetentionPolicy()
+	if err != nil {
+		return 0, err
+	}
+
+	var archive *logArchive
+	if policy.Archive {
+		if archive, err = newLogArchive(); err != nil {
+			return 0, err
+		}
+		defer func() {
+			if closeErr := archive.close(); closeErr != nil && err == nil {
+				err = closeErr
+			}
+		}()
+	}
+
+	if policy.MaxAgeDays > 0 {
+		cutoff := time.Now().AddDate(0, 0, -policy.MaxAgeDays)
+		n, err := s.pruneLogsWhere("executed_at < ?", cutoff, archive)
+		deleted += n
+		if err != nil {
+			return deleted, fmt.Errorf("failed to prune old execution logs: %w", err)
+		}
+
+		if err := s.pruneSchedulerMetrics(cutoff); err != nil {
+			return deleted, err
+		}
+	}
+
+	if policy.MaxRowsPerAPI > 0 {
+		n, err := s.pruneLogsWhere(`id IN (
+				SELECT id FROM (
+					SELECT id, ROW_NUMBER() OVER (PARTITION BY api_id ORDER BY executed_at DESC, id DESC) AS row_number
+					FROM execution_logs
+				) WHERE row_number > ?
+			)`, policy.MaxRowsPerAPI, archive)
+		deleted += n
+		if err != nil {
+			return deleted, fmt.Errorf("failed to prune excess execution logs: %w", err)
+		}
+	}
+
+	if deleted > 0 {
+		if err := s.pruneOrphanedAnnotations(); err != nil {
+			return deleted, err
+		}
+		if err := s.pruneOrphanedResponseBodies(); err != nil {
+			return deleted, err
+		}
+	}
+
+	return deleted, nil
+}
+
+// pruneLogsWhere deletes the execution logs matching a condition and returns how many were deleted.
+// With an archive the logs are deleted in batches, each after it was written to the archive.
+func (s *DBService) pruneLogsWhere(condition string, arg interface{}, archive *logArchive) (int64, error) {
+	if archive == nil {
+		result, err := s.db.Exec("DELETE FROM execution_logs WHERE "+condition, arg)
+		if err != nil {
+			return 0, err
+		}
+		return result.RowsAffected()
+	}
+
+	// Collect the IDs first so logs written meanwhile don't shift which ones match
+	rows, err := s.db.Query("SELECT id FROM execution_logs WHERE "+condition+" ORDER BY id", arg)
+	if err != nil {
+		return 0, err
+	}
+	var ids []interface{}
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+
+	var deleted int64
+	for start := 0; start < len(ids); start += archiveBatchSize {
+		batch := ids[start:min(start+archiveBatchSize, len(ids))]
+		placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(batch)), ", ")
+
+		rows, err := s.db.Query("SELECT "+executionLogColumns+" FROM execution_logs WHERE id IN ("+placeholders+") ORDER BY id", batch...)
+		if err != nil {
+			return deleted, err
+		}
+		logs, err := s.scanAnnotatedExecutionLogs(rows)
+		rows.Close()
+		if err != nil {
+			return deleted, err
+		}
+		if err := archive.write(logs); err != nil {
+			return deleted, err
+		}
+
+		result, err := s.db.Exec("DELETE FROM execution_logs WHERE id IN ("+placeholders+")", batch...)
+		if err != nil {
+			return deleted, err
+		}
+		n, _ := result.RowsAffected()
+		deleted += n
+	}
+	return deleted, nil
+}
+
+// StartPruning prunes execution logs now and then at every interval until the database is closed
+func (s *DBService) StartPruning(interval time.Duration) {
+	if s.stopPruning != nil {
+		return // Already running
+	}
+	s.stopPruning = make(chan struct{})
+
+	go func(stop chan struct{}) {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			if deleted, err := s.PruneExecutionLogs(); err != nil {
+				log.Printf("Failed to prune execution logs: %v", err)
+			} else if deleted > 0 {
+				log.Printf("Pruned %d execution logs", deleted)
+			}
+
+			select {
+			case <-ticker.C:
+			case <-stop:
+				return
+			}
+		}
+	}(s.stopPruning)
+}
@@ -0,0 +1,99 @@
+package database
+
+import (
+	"fmt"
+	"slices"
+
+	"flowpulse/pkg/models"
+)
+
+// ScheduleRunsAfter reports whether a schedule runs after another one, directly or through a chain of
+// "after" schedules. Linking the other schedule to run after it would close a cycle.
+func (s *DBService) ScheduleRunsAfter(id, otherID int) (bool, error) {
+	var found bool
+	err := s.db.QueryRow(`WITH RECURSIVE chain(id) AS (
+			SELECT ?
+			UNION
+			SELECT schedules.after_id FROM schedules JOIN chain ON schedules.id = chain.id
+			WHERE schedules.type = 'after' AND COALESCE(schedules.after_id, 0) != 0
+		) SELECT COUNT(*) FROM chain WHERE id = ?`, id, otherID).Scan(&found)
+	if err != nil {
+		return false, fmt.Errorf("failed to check schedule dependencies: %w", err)
+	}
+	return found, nil
+}
+
+// GetSchedulesAfter gets the "after" schedules fired by the runs of a schedule
+func (s *DBService) GetSchedulesAfter(id int) ([]models.Schedule, error) {
+	rows, err := s.db.Query("SELECT "+scheduleColumns+" FROM schedules WHERE type = 'after' AND after_id = ? AND "+liveSchedules+" ORDER BY id", id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query dependent schedules: %w", err)
+	}
+	defer rows.Close()
+
+	var schedules []models.Schedule
+	for rows.Next() {
+		schedule, err := scanSchedule(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan schedule row: %w", err)
+		}
+		schedules = append(schedules, schedule)
+	}
+	return schedules, rows.Err()
+}
+
+// GetScheduleGraph gets the graph of the schedules that run after one another. Links to schedules that
+// no longer exist or whose API is in the trash are left out.
+func (s *DBService) GetScheduleGraph() (models.ScheduleGraph, error) {
+	graph := models.ScheduleGraph{Nodes: []models.ScheduleGraphNode{}, Edges: []models.ScheduleGraphEdge{}}
+	schedules, err := s.GetAllSchedules()
+	if err != nil {
+		return graph, err
+	}
+
+	// GetAllSchedules lists the newest first; the graph lists the oldest first
+	slices.Reverse(schedules)
+	byID := make(map[int]models.Schedule)
+	for _, schedule := range schedules {
+		byID[schedule.ID] = schedule
+	}
+	linked := make(map[int]bool)
+	for _, schedule := range schedules {
+		if schedule.Type != "after" {
+			continue
+		}
+		if _, ok := byID[schedule.AfterID]; !ok {
+			continue
+		}
+		outcome := schedule.AfterOutcome
+		if outcome == "" {
+			outcome = models.AfterOutcomeSuccess
+		}
+		graph.Edges = append(graph.Edges, models.ScheduleGraphEdge{From: schedule.AfterID, To: schedule.ID, Outcome: outcome})
+		linked[schedule.AfterID] = true
+		linked[schedule.ID] = true
+	}
+
+	apiNames := make(map[int]string)
+	for _, schedule := range schedules {
+		if !linked[schedule.ID] {
+			continue
+		}
+		name, known := apiNames[schedule.APIID]
+		if !known {
+			if api, err := s.GetAPIByID(schedule.APIID); err == nil {
+				name = api.Name
+			}
+			apiNames[schedule.APIID] = name
+		}
+		graph.Nodes = append(graph.Nodes, models.ScheduleGraphNode{
+			ScheduleID: schedule.ID,
+			APIID:      schedule.APIID,
+			APIName:    name,
+			Type:       schedule.Type,
+			Expression: schedule.Expression,
+			IsActive:   schedule.IsActive,
+		})
+	}
+	return graph, nil
+}
@@ -0,0 +1,191 @@
+package database
+
+import (
+	"fmt"
+	"time"
+
+	"flowpulse/pkg/models"
+)
+
+// initSchedulerMetricsTables creates the table of per-minute scheduler metrics
+func (s *DBService) initSchedulerMetricsTables() error {
+	_, err := s.db.Exec(`
+		CREATE TABLE IF NOT EXISTS scheduler_metrics (
+			minute DATETIME PRIMARY KEY,
+			executions INTEGER NOT NULL DEFAULT 0,
+			retries INTEGER NOT NULL DEFAULT 0,
+			skipped_runs INTEGER NOT NULL DEFAULT 0,
+			queue_wait_ms INTEGER NOT NULL DEFAULT 0,
+			queue_wait_count INTEGER NOT NULL DEFAULT 0
+		)
+	`)
+	if err != nil {
+		return err
+	}
+
+	// Add slot wait columns measuring how long scheduled runs waited for a concurrency slot
+	if err := s.addColumnIfMissing("scheduler_metrics", "slot_wait_ms", "INTEGER NOT NULL DEFAULT 0"); err != nil {
+		return err
+	}
+	if err := s.addColumnIfMissing("scheduler_metrics", "slot_wait_count", "INTEGER NOT NULL DEFAULT 0"); err != nil {
+		return err
+	}
+	if err := s.addColumnIfMissing("scheduler_metrics", "max_slot_wait_ms", "INTEGER NOT NULL DEFAULT 0"); err != nil {
+		return err
+	}
+
+	_, err = s.db.Exec(`
+		CREATE TABLE IF NOT EXISTS monitoring_gaps (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			started_at DATETIME NOT NULL,
+			ended_at DATETIME NOT NULL
+		)
+	`)
+	return err
+}
+
+// RecordSchedulerExecution counts an execution and the retries it performed in the minute of the given time
+func (s *DBService) RecordSchedulerExecution(at time.Time, retries int) error {
+	return s.addSchedulerMetrics(at, 1, retries, 0, 0, 0)
+}
+
+// RecordSchedulerQueueWait records how long a scheduled run waited past its due time before it started
+func (s *DBService) RecordSchedulerQueueWait(at time.Time, wait time.Duration) error {
+	return s.addSchedulerMetrics(at, 0, 0, 0, wait.Milliseconds(), 1)
+}
+
+// RecordSkippedRuns counts scheduled runs that were dropped because the previous run was still going
+func (s *DBService) RecordSkippedRuns(at time.Time, count int) error {
+	return s.addSchedulerMetrics(at, 0, 0, count, 0, 0)
+}
+
+// RecordSlotWait records how long a scheduled run waited for a concurrency slot before it started
+func (s *DBService) RecordSlotWait(at time.Time, wait time.Duration) error {
+	_, err := s.db.Exec(`
+		INSERT INTO scheduler_metrics (minute, slot_wait_ms, slot_wait_count, max_slot_wait_ms)
+		VALUES (?, ?, 1, ?)
+		ON CONFLICT (minute) DO UPDATE SET
+			slot_wait_ms = slot_wait_ms + excluded.slot_wait_ms,
+			slot_wait_count = slot_wait_count + 1,
+			max_slot_wait_ms = MAX(max_slot_wait_ms, excluded.max_slot_wait_ms)`,
+		at.UTC().Truncate(time.Minute), wait.Milliseconds(), wait.Milliseconds(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record scheduler metrics: %w", err)
+	}
+	return nil
+}
+
+// RecordMonitoringGap records a period the scheduler didn't run
+func (s *DBService) RecordMonitoringGap(startedAt, endedAt time.Time) error {
+	_, err := s.db.Exec("INSERT INTO monitoring_gaps (started_at, ended_at) VALUES (?, ?)", startedAt.UTC(), endedAt.UTC())
+	if err != nil {
+		return fmt.Errorf("failed to record monitoring gap: %w", err)
+	}
+	return nil
+}
+
+// addSchedulerMetrics adds to the counters of the minute of the given time
+func (s *DBService) addSchedulerMetrics(at time.Time, executions, retries, skipped int, waitMs int64, waitCount int) error {
+	_, err := s.db.Exec(`
+		INSERT INTO scheduler_metrics (minute, executions, retries, skipped_runs, queue_wait_ms, queue_wait_count)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT (minute) DO UPDATE SET
+			executions = executions + excluded.executions,
+			retries = retries + excluded.retries,
+			skipped_runs = skipped_runs + excluded.skipped_runs,
+			queue_wait_ms = queue_wait_ms + excluded.queue_wait_ms,
+			queue_wait_count = queue_wait_count + excluded.queue_wait_count`,
+		at.UTC().Truncate(time.Minute), executions, retries, skipped, waitMs, waitCount,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record scheduler metrics: %w", err)
+	}
+	return nil
+}
+
+// GetSchedulerMetrics returns the per-minute scheduler metrics since the given time along with their totals.
+// Minutes without any activity are left out of the series but still count towards the per-minute rate.
+func (s *DBService) GetSchedulerMetrics(since time.Time) (models.SchedulerMetricsReport, error) {
+	report := models.SchedulerMetricsReport{Points: []models.SchedulerMetrics{}, Gaps: []models.MonitoringGap{}}
+
+	rows, err := s.db.Query(`
+		SELECT minute, executions, retries, skipped_runs, queue_wait_ms, queue_wait_count,
+			slot_wait_ms, slot_wait_count, max_slot_wait_ms
+		FROM scheduler_metrics
+		WHERE minute >= ?
+		ORDER BY minute`,
+		since.UTC().Truncate(time.Minute),
+	)
+	if err != nil {
+		return report, fmt.Errorf("failed to query scheduler metrics: %w", err)
+	}
+	defer rows.Close()
+
+	var waitMs, waitCount, slotWaitMs, slotWaitCount int64
+	for rows.Next() {
+		var point models.SchedulerMetrics
+		var pointWaitMs, pointWaitCount, pointSlotWaitMs, pointSlotWaitCount int64
+		if err := rows.Scan(&point.Minute, &point.Executions, &point.Retries, &point.SkippedRuns, &pointWaitMs, &pointWaitCount,
+			&pointSlotWaitMs, &pointSlotWaitCount, &point.MaxSlotWaitMs); err != nil {
+			return report, fmt.Errorf("failed to scan scheduler metrics: %w", err)
+		}
+		if pointWaitCount > 0 {
+			point.AvgQueueWaitMs = float64(pointWaitMs) / float64(pointWaitCount)
+		}
+		if pointSlotWaitCount > 0 {
+			point.AvgSlotWaitMs = float64(pointSlotWaitMs) / float64(pointSlotWaitCount)
+		}
+		report.Points = append(report.Points, point)
+
+		report.Executions += point.Executions
+		report.Retries += point.Retries
+		report.SkippedRuns += point.SkippedRuns
+		waitMs += pointWaitMs
+		waitCount += pointWaitCount
+		slotWaitMs += pointSlotWaitMs
+		slotWaitCount += pointSlotWaitCount
+		report.MaxSlotWaitMs = max(report.MaxSlotWaitMs, point.MaxSlotWaitMs)
+	}
+	if err := rows.Err(); err != nil {
+		return report, fmt.Errorf("failed to read scheduler metrics: %w", err)
+	}
+
+	if minutes := time.Since(since).Minutes(); minutes > 0 {
+		report.ExecutionsPerMinute = float64(report.Executions) / minutes
+	}
+	if waitCount > 0 {
+		report.AvgQueueWaitMs = float64(waitMs) / float64(waitCount)
+	}
+	if slotWaitCount > 0 {
+		report.AvgSlotWaitMs = float64(slotWaitMs) / float64(slotWaitCount)
+	}
+
+	gaps, err := s.db.Query("SELECT started_at, ended_at FROM monitoring_gaps WHERE ended_at >= ? ORDER BY started_at", since.UTC())
+	if err != nil {
+		return report, fmt.Errorf("failed to query monitoring gaps: %w", err)
+	}
+	defer gaps.Close()
+	for gaps.Next() {
+		var gap models.MonitoringGap
+		if err := gaps.Scan(&gap.StartedAt, &gap.EndedAt); err != nil {
+			return report, fmt.Errorf("failed to scan monitoring gap: %w", err)
+		}
+		report.Gaps = append(report.Gaps, gap)
+	}
+	if err := gaps.Err(); err != nil {
+		return report, fmt.Errorf("failed to read monitoring gaps: %w", err)
+	}
+	return report, nil
+}
+
+// pruneSchedulerMetrics deletes the scheduler metrics and monitoring gaps recorded before the given time
+func (s *DBService) pruneSchedulerMetrics(before time.Time) error {
+	if _, err := s.db.Exec("DELETE FROM scheduler_metrics WHERE minute < ?", before.UTC()); err != nil {
+		return fmt.Errorf("failed to prune scheduler metrics: %w", err)
+	}
+	if _, err := s.db.Exec("DELETE FROM monitoring_gaps WHERE ended_at < ?", before.UTC()); err != nil {
+		return fmt.Errorf("failed to prune monitoring gaps: %w", err)
+	}
+	return nil
+}
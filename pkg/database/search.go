@@ -0,0 +1,228 @@
+package database
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"flowpulse/pkg/models"
+)
+
+// searchIndex is a full-text index over some text columns of a table, kept in step with it by triggers.
+// Rows of the index have the ID of the row they index as their rowid.
+type searchIndex struct {
+	table   string
+	index   string
+	columns []string
+}
+
+// searchIndexes are the full-text indexes behind Search
+var searchIndexes = []searchIndex{
+	{table: "apis", index: "apis_search", columns: []string{"name", "url", "description"}},
+	{table: "collections", index: "collections_search", columns: []string{"name", "description"}},
+	{table: "execution_logs", index: "execution_logs_search", columns: []string{"response", "error"}},
+}
+
+// initSearchTables creates the full-text indexes and their triggers, filling new indexes from their tables.
+// The indexes use FTS5 when SQLite was built with it (go-sqlite3 needs the sqlite_fts5 build tag) and FTS4
+// otherwise; Search only uses query syntax both understand.
+func (s *DBService) initSearchTables() error {
+	var hasFTS5 bool
+	if err := s.db.QueryRow("SELECT sqlite_compileoption_used('ENABLE_FTS5')").Scan(&hasFTS5); err != nil {
+		return fmt.Errorf("failed to check for FTS5: %w", err)
+	}
+	module := "fts4"
+	if hasFTS5 {
+		module = "fts5"
+	}
+
+	for _, idx := range searchIndexes {
+		if err := s.initSearchIndex(idx, module); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// initSearchIndex creates a full-text index with the module unless it exists, and the triggers maintaining it
+func (s *DBService) initSearchIndex(idx searchIndex, module string) error {
+	var exists bool
+	if err := s.db.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE name = ?", idx.index).Scan(&exists); err != nil {
+		return fmt.Errorf("failed to check for %s index: %w", idx.index, err)
+	}
+
+	columns := strings.Join(idx.columns, ", ")
+	values := "new." + strings.Join(idx.columns, ", new.")
+	insert := fmt.Sprintf("INSERT INTO %s (rowid, %s) VALUES (new.id, %s);", idx.index, columns, values)
+	remove := fmt.Sprintf("DELETE FROM %s WHERE rowid = old.id;", idx.index)
+
+	if !exists {
+		if _, err := s.db.Exec(fmt.Sprintf("CREATE VIRTUAL TABLE %s USING %s(%s)", idx.index, module, columns)); err != nil {
+			return fmt.Errorf("failed to create %s index: %w", idx.index, err)
+		}
+		backfill := fmt.Sprintf("INSERT INTO %s (rowid, %s) SELECT id, %s FROM %s", idx.index, columns, columns, idx.table)
+		if _, err := s.db.Exec(backfill); err != nil {
+			return fmt.Errorf("failed to fill %s index: %w", idx.index, err)
+		}
+	}
+
+	_, err := s.db.Exec(fmt.Sprintf(`
+		CREATE TRIGGER IF NOT EXISTS %[1]s_insert AFTER INSERT ON %[2]s BEGIN
+			%[4]s
+		END;
+		CREATE TRIGGER IF NOT EXISTS %[1]s_update AFTER UPDATE OF %[3]s ON %[2]s BEGIN
+			%[5]s
+			%[4]s
+		END;
+		CREATE TRIGGER IF NOT EXISTS %[1]s_delete AFTER DELETE ON %[2]s BEGIN
+			%[5]s
+		END;
+	`, idx.index, idx.table, columns, insert, remove))
+	if err != nil {
+		return fmt.Errorf("failed to create %s triggers: %w", idx.index, err)
+	}
+	return nil
+}
+
+// Search finds the APIs whose name, URL or description, the collections whose name or description, and the
+// execution logs whose response or error contain every word of the query, the last one also as a prefix.
+// It returns up to limit results of each type: APIs and collections by name, then executions newest first.
+func (s *DBService) Search(query string, limit int) ([]models.SearchResult, error) {
+	terms := searchTerms(query)
+	if len(terms) == 0 {
+		return nil, nil
+	}
+	match := strings.Join(terms, " ") + "*"
+
+	var results []models.SearchResult
+
+	rows, err := s.db.Query(`
+		SELECT apis.id, apis.name, apis.url, COALESCE(apis.description, '')
+		FROM apis_search JOIN apis ON apis.id = apis_search.rowid
+		WHERE apis_search MATCH ? AND apis.deleted_at IS NULL
+		ORDER BY apis.name, apis.id
+		LIMIT ?
+	`, match, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search APIs: %w", err)
+	}
+	for rows.Next() {
+		var result models.SearchResult
+		var description string
+		if err := rows.Scan(&result.ID, &result.Title, &result.Subtitle, &description); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan API search result: %w", err)
+		}
+		result.Type = models.SearchResultAPI
+		result.Snippet = searchSnippet(terms, result.Title, result.Subtitle, description)
+		results = append(results, result)
+	}
+	rows.Close()
+
+	rows, err = s.db.Query(`
+		SELECT collections.id, collections.name, COALESCE(collections.description, '')
+		FROM collections_search JOIN collections ON collections.id = collections_search.rowid
+		WHERE collections_search MATCH ? AND collections.deleted_at IS NULL
+		ORDER BY collections.name, collections.id
+		LIMIT ?
+	`, match, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search collections: %w", err)
+	}
+	for rows.Next() {
+		var result models.SearchResult
+		if err := rows.Scan(&result.ID, &result.Title, &result.Subtitle); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan collection search result: %w", err)
+		}
+		result.Type = models.SearchResultCollection
+		result.Snippet = searchSnippet(terms, result.Title, result.Subtitle)
+		results = append(results, result)
+	}
+	rows.Close()
+
+	rows, err = s.db.Query(`
+		SELECT execution_logs.id, execution_logs.api_id, COALESCE(apis.name, ''), COALESCE(execution_logs.status_code, 0),
+			COALESCE(execution_logs.status, ''), COALESCE(execution_logs.response, ''), COALESCE(execution_logs.error, ''),
+			execution_logs.executed_at
+		FROM execution_logs_search
+		JOIN execution_logs ON execution_logs.id = execution_logs_search.rowid
+		LEFT JOIN apis ON apis.id = execution_logs.api_id
+		WHERE execution_logs_search MATCH ?
+		ORDER BY execution_logs.executed_at DESC
+		LIMIT ?
+	`, match, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search execution logs: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var result models.SearchResult
+		var statusCode int
+		var status, response, errorText string
+		var executedAt time.Time
+		if err := rows.Scan(&result.ID, &result.APIID, &result.Title, &statusCode, &status, &response, &errorText, &executedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan execution search result: %w", err)
+		}
+		result.Type = models.SearchResultExecution
+		result.Subtitle = fmt.Sprintf("%s (status %d)", status, statusCode)
+		result.Snippet = searchSnippet(terms, errorText, response)
+		result.ExecutedAt = &executedAt
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// searchTerms splits a query into lowercase words of letters and digits, which are safe to use as bare
+// terms in both FTS4 and FTS5 queries
+func searchTerms(query string) []string {
+	return strings.FieldsFunc(strings.ToLower(query), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// searchSnippetRadius is how many characters a snippet shows on each side of the match
+const searchSnippetRadius = 60
+
+// searchSnippet returns the text around the first term found in the first field containing one,
+// or the start of the first non-empty field when no term is found as such, e.g. when only a prefix matched
+func searchSnippet(terms []string, fields ...string) string {
+	for _, field := range fields {
+		lower := strings.ToLower(field)
+		for _, term := range terms {
+			if i := strings.Index(lower, term); i >= 0 && len(lower) == len(field) {
+				return excerpt(field, i, len(term))
+			}
+		}
+	}
+	for _, field := range fields {
+		if field != "" {
+			return excerpt(field, 0, 0)
+		}
+	}
+	return ""
+}
+
+// excerpt cuts a field down to the match at byte offset start and searchSnippetRadius characters around it
+func excerpt(field string, start, length int) string {
+	runes := []rune(field)
+	from := len([]rune(field[:start]))
+	to := from + len([]rune(field[start:start+length]))
+
+	prefix, suffix := "", ""
+	if from > searchSnippetRadius {
+		from -= searchSnippetRadius
+		prefix = "…"
+	} else {
+		from = 0
+	}
+	if to+searchSnippetRadius < len(runes) {
+		to += searchSnippetRadius
+		suffix = "…"
+	} else {
+		to = len(runes)
+	}
+	return prefix + strings.Join(strings.Fields(string(runes[from:to])), " ") + suffix
+}
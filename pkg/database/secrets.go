@@ -0,0 +1,117 @@
+package database
+
+import (
+	"fmt"
+	"time"
+
+	"flowpulse/pkg/models"
+)
+
+// initSecretsTables creates the secrets table. The value column only holds the encrypted
+// value of secrets kept in the local backend; keychain secrets store nothing but their name.
+func (s *DBService) initSecretsTables() error {
+	_, err := s.db.Exec(`
+		CREATE TABLE IF NOT EXISTS secrets (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT NOT NULL UNIQUE,
+			backend TEXT NOT NULL,
+			value TEXT NOT NULL DEFAULT '',
+			created_at TIMESTAMP NOT NULL,
+			updated_at TIMESTAMP NOT NULL
+		)
+	`)
+	return err
+}
+
+// Secret Operations
+
+// secretColumns is the column list matching scanSecret
+const secretColumns = "id, name, backend, validation_api_id, created_at, updated_at"
+
+// scanSecret scans a single secret selected with secretColumns
+func scanSecret(row rowScanner) (models.Secret, error) {
+	var secret models.Secret
+	err := row.Scan(&secret.ID, &secret.Name, &secret.Backend, &secret.ValidationAPIID, &secret.CreatedAt, &secret.UpdatedAt)
+	return secret, err
+}
+
+// SaveSecret creates or replaces a secret by name. value is the encrypted value for the local
+// backend and empty for the keychain.
+func (s *DBService) SaveSecret(name, backend, value string) (models.Secret, error) {
+	now := time.Now()
+	_, err := s.db.Exec(
+		`INSERT INTO secrets (name, backend, value, created_at, updated_at) VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT(name) DO UPDATE SET backend = excluded.backend, value = excluded.value, updated_at = excluded.updated_at`,
+		name, backend, value, now, now,
+	)
+	if err != nil {
+		return models.Secret{}, fmt.Errorf("failed to save secret: %w", err)
+	}
+	return s.GetSecretByName(name)
+}
+
+// SetSecretValidationAPI sets the API run to check a secret after it is rotated; 0 clears it
+func (s *DBService) SetSecretValidationAPI(id, apiID int) (models.Secret, error) {
+	_, err := s.db.Exec("UPDATE secrets SET validation_api_id = ?, updated_at = ? WHERE id = ?", apiID, time.Now(), id)
+	if err != nil {
+		return models.Secret{}, fmt.Errorf("failed to set validation API of secret: %w", err)
+	}
+	return s.GetSecretByID(id)
+}
+
+// DeleteSecret deletes a secret by ID
+func (s *DBService) DeleteSecret(id int) error {
+	_, err := s.db.Exec("DELETE FROM secrets WHERE id = ?", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete secret: %w", err)
+	}
+	return nil
+}
+
+// GetSecretByID gets a secret by ID
+func (s *DBService) GetSecretByID(id int) (models.Secret, error) {
+	secret, err := scanSecret(s.db.QueryRow("SELECT "+secretColumns+" FROM secrets WHERE id = ?", id))
+	if err != nil {
+		return secret, fmt.Errorf("failed to get secret by ID: %w", err)
+	}
+	return secret, nil
+}
+
+// GetSecretByName gets a secret by name
+func (s *DBService) GetSecretByName(name string) (models.Secret, error) {
+	secret, err := scanSecret(s.db.QueryRow("SELECT "+secretColumns+" FROM secrets WHERE name = ?", name))
+	if err != nil {
+		return secret, fmt.Errorf("failed to get secret %q: %w", name, err)
+	}
+	return secret, nil
+}
+
+// GetSecretValue gets the stored value of a secret, which is only set for the local backend
+func (s *DBService) GetSecretValue(name string) (string, error) {
+	var value string
+	err := s.db.QueryRow("SELECT value FROM secrets WHERE name = ?", name).Scan(&value)
+	if err != nil {
+		return "", fmt.Errorf("failed to get value of secret %q: %w", name, err)
+	}
+	return value, nil
+}
+
+// GetAllSecrets gets all secrets without their values
+func (s *DBService) GetAllSecrets() ([]models.Secret, error) {
+	rows, err := s.db.Query("SELECT " + secretColumns + " FROM secrets ORDER BY name")
+	if err != nil {
+		return nil, fmt.Errorf("failed to query secrets: %w", err)
+	}
+	defer rows.Close()
+
+	var secrets []models.Secret
+	for rows.Next() {
+		secret, err := scanSecret(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan secret row: %w", err)
+		}
+		secrets = append(secrets, secret)
+	}
+
+	return secrets, nil
+}
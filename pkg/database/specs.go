@@ -0,0 +1,258 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"flowpulse/pkg/models"
+)
+
+// initSpecTables creates the table of imported OpenAPI documents
+func (s *DBService) initSpecTables() error {
+	_, err := s.db.Exec(`
+		CREATE TABLE IF NOT EXISTS api_specs (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT NOT NULL,
+			source_url TEXT NOT NULL DEFAULT '',
+			document TEXT NOT NULL,
+			created_at TIMESTAMP NOT NULL,
+			updated_at TIMESTAMP NOT NULL
+		)
+	`)
+	if err != nil {
+		return err
+	}
+
+	// Add watch settings so specs can be re-fetched from their source URL
+	if err := s.addColumnIfMissing("api_specs", "collection_id", "INTEGER DEFAULT 0"); err != nil {
+		return err
+	}
+	if err := s.addColumnIfMissing("api_specs", "watch_interval_minutes", "INTEGER DEFAULT 0"); err != nil {
+		return err
+	}
+	if err := s.addColumnIfMissing("api_specs", "auto_create", "BOOLEAN DEFAULT 0"); err != nil {
+		return err
+	}
+	if err := s.addColumnIfMissing("api_specs", "last_checked_at", "TIMESTAMP"); err != nil {
+		return err
+	}
+
+	_, err = s.db.Exec(`
+		CREATE TABLE IF NOT EXISTS spec_proposals (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			spec_id INTEGER NOT NULL,
+			kind TEXT NOT NULL,
+			operation TEXT NOT NULL,
+			api_id INTEGER NOT NULL DEFAULT 0,
+			status TEXT NOT NULL,
+			detected_at TIMESTAMP NOT NULL,
+			FOREIGN KEY (spec_id) REFERENCES api_specs (id) ON DELETE CASCADE
+		)
+	`)
+	return err
+}
+
+// API Spec Operations
+
+// apiSpecColumns is the column list matching scanAPISpec
+const apiSpecColumns = `id, name, source_url, document, COALESCE(collection_id, 0), COALESCE(watch_interval_minutes, 0),
+	COALESCE(auto_create, 0), last_checked_at, created_at, updated_at`
+
+// scanAPISpec scans a single spec selected with apiSpecColumns
+func scanAPISpec(row rowScanner) (models.APISpec, error) {
+	var spec models.APISpec
+	var lastCheckedAt sql.NullTime
+	err := row.Scan(&spec.ID, &spec.Name, &spec.SourceURL, &spec.Document, &spec.CollectionID, &spec.WatchIntervalMinutes,
+		&spec.AutoCreate, &lastCheckedAt, &spec.CreatedAt, &spec.UpdatedAt)
+	if lastCheckedAt.Valid {
+		spec.LastCheckedAt = lastCheckedAt.Time
+	}
+	return spec, err
+}
+
+// CreateAPISpec stores a new OpenAPI document
+func (s *DBService) CreateAPISpec(spec models.APISpec) (models.APISpec, error) {
+	now := time.Now()
+	spec.CreatedAt = now
+	spec.UpdatedAt = now
+
+	result, err := s.db.Exec(
+		"INSERT INTO api_specs (name, source_url, document, collection_id, watch_interval_minutes, auto_create, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?)",
+		spec.Name, spec.SourceURL, spec.Document, spec.CollectionID, spec.WatchIntervalMinutes, spec.AutoCreate, spec.CreatedAt, spec.UpdatedAt,
+	)
+	if err != nil {
+		return spec, fmt.Errorf("failed to create API spec: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return spec, fmt.Errorf("failed to get last insert ID: %w", err)
+	}
+
+	spec.ID = int(id)
+	return spec, nil
+}
+
+// UpdateAPISpec updates a stored OpenAPI document
+func (s *DBService) UpdateAPISpec(spec models.APISpec) (models.APISpec, error) {
+	spec.UpdatedAt = time.Now()
+
+	_, err := s.db.Exec(
+		"UPDATE api_specs SET name = ?, source_url = ?, document = ?, collection_id = ?, watch_interval_minutes = ?, auto_create = ?, updated_at = ? WHERE id = ?",
+		spec.Name, spec.SourceURL, spec.Document, spec.CollectionID, spec.WatchIntervalMinutes, spec.AutoCreate, spec.UpdatedAt, spec.ID,
+	)
+	if err != nil {
+		return spec, fmt.Errorf("failed to update API spec: %w", err)
+	}
+	return s.GetAPISpecByID(spec.ID)
+}
+
+// DeleteAPISpec deletes an OpenAPI document and unlinks the APIs imported from it
+func (s *DBService) DeleteAPISpec(id int) error {
+	_, err := s.db.Exec("UPDATE apis SET spec_id = 0, spec_operation = '', validate_contract = 0 WHERE spec_id = ?", id)
+	if err != nil {
+		return fmt.Errorf("failed to unlink APIs from spec: %w", err)
+	}
+
+	_, err = s.db.Exec("DELETE FROM api_specs WHERE id = ?", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete API spec: %w", err)
+	}
+	return nil
+}
+
+// GetAPISpecByID gets an OpenAPI document by ID
+func (s *DBService) GetAPISpecByID(id int) (models.APISpec, error) {
+	spec, err := scanAPISpec(s.db.QueryRow("SELECT "+apiSpecColumns+" FROM api_specs WHERE id = ?", id))
+	if err != nil {
+		return spec, fmt.Errorf("failed to get API spec by ID: %w", err)
+	}
+	return spec, nil
+}
+
+// GetAllAPISpecs gets all stored OpenAPI documents
+func (s *DBService) GetAllAPISpecs() ([]models.APISpec, error) {
+	rows, err := s.db.Query("SELECT " + apiSpecColumns + " FROM api_specs ORDER BY name")
+	if err != nil {
+		return nil, fmt.Errorf("failed to query API specs: %w", err)
+	}
+	defer rows.Close()
+
+	var specs []models.APISpec
+	for rows.Next() {
+		spec, err := scanAPISpec(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan API spec row: %w", err)
+		}
+		specs = append(specs, spec)
+	}
+
+	return specs, nil
+}
+
+// GetAPIsBySpecID gets the APIs imported from an OpenAPI document
+func (s *DBService) GetAPIsBySpecID(specID int) ([]models.API, error) {
+	rows, err := s.db.Query("SELECT "+apiColumns+" FROM apis WHERE spec_id = ? AND deleted_at IS NULL ORDER BY name", specID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query APIs: %w", err)
+	}
+	defer rows.Close()
+
+	return scanAPIs(rows)
+}
+
+// MarkAPISpecChecked records when a watched spec was last fetched
+func (s *DBService) MarkAPISpecChecked(id int, checkedAt time.Time) error {
+	_, err := s.db.Exec("UPDATE api_specs SET last_checked_at = ? WHERE id = ?", checkedAt, id)
+	if err != nil {
+		return fmt.Errorf("failed to mark API spec checked: %w", err)
+	}
+	return nil
+}
+
+// Spec Proposal Operations
+
+// specProposalColumns is the column list matching scanSpecProposal
+const specProposalColumns = "id, spec_id, kind, operation, api_id, status, detected_at"
+
+// scanSpecProposal scans a single proposal selected with specProposalColumns
+func scanSpecProposal(row rowScanner) (models.SpecProposal, error) {
+	var proposal models.SpecProposal
+	err := row.Scan(&proposal.ID, &proposal.SpecID, &proposal.Kind, &proposal.Operation, &proposal.APIID, &proposal.Status, &proposal.DetectedAt)
+	return proposal, err
+}
+
+// CreateSpecProposal records a detected spec change unless the same change was already proposed or dismissed
+func (s *DBService) CreateSpecProposal(proposal models.SpecProposal) error {
+	var exists bool
+	err := s.db.QueryRow(
+		"SELECT COUNT(*) > 0 FROM spec_proposals WHERE spec_id = ? AND kind = ? AND operation = ? AND status != ?",
+		proposal.SpecID, proposal.Kind, proposal.Operation, models.SpecProposalAccepted,
+	).Scan(&exists)
+	if err != nil {
+		return fmt.Errorf("failed to check spec proposals: %w", err)
+	}
+	if exists {
+		return nil
+	}
+
+	_, err = s.db.Exec(
+		"INSERT INTO spec_proposals (spec_id, kind, operation, api_id, status, detected_at) VALUES (?, ?, ?, ?, ?, ?)",
+		proposal.SpecID, proposal.Kind, proposal.Operation, proposal.APIID, models.SpecProposalPending, time.Now(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create spec proposal: %w", err)
+	}
+	return nil
+}
+
+// DeletePendingSpecProposals removes a spec's pending proposals, e.g. before recording the latest detected changes
+func (s *DBService) DeletePendingSpecProposals(specID int) error {
+	_, err := s.db.Exec("DELETE FROM spec_proposals WHERE spec_id = ? AND status = ?", specID, models.SpecProposalPending)
+	if err != nil {
+		return fmt.Errorf("failed to delete spec proposals: %w", err)
+	}
+	return nil
+}
+
+// SetSpecProposalStatus marks a proposal as accepted or dismissed
+func (s *DBService) SetSpecProposalStatus(id int, status string) error {
+	_, err := s.db.Exec("UPDATE spec_proposals SET status = ? WHERE id = ?", status, id)
+	if err != nil {
+		return fmt.Errorf("failed to update spec proposal: %w", err)
+	}
+	return nil
+}
+
+// GetSpecProposalByID gets a spec proposal by ID
+func (s *DBService) GetSpecProposalByID(id int) (models.SpecProposal, error) {
+	proposal, err := scanSpecProposal(s.db.QueryRow("SELECT "+specProposalColumns+" FROM spec_proposals WHERE id = ?", id))
+	if err != nil {
+		return proposal, fmt.Errorf("failed to get spec proposal by ID: %w", err)
+	}
+	return proposal, nil
+}
+
+// GetPendingSpecProposals gets the pending proposals of a spec
+func (s *DBService) GetPendingSpecProposals(specID int) ([]models.SpecProposal, error) {
+	rows, err := s.db.Query(
+		"SELECT "+specProposalColumns+" FROM spec_proposals WHERE spec_id = ? AND status = ? ORDER BY operation",
+		specID, models.SpecProposalPending,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query spec proposals: %w", err)
+	}
+	defer rows.Close()
+
+	var proposals []models.SpecProposal
+	for rows.Next() {
+		proposal, err := scanSpecProposal(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan spec proposal row: %w", err)
+		}
+		proposals = append(proposals, proposal)
+	}
+
+	return proposals, nil
+}
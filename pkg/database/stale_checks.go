@@ -0,0 +1,42 @@
+package database
+
+import (
+	"strconv"
+
+	"flowpulse/pkg/models"
+)
+
+// Settings keys of the stale check policy
+const (
+	settingStaleIntervals = "stale.intervals"
+	settingStaleAlert     = "stale.alert"
+)
+
+// defaultStaleIntervals is how many intervals a schedule may miss before it is stale when not configured
+const defaultStaleIntervals = 3
+
+// GetStaleCheckPolicy gets the policy deciding when active schedules count as stale
+func (s *DBService) GetStaleCheckPolicy() (models.StaleCheckPolicy, error) {
+	policy := models.StaleCheckPolicy{Intervals: defaultStaleIntervals}
+
+	if intervals, err := s.getIntSetting(settingStaleIntervals); err != nil {
+		return policy, err
+	} else if intervals > 0 {
+		policy.Intervals = intervals
+	}
+
+	alert, err := s.GetSetting(settingStaleAlert)
+	if err != nil {
+		return policy, err
+	}
+	policy.Alert = alert == "true"
+	return policy, nil
+}
+
+// SaveStaleCheckPolicy saves the policy deciding when active schedules count as stale
+func (s *DBService) SaveStaleCheckPolicy(policy models.StaleCheckPolicy) error {
+	if err := s.SetSetting(settingStaleIntervals, strconv.Itoa(policy.Intervals)); err != nil {
+		return err
+	}
+	return s.SetSetting(settingStaleAlert, strconv.FormatBool(policy.Alert))
+}
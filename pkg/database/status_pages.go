@@ -0,0 +1,136 @@
+package database
+
+import (
+	"fmt"
+	"time"
+
+	"flowpulse/pkg/models"
+)
+
+// initStatusPageTables creates the status pages table
+func (s *DBService) initStatusPageTables() error {
+	_, err := s.db.Exec(`
+		CREATE TABLE IF NOT EXISTS status_pages (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT NOT NULL,
+			slug TEXT NOT NULL UNIQUE,
+			title TEXT NOT NULL DEFAULT '',
+			api_ids TEXT NOT NULL DEFAULT '',
+			window_days INTEGER NOT NULL DEFAULT 0,
+			public BOOLEAN NOT NULL DEFAULT 0,
+			output_dir TEXT NOT NULL DEFAULT '',
+			created_at TIMESTAMP NOT NULL,
+			updated_at TIMESTAMP NOT NULL
+		)
+	`)
+	return err
+}
+
+// statusPageColumns is the column list matching scanStatusPage
+const statusPageColumns = "id, name, slug, title, api_ids, window_days, public, output_dir, created_at, updated_at"
+
+// scanStatusPage scans a single status page selected with statusPageColumns
+func scanStatusPage(row rowScanner) (models.StatusPage, error) {
+	var page models.StatusPage
+	var apiIDs string
+	err := row.Scan(&page.ID, &page.Name, &page.Slug, &page.Title, &apiIDs, &page.WindowDays, &page.Public, &page.OutputDir,
+		&page.CreatedAt, &page.UpdatedAt)
+	if err != nil {
+		return page, err
+	}
+	return page, decodeJSONList(apiIDs, &page.APIIDs, "status page APIs")
+}
+
+// Status Page Operations
+
+// CreateStatusPage creates a new status page
+func (s *DBService) CreateStatusPage(page models.StatusPage) (models.StatusPage, error) {
+	now := time.Now()
+	page.CreatedAt = now
+	page.UpdatedAt = now
+	apiIDs, err := encodeJSONList(page.APIIDs, "status page APIs")
+	if err != nil {
+		return page, err
+	}
+
+	result, err := s.db.Exec(
+		"INSERT INTO status_pages (name, slug, title, api_ids, window_days, public, output_dir, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)",
+		page.Name, page.Slug, page.Title, apiIDs, page.WindowDays, page.Public, page.OutputDir, page.CreatedAt, page.UpdatedAt,
+	)
+	if err != nil {
+		return page, fmt.Errorf("failed to create status page: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return page, fmt.Errorf("failed to get last insert ID: %w", err)
+	}
+
+	page.ID = int(id)
+	return page, nil
+}
+
+// UpdateStatusPage updates an existing status page
+func (s *DBService) UpdateStatusPage(page models.StatusPage) (models.StatusPage, error) {
+	page.UpdatedAt = time.Now()
+	apiIDs, err := encodeJSONList(page.APIIDs, "status page APIs")
+	if err != nil {
+		return page, err
+	}
+
+	_, err = s.db.Exec(
+		"UPDATE status_pages SET name = ?, slug = ?, title = ?, api_ids = ?, window_days = ?, public = ?, output_dir = ?, updated_at = ? WHERE id = ?",
+		page.Name, page.Slug, page.Title, apiIDs, page.WindowDays, page.Public, page.OutputDir, page.UpdatedAt, page.ID,
+	)
+	if err != nil {
+		return page, fmt.Errorf("failed to update status page: %w", err)
+	}
+	return s.GetStatusPageByID(page.ID)
+}
+
+// DeleteStatusPage deletes a status page by ID
+func (s *DBService) DeleteStatusPage(id int) error {
+	_, err := s.db.Exec("DELETE FROM status_pages WHERE id = ?", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete status page: %w", err)
+	}
+	return nil
+}
+
+// GetStatusPageByID gets a status page by ID
+func (s *DBService) GetStatusPageByID(id int) (models.StatusPage, error) {
+	page, err := scanStatusPage(s.db.QueryRow("SELECT "+statusPageColumns+" FROM status_pages WHERE id = ?", id))
+	if err != nil {
+		return page, fmt.Errorf("failed to get status page by ID: %w", err)
+	}
+	return page, nil
+}
+
+// GetStatusPageBySlug gets a status page by the path it is served at
+func (s *DBService) GetStatusPageBySlug(slug string) (models.StatusPage, error) {
+	page, err := scanStatusPage(s.db.QueryRow("SELECT "+statusPageColumns+" FROM status_pages WHERE slug = ?", slug))
+	if err != nil {
+		return page, fmt.Errorf("failed to get status page by slug: %w", err)
+	}
+	return page, nil
+}
+
+// GetAllStatusPages gets all status pages ordered by name
+func (s *DBService) GetAllStatusPages() ([]models.StatusPage, error) {
+	rows, err := s.db.Query("SELECT " + statusPageColumns + " FROM status_pages ORDER BY name, id")
+	if err != nil {
+		return nil, fmt.Errorf("failed to query status pages: %w", err)
+	}
+	defer rows.Close()
+
+	var pages []models.StatusPage
+	for rows.Next() {
+		page, err := scanStatusPage(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan status page row: %w", err)
+		}
+		pages = append(pages, page)
+	}
+
+	return pages, nil
+}
@@ -0,0 +1,318 @@
+package database
+
+import (
+	"time"
+
+	"flowpulse/pkg/models"
+)
+
+// Store is the storage FlowPulse runs on. DBService implements it on SQLite, keeping the SQLite-only parts
+// to itself: the FTS search indexes, the sqlite_master lookups of its migrations, PRAGMA integrity_check and
+// the VACUUM INTO snapshots. Another backend implements Store with its own dialect for those.
+type Store interface {
+	// APIs, schedules, execution logs and collections
+	Close() error
+	CreateAPI(api models.API) (models.API, error)
+	UpdateAPI(api models.API) (models.API, error)
+	DeleteAPI(id int) error
+	GetAPIByID(id int) (models.API, error)
+	GetAllAPIs() ([]models.API, error)
+	CreateSchedule(schedule models.Schedule) (models.Schedule, error)
+	UpdateSchedule(schedule models.Schedule) error
+	DeleteSchedule(id int) error
+	GetScheduleByID(id int) (models.Schedule, error)
+	GetAllSchedules() ([]models.Schedule, error)
+	GetSchedulesByAPIID(apiID int) ([]models.Schedule, error)
+	GetAllActiveSchedules() ([]models.Schedule, error)
+	CreateExecutionLog(log models.ExecutionLog) (models.ExecutionLog, error)
+	GetExecutionLogByID(id int) (models.ExecutionLog, error)
+	GetExecutionLogsByAPIID(apiID int, limit int) ([]models.ExecutionLog, error)
+	GetLatestExecutionForAPIs(apiIDs []int) (map[int]models.ExecutionLog, error)
+	GetAllExecutionLogs(page, pageSize int) ([]models.ExecutionLog, error)
+	GetRecentExecutions(limit int) ([]models.ExecutionLog, error)
+	CreateCollection(collection models.Collection) (models.Collection, error)
+	UpdateCollection(collection models.Collection) (models.Collection, error)
+	DeleteCollection(id int) error
+	GetCollectionByID(id int) (models.Collection, error)
+	GetAllCollections() ([]models.Collection, error)
+	GetAPIsByCollectionID(collectionID int) ([]models.API, error)
+	GetAPIAnalytics(apiID int) (models.AnalyticsSummary, error)
+	GetOverallAnalytics() (models.AnalyticsSummary, error)
+
+	// Analytics
+	GetLocationComparison(apiID int, since time.Time) (models.LocationComparison, error)
+	GetAPITimeSeries(apiID int, bucket string, from, to time.Time) (models.TimeSeries, error)
+	GetCollectionLatencyPercentile(collectionID int, percentile float64, since time.Time) (int64, int, error)
+	GetIncidentHistory(apiID int, since time.Time) ([]models.Incident, error)
+	GetIncidentStats(apiID int, since time.Time) (models.IncidentStats, error)
+	GetAPIUptime(apiID int, since time.Time) (float64, int, error)
+	GetPerformanceBreakdown(apiID int, since time.Time) (models.PerformanceBreakdown, error)
+
+	// Annotations
+	CreateAnnotation(annotation models.Annotation) (models.Annotation, error)
+	UpdateAnnotationNote(id int, note string) (models.Annotation, error)
+	DeleteAnnotation(id int) error
+
+	// Assertions
+	CreateAssertion(assertion models.Assertion) (models.Assertion, error)
+	UpdateAssertion(assertion models.Assertion) (models.Assertion, error)
+	DeleteAssertion(id int) error
+	GetAssertionsByAPIID(apiID int) ([]models.Assertion, error)
+
+	// Auth configs
+	CreateAuthConfig(config models.AuthConfig) (models.AuthConfig, error)
+	UpdateAuthConfig(config models.AuthConfig) (models.AuthConfig, error)
+	DeleteAuthConfig(id int) error
+	GetAuthConfigByID(id int) (models.AuthConfig, error)
+	GetAllAuthConfigs() ([]models.AuthConfig, error)
+
+	// Backups
+	GetExecutionLogsAfterID(afterID, limit int) ([]models.ExecutionLog, error)
+	RestoreExecutionLogs(logs []models.ExecutionLog) error
+
+	// Trigger coalescing
+	GetTriggerCoalescing() (models.TriggerCoalescing, error)
+	SaveTriggerCoalescing(coalescing models.TriggerCoalescing) error
+	SetExecutionTriggers(logID int, triggers []models.ExecutionTrigger) error
+
+	// Collection runs
+	CreateCollectionRun(run models.CollectionRun) (models.CollectionRun, error)
+	FinishCollectionRun(run models.CollectionRun) error
+	GetCollectionRunByID(id int) (models.CollectionRun, error)
+	GetCollectionRunsByCollectionID(collectionID int, limit int) ([]models.CollectionRun, error)
+	SetCollectionOrder(collectionID int, apiIDs []int) error
+
+	// Nested collections
+	MoveCollection(id, parentID int) error
+	GetCollectionTree() ([]models.CollectionNode, error)
+
+	// Scheduler concurrency
+	GetSchedulerConcurrency() (models.SchedulerConcurrency, error)
+	SaveSchedulerConcurrency(concurrency models.SchedulerConcurrency) error
+
+	// Content watch
+	GetContentBaseline(apiID int) (models.ContentBaseline, bool, error)
+	SetContentBaseline(baseline models.ContentBaseline) error
+	DeleteContentBaseline(apiID int) error
+	CreateContentChange(change models.ContentChange) (models.ContentChange, error)
+	GetContentChanges(apiID int, limit int) ([]models.ContentChange, error)
+
+	// Dashboards
+	CreateDashboard(dashboard models.Dashboard) (models.Dashboard, error)
+	UpdateDashboard(dashboard models.Dashboard) (models.Dashboard, error)
+	DeleteDashboard(id int) error
+	GetDashboardByID(id int) (models.Dashboard, error)
+	GetAllDashboards() ([]models.Dashboard, error)
+
+	// Deprecation
+	GetDeprecationReport() ([]models.DeprecatedAPI, error)
+	GetAPIsToRetire(now time.Time) ([]models.API, error)
+
+	// Environments
+	CreateEnvironment(environment models.Environment) (models.Environment, error)
+	UpdateEnvironment(environment models.Environment) (models.Environment, error)
+	DeleteEnvironment(id int) error
+	GetEnvironmentByID(id int) (models.Environment, error)
+	GetAllEnvironments() ([]models.Environment, error)
+	SetCollectionEnvironment(collectionID, environmentID int) error
+	GetGlobalVariables() (string, error)
+	SaveGlobalVariables(variables string) error
+
+	// Expected failure windows
+	CreateExpectedFailureWindow(window models.ExpectedFailureWindow) (models.ExpectedFailureWindow, error)
+	UpdateExpectedFailureWindow(window models.ExpectedFailureWindow) (models.ExpectedFailureWindow, error)
+	DeleteExpectedFailureWindow(id int) error
+	GetExpectedFailureWindowsByAPIID(apiID int) ([]models.ExpectedFailureWindow, error)
+
+	// Extractions
+	CreateExtraction(extraction models.Extraction) (models.Extraction, error)
+	UpdateExtraction(extraction models.Extraction) (models.Extraction, error)
+	DeleteExtraction(id int) error
+	GetExtractionsByAPIID(apiID int) ([]models.Extraction, error)
+
+	// Find and replace
+	ReplaceInAPIs(request models.FindReplace, apply bool) (models.ReplaceResult, error)
+
+	// Incidents and alert snoozes
+	CreateIncident(apiID int) (models.Incident, error)
+	GetIncidentByID(id int) (models.Incident, error)
+	GetOpenIncidentByAPIID(apiID int) (models.Incident, bool, error)
+	GetOpenIncidents() ([]models.Incident, error)
+	GetIncidentPolicy() (models.IncidentPolicy, error)
+	SaveIncidentPolicy(policy models.IncidentPolicy) error
+	GetIncidentsByAPIID(apiID int) ([]models.Incident, error)
+	AcknowledgeIncident(id int, note string) error
+	ResolveIncident(id int) error
+	GetIncidentEvents(incidentID int) ([]models.IncidentEvent, error)
+	SnoozeAlerts(apiID int, until time.Time) error
+	ClearAlertSnooze(apiID int) error
+	GetAlertSnoozedUntil(apiID int) (time.Time, error)
+
+	// Ingestion guard
+	GetIngestionGuard() (models.IngestionGuard, error)
+	SaveIngestionGuard(guard models.IngestionGuard) error
+
+	// Integrity snapshots
+	SnapshotIfDue() error
+
+	// Latency rollups
+	GetLatencyHistogram(apiID int, from, to time.Time) (models.LatencyHistogram, error)
+
+	// Log filters
+	SearchExecutionLogs(filter models.LogFilter, limit int) ([]models.ExecutionLog, error)
+	CreateSavedLogFilter(saved models.SavedLogFilter) (models.SavedLogFilter, error)
+	UpdateSavedLogFilter(saved models.SavedLogFilter) (models.SavedLogFilter, error)
+	DeleteSavedLogFilter(id int) error
+	GetSavedLogFilterByID(id int) (models.SavedLogFilter, error)
+	GetAllSavedLogFilters() ([]models.SavedLogFilter, error)
+
+	// Maintenance windows
+	CreateMaintenanceWindow(window models.MaintenanceWindow) (models.MaintenanceWindow, error)
+	UpdateMaintenanceWindow(window models.MaintenanceWindow) (models.MaintenanceWindow, error)
+	DeleteMaintenanceWindow(id int) error
+	GetAllMaintenanceWindows() ([]models.MaintenanceWindow, error)
+
+	// Notification channels and alert rules
+	CreateNotificationChannel(channel models.NotificationChannel) (models.NotificationChannel, error)
+	UpdateNotificationChannel(channel models.NotificationChannel) (models.NotificationChannel, error)
+	DeleteNotificationChannel(id int) error
+	GetNotificationChannelByID(id int) (models.NotificationChannel, error)
+	GetAllNotificationChannels() ([]models.NotificationChannel, error)
+	CreateAlertRule(rule models.AlertRule) (models.AlertRule, error)
+	UpdateAlertRule(rule models.AlertRule) (models.AlertRule, error)
+	DeleteAlertRule(id int) error
+	GetAllAlertRules() ([]models.AlertRule, error)
+	GetAlertRuleByID(id int) (models.AlertRule, error)
+	GetAlertRulesByScheduleID(scheduleID int) ([]models.AlertRule, error)
+	GetAlertRulesByCollectionID(collectionID int) ([]models.AlertRule, error)
+	GetRecentStatusesByScheduleID(scheduleID int, limit int) ([]string, error)
+	GetRecentLatencyBreachesByScheduleID(scheduleID int, limit int) ([]string, error)
+	GetRecentScheduledStatusesByAPIID(apiID int, limit int) ([]string, error)
+	GetRecentScheduledStatusesByLocation(apiID int, location string, limit int) ([]string, error)
+
+	// On-call rotations
+	CreateOnCallUser(user models.OnCallUser) (models.OnCallUser, error)
+	UpdateOnCallUser(user models.OnCallUser) (models.OnCallUser, error)
+	DeleteOnCallUser(id int) error
+	GetOnCallUserByID(id int) (models.OnCallUser, error)
+	GetAllOnCallUsers() ([]models.OnCallUser, error)
+	CreateOnCallRotation(rotation models.OnCallRotation) (models.OnCallRotation, error)
+	UpdateOnCallRotation(rotation models.OnCallRotation) (models.OnCallRotation, error)
+	DeleteOnCallRotation(id int) error
+	GetOnCallRotationByID(id int) (models.OnCallRotation, error)
+	GetAllOnCallRotations() ([]models.OnCallRotation, error)
+
+	// Proxy settings
+	GetGlobalProxy() (models.ProxyConfig, error)
+	SaveGlobalProxy(proxy models.ProxyConfig) error
+
+	// Response bodies
+	GetExecutionResponseBody(logID int) (string, error)
+
+	// REST server
+	GetRESTServerConfig() (models.RESTServerConfig, error)
+	SaveRESTServerConfig(config models.RESTServerConfig) error
+
+	// Retention
+	GetRetentionPolicy() (models.RetentionPolicy, error)
+	SaveRetentionPolicy(policy models.RetentionPolicy) error
+	PruneExecutionLogs() (deleted int64, err error)
+	StartPruning(interval time.Duration)
+
+	// Schedule dependencies
+	ScheduleRunsAfter(id, otherID int) (bool, error)
+	GetSchedulesAfter(id int) ([]models.Schedule, error)
+	GetScheduleGraph() (models.ScheduleGraph, error)
+
+	// Scheduler metrics
+	RecordSchedulerExecution(at time.Time, retries int) error
+	RecordSchedulerQueueWait(at time.Time, wait time.Duration) error
+	RecordSkippedRuns(at time.Time, count int) error
+	RecordSlotWait(at time.Time, wait time.Duration) error
+	RecordMonitoringGap(startedAt, endedAt time.Time) error
+	GetSchedulerMetrics(since time.Time) (models.SchedulerMetricsReport, error)
+
+	// Search
+	Search(query string, limit int) ([]models.SearchResult, error)
+
+	// Secrets
+	SaveSecret(name, backend, value string) (models.Secret, error)
+	SetSecretValidationAPI(id, apiID int) (models.Secret, error)
+	DeleteSecret(id int) error
+	GetSecretByID(id int) (models.Secret, error)
+	GetSecretByName(name string) (models.Secret, error)
+	GetSecretValue(name string) (string, error)
+	GetAllSecrets() ([]models.Secret, error)
+
+	// API specs
+	CreateAPISpec(spec models.APISpec) (models.APISpec, error)
+	UpdateAPISpec(spec models.APISpec) (models.APISpec, error)
+	DeleteAPISpec(id int) error
+	GetAPISpecByID(id int) (models.APISpec, error)
+	GetAllAPISpecs() ([]models.APISpec, error)
+	GetAPIsBySpecID(specID int) ([]models.API, error)
+	MarkAPISpecChecked(id int, checkedAt time.Time) error
+	CreateSpecProposal(proposal models.SpecProposal) error
+	DeletePendingSpecProposals(specID int) error
+	SetSpecProposalStatus(id int, status string) error
+	GetSpecProposalByID(id int) (models.SpecProposal, error)
+	GetPendingSpecProposals(specID int) ([]models.SpecProposal, error)
+
+	// Stale checks
+	GetStaleCheckPolicy() (models.StaleCheckPolicy, error)
+	SaveStaleCheckPolicy(policy models.StaleCheckPolicy) error
+
+	// Status pages
+	CreateStatusPage(page models.StatusPage) (models.StatusPage, error)
+	UpdateStatusPage(page models.StatusPage) (models.StatusPage, error)
+	DeleteStatusPage(id int) error
+	GetStatusPageByID(id int) (models.StatusPage, error)
+	GetStatusPageBySlug(slug string) (models.StatusPage, error)
+	GetAllStatusPages() ([]models.StatusPage, error)
+
+	// Workspace sync
+	GetSyncConfig() (models.SyncConfig, error)
+	SaveSyncConfig(config models.SyncConfig) error
+	GetSyncBaseSnapshot() (string, error)
+	SaveSyncBaseSnapshot(snapshot string, syncedAt time.Time) error
+
+	// Tags
+	CreateTag(tag models.Tag) (models.Tag, error)
+	UpdateTag(tag models.Tag) (models.Tag, error)
+	DeleteTag(id int) error
+	GetTagByID(id int) (models.Tag, error)
+	GetAllTags() ([]models.Tag, error)
+	GetTagsByAPIID(apiID int) ([]models.Tag, error)
+	GetTagsByScheduleID(scheduleID int) ([]models.Tag, error)
+	SetAPITags(apiID int, tagIDs []int) error
+	SetScheduleTags(scheduleID int, tagIDs []int) error
+	GetAPIsByTag(tagID int) ([]models.API, error)
+	GetSchedulesByTag(tagID int) ([]models.Schedule, error)
+	GetExecutionLogsByTag(tagID int, limit int) ([]models.ExecutionLog, error)
+
+	// Trash
+	RestoreAPI(id int) error
+	RestoreCollection(id int) error
+	GetTrash() (models.Trash, error)
+	EmptyTrash() error
+
+	// Webhooks
+	CreateWebhookTrigger(trigger models.WebhookTrigger) (models.WebhookTrigger, error)
+	UpdateWebhookTrigger(trigger models.WebhookTrigger) (models.WebhookTrigger, error)
+	MarkWebhookTriggered(id int, at time.Time) error
+	DeleteWebhookTrigger(id int) error
+	GetWebhookTriggerByID(id int) (models.WebhookTrigger, error)
+	GetAllWebhookTriggers() ([]models.WebhookTrigger, error)
+	CreateEventWebhook(webhook models.EventWebhook) (models.EventWebhook, error)
+	UpdateEventWebhook(webhook models.EventWebhook) (models.EventWebhook, error)
+	DeleteEventWebhook(id int) error
+	GetAllEventWebhooks() ([]models.EventWebhook, error)
+	GetActiveEventWebhooks() ([]models.EventWebhook, error)
+
+	// Workspace report and cleanup
+	GetWorkspaceReport() (models.WorkspaceReport, error)
+	DeleteOrphanedRecords() (int64, error)
+	Vacuum() error
+}
+
+var _ Store = (*DBService)(nil)
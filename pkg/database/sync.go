@@ -0,0 +1,93 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"flowpulse/pkg/models"
+)
+
+// initSyncTables creates the table holding the sync configuration and the last synced snapshot
+func (s *DBService) initSyncTables() error {
+	_, err := s.db.Exec(`
+		CREATE TABLE IF NOT EXISTS sync_config (
+			id INTEGER PRIMARY KEY CHECK (id = 1),
+			provider TEXT NOT NULL DEFAULT '',
+			endpoint TEXT NOT NULL DEFAULT '',
+			region TEXT NOT NULL DEFAULT '',
+			bucket TEXT NOT NULL DEFAULT '',
+			path TEXT NOT NULL DEFAULT '',
+			username TEXT NOT NULL DEFAULT '',
+			password TEXT NOT NULL DEFAULT '',
+			access_key_id TEXT NOT NULL DEFAULT '',
+			secret_access_key TEXT NOT NULL DEFAULT '',
+			base_snapshot TEXT NOT NULL DEFAULT '',
+			last_synced_at TIMESTAMP
+		)
+	`)
+	return err
+}
+
+// GetSyncConfig gets the sync configuration, returning an empty config if none was saved
+func (s *DBService) GetSyncConfig() (models.SyncConfig, error) {
+	var config models.SyncConfig
+	var lastSyncedAt sql.NullTime
+	err := s.db.QueryRow(
+		"SELECT provider, endpoint, region, bucket, path, username, password, access_key_id, secret_access_key, last_synced_at FROM sync_config WHERE id = 1",
+	).Scan(
+		&config.Provider, &config.Endpoint, &config.Region, &config.Bucket, &config.Path,
+		&config.Username, &config.Password, &config.AccessKeyID, &config.SecretAccessKey, &lastSyncedAt,
+	)
+	if err == sql.ErrNoRows {
+		return config, nil
+	}
+	if err != nil {
+		return config, fmt.Errorf("failed to get sync config: %w", err)
+	}
+	if lastSyncedAt.Valid {
+		config.LastSyncedAt = lastSyncedAt.Time.Format(time.RFC3339)
+	}
+	return config, nil
+}
+
+// SaveSyncConfig saves the sync configuration
+func (s *DBService) SaveSyncConfig(config models.SyncConfig) error {
+	_, err := s.db.Exec(`
+		INSERT INTO sync_config (id, provider, endpoint, region, bucket, path, username, password, access_key_id, secret_access_key)
+		VALUES (1, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (id) DO UPDATE SET
+			provider = excluded.provider, endpoint = excluded.endpoint, region = excluded.region,
+			bucket = excluded.bucket, path = excluded.path, username = excluded.username,
+			password = excluded.password, access_key_id = excluded.access_key_id,
+			secret_access_key = excluded.secret_access_key`,
+		config.Provider, config.Endpoint, config.Region, config.Bucket, config.Path,
+		config.Username, config.Password, config.AccessKeyID, config.SecretAccessKey,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save sync config: %w", err)
+	}
+	return nil
+}
+
+// GetSyncBaseSnapshot gets the snapshot stored after the last successful sync
+func (s *DBService) GetSyncBaseSnapshot() (string, error) {
+	var snapshot string
+	err := s.db.QueryRow("SELECT base_snapshot FROM sync_config WHERE id = 1").Scan(&snapshot)
+	if err != nil && err != sql.ErrNoRows {
+		return "", fmt.Errorf("failed to get sync base snapshot: %w", err)
+	}
+	return snapshot, nil
+}
+
+// SaveSyncBaseSnapshot stores the snapshot of a successful sync and records the sync time
+func (s *DBService) SaveSyncBaseSnapshot(snapshot string, syncedAt time.Time) error {
+	_, err := s.db.Exec(
+		"UPDATE sync_config SET base_snapshot = ?, last_synced_at = ? WHERE id = 1",
+		snapshot, syncedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save sync base snapshot: %w", err)
+	}
+	return nil
+}
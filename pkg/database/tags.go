@@ -0,0 +1,232 @@
+package database
+
+import (
+	"fmt"
+	"time"
+
+	"flowpulse/pkg/models"
+)
+
+// initTagTables creates the tags table and the tables linking tags to APIs and schedules
+func (s *DBService) initTagTables() error {
+	_, err := s.db.Exec(`
+		CREATE TABLE IF NOT EXISTS tags (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT NOT NULL UNIQUE COLLATE NOCASE,
+			color TEXT NOT NULL DEFAULT '',
+			created_at TIMESTAMP NOT NULL,
+			updated_at TIMESTAMP NOT NULL
+		);
+		CREATE TABLE IF NOT EXISTS api_tags (
+			api_id INTEGER NOT NULL,
+			tag_id INTEGER NOT NULL,
+			PRIMARY KEY (api_id, tag_id)
+		);
+		CREATE INDEX IF NOT EXISTS idx_api_tags_tag ON api_tags(tag_id);
+		CREATE TABLE IF NOT EXISTS schedule_tags (
+			schedule_id INTEGER NOT NULL,
+			tag_id INTEGER NOT NULL,
+			PRIMARY KEY (schedule_id, tag_id)
+		);
+		CREATE INDEX IF NOT EXISTS idx_schedule_tags_tag ON schedule_tags(tag_id);
+	`)
+	return err
+}
+
+// tagColumns is the column list matching scanTag
+const tagColumns = "id, name, color, created_at, updated_at"
+
+// scanTag scans a single tag selected with tagColumns
+func scanTag(row rowScanner) (models.Tag, error) {
+	var tag models.Tag
+	err := row.Scan(&tag.ID, &tag.Name, &tag.Color, &tag.CreatedAt, &tag.UpdatedAt)
+	return tag, err
+}
+
+// queryTags runs a query selecting tagColumns and scans every row
+func (s *DBService) queryTags(query string, args ...interface{}) ([]models.Tag, error) {
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tags: %w", err)
+	}
+	defer rows.Close()
+
+	var tags []models.Tag
+	for rows.Next() {
+		tag, err := scanTag(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan tag row: %w", err)
+		}
+		tags = append(tags, tag)
+	}
+
+	return tags, nil
+}
+
+// Tag Operations
+
+// CreateTag creates a new tag
+func (s *DBService) CreateTag(tag models.Tag) (models.Tag, error) {
+	now := time.Now()
+	tag.CreatedAt = now
+	tag.UpdatedAt = now
+
+	result, err := s.db.Exec(
+		"INSERT INTO tags (name, color, created_at, updated_at) VALUES (?, ?, ?, ?)",
+		tag.Name, tag.Color, tag.CreatedAt, tag.UpdatedAt,
+	)
+	if err != nil {
+		return tag, fmt.Errorf("failed to create tag: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return tag, fmt.Errorf("failed to get last insert ID: %w", err)
+	}
+
+	tag.ID = int(id)
+	return tag, nil
+}
+
+// UpdateTag updates an existing tag
+func (s *DBService) UpdateTag(tag models.Tag) (models.Tag, error) {
+	tag.UpdatedAt = time.Now()
+
+	_, err := s.db.Exec("UPDATE tags SET name = ?, color = ?, updated_at = ? WHERE id = ?", tag.Name, tag.Color, tag.UpdatedAt, tag.ID)
+	if err != nil {
+		return tag, fmt.Errorf("failed to update tag: %w", err)
+	}
+	return s.GetTagByID(tag.ID)
+}
+
+// DeleteTag deletes a tag and removes it from every API and schedule
+func (s *DBService) DeleteTag(id int) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, query := range []string{
+		"DELETE FROM api_tags WHERE tag_id = ?",
+		"DELETE FROM schedule_tags WHERE tag_id = ?",
+		"DELETE FROM tags WHERE id = ?",
+	} {
+		if _, err := tx.Exec(query, id); err != nil {
+			return fmt.Errorf("failed to delete tag: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit tag deletion: %w", err)
+	}
+	return nil
+}
+
+// GetTagByID gets a tag by ID
+func (s *DBService) GetTagByID(id int) (models.Tag, error) {
+	tag, err := scanTag(s.db.QueryRow("SELECT "+tagColumns+" FROM tags WHERE id = ?", id))
+	if err != nil {
+		return tag, fmt.Errorf("failed to get tag by ID: %w", err)
+	}
+	return tag, nil
+}
+
+// GetAllTags gets all tags ordered by name
+func (s *DBService) GetAllTags() ([]models.Tag, error) {
+	return s.queryTags("SELECT " + tagColumns + " FROM tags ORDER BY name, id")
+}
+
+// GetTagsByAPIID gets the tags of an API ordered by name
+func (s *DBService) GetTagsByAPIID(apiID int) ([]models.Tag, error) {
+	return s.queryTags("SELECT "+tagColumns+" FROM tags WHERE id IN (SELECT tag_id FROM api_tags WHERE api_id = ?) ORDER BY name, id", apiID)
+}
+
+// GetTagsByScheduleID gets the tags of a schedule ordered by name
+func (s *DBService) GetTagsByScheduleID(scheduleID int) ([]models.Tag, error) {
+	return s.queryTags("SELECT "+tagColumns+" FROM tags WHERE id IN (SELECT tag_id FROM schedule_tags WHERE schedule_id = ?) ORDER BY name, id", scheduleID)
+}
+
+// SetAPITags replaces the tags of an API
+func (s *DBService) SetAPITags(apiID int, tagIDs []int) error {
+	return s.setTags("api_tags", "api_id", apiID, tagIDs)
+}
+
+// SetScheduleTags replaces the tags of a schedule
+func (s *DBService) SetScheduleTags(scheduleID int, tagIDs []int) error {
+	return s.setTags("schedule_tags", "schedule_id", scheduleID, tagIDs)
+}
+
+// setTags replaces the rows of a link table belonging to one API or schedule
+func (s *DBService) setTags(table, column string, id int, tagIDs []int) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("DELETE FROM "+table+" WHERE "+column+" = ?", id); err != nil {
+		return fmt.Errorf("failed to clear tags: %w", err)
+	}
+	for _, tagID := range tagIDs {
+		if _, err := tx.Exec("INSERT OR IGNORE INTO "+table+" ("+column+", tag_id) VALUES (?, ?)", id, tagID); err != nil {
+			return fmt.Errorf("failed to add tag: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit tags: %w", err)
+	}
+	return nil
+}
+
+// GetAPIsByTag gets the APIs carrying a tag ordered by name
+func (s *DBService) GetAPIsByTag(tagID int) ([]models.API, error) {
+	rows, err := s.db.Query("SELECT "+apiColumns+" FROM apis WHERE id IN (SELECT api_id FROM api_tags WHERE tag_id = ?) AND deleted_at IS NULL ORDER BY name", tagID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query APIs by tag: %w", err)
+	}
+	defer rows.Close()
+
+	return scanAPIs(rows)
+}
+
+// GetSchedulesByTag gets the schedules carrying a tag, newest first
+func (s *DBService) GetSchedulesByTag(tagID int) ([]models.Schedule, error) {
+	rows, err := s.db.Query("SELECT "+scheduleColumns+" FROM schedules WHERE id IN (SELECT schedule_id FROM schedule_tags WHERE tag_id = ?) AND "+liveSchedules+" ORDER BY created_at DESC", tagID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query schedules by tag: %w", err)
+	}
+	defer rows.Close()
+
+	var schedules []models.Schedule
+	for rows.Next() {
+		schedule, err := scanSchedule(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan schedule row: %w", err)
+		}
+		schedules = append(schedules, schedule)
+	}
+
+	return schedules, nil
+}
+
+// GetExecutionLogsByTag gets the newest execution logs of APIs carrying a tag and of schedules carrying it
+func (s *DBService) GetExecutionLogsByTag(tagID int, limit int) ([]models.ExecutionLog, error) {
+	query := `
+		SELECT ` + executionLogColumns + `
+		FROM execution_logs
+		WHERE api_id IN (SELECT api_id FROM api_tags WHERE tag_id = ?)
+			OR schedule_id IN (SELECT schedule_id FROM schedule_tags WHERE tag_id = ?)
+		ORDER BY executed_at DESC
+		LIMIT ?
+	`
+
+	rows, err := s.db.Query(query, tagID, tagID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query execution logs by tag: %w", err)
+	}
+	defer rows.Close()
+
+	return s.scanAnnotatedExecutionLogs(rows)
+}
@@ -0,0 +1,124 @@
+package database
+
+import (
+	"fmt"
+	"time"
+
+	"flowpulse/pkg/models"
+)
+
+// liveSchedules is the condition selecting the schedules whose API isn't in the trash
+const liveSchedules = "api_id NOT IN (SELECT id FROM apis WHERE deleted_at IS NOT NULL)"
+
+// trashedAPIChildren are the tables whose rows belong to an API and go along with it when the trash is emptied
+var trashedAPIChildren = []string{
+	"assertions", "extractions", "execution_logs", "expected_failure_windows", "incidents", "alert_snoozes",
+	"api_tags", "latency_rollups", "content_baselines", "content_changes",
+}
+
+// RestoreAPI takes an API out of the trash. It stays ungrouped while its collection is in the trash.
+func (s *DBService) RestoreAPI(id int) error {
+	result, err := s.db.Exec("UPDATE apis SET deleted_at = NULL WHERE id = ? AND deleted_at IS NOT NULL", id)
+	if err != nil {
+		return fmt.Errorf("failed to restore API: %w", err)
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return fmt.Errorf("API %d is not in the trash", id)
+	}
+	return nil
+}
+
+// RestoreCollection takes a collection out of the trash along with the grouping of its APIs and the nested
+// folders deleted with it. It shows at the top level while its parent is in the trash.
+func (s *DBService) RestoreCollection(id int) error {
+	// Nested folders deleted along with the collection were given the same deletion time
+	result, err := s.db.Exec(`
+		WITH RECURSIVE `+collectionSubtree("collections.deleted_at = (SELECT deleted_at FROM collections WHERE id = ?)")+`
+		UPDATE collections SET deleted_at = NULL WHERE id IN (SELECT id FROM subtree) AND deleted_at IS NOT NULL
+	`, id, id)
+	if err != nil {
+		return fmt.Errorf("failed to restore collection: %w", err)
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return fmt.Errorf("collection %d is not in the trash", id)
+	}
+	return nil
+}
+
+// GetTrash gets the APIs and collections in the trash, most recently deleted first
+func (s *DBService) GetTrash() (models.Trash, error) {
+	trash := models.Trash{APIs: []models.API{}, Collections: []models.Collection{}}
+
+	rows, err := s.db.Query("SELECT " + apiColumns + " FROM apis WHERE deleted_at IS NOT NULL ORDER BY deleted_at DESC")
+	if err != nil {
+		return trash, fmt.Errorf("failed to query deleted APIs: %w", err)
+	}
+	for rows.Next() {
+		api, err := scanAPI(rows)
+		if err != nil {
+			rows.Close()
+			return trash, fmt.Errorf("failed to scan API row: %w", err)
+		}
+		trash.APIs = append(trash.APIs, api)
+	}
+	rows.Close()
+
+	rows, err = s.db.Query("SELECT " + collectionColumns + " FROM collections WHERE deleted_at IS NOT NULL ORDER BY deleted_at DESC")
+	if err != nil {
+		return trash, fmt.Errorf("failed to query deleted collections: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		collection, err := scanCollection(rows)
+		if err != nil {
+			return trash, fmt.Errorf("failed to scan collection row: %w", err)
+		}
+		trash.Collections = append(trash.Collections, collection)
+	}
+	return trash, nil
+}
+
+// EmptyTrash permanently deletes the APIs in the trash along with their schedules, logs and other records,
+// and the collections in the trash, leaving the APIs and folders still in them at the top level
+func (s *DBService) EmptyTrash() error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	const trashedAPIs = "SELECT id FROM apis WHERE deleted_at IS NOT NULL"
+	const trashedSchedules = "SELECT id FROM schedules WHERE api_id IN (" + trashedAPIs + ")"
+	statements := []string{
+		"DELETE FROM schedule_tags WHERE schedule_id IN (" + trashedSchedules + ")",
+		"DELETE FROM alert_rules WHERE schedule_id IN (" + trashedSchedules + ")",
+		"DELETE FROM schedules WHERE api_id IN (" + trashedAPIs + ")",
+		"DELETE FROM response_bodies WHERE execution_log_id IN (SELECT id FROM execution_logs WHERE api_id IN (" + trashedAPIs + "))",
+		"DELETE FROM incident_events WHERE incident_id IN (SELECT id FROM incidents WHERE api_id IN (" + trashedAPIs + "))",
+	}
+	for _, table := range trashedAPIChildren {
+		statements = append(statements, fmt.Sprintf("DELETE FROM %s WHERE api_id IN (%s)", table, trashedAPIs))
+	}
+	statements = append(statements,
+		"DELETE FROM apis WHERE deleted_at IS NOT NULL",
+		"UPDATE apis SET collection_id = 0 WHERE collection_id IN (SELECT id FROM collections WHERE deleted_at IS NOT NULL)",
+		"UPDATE collections SET parent_id = 0 WHERE parent_id IN (SELECT id FROM collections WHERE deleted_at IS NOT NULL)",
+		"DELETE FROM collections WHERE deleted_at IS NOT NULL",
+	)
+	for _, statement := range statements {
+		if _, err := tx.Exec(statement); err != nil {
+			return fmt.Errorf("failed to empty trash: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit emptied trash: %w", err)
+	}
+	return nil
+}
+
+// trashRow moves a row of apis or collections to the trash
+func (s *DBService) trashRow(table string, id int) error {
+	_, err := s.db.Exec("UPDATE "+table+" SET deleted_at = ? WHERE id = ? AND deleted_at IS NULL", time.Now(), id)
+	return err
+}
@@ -0,0 +1,54 @@
+package database
+
+import (
+	"path/filepath"
+	"testing"
+
+	"flowpulse/pkg/models"
+)
+
+// TestAPIsOfTrashedCollectionAreUngrouped checks that the APIs of a collection in the trash are listed as
+// ungrouped, and no longer under the trashed collection
+func TestAPIsOfTrashedCollectionAreUngrouped(t *testing.T) {
+	db, err := NewDBServiceWithPath(filepath.Join(t.TempDir(), "flowpulse.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	collection, err := db.CreateCollection(models.Collection{Name: "Payments"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.CreateAPI(models.API{Name: "Charge", URL: "https://example.com/charge", Method: "POST", CollectionID: collection.ID}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.CreateAPI(models.API{Name: "Health", URL: "https://example.com/health", Method: "GET"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := db.DeleteCollection(collection.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	ungrouped, err := db.GetAPIsByCollectionID(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ungrouped) != 2 {
+		t.Fatalf("got %d ungrouped APIs, want 2", len(ungrouped))
+	}
+	for _, api := range ungrouped {
+		if api.CollectionID != 0 {
+			t.Errorf("API %s reads as in collection %d, want ungrouped", api.Name, api.CollectionID)
+		}
+	}
+
+	trashed, err := db.GetAPIsByCollectionID(collection.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(trashed) != 0 {
+		t.Errorf("got %d APIs in the trashed collection, want 0", len(trashed))
+	}
+}
@@ -0,0 +1,62 @@
+package database
+
+import (
+	"crypto/rand"
+	"fmt"
+)
+
+// uuidTables are the tables whose rows carry a UUID that identifies them across devices
+var uuidTables = []string{"collections", "apis", "schedules", "notification_channels", "alert_rules"}
+
+// initUUIDColumns adds the uuid column to the synced tables and assigns UUIDs to existing rows
+func (s *DBService) initUUIDColumns() error {
+	for _, table := range uuidTables {
+		if err := s.addColumnIfMissing(table, "uuid", "TEXT DEFAULT ''"); err != nil {
+			return err
+		}
+		if err := s.backfillUUIDs(table); err != nil {
+			return err
+		}
+		_, err := s.db.Exec(fmt.Sprintf("CREATE UNIQUE INDEX IF NOT EXISTS idx_%s_uuid ON %s (uuid)", table, table))
+		if err != nil {
+			return fmt.Errorf("failed to create %s UUID index: %w", table, err)
+		}
+	}
+	return nil
+}
+
+// backfillUUIDs assigns a UUID to every row of a table that doesn't have one yet
+func (s *DBService) backfillUUIDs(table string) error {
+	rows, err := s.db.Query(fmt.Sprintf("SELECT id FROM %s WHERE COALESCE(uuid, '') = ''", table))
+	if err != nil {
+		return fmt.Errorf("failed to query %s without UUID: %w", table, err)
+	}
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan %s ID: %w", table, err)
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+
+	for _, id := range ids {
+		if _, err := s.db.Exec(fmt.Sprintf("UPDATE %s SET uuid = ? WHERE id = ?", table), NewUUID(), id); err != nil {
+			return fmt.Errorf("failed to assign UUID to %s %d: %w", table, id, err)
+		}
+	}
+	return nil
+}
+
+// NewUUID generates a random (version 4) UUID
+func NewUUID() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		panic(fmt.Sprintf("failed to generate UUID: %v", err))
+	}
+	b[6] = (b[6] & 0x0f) | 0x40 // Version 4
+	b[8] = (b[8] & 0x3f) | 0x80 // RFC 4122 variant
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
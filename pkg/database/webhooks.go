@@ -0,0 +1,247 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"flowpulse/pkg/models"
+)
+
+// initWebhookTables creates the inbound webhook triggers and outbound event webhooks tables
+func (s *DBService) initWebhookTables() error {
+	_, err := s.db.Exec(`
+		CREATE TABLE IF NOT EXISTS webhook_triggers (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT NOT NULL,
+			api_id INTEGER NOT NULL DEFAULT 0,
+			collection_id INTEGER NOT NULL DEFAULT 0,
+			signature_scheme TEXT NOT NULL,
+			secret TEXT NOT NULL,
+			is_active BOOLEAN NOT NULL DEFAULT 1,
+			last_triggered_at TIMESTAMP,
+			created_at TIMESTAMP NOT NULL,
+			updated_at TIMESTAMP NOT NULL
+		);
+		CREATE TABLE IF NOT EXISTS event_webhooks (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT NOT NULL,
+			url TEXT NOT NULL,
+			secret TEXT NOT NULL DEFAULT '',
+			rules TEXT NOT NULL DEFAULT '',
+			is_active BOOLEAN NOT NULL DEFAULT 1,
+			created_at TIMESTAMP NOT NULL,
+			updated_at TIMESTAMP NOT NULL
+		)
+	`)
+	return err
+}
+
+// webhookTriggerColumns is the column list matching scanWebhookTrigger
+const webhookTriggerColumns = "id, name, api_id, collection_id, signature_scheme, secret, is_active, last_triggered_at, created_at, updated_at"
+
+// scanWebhookTrigger scans a single webhook trigger selected with webhookTriggerColumns
+func scanWebhookTrigger(row rowScanner) (models.WebhookTrigger, error) {
+	var trigger models.WebhookTrigger
+	var lastTriggeredAt sql.NullTime
+	err := row.Scan(&trigger.ID, &trigger.Name, &trigger.APIID, &trigger.CollectionID, &trigger.SignatureScheme, &trigger.Secret,
+		&trigger.IsActive, &lastTriggeredAt, &trigger.CreatedAt, &trigger.UpdatedAt)
+	if lastTriggeredAt.Valid {
+		trigger.LastTriggeredAt = lastTriggeredAt.Time
+	}
+	return trigger, err
+}
+
+// Webhook Trigger Operations
+
+// CreateWebhookTrigger creates a new webhook trigger
+func (s *DBService) CreateWebhookTrigger(trigger models.WebhookTrigger) (models.WebhookTrigger, error) {
+	now := time.Now()
+	trigger.CreatedAt = now
+	trigger.UpdatedAt = now
+
+	result, err := s.db.Exec(
+		"INSERT INTO webhook_triggers (name, api_id, collection_id, signature_scheme, secret, is_active, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?)",
+		trigger.Name, trigger.APIID, trigger.CollectionID, trigger.SignatureScheme, trigger.Secret, trigger.IsActive, trigger.CreatedAt, trigger.UpdatedAt,
+	)
+	if err != nil {
+		return trigger, fmt.Errorf("failed to create webhook trigger: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return trigger, fmt.Errorf("failed to get last insert ID: %w", err)
+	}
+
+	trigger.ID = int(id)
+	return trigger, nil
+}
+
+// UpdateWebhookTrigger updates an existing webhook trigger
+func (s *DBService) UpdateWebhookTrigger(trigger models.WebhookTrigger) (models.WebhookTrigger, error) {
+	trigger.UpdatedAt = time.Now()
+
+	_, err := s.db.Exec(
+		"UPDATE webhook_triggers SET name = ?, api_id = ?, collection_id = ?, signature_scheme = ?, secret = ?, is_active = ?, updated_at = ? WHERE id = ?",
+		trigger.Name, trigger.APIID, trigger.CollectionID, trigger.SignatureScheme, trigger.Secret, trigger.IsActive, trigger.UpdatedAt, trigger.ID,
+	)
+	if err != nil {
+		return trigger, fmt.Errorf("failed to update webhook trigger: %w", err)
+	}
+	return s.GetWebhookTriggerByID(trigger.ID)
+}
+
+// MarkWebhookTriggered records when a webhook trigger last fired
+func (s *DBService) MarkWebhookTriggered(id int, at time.Time) error {
+	if _, err := s.db.Exec("UPDATE webhook_triggers SET last_triggered_at = ? WHERE id = ?", at, id); err != nil {
+		return fmt.Errorf("failed to mark webhook trigger as triggered: %w", err)
+	}
+	return nil
+}
+
+// DeleteWebhookTrigger deletes a webhook trigger by ID
+func (s *DBService) DeleteWebhookTrigger(id int) error {
+	_, err := s.db.Exec("DELETE FROM webhook_triggers WHERE id = ?", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete webhook trigger: %w", err)
+	}
+	return nil
+}
+
+// GetWebhookTriggerByID gets a webhook trigger by ID
+func (s *DBService) GetWebhookTriggerByID(id int) (models.WebhookTrigger, error) {
+	trigger, err := scanWebhookTrigger(s.db.QueryRow("SELECT "+webhookTriggerColumns+" FROM webhook_triggers WHERE id = ?", id))
+	if err != nil {
+		return trigger, fmt.Errorf("failed to get webhook trigger by ID: %w", err)
+	}
+	return trigger, nil
+}
+
+// GetAllWebhookTriggers gets all webhook triggers ordered by name
+func (s *DBService) GetAllWebhookTriggers() ([]models.WebhookTrigger, error) {
+	rows, err := s.db.Query("SELECT " + webhookTriggerColumns + " FROM webhook_triggers ORDER BY name, id")
+	if err != nil {
+		return nil, fmt.Errorf("failed to query webhook triggers: %w", err)
+	}
+	defer rows.Close()
+
+	var triggers []models.WebhookTrigger
+	for rows.Next() {
+		trigger, err := scanWebhookTrigger(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan webhook trigger row: %w", err)
+		}
+		triggers = append(triggers, trigger)
+	}
+
+	return triggers, nil
+}
+
+// eventWebhookColumns is the column list matching scanEventWebhook
+const eventWebhookColumns = "id, name, url, secret, rules, is_active, created_at, updated_at"
+
+// scanEventWebhook scans a single event webhook selected with eventWebhookColumns
+func scanEventWebhook(row rowScanner) (models.EventWebhook, error) {
+	var webhook models.EventWebhook
+	var rules string
+	err := row.Scan(&webhook.ID, &webhook.Name, &webhook.URL, &webhook.Secret, &rules, &webhook.IsActive, &webhook.CreatedAt, &webhook.UpdatedAt)
+	if err != nil {
+		return webhook, err
+	}
+	return webhook, decodeJSONList(rules, &webhook.Rules, "event webhook rules")
+}
+
+// queryEventWebhooks runs a query selecting eventWebhookColumns and scans every row
+func (s *DBService) queryEventWebhooks(query string) ([]models.EventWebhook, error) {
+	rows, err := s.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query event webhooks: %w", err)
+	}
+	defer rows.Close()
+
+	var webhooks []models.EventWebhook
+	for rows.Next() {
+		webhook, err := scanEventWebhook(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan event webhook row: %w", err)
+		}
+		webhooks = append(webhooks, webhook)
+	}
+
+	return webhooks, nil
+}
+
+// Event Webhook Operations
+
+// CreateEventWebhook creates a new event webhook
+func (s *DBService) CreateEventWebhook(webhook models.EventWebhook) (models.EventWebhook, error) {
+	now := time.Now()
+	webhook.CreatedAt = now
+	webhook.UpdatedAt = now
+	rules, err := encodeJSONList(webhook.Rules, "event webhook rules")
+	if err != nil {
+		return webhook, err
+	}
+
+	result, err := s.db.Exec(
+		"INSERT INTO event_webhooks (name, url, secret, rules, is_active, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?)",
+		webhook.Name, webhook.URL, webhook.Secret, rules, webhook.IsActive, webhook.CreatedAt, webhook.UpdatedAt,
+	)
+	if err != nil {
+		return webhook, fmt.Errorf("failed to create event webhook: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return webhook, fmt.Errorf("failed to get last insert ID: %w", err)
+	}
+
+	webhook.ID = int(id)
+	return webhook, nil
+}
+
+// UpdateEventWebhook updates an existing event webhook
+func (s *DBService) UpdateEventWebhook(webhook models.EventWebhook) (models.EventWebhook, error) {
+	webhook.UpdatedAt = time.Now()
+	rules, err := encodeJSONList(webhook.Rules, "event webhook rules")
+	if err != nil {
+		return webhook, err
+	}
+
+	_, err = s.db.Exec(
+		"UPDATE event_webhooks SET name = ?, url = ?, secret = ?, rules = ?, is_active = ?, updated_at = ? WHERE id = ?",
+		webhook.Name, webhook.URL, webhook.Secret, rules, webhook.IsActive, webhook.UpdatedAt, webhook.ID,
+	)
+	if err != nil {
+		return webhook, fmt.Errorf("failed to update event webhook: %w", err)
+	}
+	return s.GetEventWebhookByID(webhook.ID)
+}
+
+// DeleteEventWebhook deletes an event webhook by ID
+func (s *DBService) DeleteEventWebhook(id int) error {
+	_, err := s.db.Exec("DELETE FROM event_webhooks WHERE id = ?", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete event webhook: %w", err)
+	}
+	return nil
+}
+
+// GetEventWebhookByID gets an event webhook by ID
+func (s *DBService) GetEventWebhookByID(id int) (models.EventWebhook, error) {
+	webhook, err := scanEventWebhook(s.db.QueryRow("SELECT "+eventWebhookColumns+" FROM event_webhooks WHERE id = ?", id))
+	if err != nil {
+		return webhook, fmt.Errorf("failed to get event webhook by ID: %w", err)
+	}
+	return webhook, nil
+}
+
+// GetAllEventWebhooks gets all event webhooks ordered by name
+func (s *DBService) GetAllEventWebhooks() ([]models.EventWebhook, error) {
+	return s.queryEventWebhooks("SELECT " + eventWebhookColumns + " FROM event_webhooks ORDER BY name, id")
+}
+
+// GetActiveEventWebhooks gets the event webhooks results are posted to
+func (s *DBService) GetActiveEventWebhooks() ([]models.EventWebhook, error) {
+	return s.queryEventWebhooks("SELECT " + eventWebhookColumns + " FROM event_webhooks WHERE is_active = 1 ORDER BY id")
+}
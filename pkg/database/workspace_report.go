@@ -0,0 +1,320 @@
+package database
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"flowpulse/pkg/models"
+)
+
+// reportedRecords is how many of the largest responses and noisiest APIs a workspace report lists
+const reportedRecords = 10
+
+// noisyPeriod is the period over which the noisiest APIs of a workspace report are measured
+const noisyPeriod = 7 * 24 * time.Hour
+
+// vacuumThreshold is the unused space in the database file from which vacuuming it is suggested
+const vacuumThreshold = 1 << 20
+
+// orphanedRecords are the kinds of records that outlive what they belong to, since SQLite doesn't enforce
+// the foreign keys, in the order deleting them leaves no new orphans behind
+var orphanedRecords = []struct {
+	table       string
+	description string
+	condition   string
+}{
+	{"schedules", "Schedules of deleted APIs", "api_id NOT IN (SELECT id FROM apis)"},
+	{"alert_rules", "Alert rules of deleted schedules", "schedule_id NOT IN (SELECT id FROM schedules)"},
+	{"schedule_tags", "Tags of deleted schedules or tags",
+		"schedule_id NOT IN (SELECT id FROM schedules) OR tag_id NOT IN (SELECT id FROM tags)"},
+	{"api_tags", "Tags of deleted APIs or tags", "api_id NOT IN (SELECT id FROM apis) OR tag_id NOT IN (SELECT id FROM tags)"},
+	{"assertions", "Assertions of deleted APIs", "api_id NOT IN (SELECT id FROM apis)"},
+	{"extractions", "Extractions of deleted APIs", "api_id NOT IN (SELECT id FROM apis)"},
+	{"content_baselines", "Content baselines of deleted APIs", "api_id NOT IN (SELECT id FROM apis)"},
+	{"content_changes", "Content changes of deleted APIs", "api_id NOT IN (SELECT id FROM apis)"},
+	{"execution_logs", "Execution logs of deleted APIs", "api_id NOT IN (SELECT id FROM apis)"},
+	{"response_bodies", "Full response bodies of deleted execution logs",
+		"execution_log_id NOT IN (SELECT id FROM execution_logs)"},
+	{"incidents", "Incidents of deleted APIs", "api_id NOT IN (SELECT id FROM apis)"},
+	{"incident_events", "Timeline events of deleted incidents", "incident_id NOT IN (SELECT id FROM incidents)"},
+	{"annotations", "Annotations of deleted execution logs or incidents",
+		"(execution_log_id != 0 AND execution_log_id NOT IN (SELECT id FROM execution_logs)) OR " +
+			"(incident_id != 0 AND incident_id NOT IN (SELECT id FROM incidents))"},
+}
+
+// GetWorkspaceReport summarizes what the workspace holds, where its storage goes and which cleanups are worth
+// running. The scheduler's active jobs are left for the caller to count.
+func (s *DBService) GetWorkspaceReport() (models.WorkspaceReport, error) {
+	report := models.WorkspaceReport{GeneratedAt: time.Now()}
+	var err error
+	if report.Counts, err = s.workspaceCounts(); err != nil {
+		return report, err
+	}
+
+	var pageCount, pageSize, freePages int64
+	err = s.db.QueryRow("SELECT page_count, page_size, freelist_count FROM pragma_page_count, pragma_page_size, pragma_freelist_count").
+		Scan(&pageCount, &pageSize, &freePages)
+	if err != nil {
+		return report, fmt.Errorf("failed to read database size: %w", err)
+	}
+	report.DatabaseBytes = pageCount * pageSize
+	report.FreeBytes = freePages * pageSize
+
+	if report.Tables, err = s.tableStorage(); err != nil {
+		return report, err
+	}
+	if report.LargestResponses, err = s.largestResponses(); err != nil {
+		return report, err
+	}
+	if report.NoisiestAPIs, err = s.noisiestAPIs(report.GeneratedAt.Add(-noisyPeriod)); err != nil {
+		return report, err
+	}
+
+	report.Orphans = []models.OrphanedRecords{}
+	orphans := 0
+	for _, kind := range orphanedRecords {
+		var count int
+		if err := s.db.QueryRow("SELECT COUNT(*) FROM " + kind.table + " WHERE " + kind.condition).Scan(&count); err != nil {
+			return report, fmt.Errorf("failed to count orphaned %s: %w", kind.table, err)
+		}
+		if count > 0 {
+			report.Orphans = append(report.Orphans, models.OrphanedRecords{Table: kind.table, Description: kind.description, Count: count})
+			orphans += count
+		}
+	}
+
+	report.Suggestions = []models.CleanupSuggestion{}
+	if trashed := report.Counts.TrashedAPIs + report.Counts.TrashedCollections; trashed > 0 {
+		report.Suggestions = append(report.Suggestions, models.CleanupSuggestion{
+			Action:      models.CleanupEmptyTrash,
+			Description: fmt.Sprintf("Permanently delete the %d APIs and collections in the trash", trashed),
+			Records:     trashed,
+		})
+	}
+	prunable, prunableBytes, err := s.prunableLogs()
+	if err != nil {
+		return report, err
+	}
+	if prunable > 0 {
+		report.Suggestions = append(report.Suggestions, models.CleanupSuggestion{
+			Action:      models.CleanupPruneLogs,
+			Description: fmt.Sprintf("Delete the %d execution logs outside the retention policy", prunable),
+			Records:     prunable,
+			Bytes:       prunableBytes,
+		})
+	}
+	if orphans > 0 {
+		report.Suggestions = append(report.Suggestions, models.CleanupSuggestion{
+			Action:      models.CleanupDeleteOrphans,
+			Description: fmt.Sprintf("Delete %d records left behind by deleted APIs, schedules and logs", orphans),
+			Records:     orphans,
+		})
+	}
+	if report.FreeBytes >= vacuumThreshold {
+		report.Suggestions = append(report.Suggestions, models.CleanupSuggestion{
+			Action:      models.CleanupVacuum,
+			Description: "Give the unused space of the database file back",
+			Bytes:       report.FreeBytes,
+		})
+	}
+	return report, nil
+}
+
+// workspaceCounts counts what the workspace holds
+func (s *DBService) workspaceCounts() (models.WorkspaceCounts, error) {
+	var counts models.WorkspaceCounts
+	err := s.db.QueryRow(`SELECT
+		(SELECT COUNT(*) FROM apis WHERE deleted_at IS NULL),
+		(SELECT COUNT(*) FROM collections WHERE deleted_at IS NULL),
+		(SELECT COUNT(*) FROM schedules WHERE api_id IN (SELECT id FROM apis WHERE deleted_at IS NULL)),
+		(SELECT COUNT(*) FROM schedules WHERE is_active = 1 AND api_id IN (SELECT id FROM apis WHERE deleted_at IS NULL)),
+		(SELECT COUNT(*) FROM execution_logs),
+		(SELECT COUNT(*) FROM apis WHERE deleted_at IS NOT NULL),
+		(SELECT COUNT(*) FROM collections WHERE deleted_at IS NOT NULL)`).Scan(
+		&counts.APIs, &counts.Collections, &counts.Schedules, &counts.ActiveSchedules,
+		&counts.ExecutionLogs, &counts.TrashedAPIs, &counts.TrashedCollections,
+	)
+	if err != nil {
+		return counts, fmt.Errorf("failed to count workspace records: %w", err)
+	}
+	return counts, nil
+}
+
+// tableStorage estimates the storage each table takes from the bytes of the values stored in it
+func (s *DBService) tableStorage() ([]models.TableStorage, error) {
+	rows, err := s.db.Query("SELECT name FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%' ORDER BY name")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tables: %w", err)
+	}
+	var tables []string
+	for rows.Next() {
+		var table string
+		if err := rows.Scan(&table); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan table name: %w", err)
+		}
+		tables = append(tables, table)
+	}
+	rows.Close()
+
+	storage := []models.TableStorage{}
+	for _, table := range tables {
+		columns, err := s.tableColumns(table)
+		if err != nil {
+			return nil, err
+		}
+		sizes := make([]string, len(columns))
+		for i, column := range columns {
+			sizes[i] = fmt.Sprintf("COALESCE(length(CAST(%q AS BLOB)), 0)", column)
+		}
+		usage := models.TableStorage{Table: table}
+		query := fmt.Sprintf("SELECT COUNT(*), COALESCE(SUM(%s), 0) FROM %q", strings.Join(sizes, " + "), table)
+		if err := s.db.QueryRow(query).Scan(&usage.Rows, &usage.Bytes); err != nil {
+			return nil, fmt.Errorf("failed to measure table %s: %w", table, err)
+		}
+		storage = append(storage, usage)
+	}
+	sort.Slice(storage, func(i, j int) bool {
+		return storage[i].Bytes > storage[j].Bytes
+	})
+	return storage, nil
+}
+
+// tableColumns lists the columns of a table
+func (s *DBService) tableColumns(table string) ([]string, error) {
+	rows, err := s.db.Query("SELECT name FROM pragma_table_info(?)", table)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list columns of table %s: %w", table, err)
+	}
+	defer rows.Close()
+	var columns []string
+	for rows.Next() {
+		var column string
+		if err := rows.Scan(&column); err != nil {
+			return nil, fmt.Errorf("failed to scan column of table %s: %w", table, err)
+		}
+		columns = append(columns, column)
+	}
+	return columns, rows.Err()
+}
+
+// largestResponses lists the execution logs whose responses take the most bytes, counting stored full bodies
+func (s *DBService) largestResponses() ([]models.StoredResponse, error) {
+	rows, err := s.db.Query(`SELECT execution_logs.id, execution_logs.api_id, COALESCE(apis.name, ''),
+			COALESCE(length(CAST(execution_logs.response AS BLOB)), 0) + COALESCE(length(response_bodies.body), 0) AS bytes,
+			execution_logs.executed_at
+		FROM execution_logs
+		LEFT JOIN apis ON apis.id = execution_logs.api_id
+		LEFT JOIN response_bodies ON response_bodies.execution_log_id = execution_logs.id
+		ORDER BY bytes DESC, execution_logs.id DESC LIMIT ?`, reportedRecords)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query largest responses: %w", err)
+	}
+	defer rows.Close()
+	responses := []models.StoredResponse{}
+	for rows.Next() {
+		var response models.StoredResponse
+		if err := rows.Scan(&response.ExecutionLogID, &response.APIID, &response.APIName, &response.Bytes, &response.ExecutedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan largest response: %w", err)
+		}
+		responses = append(responses, response)
+	}
+	return responses, rows.Err()
+}
+
+// noisiestAPIs lists the APIs that logged the most executions since a time
+func (s *DBService) noisiestAPIs(since time.Time) ([]models.NoisyAPI, error) {
+	rows, err := s.db.Query(`SELECT execution_logs.api_id, COALESCE(apis.name, ''), COUNT(*),
+			COALESCE(SUM(execution_logs.status = ?), 0), COALESCE(SUM(length(CAST(execution_logs.response AS BLOB))), 0)
+		FROM execution_logs
+		LEFT JOIN apis ON apis.id = execution_logs.api_id
+		WHERE execution_logs.executed_at >= ?
+		GROUP BY execution_logs.api_id
+		ORDER BY COUNT(*) DESC, execution_logs.api_id LIMIT ?`, models.ExecutionStatusFailure, since, reportedRecords)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query noisiest APIs: %w", err)
+	}
+	defer rows.Close()
+	apis := []models.NoisyAPI{}
+	for rows.Next() {
+		var api models.NoisyAPI
+		if err := rows.Scan(&api.APIID, &api.APIName, &api.Executions, &api.Failures, &api.Bytes); err != nil {
+			return nil, fmt.Errorf("failed to scan noisy API: %w", err)
+		}
+		apis = append(apis, api)
+	}
+	return apis, rows.Err()
+}
+
+// prunableLogs counts the execution logs PruneExecutionLogs would delete and the bytes of their responses
+func (s *DBService) prunableLogs() (int, int64, error) {
+	policy, err := s.GetRetentionPolicy()
+	if err != nil {
+		return 0, 0, err
+	}
+	var conditions []string
+	var args []interface{}
+	if policy.MaxAgeDays > 0 {
+		conditions = append(conditions, "executed_at < ?")
+		args = append(args, time.Now().AddDate(0, 0, -policy.MaxAgeDays))
+	}
+	if policy.MaxRowsPerAPI > 0 {
+		conditions = append(conditions, `id IN (
+				SELECT id FROM (
+					SELECT id, ROW_NUMBER() OVER (PARTITION BY api_id ORDER BY executed_at DESC, id DESC) AS row_number
+					FROM execution_logs
+				) WHERE row_number > ?
+			)`)
+		args = append(args, policy.MaxRowsPerAPI)
+	}
+	if len(conditions) == 0 {
+		return 0, 0, nil
+	}
+
+	var count int
+	var bytes int64
+	err = s.db.QueryRow("SELECT COUNT(*), COALESCE(SUM(length(CAST(response AS BLOB))), 0) FROM execution_logs WHERE "+
+		strings.Join(conditions, " OR "), args...).Scan(&count, &bytes)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to count prunable execution logs: %w", err)
+	}
+	return count, bytes, nil
+}
+
+// DeleteOrphanedRecords deletes the records left behind by the records they belonged to being deleted and
+// returns how many were deleted
+func (s *DBService) DeleteOrphanedRecords() (int64, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var deleted int64
+	for _, kind := range orphanedRecords {
+		result, err := tx.Exec("DELETE FROM " + kind.table + " WHERE " + kind.condition)
+		if err != nil {
+			return 0, fmt.Errorf("failed to delete orphaned %s: %w", kind.table, err)
+		}
+		n, err := result.RowsAffected()
+		if err != nil {
+			return 0, err
+		}
+		deleted += n
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit deleted orphans: %w", err)
+	}
+	return deleted, nil
+}
+
+// Vacuum rebuilds the database file, giving its unused space back
+func (s *DBService) Vacuum() error {
+	if _, err := s.db.Exec("VACUUM"); err != nil {
+		return fmt.Errorf("failed to vacuum database: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,179 @@
+package diff
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"flowpulse/pkg/models"
+)
+
+// Change types reported in a diff
+const (
+	Added   = "added"
+	Removed = "removed"
+	Changed = "changed"
+)
+
+// Bodies compares two response bodies. When both parse as JSON the comparison is structural,
+// so key order and formatting don't show up as changes; otherwise the bodies are compared line by line.
+func Bodies(left, right string) (changes []models.DiffChange, isJSON bool) {
+	var leftValue, rightValue interface{}
+	if json.Unmarshal([]byte(left), &leftValue) == nil && json.Unmarshal([]byte(right), &rightValue) == nil {
+		return JSON(leftValue, rightValue), true
+	}
+	return Lines(left, right), false
+}
+
+// Fingerprint hashes a response body to detect when it changes. In "json" mode a JSON body is hashed in a
+// canonical form, so key order and formatting don't change its fingerprint; other bodies are hashed as they are.
+func Fingerprint(body, mode string) string {
+	content := []byte(body)
+	if mode == models.ContentWatchJSON {
+		// Numbers are kept as written, so that changes to large ones aren't lost to rounding
+		decoder := json.NewDecoder(bytes.NewReader(content))
+		decoder.UseNumber()
+		var value interface{}
+		if decoder.Decode(&value) == nil && !decoder.More() {
+			// Objects are re-encoded with sorted keys and without whitespace
+			if canonical, err := json.Marshal(value); err == nil {
+				content = canonical
+			}
+		}
+	}
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+// JSON structurally compares two decoded JSON values and returns the changes keyed by JSONPath
+func JSON(left, right interface{}) []models.DiffChange {
+	changes := []models.DiffChange{}
+	compareValues("$", left, right, &changes)
+	return changes
+}
+
+func compareValues(path string, left, right interface{}, changes *[]models.DiffChange) {
+	switch l := left.(type) {
+	case map[string]interface{}:
+		if r, ok := right.(map[string]interface{}); ok {
+			compareObjects(path, l, r, changes)
+			return
+		}
+	case []interface{}:
+		if r, ok := right.([]interface{}); ok {
+			compareArrays(path, l, r, changes)
+			return
+		}
+	}
+
+	leftJSON, rightJSON := encode(left), encode(right)
+	if leftJSON != rightJSON {
+		*changes = append(*changes, models.DiffChange{Path: path, Type: Changed, OldValue: leftJSON, NewValue: rightJSON})
+	}
+}
+
+func compareObjects(path string, left, right map[string]interface{}, changes *[]models.DiffChange) {
+	keys := make(map[string]bool)
+	for key := range left {
+		keys[key] = true
+	}
+	for key := range right {
+		keys[key] = true
+	}
+	sorted := make([]string, 0, len(keys))
+	for key := range keys {
+		sorted = append(sorted, key)
+	}
+	sort.Strings(sorted)
+
+	for _, key := range sorted {
+		childPath := path + "." + key
+		l, inLeft := left[key]
+		r, inRight := right[key]
+		switch {
+		case !inRight:
+			*changes = append(*changes, models.DiffChange{Path: childPath, Type: Removed, OldValue: encode(l)})
+		case !inLeft:
+			*changes = append(*changes, models.DiffChange{Path: childPath, Type: Added, NewValue: encode(r)})
+		default:
+			compareValues(childPath, l, r, changes)
+		}
+	}
+}
+
+func compareArrays(path string, left, right []interface{}, changes *[]models.DiffChange) {
+	for i := 0; i < len(left) || i < len(right); i++ {
+		childPath := fmt.Sprintf("%s[%d]", path, i)
+		switch {
+		case i >= len(right):
+			*changes = append(*changes, models.DiffChange{Path: childPath, Type: Removed, OldValue: encode(left[i])})
+		case i >= len(left):
+			*changes = append(*changes, models.DiffChange{Path: childPath, Type: Added, NewValue: encode(right[i])})
+		default:
+			compareValues(childPath, left[i], right[i], changes)
+		}
+	}
+}
+
+func encode(value interface{}) string {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(encoded)
+}
+
+// maxDiffLines bounds the quadratic line diff for very large bodies
+const maxDiffLines = 2000
+
+// Lines compares two texts line by line using the longest common subsequence
+func Lines(left, right string) []models.DiffChange {
+	changes := []models.DiffChange{}
+	leftLines := strings.Split(left, "\n")
+	rightLines := strings.Split(right, "\n")
+
+	if len(leftLines) > maxDiffLines || len(rightLines) > maxDiffLines {
+		if left != right {
+			changes = append(changes, models.DiffChange{Path: "body", Type: Changed, OldValue: left, NewValue: right})
+		}
+		return changes
+	}
+
+	// lcs[i][j] is the length of the longest common subsequence of leftLines[i:] and rightLines[j:]
+	lcs := make([][]int, len(leftLines)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(rightLines)+1)
+	}
+	for i := len(leftLines) - 1; i >= 0; i-- {
+		for j := len(rightLines) - 1; j >= 0; j-- {
+			if leftLines[i] == rightLines[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(leftLines) || j < len(rightLines) {
+		switch {
+		case i < len(leftLines) && j < len(rightLines) && leftLines[i] == rightLines[j]:
+			i++
+			j++
+		case j < len(rightLines) && (i == len(leftLines) || lcs[i][j+1] >= lcs[i+1][j]):
+			changes = append(changes, models.DiffChange{Path: fmt.Sprintf("line %d", j+1), Type: Added, NewValue: rightLines[j]})
+			j++
+		default:
+			changes = append(changes, models.DiffChange{Path: fmt.Sprintf("line %d", i+1), Type: Removed, OldValue: leftLines[i]})
+			i++
+		}
+	}
+
+	return changes
+}
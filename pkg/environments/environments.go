@@ -0,0 +1,253 @@
+package environments
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"flowpulse/pkg/database"
+	"flowpulse/pkg/models"
+	"flowpulse/pkg/secrets"
+)
+
+// placeholder matches {{name}} references, allowing whitespace inside the braces
+var placeholder = regexp.MustCompile(`\{\{\s*([^{}]+?)\s*\}\}`)
+
+// Lookup resolves a variable name to its value
+type Lookup func(name string) (string, bool)
+
+// MapLookup creates a Lookup backed by a map of variables
+func MapLookup(variables map[string]string) Lookup {
+	return func(name string) (string, bool) {
+		value, ok := variables[name]
+		return value, ok
+	}
+}
+
+// Substitute replaces {{name}} references in text. Unknown variables are left untouched
+// so a missing value is visible in the request instead of silently becoming empty.
+func Substitute(text string, lookup Lookup) string {
+	return placeholder.ReplaceAllStringFunc(text, func(match string) string {
+		name := placeholder.FindStringSubmatch(match)[1]
+		if value, ok := lookup(name); ok {
+			return value
+		}
+		return match
+	})
+}
+
+// References returns the names of the variables an API refers to, in its URL, parameters, headers or body
+func References(api models.API) []string {
+	var names []string
+	seen := make(map[string]bool)
+	// Substituting with a lookup that knows nothing leaves the API as is and only collects the names
+	ApplyToAPI(api, func(name string) (string, bool) {
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+		return "", false
+	})
+	return names
+}
+
+// ParseVariables parses the JSON object of variables stored by an environment, collection or API, or as globals
+func ParseVariables(raw string) (map[string]string, error) {
+	variables := make(map[string]string)
+	if raw == "" {
+		return variables, nil
+	}
+	if err := json.Unmarshal([]byte(raw), &variables); err != nil {
+		return nil, fmt.Errorf("failed to parse variables: %w", err)
+	}
+	return variables, nil
+}
+
+// ApplyToAPI substitutes variables into the URL, parameters, headers and body of an API
+func ApplyToAPI(api models.API, lookup Lookup) (models.API, error) {
+	api.URL = Substitute(api.URL, lookup)
+	api.Body = Substitute(api.Body, lookup)
+	api.GraphQLQuery = Substitute(api.GraphQLQuery, lookup)
+	api.GraphQLVariables = Substitute(api.GraphQLVariables, lookup)
+	api.QueryParams = substituteParams(api.QueryParams, lookup)
+	api.PathParams = substituteParams(api.PathParams, lookup)
+	api.FormFields = substituteFormFields(api.FormFields, lookup)
+
+	// Headers are substituted after parsing so values containing quotes can't break the JSON
+	if api.Headers != "" {
+		var headers map[string]string
+		if err := json.Unmarshal([]byte(api.Headers), &headers); err != nil {
+			return api, fmt.Errorf("failed to parse headers: %w", err)
+		}
+		resolved := make(map[string]string, len(headers))
+		for name, value := range headers {
+			resolved[Substitute(name, lookup)] = Substitute(value, lookup)
+		}
+		encoded, err := json.Marshal(resolved)
+		if err != nil {
+			return api, fmt.Errorf("failed to encode headers: %w", err)
+		}
+		api.Headers = string(encoded)
+	}
+
+	return api, nil
+}
+
+// substituteParams returns a copy of params with variables substituted into their keys and values
+func substituteParams(params []models.Param, lookup Lookup) []models.Param {
+	if params == nil {
+		return nil
+	}
+	resolved := make([]models.Param, len(params))
+	for i, param := range params {
+		param.Key = Substitute(param.Key, lookup)
+		param.Value = Substitute(param.Value, lookup)
+		resolved[i] = param
+	}
+	return resolved
+}
+
+// substituteFormFields returns a copy of fields with variables substituted into their keys and values
+func substituteFormFields(fields []models.FormField, lookup Lookup) []models.FormField {
+	if fields == nil {
+		return nil
+	}
+	resolved := make([]models.FormField, len(fields))
+	for i, field := range fields {
+		field.Key = Substitute(field.Key, lookup)
+		field.Value = Substitute(field.Value, lookup)
+		resolved[i] = field
+	}
+	return resolved
+}
+
+// Service resolves the variables that apply to an API
+type Service struct {
+	db      database.Store
+	secrets *secrets.Service
+}
+
+// NewService creates a new environment service resolving {{secret:name}} references from the secrets store
+func NewService(db database.Store, secretStore *secrets.Service) *Service {
+	return &Service{db: db, secrets: secretStore}
+}
+
+// scopedVariables holds the variables one scope sets
+type scopedVariables struct {
+	scope     string
+	variables map[string]string
+}
+
+// scopesForAPI returns the variables of every scope applying to the API, from the most general:
+// globals, the environment active for its collection, its collection and the API itself
+func (s *Service) scopesForAPI(api models.API) ([]scopedVariables, error) {
+	var scopes []scopedVariables
+	addScope := func(scope, raw string) error {
+		variables, err := ParseVariables(raw)
+		if err != nil {
+			return fmt.Errorf("%s variables: %w", scope, err)
+		}
+		scopes = append(scopes, scopedVariables{scope, variables})
+		return nil
+	}
+
+	globals, err := s.db.GetGlobalVariables()
+	if err != nil {
+		return nil, err
+	}
+	if err := addScope(models.VariableScopeGlobal, globals); err != nil {
+		return nil, err
+	}
+
+	if api.CollectionID != 0 {
+		collection, err := s.db.GetCollectionByID(api.CollectionID)
+		if err != nil {
+			return nil, err
+		}
+		if collection.EnvironmentID != 0 {
+			environment, err := s.db.GetEnvironmentByID(collection.EnvironmentID)
+			if err != nil {
+				return nil, err
+			}
+			if err := addScope(models.VariableScopeEnvironment, environment.Variables); err != nil {
+				return nil, err
+			}
+		}
+		if err := addScope(models.VariableScopeCollection, collection.Variables); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := addScope(models.VariableScopeAPI, api.Variables); err != nil {
+		return nil, err
+	}
+	return scopes, nil
+}
+
+// VariablesForAPI returns the variables available to an API, each taken from the most specific
+// scope setting it: the API, its collection, the collection's active environment, then globals
+func (s *Service) VariablesForAPI(api models.API) (map[string]string, error) {
+	scopes, err := s.scopesForAPI(api)
+	if err != nil {
+		return nil, err
+	}
+	variables := make(map[string]string)
+	for _, scope := range scopes {
+		for name, value := range scope.variables {
+			variables[name] = value
+		}
+	}
+	return variables, nil
+}
+
+// ResolveVariables returns the variables available to an API ordered by name, each with the scope
+// supplying its value and the more general scopes it overrides
+func (s *Service) ResolveVariables(api models.API) ([]models.ResolvedVariable, error) {
+	scopes, err := s.scopesForAPI(api)
+	if err != nil {
+		return nil, err
+	}
+
+	resolved := make(map[string]*models.ResolvedVariable)
+	for _, scope := range scopes {
+		for name, value := range scope.variables {
+			variable, exists := resolved[name]
+			if !exists {
+				resolved[name] = &models.ResolvedVariable{Name: name, Value: value, Scope: scope.scope, Overridden: []string{}}
+				continue
+			}
+			variable.Overridden = append(variable.Overridden, variable.Scope)
+			variable.Value = value
+			variable.Scope = scope.scope
+		}
+	}
+
+	variables := make([]models.ResolvedVariable, 0, len(resolved))
+	for _, variable := range resolved {
+		variables = append(variables, *variable)
+	}
+	sort.Slice(variables, func(i, j int) bool { return variables[i].Name < variables[j].Name })
+	return variables, nil
+}
+
+// Resolve returns a copy of the API with its scoped variables and secrets substituted.
+// Chained variables, extracted from earlier responses, take precedence over every scope.
+func (s *Service) Resolve(api models.API, chained map[string]string) (models.API, error) {
+	variables, err := s.VariablesForAPI(api)
+	if err != nil {
+		return api, err
+	}
+	for name, value := range chained {
+		variables[name] = value
+	}
+
+	lookup := MapLookup(variables)
+	return ApplyToAPI(api, func(name string) (string, bool) {
+		if strings.HasPrefix(name, secrets.Prefix) {
+			return s.secrets.Lookup(name)
+		}
+		return lookup(name)
+	})
+}
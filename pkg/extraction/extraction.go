@@ -0,0 +1,70 @@
+package extraction
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+
+	"flowpulse/pkg/jsonpath"
+	"flowpulse/pkg/models"
+)
+
+// variableName matches names that can be referenced as {{name}}
+var variableName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.-]*$`)
+
+// Validate checks that an extraction definition can be applied
+func Validate(extraction models.Extraction) error {
+	if !variableName.MatchString(extraction.Variable) {
+		return fmt.Errorf("invalid variable name: %q", extraction.Variable)
+	}
+	switch extraction.Source {
+	case models.ExtractionJSONPath:
+		_, err := jsonpath.Compile(extraction.Expression)
+		return err
+	case models.ExtractionHeader:
+		if extraction.Expression == "" {
+			return fmt.Errorf("header extraction requires a header name")
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported extraction source: %s", extraction.Source)
+	}
+}
+
+// Apply extracts the variables defined by the extractions from a response.
+// Values that can't be found are left out and reported in the returned errors.
+func Apply(extractions []models.Extraction, headers http.Header, body string) (map[string]string, []error) {
+	variables := make(map[string]string)
+	var errs []error
+	for _, extraction := range extractions {
+		value, err := extract(extraction, headers, body)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", extraction.Variable, err))
+			continue
+		}
+		variables[extraction.Variable] = value
+	}
+	return variables, errs
+}
+
+func extract(extraction models.Extraction, headers http.Header, body string) (string, error) {
+	switch extraction.Source {
+	case models.ExtractionJSONPath:
+		matches, err := jsonpath.Query(body, extraction.Expression)
+		if err != nil {
+			return "", err
+		}
+		if len(matches) == 0 {
+			return "", fmt.Errorf("%s matched nothing", extraction.Expression)
+		}
+		return jsonpath.Stringify(matches[0]), nil
+	case models.ExtractionHeader:
+		value := headers.Get(extraction.Expression)
+		if value == "" {
+			return "", fmt.Errorf("header %s is missing", extraction.Expression)
+		}
+		return value, nil
+	default:
+		return "", fmt.Errorf("unsupported extraction source: %s", extraction.Source)
+	}
+}
@@ -0,0 +1,61 @@
+package graphql
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"flowpulse/pkg/models"
+)
+
+// request is the JSON body of a GraphQL request over HTTP
+type request struct {
+	Query         string          `json:"query"`
+	Variables     json.RawMessage `json:"variables,omitempty"`
+	OperationName string          `json:"operationName,omitempty"`
+}
+
+// Body composes the POST body of a GraphQL API from its document, variables and operation name
+func Body(api models.API) ([]byte, error) {
+	body := request{Query: api.GraphQLQuery, OperationName: strings.TrimSpace(api.GraphQLOperationName)}
+	if variables := strings.TrimSpace(api.GraphQLVariables); variables != "" {
+		if err := ValidateVariables(variables); err != nil {
+			return nil, err
+		}
+		body.Variables = json.RawMessage(variables)
+	}
+
+	encoded, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode GraphQL request: %w", err)
+	}
+	return encoded, nil
+}
+
+// ValidateVariables checks that the variables are a JSON object
+func ValidateVariables(variables string) error {
+	var object map[string]interface{}
+	if err := json.Unmarshal([]byte(variables), &object); err != nil {
+		return fmt.Errorf("GraphQL variables must be a JSON object: %w", err)
+	}
+	return nil
+}
+
+// Errors returns the messages of the errors in a GraphQL response. GraphQL servers usually answer
+// 200 even when the operation failed, so the errors are the only sign of it.
+func Errors(body string) ([]string, error) {
+	var response struct {
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := json.Unmarshal([]byte(body), &response); err != nil {
+		return nil, fmt.Errorf("response is not valid JSON: %w", err)
+	}
+
+	messages := make([]string, 0, len(response.Errors))
+	for _, e := range response.Errors {
+		messages = append(messages, e.Message)
+	}
+	return messages, nil
+}
@@ -0,0 +1,396 @@
+package importer
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"flowpulse/pkg/graphql"
+	"flowpulse/pkg/models"
+	"flowpulse/pkg/params"
+)
+
+// curlValueFlags are options whose argument FlowPulse has no use for but that must not be read as the URL
+var curlValueFlags = map[string]bool{
+	"-o": true, "--output": true, "-m": true, "--max-time": true, "--connect-timeout": true,
+	"-x": true, "--proxy": true, "-w": true, "--write-out": true, "--retry": true,
+	"-c": true, "--cookie-jar": true, "--cacert": true, "--cert": true, "--key": true,
+	"-E": true, "-r": true, "--range": true, "--resolve": true, "--max-redirs": true,
+}
+
+// ParseCurl converts a cURL command, e.g. from a browser's "Copy as cURL", into an API definition
+func ParseCurl(command string) (models.API, error) {
+	var api models.API
+
+	args, err := splitShellWords(command)
+	if err != nil {
+		return api, fmt.Errorf("failed to parse curl command: %w", err)
+	}
+	if len(args) == 0 || args[0] != "curl" {
+		return api, fmt.Errorf("command does not start with curl")
+	}
+
+	var rawURL, method string
+	var data []string
+	var form []models.FormField
+	var get bool
+	headers := make(map[string]string)
+	for i := 1; i < len(args); i++ {
+		arg := args[i]
+		name, value, hasValue := arg, "", false
+
+		// Accept --flag=value as well as short flags with an attached value, e.g. -XPOST
+		if strings.HasPrefix(arg, "--") {
+			if eq := strings.Index(arg, "="); eq >= 0 {
+				name, value, hasValue = arg[:eq], arg[eq+1:], true
+			}
+		} else if strings.HasPrefix(arg, "-") && len(arg) > 2 && strings.ContainsRune("XHdubAeF", rune(arg[1])) {
+			name, value, hasValue = arg[:2], arg[2:], true
+		}
+		next := func() (string, error) {
+			if hasValue {
+				return value, nil
+			}
+			if i+1 >= len(args) {
+				return "", fmt.Errorf("missing value for %s", name)
+			}
+			i++
+			return args[i], nil
+		}
+
+		switch name {
+		case "-X", "--request":
+			if method, err = next(); err != nil {
+				return api, err
+			}
+		case "-H", "--header":
+			header, err := next()
+			if err != nil {
+				return api, err
+			}
+			key, val, ok := strings.Cut(header, ":")
+			if !ok {
+				return api, fmt.Errorf("invalid header: %s", header)
+			}
+			headers[strings.TrimSpace(key)] = strings.TrimSpace(val)
+		case "-d", "--data", "--data-raw", "--data-binary", "--data-ascii":
+			body, err := next()
+			if err != nil {
+				return api, err
+			}
+			data = append(data, body)
+		case "--data-urlencode":
+			field, err := next()
+			if err != nil {
+				return api, err
+			}
+			// Only the part after the first "=" is encoded, e.g. name=some value
+			if key, val, ok := strings.Cut(field, "="); ok {
+				field = key + "=" + url.QueryEscape(val)
+			} else {
+				field = url.QueryEscape(field)
+			}
+			data = append(data, field)
+		case "--json":
+			body, err := next()
+			if err != nil {
+				return api, err
+			}
+			data = append(data, body)
+			headers["Content-Type"] = "application/json"
+			headers["Accept"] = "application/json"
+		case "-F", "--form", "--form-string":
+			field, err := next()
+			if err != nil {
+				return api, err
+			}
+			key, val, ok := strings.Cut(field, "=")
+			if !ok {
+				return api, fmt.Errorf("invalid form field: %s", field)
+			}
+			formField := models.FormField{Key: key, Value: val, Enabled: true}
+			if name != "--form-string" && strings.HasPrefix(val, "@") {
+				// Options such as ;type= or ;filename= after the path aren't kept
+				formField.Value, _, _ = strings.Cut(val[1:], ";")
+				formField.File = true
+			}
+			form = append(form, formField)
+		case "-u", "--user":
+			credentials, err := next()
+			if err != nil {
+				return api, err
+			}
+			headers["Authorization"] = "Basic " + base64.StdEncoding.EncodeToString([]byte(credentials))
+		case "-A", "--user-agent":
+			if headers["User-Agent"], err = next(); err != nil {
+				return api, err
+			}
+		case "-e", "--referer":
+			if headers["Referer"], err = next(); err != nil {
+				return api, err
+			}
+		case "-b", "--cookie":
+			if headers["Cookie"], err = next(); err != nil {
+				return api, err
+			}
+		case "--url":
+			if rawURL, err = next(); err != nil {
+				return api, err
+			}
+		case "-G", "--get":
+			get = true
+		case "-I", "--head":
+			method = "HEAD"
+		default:
+			if curlValueFlags[name] {
+				if _, err := next(); err != nil {
+					return api, err
+				}
+			} else if !strings.HasPrefix(arg, "-") && rawURL == "" {
+				rawURL = arg
+			}
+			// Other flags such as --compressed, -k or -L don't change the request itself
+		}
+	}
+	if rawURL == "" {
+		return api, fmt.Errorf("curl command does not contain a URL")
+	}
+
+	body := strings.Join(data, "&")
+	if get && body != "" {
+		// -G sends the data as query parameters instead of a body
+		separator := "?"
+		if strings.Contains(rawURL, "?") {
+			separator = "&"
+		}
+		rawURL += separator + body
+		body = ""
+	}
+	if method == "" {
+		method = "GET"
+		if body != "" || len(form) > 0 {
+			method = "POST"
+		}
+	}
+	if len(form) > 0 {
+		// curl sets the multipart Content-Type with its boundary itself
+		api.BodyType = models.BodyTypeMultipart
+		api.FormFields = form
+	}
+
+	api.Method = strings.ToUpper(method)
+	api.URL = rawURL
+	api.Name = defaultName(api.Method, rawURL)
+	api.Body = body
+	if len(headers) > 0 {
+		encoded, err := json.Marshal(headers)
+		if err != nil {
+			return api, fmt.Errorf("failed to encode headers: %w", err)
+		}
+		api.Headers = string(encoded)
+	}
+
+	return api, nil
+}
+
+// FormatCurl renders an API as a cURL command that can be pasted into a shell
+func FormatCurl(api models.API) (string, error) {
+	headers := make(map[string]string)
+	if api.Headers != "" {
+		if err := json.Unmarshal([]byte(api.Headers), &headers); err != nil {
+			return "", fmt.Errorf("failed to parse headers: %w", err)
+		}
+	}
+
+	requestURL, err := params.BuildURL(api)
+	if err != nil {
+		return "", err
+	}
+	method := strings.ToUpper(api.Method)
+	if method == "" {
+		method = "GET"
+	}
+
+	// One option per line, continued with backslashes like browsers do
+	lines := []string{"curl " + shellQuote(requestURL)}
+	if method != "GET" {
+		lines[0] = "curl -X " + method + " " + shellQuote(requestURL)
+	}
+
+	body := api.Body
+	if api.BodyType == models.BodyTypeGraphQL {
+		encoded, err := graphql.Body(api)
+		if err != nil {
+			return "", err
+		}
+		body = string(encoded)
+	}
+	jsonBody := api.BodyType == models.BodyTypeJSON || api.BodyType == models.BodyTypeGraphQL
+	if jsonBody && body != "" && !hasHeader(headers, "Content-Type") {
+		headers["Content-Type"] = "application/json"
+	}
+
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		lines = append(lines, "-H "+shellQuote(name+": "+headers[name]))
+	}
+	switch api.BodyType {
+	case models.BodyTypeForm, models.BodyTypeMultipart:
+		for _, field := range api.FormFields {
+			if !field.Enabled || field.Key == "" {
+				continue
+			}
+			switch {
+			case api.BodyType == models.BodyTypeForm:
+				lines = append(lines, "--data-urlencode "+shellQuote(field.Key+"="+field.Value))
+			case field.File:
+				lines = append(lines, "-F "+shellQuote(field.Key+"=@"+field.Value))
+			default:
+				lines = append(lines, "--form-string "+shellQuote(field.Key+"="+field.Value))
+			}
+		}
+	default:
+		if body != "" {
+			lines = append(lines, "--data-raw "+shellQuote(body))
+		}
+	}
+
+	return strings.Join(lines, " \\\n  "), nil
+}
+
+// hasHeader reports whether a header is set, ignoring the case of its name
+func hasHeader(headers map[string]string, name string) bool {
+	for key := range headers {
+		if strings.EqualFold(key, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// shellQuote wraps a value in single quotes unless it only contains characters that are safe unquoted
+func shellQuote(value string) string {
+	if value != "" && strings.Trim(value, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-_./:=") == "" {
+		return value
+	}
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
+
+// splitShellWords splits a command line into arguments the way a POSIX shell would, including
+// line continuations and the $'...' quoting that browsers use for values with control characters
+func splitShellWords(command string) ([]string, error) {
+	var args []string
+	var word strings.Builder
+	inWord := false
+
+	for i := 0; i < len(command); i++ {
+		c := command[i]
+		switch {
+		case c == '\\' && i+1 < len(command) && (command[i+1] == '\n' || command[i+1] == '\r'):
+			// Line continuation
+			i++
+			if command[i] == '\r' && i+1 < len(command) && command[i+1] == '\n' {
+				i++
+			}
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			if inWord {
+				args = append(args, word.String())
+				word.Reset()
+				inWord = false
+			}
+		case c == '\\':
+			inWord = true
+			if i+1 < len(command) {
+				i++
+				word.WriteByte(command[i])
+			}
+		case c == '\'':
+			inWord = true
+			end := strings.IndexByte(command[i+1:], '\'')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated single quote")
+			}
+			word.WriteString(command[i+1 : i+1+end])
+			i += end + 1
+		case c == '$' && i+1 < len(command) && command[i+1] == '\'':
+			inWord = true
+			value, length, err := readANSIQuoted(command[i+2:])
+			if err != nil {
+				return nil, err
+			}
+			word.WriteString(value)
+			i += length + 1
+		case c == '"':
+			inWord = true
+			closed := false
+			for i++; i < len(command); i++ {
+				if command[i] == '"' {
+					closed = true
+					break
+				}
+				if command[i] == '\\' && i+1 < len(command) && strings.IndexByte("\"\\$`\n", command[i+1]) >= 0 {
+					i++
+					if command[i] == '\n' {
+						continue
+					}
+				}
+				word.WriteByte(command[i])
+			}
+			if !closed {
+				return nil, fmt.Errorf("unterminated double quote")
+			}
+		default:
+			inWord = true
+			word.WriteByte(c)
+		}
+	}
+	if inWord {
+		args = append(args, word.String())
+	}
+	return args, nil
+}
+
+// readANSIQuoted reads the body of a $'...' string and returns its value and the length consumed,
+// including the closing quote
+func readANSIQuoted(input string) (string, int, error) {
+	var value strings.Builder
+	for i := 0; i < len(input); i++ {
+		c := input[i]
+		if c == '\'' {
+			return value.String(), i + 1, nil
+		}
+		if c != '\\' || i+1 >= len(input) {
+			value.WriteByte(c)
+			continue
+		}
+		i++
+		switch input[i] {
+		case 'n':
+			value.WriteByte('\n')
+		case 't':
+			value.WriteByte('\t')
+		case 'r':
+			value.WriteByte('\r')
+		case 'x':
+			var b byte
+			if i+2 < len(input) {
+				if _, err := fmt.Sscanf(input[i+1:i+3], "%02x", &b); err == nil {
+					value.WriteByte(b)
+					i += 2
+					continue
+				}
+			}
+			value.WriteString(`\x`)
+		default:
+			value.WriteByte(input[i])
+		}
+	}
+	return "", 0, fmt.Errorf("unterminated $' quote")
+}
@@ -0,0 +1,163 @@
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"flowpulse/pkg/models"
+)
+
+// fetchOptions is the subset of the fetch() init object that maps onto an API
+type fetchOptions struct {
+	Method   string            `json:"method"`
+	Headers  map[string]string `json:"headers"`
+	Body     *string           `json:"body"`
+	Referrer string            `json:"referrer"`
+}
+
+var (
+	// unquotedKey matches object keys written without quotes, e.g. {method: "GET"}
+	unquotedKey = regexp.MustCompile(`([{,]\s*)([A-Za-z_$][A-Za-z0-9_$-]*)\s*:`)
+	// trailingComma matches a comma directly before a closing brace
+	trailingComma = regexp.MustCompile(`,\s*}`)
+)
+
+// ParseFetch converts a browser DevTools "Copy as fetch" snippet into an API definition
+func ParseFetch(snippet string) (models.API, error) {
+	var api models.API
+
+	start := strings.Index(snippet, "fetch(")
+	if start < 0 {
+		return api, fmt.Errorf("snippet does not contain a fetch call")
+	}
+	rest := strings.TrimSpace(snippet[start+len("fetch("):])
+
+	url, rest, err := readStringLiteral(rest)
+	if err != nil {
+		return api, fmt.Errorf("failed to parse fetch URL: %w", err)
+	}
+
+	options := fetchOptions{Method: "GET"}
+	rest = strings.TrimSpace(rest)
+	if strings.HasPrefix(rest, ",") {
+		object, err := readObjectLiteral(strings.TrimSpace(rest[1:]))
+		if err != nil {
+			return api, fmt.Errorf("failed to parse fetch options: %w", err)
+		}
+		// Browsers emit JSON, so only loosen hand-edited snippets that fail to parse
+		if err := json.Unmarshal([]byte(object), &options); err != nil {
+			object = unquotedKey.ReplaceAllString(object, `$1"$2":`)
+			object = trailingComma.ReplaceAllString(object, "}")
+			if err := json.Unmarshal([]byte(object), &options); err != nil {
+				return api, fmt.Errorf("failed to parse fetch options: %w", err)
+			}
+		}
+	}
+
+	headers := make(map[string]string)
+	for name, value := range options.Headers {
+		headers[name] = value
+	}
+	if options.Referrer != "" {
+		headers["Referer"] = options.Referrer
+	}
+
+	api.Method = strings.ToUpper(options.Method)
+	api.URL = url
+	api.Name = defaultName(api.Method, url)
+	if options.Body != nil {
+		api.Body = *options.Body
+	}
+	if len(headers) > 0 {
+		encoded, err := json.Marshal(headers)
+		if err != nil {
+			return api, fmt.Errorf("failed to encode headers: %w", err)
+		}
+		api.Headers = string(encoded)
+	}
+
+	return api, nil
+}
+
+// readStringLiteral reads a leading JavaScript string literal and returns its value and the remaining input
+func readStringLiteral(input string) (string, string, error) {
+	if input == "" {
+		return "", "", fmt.Errorf("expected a string literal")
+	}
+
+	quote := input[0]
+	if quote != '"' && quote != '\'' && quote != '`' {
+		return "", "", fmt.Errorf("expected a string literal")
+	}
+
+	escaped := false
+	for i := 1; i < len(input); i++ {
+		switch {
+		case escaped:
+			escaped = false
+		case input[i] == '\\':
+			escaped = true
+		case input[i] == quote:
+			literal := input[1:i]
+			if quote != '"' {
+				// Re-quote so JSON unescaping handles the escape sequences
+				literal = strings.ReplaceAll(literal, `\`+string(quote), string(quote))
+				literal = strings.ReplaceAll(literal, `"`, `\"`)
+			}
+			var value string
+			if err := json.Unmarshal([]byte(`"`+literal+`"`), &value); err != nil {
+				return "", "", err
+			}
+			return value, input[i+1:], nil
+		}
+	}
+	return "", "", fmt.Errorf("unterminated string literal")
+}
+
+// readObjectLiteral returns the leading {...} object literal, skipping braces inside strings
+func readObjectLiteral(input string) (string, error) {
+	if !strings.HasPrefix(input, "{") {
+		return "", fmt.Errorf("expected an object literal")
+	}
+
+	depth := 0
+	var quote byte
+	escaped := false
+	for i := 0; i < len(input); i++ {
+		c := input[i]
+		switch {
+		case escaped:
+			escaped = false
+		case quote != 0:
+			if c == '\\' {
+				escaped = true
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '{':
+			depth++
+		case c == '}':
+			depth--
+			if depth == 0 {
+				return input[:i+1], nil
+			}
+		}
+	}
+	return "", fmt.Errorf("unterminated object literal")
+}
+
+// defaultName derives an API name from the method and URL path
+func defaultName(method, rawURL string) string {
+	path := rawURL
+	if i := strings.Index(path, "://"); i >= 0 {
+		path = path[i+3:]
+	}
+	if i := strings.IndexAny(path, "?#"); i >= 0 {
+		path = path[:i]
+	}
+	return method + " " + path
+}
@@ -0,0 +1,47 @@
+package importer
+
+import (
+	"regexp"
+	"strings"
+
+	"flowpulse/pkg/models"
+	"flowpulse/pkg/openapi"
+)
+
+// pathParameter matches an OpenAPI path template parameter such as {id}
+var pathParameter = regexp.MustCompile(`\{([^{}]+)\}`)
+
+// OpenAPIOperations converts every operation of an OpenAPI document into an API definition.
+// Path parameters become {{name}} variables so they can be filled in from an environment.
+func OpenAPIOperations(doc *openapi.Document) []models.API {
+	var apis []models.API
+	for _, op := range doc.Operations() {
+		apis = append(apis, APIFromOperation(doc, op))
+	}
+	return apis
+}
+
+// APIFromOperation converts a single OpenAPI operation into an API definition
+func APIFromOperation(doc *openapi.Document, op openapi.Operation) models.API {
+	baseURL := pathParameter.ReplaceAllString(doc.BaseURL(), "{{$1}}") // Server variables, e.g. {version}
+	if !strings.Contains(baseURL, "://") {
+		// Relative or missing servers need the host from an environment
+		baseURL = "{{baseUrl}}" + baseURL
+	}
+
+	name := strings.TrimSpace(op.Summary)
+	if name == "" {
+		name = op.OperationID
+	}
+	if name == "" {
+		name = op.Key()
+	}
+
+	return models.API{
+		Name:          name,
+		Method:        op.Method,
+		URL:           baseURL + pathParameter.ReplaceAllString(op.Path, "{{$1}}"),
+		Description:   op.Description,
+		SpecOperation: op.Key(),
+	}
+}
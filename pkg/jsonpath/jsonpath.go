@@ -0,0 +1,194 @@
+package jsonpath
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// segment is one step of a compiled path
+type segment struct {
+	name      string // Object key to select (empty for index or wildcard segments)
+	index     int    // Array index to select when isIndex is set; negative counts from the end
+	isIndex   bool
+	wildcard  bool // Selects every child of an object or array
+	recursive bool // Applies the selector at any depth (..)
+}
+
+// Path is a compiled JSONPath expression.
+// The supported subset covers $, .key, ['key'], [n], [*], .* and recursive descent (..key).
+type Path struct {
+	raw      string
+	segments []segment
+}
+
+// Compile parses a JSONPath expression
+func Compile(expression string) (*Path, error) {
+	expr := strings.TrimSpace(expression)
+	if !strings.HasPrefix(expr, "$") {
+		return nil, fmt.Errorf("JSONPath must start with $: %s", expression)
+	}
+
+	path := &Path{raw: expression}
+	rest := expr[1:]
+	for rest != "" {
+		recursive := false
+		switch {
+		case strings.HasPrefix(rest, ".."):
+			recursive = true
+			rest = rest[2:]
+		case strings.HasPrefix(rest, "."):
+			rest = rest[1:]
+		case strings.HasPrefix(rest, "["):
+		default:
+			return nil, fmt.Errorf("unexpected %q in JSONPath %s", rest, expression)
+		}
+
+		if strings.HasPrefix(rest, "[") {
+			end := strings.Index(rest, "]")
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated [ in JSONPath %s", expression)
+			}
+			seg, err := parseBracket(rest[1:end])
+			if err != nil {
+				return nil, fmt.Errorf("%v in JSONPath %s", err, expression)
+			}
+			seg.recursive = recursive
+			path.segments = append(path.segments, seg)
+			rest = rest[end+1:]
+			continue
+		}
+
+		end := strings.IndexAny(rest, ".[")
+		if end < 0 {
+			end = len(rest)
+		}
+		name := rest[:end]
+		if name == "" {
+			return nil, fmt.Errorf("empty key in JSONPath %s", expression)
+		}
+		seg := segment{name: name, recursive: recursive}
+		if name == "*" {
+			seg = segment{wildcard: true, recursive: recursive}
+		}
+		path.segments = append(path.segments, seg)
+		rest = rest[end:]
+	}
+
+	return path, nil
+}
+
+// parseBracket parses the contents of a [...] selector
+func parseBracket(content string) (segment, error) {
+	content = strings.TrimSpace(content)
+	switch {
+	case content == "*":
+		return segment{wildcard: true}, nil
+	case len(content) >= 2 && (content[0] == '\'' || content[0] == '"') && content[len(content)-1] == content[0]:
+		return segment{name: content[1 : len(content)-1]}, nil
+	default:
+		index, err := strconv.Atoi(content)
+		if err != nil {
+			return segment{}, fmt.Errorf("invalid selector [%s]", content)
+		}
+		return segment{index: index, isIndex: true}, nil
+	}
+}
+
+// String returns the original expression
+func (p *Path) String() string {
+	return p.raw
+}
+
+// Find returns every value in the decoded JSON document matched by the path
+func (p *Path) Find(document interface{}) []interface{} {
+	current := []interface{}{document}
+	for _, seg := range p.segments {
+		var next []interface{}
+		for _, value := range current {
+			if seg.recursive {
+				for _, descendant := range descendants(value) {
+					next = append(next, seg.apply(descendant)...)
+				}
+			} else {
+				next = append(next, seg.apply(value)...)
+			}
+		}
+		current = next
+	}
+	return current
+}
+
+// apply selects the children of value matched by the segment
+func (seg segment) apply(value interface{}) []interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if seg.wildcard {
+			results := make([]interface{}, 0, len(v))
+			for _, child := range v {
+				results = append(results, child)
+			}
+			return results
+		}
+		if child, ok := v[seg.name]; ok && !seg.isIndex {
+			return []interface{}{child}
+		}
+	case []interface{}:
+		if seg.wildcard {
+			return append([]interface{}{}, v...)
+		}
+		if seg.isIndex {
+			index := seg.index
+			if index < 0 {
+				index += len(v)
+			}
+			if index >= 0 && index < len(v) {
+				return []interface{}{v[index]}
+			}
+		}
+	}
+	return nil
+}
+
+// descendants returns the value and all values nested below it
+func descendants(value interface{}) []interface{} {
+	results := []interface{}{value}
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for _, child := range v {
+			results = append(results, descendants(child)...)
+		}
+	case []interface{}:
+		for _, child := range v {
+			results = append(results, descendants(child)...)
+		}
+	}
+	return results
+}
+
+// Query decodes a JSON body and evaluates the expression against it
+func Query(body string, expression string) ([]interface{}, error) {
+	path, err := Compile(expression)
+	if err != nil {
+		return nil, err
+	}
+
+	var document interface{}
+	if err := json.Unmarshal([]byte(body), &document); err != nil {
+		return nil, fmt.Errorf("response is not valid JSON: %w", err)
+	}
+	return path.Find(document), nil
+}
+
+// Stringify renders a matched value as text: strings as-is, everything else as JSON
+func Stringify(value interface{}) string {
+	if s, ok := value.(string); ok {
+		return s
+	}
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(encoded)
+}
@@ -6,59 +6,1493 @@ import (
 
 // API represents an API configuration that can be scheduled
 type API struct {
+	ID                   int         `json:"id"`
+	UUID                 string      `json:"uuid"` // Identifies the API across devices for sync and sharing
+	Name                 string      `json:"name"`
+	Method               string      `json:"method"`
+	URL                  string      `json:"url"`
+	Headers              string      `json:"headers"` // JSON string of headers
+	Body                 string      `json:"body"`
+	Description          string      `json:"description"`
+	CollectionID         int         `json:"collectionId"`         // ID of the collection this API belongs to (0 for no collection)
+	ExpectedOutcome      string      `json:"expectedOutcome"`      // "success" (default) or "failure" to assert the endpoint is unreachable or rejects the request
+	LogPolicy            string      `json:"logPolicy"`            // Which executions are stored: "all" (default), "failures" or "changes"
+	SpecID               int         `json:"specId"`               // ID of the OpenAPI spec this API was imported from (0 for none)
+	SpecOperation        string      `json:"specOperation"`        // Operation in the spec, e.g. "GET /pets/{id}"
+	ValidateContract     bool        `json:"validateContract"`     // Validate responses against the spec and fail on contract violations
+	SortOrder            int         `json:"sortOrder"`            // Position within the collection when it is run as a sequence
+	StepDelayMs          int         `json:"stepDelayMs"`          // Delay before this API's step in a collection run, replacing the collection's step delay (0 to use it)
+	ContentWatch         string      `json:"contentWatch"`         // "" (off), "hash" or "json": flag and alert when the response body changes from its baseline
+	AuthConfigID         int         `json:"authConfigId"`         // Auth used for requests, overriding the collection's (0 to inherit)
+	SuccessCodes         string      `json:"successCodes"`         // Status codes counted as healthy, e.g. "200-299,404" (empty for 2xx)
+	DegradedCodes        string      `json:"degradedCodes"`        // Status codes counted as degraded rather than failed, e.g. "429"
+	LatencyWarnMs        int         `json:"latencyWarnMs"`        // Response time above which a healthy check is counted as degraded (0 for none)
+	LatencyCriticalMs    int         `json:"latencyCriticalMs"`    // Response time above which a check is degraded and alerts the schedule's alert rules (0 for none)
+	QueryParams          []Param     `json:"queryParams"`          // Query parameters appended to the URL
+	PathParams           []Param     `json:"pathParams"`           // Values of the :name or {name} segments in the URL path
+	BodyType             string      `json:"bodyType"`             // "raw" (default), "json", "form", "multipart" or "graphql"
+	GraphQLQuery         string      `json:"graphqlQuery"`         // Query or mutation document of GraphQL bodies
+	GraphQLVariables     string      `json:"graphqlVariables"`     // JSON object of variables of GraphQL bodies
+	GraphQLOperationName string      `json:"graphqlOperationName"` // Operation to run when the document has several
+	CheckType            string      `json:"checkType"`            // "http" (default), "websocket", "tcp", "icmp" or "dns"
+	DNSRecordType        string      `json:"dnsRecordType"`        // Record type DNS checks look up: "A" (default), "AAAA", "CNAME", "MX", "NS" or "TXT"
+	DNSExpected          string      `json:"dnsExpected"`          // Value one of the records must have (empty for any answer)
+	DNSServer            string      `json:"dnsServer"`            // DNS server the check resolves host names with, e.g. "1.1.1.1" or "10.0.0.2:53" (empty for the system's)
+	FormFields           []FormField `json:"formFields"`           // Fields of form and multipart bodies, which replace Body
+	Variables            string      `json:"variables"`            // JSON object of variables overriding those of the collection, environment and globals
+	ProxyMode            string      `json:"proxyMode"`            // "global" (default) uses the global proxy, "custom" uses Proxy and "none" connects directly
+	Proxy                ProxyConfig `json:"proxy"`                // Proxy of HTTP requests when ProxyMode is "custom"
+	TLS                  TLSConfig   `json:"tls"`                  // How HTTPS and WSS connections verify the server and identify themselves
+	RedirectPolicy       string      `json:"redirectPolicy"`       // "follow" (default) follows redirects up to MaxRedirects, "none" checks the 3xx response itself
+	MaxRedirects         int         `json:"maxRedirects"`         // Most redirects followed before the check fails (0 for 10)
+	OverlapPolicy        string      `json:"overlapPolicy"`        // "queue" (default) delays a scheduled run until the API's check in flight finishes, "skip" skips it
+	DeprecatedAfter      *time.Time  `json:"deprecatedAfter"`      // Date the endpoint is sunset after (nil when it isn't deprecated)
+	AutoDisable          bool        `json:"autoDisable"`          // Deactivate the API's schedules once DeprecatedAfter has passed
+	RunbookURL           string      `json:"runbookUrl"`           // Link to the remediation steps, included in alerts
+	Notes                string      `json:"notes"`                // Markdown notes for whoever is alerted, such as who owns the endpoint
+	LogRequestHeaders    bool        `json:"logRequestHeaders"`    // Keep the headers requests were sent with, secrets masked, in the execution logs
+	Deprecated           bool        `json:"deprecated"`           // DeprecatedAfter has passed; set when the API is read
+	DeletedAt            *time.Time  `json:"deletedAt,omitempty"`  // When the API was moved to the trash (nil when it isn't there)
+	CreatedAt            time.Time   `json:"createdAt"`
+	UpdatedAt            time.Time   `json:"updatedAt"`
+}
+
+// Trash lists the deleted APIs and collections that can still be restored
+type Trash struct {
+	APIs        []API        `json:"apis"`
+	Collections []Collection `json:"collections"`
+}
+
+// DeprecatedAPI is an API with a deprecation date, as listed in the deprecation report
+type DeprecatedAPI struct {
+	APIID           int       `json:"apiId"`
+	APIName         string    `json:"apiName"`
+	URL             string    `json:"url"`
+	DeprecatedAfter time.Time `json:"deprecatedAfter"`
+	Deprecated      bool      `json:"deprecated"`      // The date has passed
+	AutoDisable     bool      `json:"autoDisable"`     // Its schedules are deactivated once the date has passed
+	ActiveSchedules int       `json:"activeSchedules"` // Schedules still checking the API
+}
+
+// ProxyConfig routes HTTP requests through an HTTP, HTTPS or SOCKS5 proxy
+type ProxyConfig struct {
+	URL      string `json:"url"` // e.g. "http://proxy:3128" or "socks5://proxy:1080"; empty for no proxy
+	Username string `json:"username"`
+	Password string `json:"password"`
+	NoProxy  string `json:"noProxy"` // Comma-separated hosts reached directly: names, ".domain" suffixes, IPs, CIDRs or "*"
+}
+
+// TLSConfig customizes the TLS of an API's connections, e.g. for self-signed or mTLS endpoints
+type TLSConfig struct {
+	InsecureSkipVerify bool   `json:"insecureSkipVerify"` // Accept any server certificate; meant for test endpoints only
+	CACertPath         string `json:"caCertPath"`         // PEM bundle of CAs trusted on top of the system's
+	ClientCertPath     string `json:"clientCertPath"`     // PEM client certificate presented for mTLS, along with ClientKeyPath
+	ClientKeyPath      string `json:"clientKeyPath"`
+}
+
+// Proxy modes of an API
+const (
+	ProxyModeGlobal = "global"
+	ProxyModeCustom = "custom"
+	ProxyModeNone   = "none"
+)
+
+// Redirect policies of an API
+const (
+	RedirectPolicyFollow = "follow"
+	RedirectPolicyNone   = "none"
+)
+
+// Overlap policies of an API
+const (
+	OverlapPolicyQueue = "queue"
+	OverlapPolicySkip  = "skip"
+)
+
+// RedirectHop is a response that redirected a request
+type RedirectHop struct {
+	URL        string `json:"url"` // URL that was requested
+	StatusCode int    `json:"statusCode"`
+}
+
+// Check types of an API
+const (
+	CheckTypeHTTP      = "http"      // HTTP request
+	CheckTypeWebSocket = "websocket" // WebSocket connection to the URL; Body is sent as the first message and the first message received is the response
+	CheckTypeTCP       = "tcp"       // TCP connection to the host:port in the URL
+	CheckTypeICMP      = "icmp"      // ICMP echo (ping) of the host in the URL
+	CheckTypeDNS       = "dns"       // DNS lookup of the host name in the URL, see DNSRecordType and DNSExpected
+)
+
+// Body types of an API
+const (
+	BodyTypeRaw       = "raw"       // Body is sent as is
+	BodyTypeJSON      = "json"      // Body is sent as application/json
+	BodyTypeForm      = "form"      // Form fields are sent as application/x-www-form-urlencoded
+	BodyTypeMultipart = "multipart" // Form fields and files are sent as multipart/form-data
+	BodyTypeGraphQL   = "graphql"   // GraphQL document, variables and operation name are sent as a JSON POST body
+)
+
+// FormField is a field of a form or multipart body
+type FormField struct {
+	Key     string `json:"key"`
+	Value   string `json:"value"`   // Text of the field, or the path of the file to upload
+	File    bool   `json:"file"`    // Whether Value is a file path whose contents are uploaded (multipart only)
+	Enabled bool   `json:"enabled"` // Disabled fields are kept but not sent
+}
+
+// Param is a named request value that can be switched off without removing it
+type Param struct {
+	Key     string `json:"key"`
+	Value   string `json:"value"`
+	Enabled bool   `json:"enabled"`
+}
+
+// APISpec is an imported OpenAPI document that APIs can be validated against
+type APISpec struct {
+	ID                   int       `json:"id"`
+	Name                 string    `json:"name"`
+	SourceURL            string    `json:"sourceUrl"`            // Where the document was fetched from (empty when pasted)
+	Document             string    `json:"document"`             // The OpenAPI document as JSON
+	CollectionID         int       `json:"collectionId"`         // Collection that APIs for new operations are added to
+	WatchIntervalMinutes int       `json:"watchIntervalMinutes"` // How often to re-fetch SourceURL for changes (0 disables watching)
+	AutoCreate           bool      `json:"autoCreate"`           // Create APIs for new operations instead of only proposing them
+	LastCheckedAt        time.Time `json:"lastCheckedAt"`
+	CreatedAt            time.Time `json:"createdAt"`
+	UpdatedAt            time.Time `json:"updatedAt"`
+}
+
+// SpecProposal is a change detected between a watched spec and the APIs imported from it
+type SpecProposal struct {
+	ID         int       `json:"id"`
+	SpecID     int       `json:"specId"`
+	Kind       string    `json:"kind"`      // "added" (new operation without an API) or "removed" (API whose operation is gone)
+	Operation  string    `json:"operation"` // e.g. "GET /pets/{id}"
+	APIID      int       `json:"apiId"`     // API of a removed operation (0 for added)
+	Status     string    `json:"status"`    // "pending", "accepted" or "dismissed"
+	DetectedAt time.Time `json:"detectedAt"`
+}
+
+// Spec proposal kinds and statuses
+const (
+	SpecProposalAdded     = "added"
+	SpecProposalRemoved   = "removed"
+	SpecProposalPending   = "pending"
+	SpecProposalAccepted  = "accepted"
+	SpecProposalDismissed = "dismissed"
+)
+
+// Collection represents a group of APIs
+type Collection struct {
+	ID              int             `json:"id"`
+	UUID            string          `json:"uuid"` // Identifies the collection across devices for sync and sharing
+	Name            string          `json:"name"`
+	Description     string          `json:"description"`
+	ParentID        int             `json:"parentId"`            // Folder the collection is nested in (0 for top level); changed with MoveCollection
+	EnvironmentID   int             `json:"environmentId"`       // ID of the active environment for this collection (0 for none)
+	LatencyBudgetMs int64           `json:"latencyBudgetMs"`     // Total latency budget for running every API in the collection (0 for none)
+	StopOnFailure   bool            `json:"stopOnFailure"`       // Stop a collection run at the first failing API instead of continuing
+	MaxParallel     int             `json:"maxParallel"`         // Requests a collection run may have in flight at once (0 or 1 runs them one by one)
+	StepDelayMs     int             `json:"stepDelayMs"`         // Delay before starting each step after the first, to throttle runs
+	AuthConfigID    int             `json:"authConfigId"`        // Auth used by the collection's APIs that don't set their own (0 for none)
+	Variables       string          `json:"variables"`           // JSON object of variables overriding those of the environment and globals
+	LatencyTargets  []LatencyTarget `json:"latencyTargets"`      // Latency SLAs of the collection's APIs, evaluated continuously
+	DeletedAt       *time.Time      `json:"deletedAt,omitempty"` // When the collection was moved to the trash (nil when it isn't there)
+	CreatedAt       time.Time       `json:"createdAt"`
+	UpdatedAt       time.Time       `json:"updatedAt"`
+}
+
+// CollectionNode is a collection in the folder tree, with the folders nested in it and its APIs
+type CollectionNode struct {
+	Collection Collection       `json:"collection"`
+	Children   []CollectionNode `json:"children"` // By name
+	APIs       []API            `json:"apis"`     // In their stored order
+}
+
+// LatencyTarget is a latency SLA at a percentile, e.g. p95 under 800ms over the last 24 hours
+type LatencyTarget struct {
+	Percentile  float64 `json:"percentile"`  // e.g. 95 for p95
+	ThresholdMs int64   `json:"thresholdMs"` // Latency the percentile must stay under
+	WindowHours int     `json:"windowHours"` // Rolling window of executions it is evaluated over (0 for 24 hours)
+}
+
+// LatencyTargetResult is a latency target evaluated over its window
+type LatencyTargetResult struct {
+	Target     LatencyTarget `json:"target"`
+	ActualMs   int64         `json:"actualMs"`   // Latency at the target's percentile
+	Executions int           `json:"executions"` // Executions in the window; a target without any is met
+	Met        bool          `json:"met"`
+}
+
+// LatencyCompliance reports whether a collection's APIs meet its latency targets
+type LatencyCompliance struct {
+	CollectionID int                   `json:"collectionId"`
+	Compliant    bool                  `json:"compliant"` // Whether every target is met
+	Targets      []LatencyTargetResult `json:"targets"`
+	EvaluatedAt  time.Time             `json:"evaluatedAt"`
+}
+
+// CollectionRun represents one sequential run of every API in a collection
+type CollectionRun struct {
+	ID           int            `json:"id"`
+	CollectionID int            `json:"collectionId"`
+	Status       string         `json:"status"` // "running", "success" or "failure"
+	Total        int            `json:"total"`
+	SuccessCount int            `json:"successCount"`
+	FailureCount int            `json:"failureCount"`
+	SkippedCount int            `json:"skippedCount"` // APIs not run because the run stopped on a failure
+	DurationMs   int64          `json:"durationMs"`
+	StartedAt    time.Time      `json:"startedAt"`
+	FinishedAt   time.Time      `json:"finishedAt"`
+	Executions   []ExecutionLog `json:"executions"` // Execution logs of the run in order
+	Budget       BudgetReport   `json:"budget"`     // Latency budget evaluated over the run's steps
+}
+
+// Collection run statuses
+const (
+	CollectionRunRunning = "running"
+	CollectionRunSuccess = "success"
+	CollectionRunFailure = "failure"
+)
+
+// BudgetStep represents the latency of one API within a collection latency budget
+type BudgetStep struct {
+	APIID        int     `json:"apiId"`
+	APIName      string  `json:"apiName"`
+	LogID        int     `json:"logId"`
+	DurationMs   int64   `json:"durationMs"`
+	SharePercent float64 `json:"sharePercent"` // Share of the budget consumed by this step
+	ElapsedMs    int64   `json:"elapsedMs"`    // Cumulative latency up to and including this step
+}
+
+// BudgetReport represents the evaluation of a collection's latency budget
+type BudgetReport struct {
+	CollectionID   int          `json:"collectionId"`
+	BudgetMs       int64        `json:"budgetMs"`
+	TotalMs        int64        `json:"totalMs"`
+	Exceeded       bool         `json:"exceeded"`
+	Steps          []BudgetStep `json:"steps"`
+	BreachingAPIID int          `json:"breachingApiId"` // Step during which the budget ran out (0 when within budget)
+	SlowestAPIID   int          `json:"slowestApiId"`   // Step that consumed the largest share of the budget
+}
+
+// WaterfallStep represents when one step of a collection run ran, relative to the start of the run
+type WaterfallStep struct {
+	Index         int    `json:"index"` // Position of the step in the run
+	APIID         int    `json:"apiId"`
+	APIName       string `json:"apiName"`
+	LogID         int    `json:"logId"`
+	Status        string `json:"status"`
+	StartOffsetMs int64  `json:"startOffsetMs"`
+	DurationMs    int64  `json:"durationMs"`
+	EndOffsetMs   int64  `json:"endOffsetMs"`
+	DependsOn     []int  `json:"dependsOn"` // Indexes of earlier steps that extract variables this step uses
+}
+
+// Waterfall represents the timing of the steps of a collection run, for rendering as a waterfall chart
+type Waterfall struct {
+	RunID     int             `json:"runId"`
+	StartedAt time.Time       `json:"startedAt"`
+	TotalMs   int64           `json:"totalMs"` // Wall-clock time from the start of the run to the end of its last step
+	Steps     []WaterfallStep `json:"steps"`
+}
+
+// AuthConfig holds credentials used to authenticate the requests of APIs or collections
+type AuthConfig struct {
 	ID           int       `json:"id"`
 	Name         string    `json:"name"`
-	Method       string    `json:"method"`
-	URL          string    `json:"url"`
-	Headers      string    `json:"headers"` // JSON string of headers
-	Body         string    `json:"body"`
-	Description  string    `json:"description"`
-	CollectionID int       `json:"collectionId"` // ID of the collection this API belongs to (0 for no collection)
+	Type         string    `json:"type"` // "oauth2_client_credentials"
+	TokenURL     string    `json:"tokenUrl"`
+	ClientID     string    `json:"clientId"`
+	ClientSecret string    `json:"clientSecret"`
+	Scopes       string    `json:"scopes"`   // Space-separated scopes to request
+	Audience     string    `json:"audience"` // Optional audience parameter required by some providers
 	CreatedAt    time.Time `json:"createdAt"`
 	UpdatedAt    time.Time `json:"updatedAt"`
 }
 
-// Collection represents a group of APIs
-type Collection struct {
-	ID          int       `json:"id"`
-	Name        string    `json:"name"`
-	Description string    `json:"description"`
-	CreatedAt   time.Time `json:"createdAt"`
-	UpdatedAt   time.Time `json:"updatedAt"`
+// AuthOAuth2ClientCredentials fetches a bearer token with the OAuth2 client credentials grant
+const AuthOAuth2ClientCredentials = "oauth2_client_credentials"
+
+// Secret is a sensitive value referenced as {{secret:name}} in API URLs, headers and bodies.
+// The value itself is never sent to the frontend.
+type Secret struct {
+	ID              int       `json:"id"`
+	Name            string    `json:"name"`
+	Backend         string    `json:"backend"`         // Where the value is kept: "keychain" or "local"
+	ValidationAPIID int       `json:"validationApiId"` // API run to check the secret after it is rotated; 0 for none
+	CreatedAt       time.Time `json:"createdAt"`
+	UpdatedAt       time.Time `json:"updatedAt"`
+}
+
+// SecretRotation is the outcome of replacing a secret's value and checking it with its validation API
+type SecretRotation struct {
+	Secret     Secret       `json:"secret"`
+	Validation ExecutionLog `json:"validation"` // The validation API's check made with the new value
+	Valid      bool         `json:"valid"`      // Whether the check passed, as success or degraded
+	RolledBack bool         `json:"rolledBack"` // Whether the previous value was restored because the check failed
+}
+
+// Secret storage backends
+const (
+	SecretBackendKeychain = "keychain" // The operating system's keychain
+	SecretBackendLocal    = "local"    // Encrypted in the database with a key stored in the app directory
+)
+
+// Environment represents a named set of variables (e.g. dev, stage, prod) substituted into requests
+type Environment struct {
+	ID        int       `json:"id"`
+	Name      string    `json:"name"`
+	Variables string    `json:"variables"` // JSON string of variable names to values
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// Variable scopes, from the most general to the most specific. A variable set in a more specific
+// scope overrides the same variable in a more general one.
+const (
+	VariableScopeGlobal      = "global"
+	VariableScopeEnvironment = "environment" // The active environment of the API's collection
+	VariableScopeCollection  = "collection"
+	VariableScopeAPI         = "api"
+)
+
+// ResolvedVariable is a variable available to an API together with the scope that supplied its value
+type ResolvedVariable struct {
+	Name       string   `json:"name"`
+	Value      string   `json:"value"`
+	Scope      string   `json:"scope"`      // Scope the value comes from
+	Overridden []string `json:"overridden"` // More general scopes that also set the variable, from the most general
 }
 
 // Schedule represents a schedule for executing an API
 type Schedule struct {
-	ID            int       `json:"id"`
-	APIID         int       `json:"apiId"`
-	Type          string    `json:"type"` // "cron" or "interval"
-	Expression    string    `json:"expression"` // Cron expression or interval in seconds
-	IsActive      bool      `json:"isActive"`
-	RetryCount    int       `json:"retryCount"`
-	FallbackDelay int       `json:"fallbackDelay"` // In seconds
-	CreatedAt     time.Time `json:"createdAt"`
-	UpdatedAt     time.Time `json:"updatedAt"`
+	ID            int         `json:"id"`
+	UUID          string      `json:"uuid"` // Identifies the schedule across devices for sync and sharing
+	APIID         int         `json:"apiId"`
+	Type          string      `json:"type"`         // "cron", "interval", "once" or "after"
+	Expression    string      `json:"expression"`   // Cron expression, interval in seconds or RFC3339 timestamp (unused by "after")
+	Timezone      string      `json:"timezone"`     // IANA zone cron expressions are evaluated in (empty for the local zone)
+	AfterID       int         `json:"afterId"`      // Schedule whose runs fire an "after" schedule (0 for other types)
+	AfterOutcome  string      `json:"afterOutcome"` // Outcome of that run that fires it: "success" (default), "failure" or "any"
+	IsActive      bool        `json:"isActive"`
+	RetryCount    int         `json:"retryCount"`
+	FallbackDelay int         `json:"fallbackDelay"` // In seconds, the delay before the first retry
+	RetryPolicy   RetryPolicy `json:"retryPolicy"`   // How the delay grows between later retries
+	CreatedAt     time.Time   `json:"createdAt"`
+	UpdatedAt     time.Time   `json:"updatedAt"`
 }
 
+// Outcomes of a schedule's run that fire the "after" schedules depending on it. Degraded runs count as successes.
+const (
+	AfterOutcomeSuccess = "success"
+	AfterOutcomeFailure = "failure"
+	AfterOutcomeAny     = "any"
+)
+
+// ScheduleGraph is the graph of the schedules that run after one another, to draw their dependencies
+type ScheduleGraph struct {
+	Nodes []ScheduleGraphNode `json:"nodes"` // Schedules running after another one or with others running after them
+	Edges []ScheduleGraphEdge `json:"edges"`
+}
+
+// ScheduleGraphNode is a schedule in the dependency graph
+type ScheduleGraphNode struct {
+	ScheduleID int    `json:"scheduleId"`
+	APIID      int    `json:"apiId"`
+	APIName    string `json:"apiName"`
+	Type       string `json:"type"`
+	Expression string `json:"expression"`
+	IsActive   bool   `json:"isActive"`
+}
+
+// ScheduleGraphEdge links a schedule to the "after" schedule its runs fire
+type ScheduleGraphEdge struct {
+	From    int    `json:"from"`
+	To      int    `json:"to"`
+	Outcome string `json:"outcome"` // Outcome of the run of From that fires To
+}
+
+// ScheduledRun is an upcoming run of a schedule
+type ScheduledRun struct {
+	ScheduleID int       `json:"scheduleId"`
+	APIID      int       `json:"apiId"`
+	APIName    string    `json:"apiName"`
+	Type       string    `json:"type"`
+	Expression string    `json:"expression"`
+	RunAt      time.Time `json:"runAt"`
+}
+
+// ScheduleCalendar lists the upcoming runs of every active schedule over a period, in order
+type ScheduleCalendar struct {
+	From      time.Time      `json:"from"`
+	To        time.Time      `json:"to"`
+	Runs      []ScheduledRun `json:"runs"`
+	Truncated []int          `json:"truncated"` // IDs of schedules with more runs in the period than listed
+}
+
+// RetryPolicy controls the delays between the retries of a failed check
+type RetryPolicy struct {
+	Strategy   string  `json:"strategy"`   // "fixed" (default) or "exponential"
+	Multiplier float64 `json:"multiplier"` // Factor each exponential delay grows by (2 when unset)
+	MaxDelay   int     `json:"maxDelay"`   // Upper bound of a delay in seconds (0 for none)
+	Jitter     float64 `json:"jitter"`     // Fraction from 0 to 1 by which each delay is randomly shortened or lengthened
+}
+
+// Retry strategies of a RetryPolicy
+const (
+	RetryStrategyFixed       = "fixed"       // Every retry waits the fallback delay
+	RetryStrategyExponential = "exponential" // Each retry waits Multiplier times longer than the previous one
+)
+
+// Expected outcomes of an API check
+const (
+	ExpectedOutcomeSuccess = "success" // The endpoint must answer with a success code (2xx unless overridden)
+	ExpectedOutcomeFailure = "failure" // The endpoint must be unreachable or answer with a non-success code (e.g. 401/403)
+)
+
+// Log policies controlling which executions of an API are stored
+const (
+	LogPolicyAll      = "all"      // Store every execution
+	LogPolicyFailures = "failures" // Store failures, plus the first success after a failure so outages have a visible end
+	LogPolicyChanges  = "changes"  // Store only executions whose status differs from the previous stored one
+)
+
+// Execution statuses recorded in execution logs
+const (
+	ExecutionStatusSuccess  = "success"
+	ExecutionStatusDegraded = "degraded" // Answered with one of the API's degraded codes; counts as up but is not retried
+	ExecutionStatusFailure  = "failure"
+	ExecutionStatusSkipped  = "skipped" // Not run because a maintenance window covered it; left out of uptime and latency
+)
+
+// LocationLocal is the location recorded for checks run by this machine
+const LocationLocal = "local"
+
 // ExecutionLog represents a log of an API execution
 type ExecutionLog struct {
-	ID          int       `json:"id"`
-	APIID       int       `json:"apiId"`
-	ScheduleID  int       `json:"scheduleId"`
-	StatusCode  int       `json:"statusCode"`
-	Response    string    `json:"response"`
-	Error       string    `json:"error"`
-	ExecutedAt  time.Time `json:"executedAt"`
+	ID                 int                 `json:"id"`
+	APIID              int                 `json:"apiId"`
+	ScheduleID         int                 `json:"scheduleId"`
+	StatusCode         int                 `json:"statusCode"`
+	Status             string              `json:"status"` // Evaluated outcome: "success", "degraded" or "failure"
+	Response           string              `json:"response"`
+	Error              string              `json:"error"`
+	DurationMs         int64               `json:"durationMs"`         // Round-trip time of the request in milliseconds
+	Location           string              `json:"location"`           // Where the check ran ("local" for this machine, otherwise the agent's location)
+	AssertionResults   []AssertionResult   `json:"assertionResults"`   // Per-assertion outcome of this execution
+	CollectionRunID    int                 `json:"collectionRunId"`    // ID of the collection run this execution was part of (0 for none)
+	FailurePhase       string              `json:"failurePhase"`       // Request phase a transport error happened in (empty when the response was read), or "internal" when the check panicked
+	TimedOut           bool                `json:"timedOut"`           // Whether the transport error was a deadline being hit
+	LatencyBreach      string              `json:"latencyBreach"`      // Latency threshold of the API the response time exceeded: "warn" or "critical" (empty for none)
+	ContentChanged     bool                `json:"contentChanged"`     // Whether the response body differed from the API's content baseline, see ContentWatch
+	FullResponseStored bool                `json:"fullResponseStored"` // Whether the response was cut short here but is stored in full, see GetExecutionResponseBody
+	FinalURL           string              `json:"finalUrl"`           // URL the response came from after following redirects (empty when none were followed)
+	RedirectChain      []RedirectHop       `json:"redirectChain"`      // Redirects followed, in order
+	Annotations        []Annotation        `json:"annotations"`        // Notes people attached to this execution
+	Triggers           []ExecutionTrigger  `json:"triggers"`           // Every trigger that shared this execution when several were coalesced into it (empty otherwise)
+	RequestHeaders     map[string][]string `json:"requestHeaders"`     // Headers the request was sent with after templating and auth, secrets masked (nil unless the API captures them)
+	ResponseHeaders    map[string][]string `json:"responseHeaders"`    // Headers of the response (nil when none was received)
+	ExecutedAt         time.Time           `json:"executedAt"`
+}
+
+// Latency thresholds of an API an execution can exceed
+const (
+	LatencyBreachWarn     = "warn"
+	LatencyBreachCritical = "critical"
+)
+
+// PerformanceBreakdown splits an API's executions over a period by availability, whether the API answered
+// as expected, and by performance, whether the answers came within its latency thresholds
+type PerformanceBreakdown struct {
+	APIID             int     `json:"apiId"`
+	LatencyWarnMs     int     `json:"latencyWarnMs"`
+	LatencyCriticalMs int     `json:"latencyCriticalMs"`
+	TotalExecutions   int     `json:"totalExecutions"`
+	Available         int     `json:"available"`        // Executions that succeeded or were degraded
+	Failed            int     `json:"failed"`           // Executions that failed
+	WithinThresholds  int     `json:"withinThresholds"` // Available executions within the latency thresholds
+	OverWarn          int     `json:"overWarn"`         // Available executions over the warning threshold but not the critical one
+	OverCritical      int     `json:"overCritical"`     // Available executions over the critical threshold
+	Availability      float64 `json:"availability"`     // Percentage of executions that were available
+	Performance       float64 `json:"performance"`      // Percentage of available executions within the latency thresholds
+	AverageTimeMs     float64 `json:"averageTimeMs"`    // Average response time of the available executions
+}
+
+// ExecutionTrigger is what started a check: a schedule or a manual run
+type ExecutionTrigger struct {
+	Source      string    `json:"source"`     // "schedule" or "manual"
+	ScheduleID  int       `json:"scheduleId"` // 0 for manual runs
+	TriggeredAt time.Time `json:"triggeredAt"`
+}
+
+// Trigger sources of an execution
+const (
+	TriggerSourceSchedule = "schedule"
+	TriggerSourceManual   = "manual"
+)
+
+// TriggerCoalescing merges triggers of the same API arriving close together, such as a manual run colliding with
+// a scheduled one, into a single execution. Triggers joining an execution get its result instead of a check of
+// their own, and the execution is logged once listing every trigger. Collection runs are never coalesced.
+type TriggerCoalescing struct {
+	WindowSeconds int `json:"windowSeconds"` // How long after an execution starts later triggers share it (0 to never coalesce)
+}
+
+// RunningExecution is a check in flight, which can be cancelled by its ID
+type RunningExecution struct {
+	ID              int       `json:"id"` // Identifies the execution while it runs; unrelated to the ID of its execution log
+	APIID           int       `json:"apiId"`
+	APIName         string    `json:"apiName"`
+	ScheduleID      int       `json:"scheduleId"`      // 0 for manual runs and collection runs
+	CollectionRunID int       `json:"collectionRunId"` // 0 when not part of a collection run
+	StartedAt       time.Time `json:"startedAt"`
+}
+
+// PendingRetry is a check in flight waiting out the delay before its next retry
+type PendingRetry struct {
+	ExecutionID   int       `json:"executionId"` // ID of the check in flight, as in RunningExecution
+	APIID         int       `json:"apiId"`
+	APIName       string    `json:"apiName"`
+	ScheduleID    int       `json:"scheduleId"`
+	Attempt       int       `json:"attempt"`     // Retry the check waits to make, from 1
+	MaxAttempts   int       `json:"maxAttempts"` // Retries the schedule allows
+	LastError     string    `json:"lastError"`   // Why the previous attempt failed
+	NextAttemptAt time.Time `json:"nextAttemptAt"`
+}
+
+// CompletedExecution is a check that finished, with the execution log recorded for it
+type CompletedExecution struct {
+	ExecutionID int          `json:"executionId"` // ID the check had while running; 0 for runs skipped during maintenance
+	Log         ExecutionLog `json:"log"`         // Its ID is 0 when the API's log policy skipped storing it
+}
+
+// ExecutionBatch is a set of manual runs started together, with the progress of each
+type ExecutionBatch struct {
+	ID         int        `json:"id"`
+	Status     string     `json:"status"` // "running" until every run has finished or was skipped, then "completed"
+	Total      int        `json:"total"`
+	Completed  int        `json:"completed"` // Runs finished or skipped
+	Succeeded  int        `json:"succeeded"` // Runs logged as success or degraded
+	Failed     int        `json:"failed"`
+	Skipped    int        `json:"skipped"` // Runs of APIs that skip overlapping checks and were already being checked
+	Runs       []BatchRun `json:"runs"`    // In the order the APIs were given
+	StartedAt  time.Time  `json:"startedAt"`
+	FinishedAt *time.Time `json:"finishedAt"`
 }
 
+// BatchRun is the run of one API in an execution batch
+type BatchRun struct {
+	APIID   int           `json:"apiId"`
+	APIName string        `json:"apiName"`
+	Status  string        `json:"status"` // "queued", "running", "skipped", or once finished the status of its execution log
+	Log     *ExecutionLog `json:"log,omitempty"`
+}
+
+// Execution batch states
+const (
+	ExecutionBatchRunning   = "running"
+	ExecutionBatchCompleted = "completed"
+)
+
+// States of a run in an execution batch before it finishes
+const (
+	BatchRunQueued  = "queued"
+	BatchRunRunning = "running"
+	BatchRunSkipped = "skipped"
+)
+
+// ScheduleStateChange reports a schedule's job changing state
+type ScheduleStateChange struct {
+	ScheduleID int       `json:"scheduleId"`
+	State      string    `json:"state"`
+	ChangedAt  time.Time `json:"changedAt"`
+}
+
+// States a schedule's job can change to
+const (
+	ScheduleStateScheduled = "scheduled" // The job was started, or ran again after being stale
+	ScheduleStateStopped   = "stopped"
+	ScheduleStateCompleted = "completed" // A one-time schedule ran and was deactivated
+	ScheduleStateStale     = "stale"     // The job missed its runs
+)
+
+// Request phases recorded when a request fails before its response is fully read, plus the internal phase of checks that panicked
+const (
+	RequestPhaseDNS      = "dns"      // Resolving the host name
+	RequestPhaseConnect  = "connect"  // Opening the TCP connection
+	RequestPhaseTLS      = "tls"      // TLS handshake
+	RequestPhaseRequest  = "request"  // Sending the request
+	RequestPhaseHeaders  = "headers"  // Waiting for the response headers
+	RequestPhaseBody     = "body"     // Reading the response body
+	RequestPhaseInternal = "internal" // FlowPulse itself failed while running the check
+)
+
 // AnalyticsSummary represents a summary of execution statistics
 type AnalyticsSummary struct {
 	TotalExecutions   int     `json:"totalExecutions"`
 	SuccessCount      int     `json:"successCount"`
+	DegradedCount     int     `json:"degradedCount"`
 	FailureCount      int     `json:"failureCount"`
 	SuccessRate       float64 `json:"successRate"`
 	AverageTimeMs     float64 `json:"averageTimeMs"` // Average execution time in milliseconds (if tracked)
+	P50TimeMs         float64 `json:"p50TimeMs"`     // Median execution time in milliseconds
+	P95TimeMs         float64 `json:"p95TimeMs"`     // 95th percentile execution time in milliseconds
+	P99TimeMs         float64 `json:"p99TimeMs"`     // 99th percentile execution time in milliseconds
 	LastExecutionTime string  `json:"lastExecutionTime"`
-	ErrorRate         float64 `json:"errorRate"`     // Calculated as 100 - successRate
-	Uptime            float64 `json:"uptime"`        // If calculating uptime is relevant
-} 
\ No newline at end of file
+	ErrorRate         float64 `json:"errorRate"` // Percentage of failed executions
+	Uptime            float64 `json:"uptime"`    // Percentage of successful or degraded executions
+}
+
+// TimeSeriesBucket summarizes an API's executions during one hour or day
+type TimeSeriesBucket struct {
+	Start         time.Time `json:"start"`
+	Executions    int       `json:"executions"`
+	SuccessCount  int       `json:"successCount"`
+	DegradedCount int       `json:"degradedCount"`
+	FailureCount  int       `json:"failureCount"`
+	AverageTimeMs float64   `json:"averageTimeMs"`
+	P50TimeMs     float64   `json:"p50TimeMs"`
+	P95TimeMs     float64   `json:"p95TimeMs"`
+	P99TimeMs     float64   `json:"p99TimeMs"`
+}
+
+// TimeSeries is an API's executions over a period, bucketed by hour or day to chart trends
+type TimeSeries struct {
+	APIID   int                `json:"apiId"`
+	Bucket  string             `json:"bucket"` // "hour" or "day"
+	From    time.Time          `json:"from"`
+	To      time.Time          `json:"to"`
+	Buckets []TimeSeriesBucket `json:"buckets"` // Buckets with executions, oldest first
+}
+
+// Time series bucket sizes
+const (
+	TimeSeriesBucketHour = "hour"
+	TimeSeriesBucketDay  = "day"
+)
+
+// DatabaseSnapshot is a copy of a healthy database kept to restore from after corruption
+type DatabaseSnapshot struct {
+	Path    string    `json:"path"`
+	TakenAt time.Time `json:"takenAt"`
+}
+
+// DatabaseRestore describes the replacement of a corrupt database with its latest snapshot
+type DatabaseRestore struct {
+	Snapshot    DatabaseSnapshot `json:"snapshot"`
+	CorruptPath string           `json:"corruptPath"` // Where the corrupt database was moved
+}
+
+// ResponseQuery is the result of evaluating a JSONPath against a stored response, previewing an extraction
+type ResponseQuery struct {
+	Expression string        `json:"expression"`
+	Matches    []interface{} `json:"matches"` // Every value matched, as decoded from the response
+	Value      string        `json:"value"`   // What an extraction would set its variable to: the first match as text
+}
+
+// LatencyHistogram is the latency distribution of an API's checks over a period, read from hourly rollups
+// that outlive the execution logs they were computed from. Percentiles are estimated within their bucket.
+type LatencyHistogram struct {
+	APIID          int       `json:"apiId"`
+	From           time.Time `json:"from"` // Start of the first hour covered
+	To             time.Time `json:"to"`   // End of the last hour covered
+	Executions     int64     `json:"executions"`
+	BucketBoundsMs []int64   `json:"bucketBoundsMs"` // Upper bound of each bucket but the last, which holds everything slower
+	Counts         []int64   `json:"counts"`         // Executions per bucket
+	AverageTimeMs  float64   `json:"averageTimeMs"`
+	P50TimeMs      int64     `json:"p50TimeMs"`
+	P95TimeMs      int64     `json:"p95TimeMs"`
+	P99TimeMs      int64     `json:"p99TimeMs"`
+	MaxTimeMs      int64     `json:"maxTimeMs"`
+}
+
+// Assertion represents a check evaluated against every response of an API
+type Assertion struct {
+	ID        int       `json:"id"`
+	APIID     int       `json:"apiId"`
+	Type      string    `json:"type"`     // "status_code", "json_path", "body_regex", "header", "latency" or "graphql_errors"
+	Target    string    `json:"target"`   // JSONPath for json_path, header name for header
+	Operator  string    `json:"operator"` // "equals", "not_equals", "contains", "matches" or "exists"
+	Expected  string    `json:"expected"` // Expected value, status code list/range, regex or latency limit in ms
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// Assertion types
+const (
+	AssertionStatusCode    = "status_code"    // Status code is in a list/range, e.g. "200,201" or "200-299"
+	AssertionJSONPath      = "json_path"      // Value at a JSONPath compared with the operator
+	AssertionBodyRegex     = "body_regex"     // Body matches a regular expression
+	AssertionHeader        = "header"         // Header is present, optionally compared with the operator
+	AssertionLatency       = "latency"        // Round trip is under the given number of milliseconds
+	AssertionGraphQLErrors = "graphql_errors" // GraphQL response has no errors, or with an expected value, an error message contains it
+	AssertionContract      = "contract"       // Response conforms to the OpenAPI spec (evaluated automatically, not user-defined)
+)
+
+// Assertion operators
+const (
+	OperatorEquals    = "equals"
+	OperatorNotEquals = "not_equals"
+	OperatorContains  = "contains"
+	OperatorMatches   = "matches"
+	OperatorExists    = "exists"
+)
+
+// AssertionResult represents the outcome of one assertion for an execution
+type AssertionResult struct {
+	AssertionID int    `json:"assertionId"`
+	Type        string `json:"type"`
+	Target      string `json:"target"`
+	Passed      bool   `json:"passed"`
+	Actual      string `json:"actual"`
+	Message     string `json:"message"`
+}
+
+// Extraction captures a value from an API's response into a variable that later requests in the same
+// collection can reference as {{variable}}, e.g. a login token used by the APIs after it
+type Extraction struct {
+	ID         int       `json:"id"`
+	APIID      int       `json:"apiId"`
+	Variable   string    `json:"variable"`   // Name of the variable to set
+	Source     string    `json:"source"`     // "json_path" or "header"
+	Expression string    `json:"expression"` // JSONPath into the body, or the header name
+	CreatedAt  time.Time `json:"createdAt"`
+	UpdatedAt  time.Time `json:"updatedAt"`
+}
+
+// Extraction sources
+const (
+	ExtractionJSONPath = "json_path"
+	ExtractionHeader   = "header"
+)
+
+// DiffChange represents a single difference between two responses
+type DiffChange struct {
+	Path     string `json:"path"` // JSONPath for JSON bodies, "line N" for text bodies
+	Type     string `json:"type"` // "added", "removed" or "changed"
+	OldValue string `json:"oldValue"`
+	NewValue string `json:"newValue"`
+}
+
+// Content watch modes of an API
+const (
+	ContentWatchHash = "hash" // Any change to the body counts
+	ContentWatchJSON = "json" // JSON bodies are compared structurally, ignoring key order and formatting
+)
+
+// ContentBaseline is the response body an API's checks are compared against when it watches its content
+type ContentBaseline struct {
+	APIID       int       `json:"apiId"`
+	Mode        string    `json:"mode"`        // Content watch mode the fingerprint was computed with
+	Fingerprint string    `json:"fingerprint"` // SHA-256 of the body, normalized as the mode says
+	Body        string    `json:"body"`
+	CapturedAt  time.Time `json:"capturedAt"`
+}
+
+// ContentChange records a response body that differed from the API's content baseline, which it became
+type ContentChange struct {
+	ID                  int          `json:"id"`
+	APIID               int          `json:"apiId"`
+	ExecutionLogID      int          `json:"executionLogId"` // Execution that saw the new content (0 when its log wasn't stored)
+	PreviousFingerprint string       `json:"previousFingerprint"`
+	Fingerprint         string       `json:"fingerprint"`
+	PreviousBody        string       `json:"previousBody"`
+	Body                string       `json:"body"`
+	Changes             []DiffChange `json:"changes"` // Differences between the bodies, computed when read
+	IsJSON              bool         `json:"isJson"`
+	DetectedAt          time.Time    `json:"detectedAt"`
+}
+
+// ExecutionDiff represents the differences between the stored results of two executions
+type ExecutionDiff struct {
+	LeftLog   ExecutionLog `json:"leftLog"`
+	RightLog  ExecutionLog `json:"rightLog"`
+	IsJSON    bool         `json:"isJson"` // Whether the bodies were compared structurally as JSON
+	Changes   []DiffChange `json:"changes"`
+	Identical bool         `json:"identical"`
+}
+
+// LocationStats represents the availability and latency of an API as seen from one location
+type LocationStats struct {
+	Location        string  `json:"location"`
+	TotalExecutions int     `json:"totalExecutions"`
+	SuccessCount    int     `json:"successCount"`
+	Availability    float64 `json:"availability"` // Percentage of successful or degraded executions
+	AverageTimeMs   float64 `json:"averageTimeMs"`
+	LastStatus      string  `json:"lastStatus"`
+	LastExecutedAt  string  `json:"lastExecutedAt"`
+}
+
+// LocationComparison compares one API across all locations that checked it
+type LocationComparison struct {
+	APIID            int             `json:"apiId"`
+	Locations        []LocationStats `json:"locations"`
+	FailingLocations []string        `json:"failingLocations"`
+	OutageScope      string          `json:"outageScope"` // "none", "regional" (some locations failing) or "global" (all failing)
+}
+
+// Outage scopes reported by LocationComparison
+const (
+	OutageScopeNone     = "none"
+	OutageScopeRegional = "regional"
+	OutageScopeGlobal   = "global"
+)
+
+// NotificationChannel represents a destination that alerts are delivered to
+type NotificationChannel struct {
+	ID        int       `json:"id"`
+	UUID      string    `json:"uuid"` // Identifies the channel across devices for sync and sharing
+	Name      string    `json:"name"`
+	Type      string    `json:"type"`   // "slack", "discord", "webhook" or "email"
+	Config    string    `json:"config"` // JSON string of the channel settings (see ChannelConfig)
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// Notification channel types
+const (
+	ChannelSlack   = "slack"
+	ChannelDiscord = "discord"
+	ChannelWebhook = "webhook"
+	ChannelEmail   = "email"
+)
+
+// ChannelConfig holds the settings stored in NotificationChannel.Config
+type ChannelConfig struct {
+	URL      string `json:"url"`      // Webhook URL for slack, discord and webhook channels
+	Host     string `json:"host"`     // SMTP host for email channels
+	Port     int    `json:"port"`     // SMTP port for email channels
+	Username string `json:"username"` // SMTP username
+	Password string `json:"password"` // SMTP password
+	From     string `json:"from"`     // Sender address
+	To       string `json:"to"`       // Comma-separated recipient addresses
+}
+
+// ChannelTestResult is the outcome of sending a sample alert through a notification channel
+type ChannelTestResult struct {
+	Delivered  bool      `json:"delivered"`
+	Error      string    `json:"error"`      // Why the alert wasn't delivered, e.g. the channel's endpoint rejecting it
+	DurationMs int64     `json:"durationMs"` // Time taken to deliver or fail
+	SentAt     time.Time `json:"sentAt"`
+}
+
+// AlertRule represents when a schedule's results should be sent to a notification channel
+type AlertRule struct {
+	ID               int       `json:"id"`
+	UUID             string    `json:"uuid"` // Identifies the rule across devices for sync and sharing
+	ScheduleID       int       `json:"scheduleId"`
+	ChannelID        int       `json:"channelId"`
+	RotationID       int       `json:"rotationId"`       // Route to whoever is on call in this rotation instead of ChannelID (0 for none)
+	FailureThreshold int       `json:"failureThreshold"` // Notify after this many consecutive failures
+	NotifyOnRecovery bool      `json:"notifyOnRecovery"` // Notify when the schedule succeeds again after an alert
+	IsActive         bool      `json:"isActive"`
+	CreatedAt        time.Time `json:"createdAt"`
+	UpdatedAt        time.Time `json:"updatedAt"`
+}
+
+// OnCallUser represents a person who can be on call, reachable through their own notification channel
+type OnCallUser struct {
+	ID        int       `json:"id"`
+	Name      string    `json:"name"`
+	ChannelID int       `json:"channelId"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// OnCallRotation represents a weekly rotation: each user in turn is on call for a week, starting at StartsAt
+type OnCallRotation struct {
+	ID        int       `json:"id"`
+	Name      string    `json:"name"`
+	UserIDs   []int     `json:"userIds"`  // Users in rotation order
+	StartsAt  time.Time `json:"startsAt"` // Handoff time of the first user's week; later handoffs happen weekly at the same time
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// Alert represents a notification sent about an API
+type Alert struct {
+	Kind                string    `json:"kind"` // "failure", "recovery", "stale", "slow", "content_changed", "regional_outage", "latency", "latency_recovery", "ingestion" or "ingestion_normal"
+	APIID               int       `json:"apiId"`
+	CollectionID        int       `json:"collectionId"` // Collection whose latency target the alert is about, for latency alerts
+	CollectionName      string    `json:"collectionName"`
+	APIName             string    `json:"apiName"`
+	URL                 string    `json:"url"`
+	ScheduleID          int       `json:"scheduleId"`
+	StatusCode          int       `json:"statusCode"`
+	Error               string    `json:"error"`
+	ConsecutiveFailures int       `json:"consecutiveFailures"`
+	ExecutedAt          time.Time `json:"executedAt"`
+	RunbookURL          string    `json:"runbookUrl"` // Remediation steps of the API, for API alerts
+	Notes               string    `json:"notes"`      // Markdown notes of the API, for API alerts
+}
+
+// Alert kinds
+const (
+	AlertFailure         = "failure"
+	AlertRecovery        = "recovery"
+	AlertStale           = "stale"            // The schedule's job stopped running
+	AlertSlow            = "slow"             // A check of the API exceeded its critical latency threshold
+	AlertContentChanged  = "content_changed"  // The response body of the API changed from its content baseline
+	AlertRegionalOutage  = "regional_outage"  // The API fails from some locations while others still reach it
+	AlertLatency         = "latency"          // A collection's latency target was breached
+	AlertLatencyRecovery = "latency_recovery" // A breached latency target is met again
+	AlertIngestion       = "ingestion"        // Log volume exceeded the ingestion guard, which started sampling
+	AlertIngestionNormal = "ingestion_normal" // Log volume is back within the ingestion guard
+)
+
+// StaleCheck represents an active schedule whose job has stopped running
+type StaleCheck struct {
+	ScheduleID     int       `json:"scheduleId"`
+	APIID          int       `json:"apiId"`
+	APIName        string    `json:"apiName"`
+	Type           string    `json:"type"`
+	Expression     string    `json:"expression"`
+	Scheduled      bool      `json:"scheduled"`      // Whether the schedule has a job at all; false when it failed to start or was lost
+	LastRunAt      time.Time `json:"lastRunAt"`      // When the job last ran, or was started if it never ran (zero when unknown)
+	OverdueSeconds int64     `json:"overdueSeconds"` // How long ago the job should have run at the latest
+}
+
+// SchedulerConcurrency limits the scheduled checks running at once; manual runs and collection runs aren't limited
+type SchedulerConcurrency struct {
+	MaxConcurrent int `json:"maxConcurrent"` // Most scheduled checks in flight at once; the rest wait for a slot (0 for no limit)
+	MaxPerHost    int `json:"maxPerHost"`    // Most scheduled checks in flight against one host (0 for no limit)
+}
+
+// IngestionGuard protects the database from runaway log volume, e.g. a 1-second interval against an endpoint
+// with huge responses. While more logs arrive in a minute than it allows, successes that follow another success
+// of the same API are sampled; failures, recoveries, manual runs and collection runs are always stored.
+type IngestionGuard struct {
+	MaxLogsPerMinute  int `json:"maxLogsPerMinute"`  // Logs arriving per minute above which successes are sampled (0 for 600)
+	MaxBytesPerMinute int `json:"maxBytesPerMinute"` // Response and error bytes arriving per minute above which successes are sampled (0 for 50 MB)
+	SampleRate        int `json:"sampleRate"`        // While sampling, store one in this many successes (0 for 10)
+	AlertChannelID    int `json:"alertChannelId"`    // Channel alerted when sampling starts and stops (0 for none)
+}
+
+// Defaults of the ingestion guard settings left at 0
+const (
+	DefaultIngestionMaxLogsPerMinute  = 600
+	DefaultIngestionMaxBytesPerMinute = 50 << 20
+	DefaultIngestionSampleRate        = 10
+)
+
+// IngestionStatus reports the log volume seen by the ingestion guard and whether it is sampling
+type IngestionStatus struct {
+	Sampling    bool       `json:"sampling"`
+	Since       *time.Time `json:"since"`       // When sampling started (nil when not sampling)
+	Logs        int        `json:"logs"`        // Logs that arrived during the current minute
+	Bytes       int64      `json:"bytes"`       // Response and error bytes that arrived during the current minute
+	SkippedLogs int64      `json:"skippedLogs"` // Successes left out since sampling started
+	WindowStart time.Time  `json:"windowStart"` // Start of the current minute
+}
+
+// HostLoad is the scheduled checks of one host in flight and waiting for a slot right now
+type HostLoad struct {
+	Host         string `json:"host"`
+	Running      int    `json:"running"`
+	Waiting      int    `json:"waiting"`
+	OldestWaitMs int64  `json:"oldestWaitMs"` // How long the longest waiting check has waited
+}
+
+// StaleCheckPolicy controls when active schedules count as stale and whether that is alerted
+type StaleCheckPolicy struct {
+	Intervals int  `json:"intervals"` // Number of intervals without a run after which a schedule is stale
+	Alert     bool `json:"alert"`     // Whether stale schedules are reported through the channels of their alert rules
+}
+
+// MaintenanceWindow is a one-off or recurring period during which scheduled checks of the APIs it
+// covers are skipped and their alerts suppressed
+type MaintenanceWindow struct {
+	ID              int       `json:"id"`
+	Name            string    `json:"name"`
+	Scope           string    `json:"scope"`           // "global", "api" or "collection"
+	APIID           int       `json:"apiId"`           // Covered API when the scope is "api"
+	CollectionID    int       `json:"collectionId"`    // Covered collection when the scope is "collection"
+	StartsAt        time.Time `json:"startsAt"`        // Start of a one-off window, or when a recurring one takes effect (zero for always)
+	EndsAt          time.Time `json:"endsAt"`          // End of a one-off window, or when a recurring one stops (zero for never)
+	Recurrence      string    `json:"recurrence"`      // Cron expression of when each recurring window opens (empty for one-off)
+	DurationMinutes int       `json:"durationMinutes"` // How long each recurring window stays open
+	Timezone        string    `json:"timezone"`        // IANA zone the recurrence is evaluated in (empty for the local zone)
+	CreatedAt       time.Time `json:"createdAt"`
+	UpdatedAt       time.Time `json:"updatedAt"`
+}
+
+// Maintenance window scopes
+const (
+	MaintenanceScopeGlobal     = "global"
+	MaintenanceScopeAPI        = "api"
+	MaintenanceScopeCollection = "collection"
+)
+
+// ExpectedFailureWindow marks a past or present time range of an API as expected to fail, so its
+// executions are left out of uptime and latency statistics after the fact
+type ExpectedFailureWindow struct {
+	ID        int       `json:"id"`
+	APIID     int       `json:"apiId"`
+	StartsAt  time.Time `json:"startsAt"`
+	EndsAt    time.Time `json:"endsAt"`
+	Reason    string    `json:"reason"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// Dashboard is a saved view of the APIs, charts and metrics a user chose to watch together
+type Dashboard struct {
+	ID          int       `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Layout      string    `json:"layout"`    // JSON describing the widgets and where they sit, owned by the frontend
+	SortOrder   int       `json:"sortOrder"` // Position among the dashboards, lowest first
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+// LogFilter selects execution logs; fields left empty or zero don't filter
+type LogFilter struct {
+	APIIDs        []int  `json:"apiIds"`        // Logs of any of these APIs
+	StatusCodeMin int    `json:"statusCodeMin"` // Lowest status code, e.g. 500 with StatusCodeMax 599 for server errors
+	StatusCodeMax int    `json:"statusCodeMax"` // Highest status code
+	Status        string `json:"status"`        // Evaluated outcome: "success", "degraded", "failure" or "skipped"
+	Query         string `json:"query"`         // Text the error or stored response contains, ignoring case
+	PeriodHours   int    `json:"periodHours"`   // Only logs from the last this many hours, e.g. 168 for a week
+}
+
+// SearchResult is a match of the universal search across APIs, collections and execution logs
+type SearchResult struct {
+	Type       string     `json:"type"`       // "api", "collection" or "execution"
+	ID         int        `json:"id"`         // ID of the API, collection or execution log
+	Title      string     `json:"title"`      // Name of the API or collection; the API's name for executions
+	Subtitle   string     `json:"subtitle"`   // URL of an API, description of a collection, outcome of an execution
+	Snippet    string     `json:"snippet"`    // Text around the first match in the field that matched
+	APIID      int        `json:"apiId"`      // API an execution belongs to
+	ExecutedAt *time.Time `json:"executedAt"` // When an execution ran (nil for APIs and collections)
+}
+
+// Search result types
+const (
+	SearchResultAPI        = "api"
+	SearchResultCollection = "collection"
+	SearchResultExecution  = "execution"
+)
+
+// SavedLogFilter is a named log filter kept for recurring investigations
+type SavedLogFilter struct {
+	ID        int       `json:"id"`
+	Name      string    `json:"name"`
+	Filter    LogFilter `json:"filter"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// WebhookTrigger lets an external system, such as a CI pipeline or a payment provider, run an API or a
+// collection by posting to /hooks/{id} on the REST API. Each request must be signed with the trigger's secret,
+// or for senders that can't sign, posted to the secret URL /hooks/{id}/{secret}.
+type WebhookTrigger struct {
+	ID              int       `json:"id"`
+	Name            string    `json:"name"`
+	APIID           int       `json:"apiId"`           // API run when the trigger fires (0 when it runs a collection)
+	CollectionID    int       `json:"collectionId"`    // Collection run when the trigger fires (0 when it runs an API)
+	SignatureScheme string    `json:"signatureScheme"` // How requests are signed: "github", "stripe" or "url"
+	Secret          string    `json:"secret"`          // HMAC key shared with the sender; generated when left empty
+	IsActive        bool      `json:"isActive"`
+	LastTriggeredAt time.Time `json:"lastTriggeredAt"` // Zero until it fires
+	CreatedAt       time.Time `json:"createdAt"`
+	UpdatedAt       time.Time `json:"updatedAt"`
+}
+
+// Webhook signature schemes
+const (
+	WebhookSignatureGitHub = "github" // X-Hub-Signature-256 header
+	WebhookSignatureStripe = "stripe" // Stripe-Signature header with a timestamp
+	WebhookSignatureURL    = "url"    // No signature; the secret is the last segment of the URL
+)
+
+// EventWebhook posts the result of every check matching its rules to an external URL, e.g. to feed a chat-ops
+// bot or data pipeline without flooding it with every success
+type EventWebhook struct {
+	ID        int                `json:"id"`
+	Name      string             `json:"name"`
+	URL       string             `json:"url"`
+	Secret    string             `json:"secret"` // HMAC key the X-FlowPulse-Signature-256 header is computed with (empty for unsigned)
+	Rules     []EventWebhookRule `json:"rules"`  // All must match for a result to be posted (none posts every result)
+	IsActive  bool               `json:"isActive"`
+	CreatedAt time.Time          `json:"createdAt"`
+	UpdatedAt time.Time          `json:"updatedAt"`
+}
+
+// EventWebhookRule compares one field of a check's result, e.g. status_code at_least 500
+type EventWebhookRule struct {
+	Field    string `json:"field"`    // "status", "status_code", "api", "collection" or "duration_ms"
+	Operator string `json:"operator"` // "equals", "not_equals", "in", "at_least" or "at_most"
+	Value    string `json:"value"`    // Compared value; a comma-separated list for "in", which also takes status code ranges
+}
+
+// Fields an event webhook rule compares
+const (
+	EventRuleStatus     = "status"      // Evaluated outcome, e.g. "failure"
+	EventRuleStatusCode = "status_code" // HTTP status code
+	EventRuleAPI        = "api"         // API ID
+	EventRuleCollection = "collection"  // ID of the API's collection (0 for none)
+	EventRuleDuration   = "duration_ms" // Round trip in milliseconds
+)
+
+// Operators of event webhook rules besides OperatorEquals and OperatorNotEquals
+const (
+	OperatorIn      = "in"
+	OperatorAtLeast = "at_least"
+	OperatorAtMost  = "at_most"
+)
+
+// ExecutionEvent is the body an event webhook posts for a check's result
+type ExecutionEvent struct {
+	Event        string       `json:"event"` // "execution:completed"
+	APIName      string       `json:"apiName"`
+	CollectionID int          `json:"collectionId"`
+	Log          ExecutionLog `json:"log"`
+}
+
+// StatusPage is a public status page of a chosen set of APIs, generated as static HTML and JSON
+type StatusPage struct {
+	ID         int       `json:"id"`
+	Name       string    `json:"name"`
+	Slug       string    `json:"slug"`       // Path the page is served at on the REST API, as in /status/{slug}
+	Title      string    `json:"title"`      // Heading of the page (empty for the name)
+	APIIDs     []int     `json:"apiIds"`     // APIs shown on the page, in this order
+	WindowDays int       `json:"windowDays"` // Days uptime and incident history cover (0 for 30)
+	Public     bool      `json:"public"`     // Serve the page on the REST API without a token
+	OutputDir  string    `json:"outputDir"`  // Directory the page is written to when exported (empty for none)
+	CreatedAt  time.Time `json:"createdAt"`
+	UpdatedAt  time.Time `json:"updatedAt"`
+}
+
+// StatusPageReport is the content of a generated status page. It leaves out URLs and other configuration,
+// since the page is meant to be public.
+type StatusPageReport struct {
+	Title       string               `json:"title"`
+	Status      string               `json:"status"` // Overall status: "operational", "degraded" or "outage"
+	WindowDays  int                  `json:"windowDays"`
+	GeneratedAt time.Time            `json:"generatedAt"`
+	APIs        []StatusPageAPI      `json:"apis"`
+	Incidents   []StatusPageIncident `json:"incidents"` // Incidents opened during the window, newest first
+}
+
+// StatusPageAPI is the status of one API on a status page
+type StatusPageAPI struct {
+	Name       string  `json:"name"`
+	Status     string  `json:"status"`     // "operational", "degraded", "outage" or "unknown" before its first check
+	Uptime     float64 `json:"uptime"`     // Percentage of checks during the window that succeeded or were degraded
+	Executions int     `json:"executions"` // Checks the uptime is based on
+}
+
+// StatusPageIncident is an incident listed on a status page
+type StatusPageIncident struct {
+	APIName         string     `json:"apiName"`
+	Status          string     `json:"status"` // "open", "acknowledged" or "resolved"
+	OpenedAt        time.Time  `json:"openedAt"`
+	ResolvedAt      *time.Time `json:"resolvedAt"`
+	DurationSeconds int64      `json:"durationSeconds"`
+}
+
+// Statuses shown on a status page
+const (
+	StatusPageOperational = "operational"
+	StatusPageDegraded    = "degraded"
+	StatusPageOutage      = "outage"
+	StatusPageUnknown     = "unknown"
+)
+
+// Tag labels APIs and schedules, e.g. by team or service, so they can be filtered together
+type Tag struct {
+	ID        int       `json:"id"`
+	Name      string    `json:"name"`
+	Color     string    `json:"color"` // Hex color shown on the tag, e.g. "#3b82f6" (empty for the default)
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// SyncConfig represents the remote location used to sync the workspace between devices
+type SyncConfig struct {
+	Provider        string `json:"provider"` // "webdav" or "s3" (empty disables sync)
+	Endpoint        string `json:"endpoint"` // WebDAV folder URL or custom S3 endpoint (empty for AWS)
+	Region          string `json:"region"`   // S3 region
+	Bucket          string `json:"bucket"`   // S3 bucket
+	Path            string `json:"path"`     // File name or object key of the workspace snapshot
+	Username        string `json:"username"` // WebDAV username
+	Password        string `json:"password"` // WebDAV password
+	AccessKeyID     string `json:"accessKeyId"`
+	SecretAccessKey string `json:"secretAccessKey"`
+	LastSyncedAt    string `json:"lastSyncedAt"`
+}
+
+// SyncResult summarizes the changes made by a workspace sync
+type SyncResult struct {
+	Created   int    `json:"created"`   // Items created locally from the remote
+	Updated   int    `json:"updated"`   // Items updated locally from the remote
+	Deleted   int    `json:"deleted"`   // Items deleted locally because they were removed remotely
+	Conflicts int    `json:"conflicts"` // Items changed on both sides, resolved by the newest edit
+	SyncedAt  string `json:"syncedAt"`
+}
+
+// BackupResult summarizes the records a workspace backup restored
+type BackupResult struct {
+	Environments int `json:"environments"`
+	Collections  int `json:"collections"`
+	APIs         int `json:"apis"`
+	Schedules    int `json:"schedules"`
+	Logs         int `json:"logs"`
+	Skipped      int `json:"skipped"` // Environments, collections, APIs and schedules that already existed
+}
+
+// FindReplace describes a find and replace over the URLs and bodies of APIs
+type FindReplace struct {
+	Find         string `json:"find"`    // Text, or regular expression when Regex is set
+	Replace      string `json:"replace"` // Replacement, which may refer to regex groups as $1
+	Regex        bool   `json:"regex"`
+	IgnoreCase   bool   `json:"ignoreCase"`
+	InURLs       bool   `json:"inUrls"`
+	InBodies     bool   `json:"inBodies"`
+	CollectionID int    `json:"collectionId"` // Collection to limit the replacement to (0 for every API)
+}
+
+// ReplaceMatch is a field a find and replace changes
+type ReplaceMatch struct {
+	APIID   int    `json:"apiId"`
+	APIName string `json:"apiName"`
+	Field   string `json:"field"` // "url" or "body"
+	Before  string `json:"before"`
+	After   string `json:"after"`
+}
+
+// ReplaceResult lists the fields a find and replace changed, or would change when previewed
+type ReplaceResult struct {
+	Matches     []ReplaceMatch `json:"matches"`
+	APIsChanged int            `json:"apisChanged"`
+	Applied     bool           `json:"applied"` // False for a preview
+}
+
+// ChangePlan lists the changes an import or sync would make, returned by its dry run
+type ChangePlan struct {
+	Changes   []PlannedChange `json:"changes"`
+	Conflicts int             `json:"conflicts"` // Items changed on both sides of a sync, resolved by the newest edit
+}
+
+// PlannedChange is a single item a dry run would create, update or delete
+type PlannedChange struct {
+	Action string   `json:"action"` // "create", "update" or "delete"
+	Kind   string   `json:"kind"`   // "spec", "collection", "api", "schedule", "channel" or "alert_rule"
+	Name   string   `json:"name"`
+	Fields []string `json:"fields"` // Fields that would change in an update
+}
+
+// Actions of a PlannedChange
+const (
+	ChangeActionCreate = "create"
+	ChangeActionUpdate = "update"
+	ChangeActionDelete = "delete"
+)
+
+// Kinds of items in a PlannedChange
+const (
+	ChangeKindSpec       = "spec"
+	ChangeKindCollection = "collection"
+	ChangeKindAPI        = "api"
+	ChangeKindSchedule   = "schedule"
+	ChangeKindChannel    = "channel"
+	ChangeKindAlertRule  = "alert_rule"
+)
+
+// Incident represents a period during which an API was failing, opened once it failed enough times in a row
+// or one of its failure alerts fired
+type Incident struct {
+	ID              int          `json:"id"`
+	APIID           int          `json:"apiId"`
+	Status          string       `json:"status"` // "open", "acknowledged" or "resolved"
+	OpenedAt        time.Time    `json:"openedAt"`
+	AcknowledgedAt  *time.Time   `json:"acknowledgedAt"`
+	ResolvedAt      *time.Time   `json:"resolvedAt"`
+	DurationSeconds int64        `json:"durationSeconds"` // How long the API was failing; so far while the incident is unresolved
+	Annotations     []Annotation `json:"annotations"`     // Notes people attached to this incident
+}
+
+// IncidentPolicy decides when an API's failures turn into an incident
+type IncidentPolicy struct {
+	FailureThreshold int `json:"failureThreshold"` // Consecutive failed scheduled checks that open an incident (0 for the default)
+}
+
+// DefaultIncidentFailureThreshold is the number of consecutive failures that opens an incident unless configured otherwise
+const DefaultIncidentFailureThreshold = 3
+
+// IncidentStats summarizes the incidents opened during a period
+type IncidentStats struct {
+	APIID           int       `json:"apiId"` // 0 for every API
+	Since           time.Time `json:"since"`
+	Incidents       int       `json:"incidents"`
+	Open            int       `json:"open"` // Still unresolved, acknowledged or not
+	Resolved        int       `json:"resolved"`
+	MTTRSeconds     float64   `json:"mttrSeconds"`     // Mean time to resolve the resolved incidents
+	MTTASeconds     float64   `json:"mttaSeconds"`     // Mean time to acknowledge the acknowledged incidents
+	LongestSeconds  int64     `json:"longestSeconds"`  // Longest resolved incident
+	DowntimeSeconds int64     `json:"downtimeSeconds"` // Total time spent in the incidents, counting unresolved ones up to now
+}
+
+// Incident statuses
+const (
+	IncidentOpen         = "open"
+	IncidentAcknowledged = "acknowledged" // Someone is on it; further failure notifications are suppressed
+	IncidentResolved     = "resolved"
+)
+
+// Annotation is a free-text note attached to an execution log or an incident,
+// such as "known outage, provider ticket #123"
+type Annotation struct {
+	ID             int       `json:"id"`
+	ExecutionLogID int       `json:"executionLogId"` // Annotated execution log (0 when annotating an incident)
+	IncidentID     int       `json:"incidentId"`     // Annotated incident (0 when annotating an execution log)
+	Note           string    `json:"note"`
+	CreatedAt      time.Time `json:"createdAt"`
+	UpdatedAt      time.Time `json:"updatedAt"`
+}
+
+// IncidentEvent is an entry in the history of an incident
+type IncidentEvent struct {
+	ID         int       `json:"id"`
+	IncidentID int       `json:"incidentId"`
+	Type       string    `json:"type"` // "opened", "acknowledged", "snoozed" or "resolved"
+	Note       string    `json:"note"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+// Incident event types
+const (
+	IncidentEventOpened       = "opened"
+	IncidentEventAcknowledged = "acknowledged"
+	IncidentEventSnoozed      = "snoozed"
+	IncidentEventResolved     = "resolved"
+)
+
+// SchedulerMetrics is the scheduler's own activity during one minute
+type SchedulerMetrics struct {
+	Minute         time.Time `json:"minute"`
+	Executions     int       `json:"executions"`     // Checks run, including manual and collection runs
+	Retries        int       `json:"retries"`        // Retry attempts made after a failed attempt
+	SkippedRuns    int       `json:"skippedRuns"`    // Scheduled runs dropped because the previous run was still going
+	AvgQueueWaitMs float64   `json:"avgQueueWaitMs"` // Average delay between a run's due time and its start
+	AvgSlotWaitMs  float64   `json:"avgSlotWaitMs"`  // Average time scheduled runs waited for a concurrency slot
+	MaxSlotWaitMs  int64     `json:"maxSlotWaitMs"`  // Longest time a scheduled run waited for a concurrency slot
+}
+
+// SchedulerMetricsReport summarizes the scheduler's activity over a period, to tell when FlowPulse itself is the bottleneck
+type SchedulerMetricsReport struct {
+	Points              []SchedulerMetrics `json:"points"` // Minutes with activity, oldest first
+	Executions          int                `json:"executions"`
+	ExecutionsPerMinute float64            `json:"executionsPerMinute"`
+	Retries             int                `json:"retries"`
+	SkippedRuns         int                `json:"skippedRuns"`
+	AvgQueueWaitMs      float64            `json:"avgQueueWaitMs"`
+	AvgSlotWaitMs       float64            `json:"avgSlotWaitMs"`
+	MaxSlotWaitMs       int64              `json:"maxSlotWaitMs"` // Longest wait for a concurrency slot, where starved checks show up
+	Gaps                []MonitoringGap    `json:"gaps"`          // Periods nothing was checked, oldest first
+	Hosts               []HostLoad         `json:"hosts"`         // Scheduled checks in flight and waiting per host right now, busiest first
+}
+
+// MonitoringGap is a period the scheduler didn't run, e.g. while the computer was asleep
+type MonitoringGap struct {
+	StartedAt time.Time `json:"startedAt"`
+	EndedAt   time.Time `json:"endedAt"`
+}
+
+// Profile is a named workspace with its own database, e.g. to keep work and personal monitors apart
+type Profile struct {
+	Name   string `json:"name"`
+	DBPath string `json:"dbPath"` // Database file of the profile (empty for the default location)
+	Active bool   `json:"active"` // Whether the app currently uses this profile
+}
+
+// RetentionPolicy controls how long execution logs are kept. A zero limit is disabled.
+type RetentionPolicy struct {
+	MaxAgeDays         int  `json:"maxAgeDays"`         // Delete logs older than this many days
+	MaxRowsPerAPI      int  `json:"maxRowsPerApi"`      // Keep only this many of the newest logs per API
+	Archive            bool `json:"archive"`            // Write pruned logs to compressed NDJSON archives in the archive directory of the app data before deleting them
+	StoreFullResponses bool `json:"storeFullResponses"` // Keep responses longer than the log's preview in full, compressed, until their log is pruned
+}
+
+// RESTServerConfig controls the embedded REST API used by external tooling and CI
+type RESTServerConfig struct {
+	Enabled bool   `json:"enabled"`
+	Host    string `json:"host"` // Interface to listen on; 127.0.0.1 keeps the API local
+	Port    int    `json:"port"`
+	Token   string `json:"token"` // Bearer token required on every request except /health
+}
+
+// WorkspaceReport summarizes what a workspace holds and where its storage goes, with the cleanups worth running
+type WorkspaceReport struct {
+	Counts           WorkspaceCounts     `json:"counts"`
+	DatabaseBytes    int64               `json:"databaseBytes"`    // Size of the database file
+	FreeBytes        int64               `json:"freeBytes"`        // Unused space in the file that CleanupVacuum would give back
+	Tables           []TableStorage      `json:"tables"`           // Largest first
+	LargestResponses []StoredResponse    `json:"largestResponses"` // Largest first
+	NoisiestAPIs     []NoisyAPI          `json:"noisiestApis"`     // APIs logging the most executions over the last week, noisiest first
+	Orphans          []OrphanedRecords   `json:"orphans"`          // Kinds of records left behind by something deleted (only those found)
+	Suggestions      []CleanupSuggestion `json:"suggestions"`      // Cleanups worth running, see RunCleanupAction
+	GeneratedAt      time.Time           `json:"generatedAt"`
+}
+
+// WorkspaceCounts counts what a workspace holds
+type WorkspaceCounts struct {
+	APIs               int `json:"apis"` // Not counting those in the trash
+	Collections        int `json:"collections"`
+	Schedules          int `json:"schedules"`
+	ActiveSchedules    int `json:"activeSchedules"`
+	ActiveJobs         int `json:"activeJobs"` // Jobs the scheduler is running right now
+	ExecutionLogs      int `json:"executionLogs"`
+	TrashedAPIs        int `json:"trashedApis"`
+	TrashedCollections int `json:"trashedCollections"`
+}
+
+// TableStorage is the storage a database table takes, estimated from the values stored in it without indexes
+type TableStorage struct {
+	Table string `json:"table"`
+	Rows  int    `json:"rows"`
+	Bytes int64  `json:"bytes"`
+}
+
+// StoredResponse is the response of an execution log with the bytes it takes, counting its full body when stored
+type StoredResponse struct {
+	ExecutionLogID int       `json:"executionLogId"`
+	APIID          int       `json:"apiId"`
+	APIName        string    `json:"apiName"` // Empty when the API no longer exists
+	Bytes          int64     `json:"bytes"`
+	ExecutedAt     time.Time `json:"executedAt"`
+}
+
+// NoisyAPI is an API with the executions it logged over a period
+type NoisyAPI struct {
+	APIID      int    `json:"apiId"`
+	APIName    string `json:"apiName"` // Empty when the API no longer exists
+	Executions int    `json:"executions"`
+	Failures   int    `json:"failures"`
+	Bytes      int64  `json:"bytes"` // Taken by the responses of the logs
+}
+
+// OrphanedRecords counts the records of a table left behind by the record they belonged to being deleted
+type OrphanedRecords struct {
+	Table       string `json:"table"`
+	Description string `json:"description"`
+	Count       int    `json:"count"`
+}
+
+// CleanupSuggestion is a cleanup worth running on a workspace, run with RunCleanupAction
+type CleanupSuggestion struct {
+	Action      string `json:"action"` // One of the Cleanup constants
+	Description string `json:"description"`
+	Records     int    `json:"records"` // Records the cleanup would delete (0 for CleanupVacuum)
+	Bytes       int64  `json:"bytes"`   // Storage the cleanup would free, as far as it is known
+}
+
+// Cleanup actions of a workspace
+const (
+	CleanupEmptyTrash    = "empty_trash"    // Permanently delete everything in the trash
+	CleanupPruneLogs     = "prune_logs"     // Delete the execution logs outside the retention policy
+	CleanupDeleteOrphans = "delete_orphans" // Delete records left behind by something deleted
+	CleanupVacuum        = "vacuum"         // Give the unused space of the database file back
+)
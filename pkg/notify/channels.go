@@ -0,0 +1,189 @@
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+
+	"flowpulse/pkg/models"
+)
+
+// ParseChannelConfig parses and validates the settings of a notification channel
+func ParseChannelConfig(channel models.NotificationChannel) (models.ChannelConfig, error) {
+	var config models.ChannelConfig
+	if channel.Config != "" {
+		if err := json.Unmarshal([]byte(channel.Config), &config); err != nil {
+			return config, fmt.Errorf("failed to parse channel config: %w", err)
+		}
+	}
+
+	switch channel.Type {
+	case models.ChannelSlack, models.ChannelDiscord, models.ChannelWebhook:
+		if config.URL == "" {
+			return config, fmt.Errorf("%s channel requires a URL", channel.Type)
+		}
+	case models.ChannelEmail:
+		if config.Host == "" || config.From == "" || config.To == "" {
+			return config, fmt.Errorf("email channel requires host, from and to")
+		}
+		if config.Port == 0 {
+			config.Port = 587
+		}
+	default:
+		return config, fmt.Errorf("unsupported channel type: %s", channel.Type)
+	}
+	return config, nil
+}
+
+// FormatAlert renders an alert as a short human-readable message
+func FormatAlert(alert models.Alert) string {
+	if alert.Kind == models.AlertLatency {
+		return fmt.Sprintf("🐢 %s is too slow\n%s", alert.CollectionName, alert.Error)
+	}
+	if alert.Kind == models.AlertLatencyRecovery {
+		return fmt.Sprintf("✅ %s is fast again\n%s", alert.CollectionName, alert.Error)
+	}
+	if alert.Kind == models.AlertIngestion {
+		return fmt.Sprintf("📈 Log volume is too high, sampling successful checks\n%s", alert.Error)
+	}
+	if alert.Kind == models.AlertIngestionNormal {
+		return fmt.Sprintf("✅ Log volume is back to normal, storing every check\n%s", alert.Error)
+	}
+	if alert.Kind == models.AlertSlow {
+		return withRunbook(fmt.Sprintf("🐢 %s is slow\n%s\n%s", alert.APIName, alert.Error, alert.URL), alert)
+	}
+	if alert.Kind == models.AlertRegionalOutage {
+		return withRunbook(fmt.Sprintf("🌍 %s is down in some locations\n%s\n%s", alert.APIName, alert.Error, alert.URL), alert)
+	}
+	if alert.Kind == models.AlertContentChanged {
+		return withRunbook(fmt.Sprintf("📝 %s changed\n%s\n%s", alert.APIName, alert.Error, alert.URL), alert)
+	}
+	if alert.Kind == models.AlertStale {
+		return withRunbook(fmt.Sprintf("⏳ %s stopped running\n%s\n%s", alert.APIName, alert.Error, alert.URL), alert)
+	}
+	if alert.Kind == models.AlertRecovery {
+		return fmt.Sprintf("✅ %s recovered (status %d) after %d consecutive failures\n%s",
+			alert.APIName, alert.StatusCode, alert.ConsecutiveFailures, alert.URL)
+	}
+
+	message := fmt.Sprintf("🚨 %s failed %d time(s) in a row (status %d)\n%s",
+		alert.APIName, alert.ConsecutiveFailures, alert.StatusCode, alert.URL)
+	if alert.Error != "" {
+		message += "\n" + alert.Error
+	}
+	return withRunbook(message, alert)
+}
+
+// withRunbook appends the API's runbook link and notes to an alert message, so whoever is alerted knows what to do
+func withRunbook(message string, alert models.Alert) string {
+	if alert.RunbookURL != "" {
+		message += "\nRunbook: " + alert.RunbookURL
+	}
+	if alert.Notes != "" {
+		message += "\n\n" + alert.Notes
+	}
+	return message
+}
+
+// Send delivers an alert through a notification channel
+func (s *Service) Send(channel models.NotificationChannel, alert models.Alert) error {
+	config, err := ParseChannelConfig(channel)
+	if err != nil {
+		return err
+	}
+
+	switch channel.Type {
+	case models.ChannelSlack:
+		return s.postJSON(config.URL, map[string]string{"text": FormatAlert(alert)})
+	case models.ChannelDiscord:
+		return s.postJSON(config.URL, map[string]string{"content": FormatAlert(alert)})
+	case models.ChannelWebhook:
+		return s.postJSON(config.URL, alert)
+	case models.ChannelEmail:
+		return sendEmail(config, alert)
+	default:
+		return fmt.Errorf("unsupported channel type: %s", channel.Type)
+	}
+}
+
+// Test sends a sample failure alert through a notification channel, so its settings can be checked
+// without waiting for a real outage
+func (s *Service) Test(channel models.NotificationChannel) models.ChannelTestResult {
+	result := models.ChannelTestResult{SentAt: time.Now()}
+	err := s.Send(channel, SampleAlert(result.SentAt))
+	result.DurationMs = time.Since(result.SentAt).Milliseconds()
+	if err != nil {
+		result.Error = err.Error()
+	} else {
+		result.Delivered = true
+	}
+	return result
+}
+
+// SampleAlert returns the failure alert sent when testing a channel
+func SampleAlert(now time.Time) models.Alert {
+	return models.Alert{
+		Kind:                models.AlertFailure,
+		APIName:             "FlowPulse test alert",
+		URL:                 "https://example.com/health",
+		StatusCode:          503,
+		Error:               "This is a test notification to check the channel's settings. No API is down.",
+		ConsecutiveFailures: 1,
+		ExecutedAt:          now,
+	}
+}
+
+// postJSON posts a JSON payload to a webhook URL
+func (s *Service) postJSON(url string, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode notification: %w", err)
+	}
+
+	resp, err := s.client.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to send notification: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("notification endpoint returned %s", resp.Status)
+	}
+	return nil
+}
+
+// sendEmail sends an alert via SMTP
+func sendEmail(config models.ChannelConfig, alert models.Alert) error {
+	var recipients []string
+	for _, to := range strings.Split(config.To, ",") {
+		if to = strings.TrimSpace(to); to != "" {
+			recipients = append(recipients, to)
+		}
+	}
+
+	name := alert.APIName
+	if alert.CollectionName != "" {
+		name = alert.CollectionName
+	}
+	subject := fmt.Sprintf("[FlowPulse] %s %s", name, alert.Kind)
+	message := "From: " + config.From + "\r\n" +
+		"To: " + strings.Join(recipients, ", ") + "\r\n" +
+		"Subject: " + subject + "\r\n" +
+		"Content-Type: text/plain; charset=UTF-8\r\n" +
+		"\r\n" + FormatAlert(alert) + "\r\n"
+
+	var auth smtp.Auth
+	if config.Username != "" {
+		auth = smtp.PlainAuth("", config.Username, config.Password, config.Host)
+	}
+
+	addr := config.Host + ":" + strconv.Itoa(config.Port)
+	if err := smtp.SendMail(addr, auth, config.From, recipients, []byte(message)); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,426 @@
+package notify
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"flowpulse/pkg/database"
+	"flowpulse/pkg/models"
+)
+
+// regionalOutageWindow is how far back the locations checking an API are compared, so that a location
+// that stopped checking it no longer counts
+const regionalOutageWindow = time.Hour
+
+// Service evaluates alert rules after each execution and delivers notifications
+type Service struct {
+	db     database.Store
+	client *http.Client
+	mu     sync.Mutex // Serializes incident updates between concurrent executions
+}
+
+// NewService creates a new notification service
+func NewService(db database.Store) *Service {
+	return &Service{
+		db: db,
+		client: &http.Client{
+			Timeout: 15 * time.Second,
+		},
+	}
+}
+
+// HandleExecution evaluates the alert rules of the execution's schedule and sends any resulting notifications,
+// including a slow alert when checks start exceeding the API's critical latency threshold, a content alert
+// when the response body changed from the API's baseline and a regional alert when the API starts failing
+// from one location while others still reach it. It also resolves the API's open incident on success, opens one once the API failed enough times in a row
+// or a failure alert fires, and holds back notifications for acknowledged incidents and snoozed APIs.
+// It is meant to be called asynchronously after the execution has been logged.
+func (s *Service) HandleExecution(api models.API, execution models.ExecutionLog) {
+	if execution.ScheduleID == 0 {
+		return // Manual runs have no alert rules
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	incident, hasIncident, err := s.db.GetOpenIncidentByAPIID(api.ID)
+	if err != nil {
+		log.Printf("Failed to load open incident for API %d: %v", api.ID, err)
+		return
+	}
+	if hasIncident && execution.Status != models.ExecutionStatusFailure {
+		if err := s.db.ResolveIncident(incident.ID); err != nil {
+			log.Printf("Failed to resolve incident %d: %v", incident.ID, err)
+		}
+	}
+	if !hasIncident && execution.Status == models.ExecutionStatusFailure {
+		opened, err := s.failingLongEnough(api.ID)
+		if err != nil {
+			log.Printf("Failed to count consecutive failures of API %d: %v", api.ID, err)
+		} else if opened {
+			if incident, err = s.db.CreateIncident(api.ID); err != nil {
+				log.Printf("Failed to open incident for API %d: %v", api.ID, err)
+			}
+			hasIncident = true
+		}
+	}
+
+	rules, err := s.db.GetAlertRulesByScheduleID(execution.ScheduleID)
+	if err != nil {
+		log.Printf("Failed to load alert rules for schedule ID %d: %v", execution.ScheduleID, err)
+		return
+	}
+
+	snoozedUntil, err := s.db.GetAlertSnoozedUntil(api.ID)
+	if err != nil {
+		log.Printf("Failed to load alert snooze for API %d: %v", api.ID, err)
+	}
+	snoozed := time.Now().Before(snoozedUntil)
+
+	// Slow and content alerts go to every active rule, apart from the failure and recovery alerts they evaluate
+	var executionAlerts []models.Alert
+	slow, err := s.startsSlowStreak(execution)
+	if err != nil {
+		log.Printf("Failed to load latency breaches of schedule ID %d: %v", execution.ScheduleID, err)
+	}
+	if slow {
+		executionAlerts = append(executionAlerts, executionAlert(models.AlertSlow, api, execution, execution.Error))
+	}
+	if execution.ContentChanged {
+		executionAlerts = append(executionAlerts, executionAlert(models.AlertContentChanged, api, execution,
+			"The response body changed since the previous check"))
+	}
+	regional, err := s.startsRegionalOutage(execution)
+	if err != nil {
+		log.Printf("Failed to compare locations of API %d: %v", api.ID, err)
+	}
+	if regional != nil {
+		executionAlerts = append(executionAlerts, executionAlert(models.AlertRegionalOutage, api, execution,
+			fmt.Sprintf("Failing from %s while %s still succeed", strings.Join(regional.FailingLocations, ", "),
+				strings.Join(succeedingLocations(*regional), ", "))))
+	}
+
+	for _, rule := range rules {
+		if !rule.IsActive {
+			continue
+		}
+
+		if len(executionAlerts) > 0 && !snoozed {
+			if channelID, err := s.ruleChannelID(rule); err != nil {
+				log.Printf("Failed to resolve on-call recipient for alert rule %d: %v", rule.ID, err)
+			} else {
+				for _, alert := range executionAlerts {
+					s.deliver(channelID, alert)
+				}
+			}
+		}
+
+		kind, failures, err := s.evaluateRule(rule, execution)
+		if err != nil {
+			log.Printf("Failed to evaluate alert rule %d: %v", rule.ID, err)
+			continue
+		}
+		if kind == "" {
+			continue
+		}
+
+		// A firing failure alert opens an incident; once it's acknowledged further failure alerts are suppressed
+		if kind == models.AlertFailure {
+			if !hasIncident {
+				incident, err = s.db.CreateIncident(api.ID)
+				if err != nil {
+					log.Printf("Failed to open incident for API %d: %v", api.ID, err)
+				}
+				hasIncident = true
+			} else if incident.Status == models.IncidentAcknowledged {
+				continue
+			}
+		}
+		if snoozed {
+			continue
+		}
+
+		alert := models.Alert{
+			Kind:                kind,
+			APIID:               api.ID,
+			APIName:             api.Name,
+			URL:                 api.URL,
+			ScheduleID:          execution.ScheduleID,
+			StatusCode:          execution.StatusCode,
+			Error:               execution.Error,
+			ConsecutiveFailures: failures,
+			ExecutedAt:          execution.ExecutedAt,
+			RunbookURL:          api.RunbookURL,
+			Notes:               api.Notes,
+		}
+		channelID, err := s.ruleChannelID(rule)
+		if err != nil {
+			log.Printf("Failed to resolve on-call recipient for alert rule %d: %v", rule.ID, err)
+			continue
+		}
+		s.deliver(channelID, alert)
+	}
+}
+
+// executionAlert builds an alert of the given kind about an execution of an API
+func executionAlert(kind string, api models.API, execution models.ExecutionLog, message string) models.Alert {
+	return models.Alert{
+		Kind:       kind,
+		APIID:      api.ID,
+		APIName:    api.Name,
+		URL:        api.URL,
+		ScheduleID: execution.ScheduleID,
+		StatusCode: execution.StatusCode,
+		Error:      message,
+		ExecutedAt: execution.ExecutedAt,
+		RunbookURL: api.RunbookURL,
+		Notes:      api.Notes,
+	}
+}
+
+// startsSlowStreak reports whether the execution exceeded its API's critical latency threshold while the
+// schedule's execution before it didn't, so that a slow API is alerted once rather than on every check
+func (s *Service) startsSlowStreak(execution models.ExecutionLog) (bool, error) {
+	if execution.LatencyBreach != models.LatencyBreachCritical {
+		return false, nil
+	}
+	// The execution is logged already, so the previous one comes second
+	breaches, err := s.db.GetRecentLatencyBreachesByScheduleID(execution.ScheduleID, 2)
+	if err != nil {
+		return false, err
+	}
+	return len(breaches) < 2 || breaches[1] != models.LatencyBreachCritical, nil
+}
+
+// startsRegionalOutage returns the comparison of the API's locations when the execution is the first failure in
+// a row from its location and only some of the locations checking the API are failing, so that a regional
+// outage is alerted once per location rather than on every check. It returns nil otherwise.
+func (s *Service) startsRegionalOutage(execution models.ExecutionLog) (*models.LocationComparison, error) {
+	if execution.Status != models.ExecutionStatusFailure {
+		return nil, nil
+	}
+	location := execution.Location
+	if location == "" {
+		location = models.LocationLocal
+	}
+	// The execution is logged already, so the previous one from the location comes second
+	statuses, err := s.db.GetRecentScheduledStatusesByLocation(execution.APIID, location, 2)
+	if err != nil {
+		return nil, err
+	}
+	if len(statuses) == 2 && statuses[1] == models.ExecutionStatusFailure {
+		return nil, nil
+	}
+
+	comparison, err := s.db.GetLocationComparison(execution.APIID, time.Now().Add(-regionalOutageWindow))
+	if err != nil {
+		return nil, err
+	}
+	if comparison.OutageScope != models.OutageScopeRegional {
+		return nil, nil
+	}
+	return &comparison, nil
+}
+
+// succeedingLocations returns the locations of a comparison whose latest check didn't fail
+func succeedingLocations(comparison models.LocationComparison) []string {
+	failing := make(map[string]bool, len(comparison.FailingLocations))
+	for _, location := range comparison.FailingLocations {
+		failing[location] = true
+	}
+	var locations []string
+	for _, stats := range comparison.Locations {
+		if !failing[stats.Location] {
+			locations = append(locations, stats.Location)
+		}
+	}
+	return locations
+}
+
+// failingLongEnough reports whether the latest scheduled checks of an API failed as many times in a row
+// as the incident policy takes to open an incident
+func (s *Service) failingLongEnough(apiID int) (bool, error) {
+	policy, err := s.db.GetIncidentPolicy()
+	if err != nil {
+		return false, err
+	}
+	threshold := policy.FailureThreshold
+	if threshold <= 0 {
+		threshold = models.DefaultIncidentFailureThreshold
+	}
+
+	statuses, err := s.db.GetRecentScheduledStatusesByAPIID(apiID, threshold)
+	if err != nil {
+		return false, err
+	}
+	if len(statuses) < threshold {
+		return false, nil
+	}
+	for _, status := range statuses {
+		if status != models.ExecutionStatusFailure {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// HandleStaleCheck alerts the channels of the schedule's active alert rules that its job stopped running.
+// Snoozed APIs are not alerted.
+func (s *Service) HandleStaleCheck(api models.API, check models.StaleCheck) {
+	snoozedUntil, err := s.db.GetAlertSnoozedUntil(api.ID)
+	if err != nil {
+		log.Printf("Failed to load alert snooze for API %d: %v", api.ID, err)
+	}
+	if time.Now().Before(snoozedUntil) {
+		return
+	}
+
+	rules, err := s.db.GetAlertRulesByScheduleID(check.ScheduleID)
+	if err != nil {
+		log.Printf("Failed to load alert rules for schedule ID %d: %v", check.ScheduleID, err)
+		return
+	}
+
+	message := fmt.Sprintf("Its %s schedule %q has no job", check.Type, check.Expression)
+	if check.Scheduled {
+		message = fmt.Sprintf("No check has run since %s, %v after its %s schedule %q was due",
+			check.LastRunAt.Format(time.RFC3339), time.Duration(check.OverdueSeconds)*time.Second, check.Type, check.Expression)
+	}
+	alert := models.Alert{
+		Kind:       models.AlertStale,
+		APIID:      api.ID,
+		APIName:    api.Name,
+		URL:        api.URL,
+		ScheduleID: check.ScheduleID,
+		Error:      message,
+		ExecutedAt: check.LastRunAt,
+		RunbookURL: api.RunbookURL,
+		Notes:      api.Notes,
+	}
+	for _, rule := range rules {
+		if !rule.IsActive {
+			continue
+		}
+		channelID, err := s.ruleChannelID(rule)
+		if err != nil {
+			log.Printf("Failed to resolve on-call recipient for alert rule %d: %v", rule.ID, err)
+			continue
+		}
+		s.deliver(channelID, alert)
+	}
+}
+
+// HandleIngestion alerts a channel that the log ingestion guard started sampling successes, or stopped when
+// sampling is false. A channel ID of 0 alerts nobody.
+func (s *Service) HandleIngestion(channelID int, status models.IngestionStatus, sampling bool) {
+	if channelID == 0 {
+		return
+	}
+	kind := models.AlertIngestion
+	message := fmt.Sprintf("%d logs (%d bytes) arrived since %s", status.Logs, status.Bytes, status.WindowStart.Format(time.RFC3339))
+	if !sampling {
+		kind = models.AlertIngestionNormal
+		message = fmt.Sprintf("%d successful checks were left out of the logs while sampling", status.SkippedLogs)
+	}
+	s.deliver(channelID, models.Alert{
+		Kind:       kind,
+		Error:      message,
+		ExecutedAt: time.Now(),
+	})
+}
+
+// HandleLatencyTarget alerts the channels of the active alert rules of a collection's schedules that one of
+// its latency targets was breached, or is met again when breached is false. Each channel is alerted once.
+func (s *Service) HandleLatencyTarget(collection models.Collection, result models.LatencyTargetResult, breached bool) {
+	rules, err := s.db.GetAlertRulesByCollectionID(collection.ID)
+	if err != nil {
+		log.Printf("Failed to load alert rules for collection ID %d: %v", collection.ID, err)
+		return
+	}
+
+	kind, verb := models.AlertLatency, "breached"
+	if !breached {
+		kind, verb = models.AlertLatencyRecovery, "met again"
+	}
+	alert := models.Alert{
+		Kind:           kind,
+		CollectionID:   collection.ID,
+		CollectionName: collection.Name,
+		Error: fmt.Sprintf("p%g latency target of %dms %s: %dms over the last %dh (%d executions)",
+			result.Target.Percentile, result.Target.ThresholdMs, verb, result.ActualMs, result.Target.WindowHours, result.Executions),
+		ExecutedAt: time.Now(),
+	}
+
+	alerted := make(map[int]bool)
+	for _, rule := range rules {
+		if !rule.IsActive {
+			continue
+		}
+		channelID, err := s.ruleChannelID(rule)
+		if err != nil {
+			log.Printf("Failed to resolve on-call recipient for alert rule %d: %v", rule.ID, err)
+			continue
+		}
+		if alerted[channelID] {
+			continue
+		}
+		alerted[channelID] = true
+		s.deliver(channelID, alert)
+	}
+}
+
+// evaluateRule decides whether the latest execution triggers the rule. It fires once when the
+// consecutive failure streak reaches the threshold, and once on the first success after such a streak.
+func (s *Service) evaluateRule(rule models.AlertRule, execution models.ExecutionLog) (string, int, error) {
+	threshold := rule.FailureThreshold
+	if threshold < 1 {
+		threshold = 1
+	}
+
+	statuses, err := s.db.GetRecentStatusesByScheduleID(execution.ScheduleID, threshold+1)
+	if err != nil {
+		return "", 0, err
+	}
+
+	if execution.Status != models.ExecutionStatusFailure {
+		// Recovery: the runs before this one form a streak that had reached the threshold
+		if !rule.NotifyOnRecovery || len(statuses) < threshold+1 {
+			return "", 0, nil
+		}
+		for _, status := range statuses[1 : threshold+1] {
+			if status != models.ExecutionStatusFailure {
+				return "", 0, nil
+			}
+		}
+		return models.AlertRecovery, threshold, nil
+	}
+
+	// Failure: exactly threshold failures in a row, preceded by a non-failure or nothing at all
+	failures := 0
+	for _, status := range statuses {
+		if status != models.ExecutionStatusFailure {
+			break
+		}
+		failures++
+	}
+	if failures == threshold {
+		return models.AlertFailure, failures, nil
+	}
+	return "", 0, nil
+}
+
+// deliver sends an alert through a channel, logging delivery errors
+func (s *Service) deliver(channelID int, alert models.Alert) {
+	channel, err := s.db.GetNotificationChannelByID(channelID)
+	if err != nil {
+		log.Printf("Failed to load notification channel %d: %v", channelID, err)
+		return
+	}
+	if err := s.Send(channel, alert); err != nil {
+		log.Printf("Failed to send %s alert for API %d via channel %s: %v", alert.Kind, alert.APIID, channel.Name, err)
+	}
+}
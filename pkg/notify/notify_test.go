@@ -0,0 +1,61 @@
+package notify
+
+import (
+	"path/filepath"
+	"testing"
+
+	"flowpulse/pkg/database"
+	"flowpulse/pkg/models"
+)
+
+// TestStartsRegionalOutage checks that a location starting to fail while others succeed is alerted once,
+// and that a failure seen from every location is not regional
+func TestStartsRegionalOutage(t *testing.T) {
+	db, err := database.NewDBServiceWithPath(filepath.Join(t.TempDir(), "flowpulse.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	service := NewService(db)
+
+	api, err := db.CreateAPI(models.API{Name: "Orders", URL: "https://example.com/orders", Method: "GET"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	schedule, err := db.CreateSchedule(models.Schedule{APIID: api.ID, Type: "interval", Expression: "60"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	check := func(location, status string) models.ExecutionLog {
+		t.Helper()
+		execution, err := db.CreateExecutionLog(models.ExecutionLog{
+			APIID: api.ID, ScheduleID: schedule.ID, Status: status, Location: location,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return execution
+	}
+
+	check("eu-west", models.ExecutionStatusSuccess)
+	check("us-east", models.ExecutionStatusSuccess)
+
+	comparison, err := service.startsRegionalOutage(check("us-east", models.ExecutionStatusFailure))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if comparison == nil {
+		t.Fatal("first failure from us-east while eu-west succeeds was not regional")
+	}
+	if got := succeedingLocations(*comparison); len(got) != 1 || got[0] != "eu-west" {
+		t.Errorf("succeeding locations = %v, want [eu-west]", got)
+	}
+
+	if comparison, err := service.startsRegionalOutage(check("us-east", models.ExecutionStatusFailure)); err != nil || comparison != nil {
+		t.Errorf("second failure in a row from us-east alerted again (comparison %v, err %v)", comparison, err)
+	}
+
+	if comparison, err := service.startsRegionalOutage(check("eu-west", models.ExecutionStatusFailure)); err != nil || comparison != nil {
+		t.Errorf("failure from every location was alerted as regional (comparison %v, err %v)", comparison, err)
+	}
+}
@@ -0,0 +1,45 @@
+package notify
+
+import (
+	"fmt"
+	"time"
+
+	"flowpulse/pkg/models"
+)
+
+// rotationPeriod is how long each user in a rotation stays on call
+const rotationPeriod = 7 * 24 * time.Hour
+
+// CurrentOnCall returns the ID of the user on call in a rotation at the given time.
+// Before the rotation starts the first user is on call.
+func CurrentOnCall(rotation models.OnCallRotation, at time.Time) (int, error) {
+	if len(rotation.UserIDs) == 0 {
+		return 0, fmt.Errorf("rotation %s has no users", rotation.Name)
+	}
+	if at.Before(rotation.StartsAt) {
+		return rotation.UserIDs[0], nil
+	}
+	weeks := int(at.Sub(rotation.StartsAt) / rotationPeriod)
+	return rotation.UserIDs[weeks%len(rotation.UserIDs)], nil
+}
+
+// ruleChannelID resolves the channel an alert rule delivers to, following its rotation when it has one
+func (s *Service) ruleChannelID(rule models.AlertRule) (int, error) {
+	if rule.RotationID == 0 {
+		return rule.ChannelID, nil
+	}
+
+	rotation, err := s.db.GetOnCallRotationByID(rule.RotationID)
+	if err != nil {
+		return 0, err
+	}
+	userID, err := CurrentOnCall(rotation, time.Now())
+	if err != nil {
+		return 0, err
+	}
+	user, err := s.db.GetOnCallUserByID(userID)
+	if err != nil {
+		return 0, err
+	}
+	return user.ChannelID, nil
+}
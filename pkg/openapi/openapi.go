@@ -0,0 +1,165 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// methods are the HTTP methods that can appear as operations in a path item
+var methods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// Document is the subset of an OpenAPI 3.x or Swagger 2.0 document needed to import operations
+// and validate responses. Only JSON documents are supported.
+type Document struct {
+	OpenAPI string `json:"openapi"`
+	Swagger string `json:"swagger"`
+	Info    struct {
+		Title string `json:"title"`
+	} `json:"info"`
+	Servers []struct {
+		URL string `json:"url"`
+	} `json:"servers"`
+	Host        string                                `json:"host"`     // Swagger 2.0
+	BasePath    string                                `json:"basePath"` // Swagger 2.0
+	Schemes     []string                              `json:"schemes"`  // Swagger 2.0
+	Paths       map[string]map[string]json.RawMessage `json:"paths"`
+	Definitions map[string]*Schema                    `json:"definitions"` // Swagger 2.0
+	Components  struct {
+		Schemas map[string]*Schema `json:"schemas"`
+	} `json:"components"`
+}
+
+// Operation is a single method on a path
+type Operation struct {
+	Method      string              `json:"-"`
+	Path        string              `json:"-"`
+	OperationID string              `json:"operationId"`
+	Summary     string              `json:"summary"`
+	Description string              `json:"description"`
+	Responses   map[string]Response `json:"responses"`
+}
+
+// Response describes one documented response of an operation
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content"` // OpenAPI 3.x
+	Schema      *Schema              `json:"schema"`  // Swagger 2.0
+}
+
+// MediaType holds the schema of one response content type
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// Parse decodes an OpenAPI document
+func Parse(data []byte) (*Document, error) {
+	trimmed := strings.TrimSpace(string(data))
+	if !strings.HasPrefix(trimmed, "{") {
+		return nil, fmt.Errorf("only JSON OpenAPI documents are supported; convert YAML specs to JSON first")
+	}
+
+	var doc Document
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse OpenAPI document: %w", err)
+	}
+	if doc.OpenAPI == "" && doc.Swagger == "" {
+		return nil, fmt.Errorf("document is missing the openapi or swagger version field")
+	}
+	if len(doc.Paths) == 0 {
+		return nil, fmt.Errorf("document has no paths")
+	}
+	return &doc, nil
+}
+
+// OperationKey identifies an operation by method and path template, e.g. "GET /pets/{id}"
+func OperationKey(method, path string) string {
+	return strings.ToUpper(method) + " " + path
+}
+
+// Key returns the operation's key
+func (op Operation) Key() string {
+	return OperationKey(op.Method, op.Path)
+}
+
+// Operations returns every operation in the document, sorted by path and method
+func (d *Document) Operations() []Operation {
+	var operations []Operation
+	for path, item := range d.Paths {
+		for _, method := range methods {
+			raw, ok := item[method]
+			if !ok {
+				continue
+			}
+			var op Operation
+			if err := json.Unmarshal(raw, &op); err != nil {
+				continue // Malformed operations are skipped rather than failing the whole import
+			}
+			op.Method = strings.ToUpper(method)
+			op.Path = path
+			operations = append(operations, op)
+		}
+	}
+
+	sort.Slice(operations, func(i, j int) bool {
+		if operations[i].Path != operations[j].Path {
+			return operations[i].Path < operations[j].Path
+		}
+		return operations[i].Method < operations[j].Method
+	})
+	return operations
+}
+
+// Operation finds an operation by its key
+func (d *Document) Operation(key string) (Operation, bool) {
+	for _, op := range d.Operations() {
+		if op.Key() == key {
+			return op, true
+		}
+	}
+	return Operation{}, false
+}
+
+// BaseURL returns the URL the document's paths are relative to
+func (d *Document) BaseURL() string {
+	if len(d.Servers) > 0 {
+		return strings.TrimSuffix(d.Servers[0].URL, "/")
+	}
+	if d.Host != "" {
+		scheme := "https"
+		if len(d.Schemes) > 0 {
+			scheme = d.Schemes[0]
+		}
+		return scheme + "://" + d.Host + strings.TrimSuffix(d.BasePath, "/")
+	}
+	return strings.TrimSuffix(d.BasePath, "/")
+}
+
+// ResponseFor finds the documented response for a status code, trying the exact code,
+// then its range (e.g. "2XX"), then "default"
+func (op Operation) ResponseFor(statusCode int) (Response, bool) {
+	code := fmt.Sprint(statusCode)
+	for _, key := range []string{code, code[:1] + "XX", code[:1] + "xx", "default"} {
+		if response, ok := op.Responses[key]; ok {
+			return response, true
+		}
+	}
+	return Response{}, false
+}
+
+// JSONSchema returns the schema of the response's JSON body, if it documents one
+func (r Response) JSONSchema() *Schema {
+	if r.Schema != nil {
+		return r.Schema
+	}
+	if media, ok := r.Content["application/json"]; ok {
+		return media.Schema
+	}
+	for contentType, media := range r.Content {
+		if strings.Contains(contentType, "json") {
+			return media.Schema
+		}
+	}
+	return nil
+}
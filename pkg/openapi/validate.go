@@ -0,0 +1,241 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// maxViolations caps how many violations are reported for a single response
+const maxViolations = 20
+
+// maxRefDepth guards against circular $ref chains
+const maxRefDepth = 32
+
+// Schema is the subset of JSON Schema used to validate response bodies
+type Schema struct {
+	Ref                  string             `json:"$ref"`
+	Type                 schemaType         `json:"type"`
+	Properties           map[string]*Schema `json:"properties"`
+	Required             []string           `json:"required"`
+	Items                *Schema            `json:"items"`
+	Enum                 []interface{}      `json:"enum"`
+	Nullable             bool               `json:"nullable"`
+	AllOf                []*Schema          `json:"allOf"`
+	AnyOf                []*Schema          `json:"anyOf"`
+	OneOf                []*Schema          `json:"oneOf"`
+	AdditionalProperties json.RawMessage    `json:"additionalProperties"`
+}
+
+// schemaType accepts both "type": "string" and the OpenAPI 3.1 form "type": ["string", "null"]
+type schemaType []string
+
+func (t *schemaType) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*t = schemaType{single}
+		return nil
+	}
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return err
+	}
+	*t = list
+	return nil
+}
+
+// ValidateResponse checks a response against the operation's documented responses and
+// returns the contract violations found (none when the response conforms)
+func (d *Document) ValidateResponse(operationKey string, statusCode int, body string) []string {
+	op, ok := d.Operation(operationKey)
+	if !ok {
+		return []string{fmt.Sprintf("operation %s is not in the spec", operationKey)}
+	}
+
+	response, ok := op.ResponseFor(statusCode)
+	if !ok {
+		return []string{fmt.Sprintf("status %d is not documented for %s", statusCode, operationKey)}
+	}
+
+	schema := response.JSONSchema()
+	if schema == nil {
+		return nil
+	}
+
+	var value interface{}
+	if err := json.Unmarshal([]byte(body), &value); err != nil {
+		return []string{fmt.Sprintf("response is not valid JSON: %v", err)}
+	}
+
+	var violations []string
+	d.validate(value, schema, "$", 0, &violations)
+	return violations
+}
+
+// resolve follows $ref pointers into components/schemas or definitions
+func (d *Document) resolve(schema *Schema) (*Schema, error) {
+	for depth := 0; schema != nil && schema.Ref != ""; depth++ {
+		if depth > maxRefDepth {
+			return nil, fmt.Errorf("$ref chain too deep at %s", schema.Ref)
+		}
+		var target *Schema
+		switch {
+		case strings.HasPrefix(schema.Ref, "#/components/schemas/"):
+			target = d.Components.Schemas[strings.TrimPrefix(schema.Ref, "#/components/schemas/")]
+		case strings.HasPrefix(schema.Ref, "#/definitions/"):
+			target = d.Definitions[strings.TrimPrefix(schema.Ref, "#/definitions/")]
+		}
+		if target == nil {
+			return nil, fmt.Errorf("unresolved $ref %s", schema.Ref)
+		}
+		schema = target
+	}
+	return schema, nil
+}
+
+func (d *Document) validate(value interface{}, schema *Schema, path string, depth int, violations *[]string) {
+	if len(*violations) >= maxViolations {
+		return
+	}
+	report := func(format string, args ...interface{}) {
+		*violations = append(*violations, path+": "+fmt.Sprintf(format, args...))
+	}
+
+	schema, err := d.resolve(schema)
+	if err != nil {
+		report("%v", err)
+		return
+	}
+	if schema == nil || depth > maxRefDepth {
+		return
+	}
+
+	for _, sub := range schema.AllOf {
+		d.validate(value, sub, path, depth+1, violations)
+	}
+	if len(schema.AnyOf) > 0 && d.matchCount(value, schema.AnyOf, path, depth) == 0 {
+		report("does not match any of the allowed schemas")
+	}
+	if len(schema.OneOf) > 0 && d.matchCount(value, schema.OneOf, path, depth) != 1 {
+		report("does not match exactly one of the allowed schemas")
+	}
+
+	if value == nil {
+		if schema.Nullable || len(schema.Type) == 0 || schema.Type.allows("null") {
+			return
+		}
+		report("is null, expected %s", strings.Join(schema.Type, " or "))
+		return
+	}
+
+	if len(schema.Type) > 0 && !schema.Type.matches(value) {
+		report("is %s, expected %s", jsonType(value), strings.Join(schema.Type, " or "))
+		return
+	}
+
+	if len(schema.Enum) > 0 && !inEnum(value, schema.Enum) {
+		report("value %s is not one of the allowed values", encode(value))
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for _, name := range schema.Required {
+			if _, ok := v[name]; !ok {
+				report("missing required property %q", name)
+			}
+		}
+		names := make([]string, 0, len(v))
+		for name := range v {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if property, ok := schema.Properties[name]; ok {
+				d.validate(v[name], property, path+"."+name, depth+1, violations)
+			} else if strings.TrimSpace(string(schema.AdditionalProperties)) == "false" {
+				report("unexpected property %q", name)
+			}
+		}
+	case []interface{}:
+		if schema.Items != nil {
+			for i, item := range v {
+				d.validate(item, schema.Items, fmt.Sprintf("%s[%d]", path, i), depth+1, violations)
+			}
+		}
+	}
+}
+
+// matchCount returns how many of the schemas the value conforms to
+func (d *Document) matchCount(value interface{}, schemas []*Schema, path string, depth int) int {
+	matches := 0
+	for _, sub := range schemas {
+		var subViolations []string
+		d.validate(value, sub, path, depth+1, &subViolations)
+		if len(subViolations) == 0 {
+			matches++
+		}
+	}
+	return matches
+}
+
+func (t schemaType) allows(name string) bool {
+	for _, allowed := range t {
+		if allowed == name {
+			return true
+		}
+	}
+	return false
+}
+
+// matches reports whether a decoded JSON value has one of the schema types
+func (t schemaType) matches(value interface{}) bool {
+	actual := jsonType(value)
+	if t.allows(actual) {
+		return true
+	}
+	// JSON numbers decode as float64, so integers are numbers without a fractional part
+	if actual == "number" && t.allows("integer") {
+		n := value.(float64)
+		return n == math.Trunc(n)
+	}
+	return false
+}
+
+func jsonType(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+func inEnum(value interface{}, enum []interface{}) bool {
+	encoded := encode(value)
+	for _, allowed := range enum {
+		if encode(allowed) == encoded {
+			return true
+		}
+	}
+	return false
+}
+
+func encode(value interface{}) string {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(encoded)
+}
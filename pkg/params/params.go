@@ -0,0 +1,81 @@
+package params
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"flowpulse/pkg/models"
+)
+
+// BuildURL returns the URL an API is requested at: its path variables filled in and its enabled
+// query parameters appended to any query already in the URL. Path segments written as :name or
+// {name} are replaced by the enabled path variable of that name; others are left as they are.
+func BuildURL(api models.API) (string, error) {
+	if len(api.QueryParams) == 0 && len(api.PathParams) == 0 {
+		return api.URL, nil
+	}
+
+	u, err := url.Parse(api.URL)
+	if err != nil {
+		return "", fmt.Errorf("invalid URL: %w", err)
+	}
+
+	if values := enabledValues(api.PathParams); len(values) > 0 {
+		segments := strings.Split(u.EscapedPath(), "/")
+		for i, segment := range segments {
+			if value, ok := values[variableName(segment)]; ok {
+				segments[i] = url.PathEscape(value)
+			}
+		}
+		escaped := strings.Join(segments, "/")
+		if u.Path, err = url.PathUnescape(escaped); err != nil {
+			return "", fmt.Errorf("invalid URL path: %w", err)
+		}
+		u.RawPath = escaped
+	}
+
+	// Append rather than re-encode so the order and encoding of existing parameters are kept
+	var query []string
+	if u.RawQuery != "" {
+		query = append(query, u.RawQuery)
+	}
+	for _, param := range api.QueryParams {
+		if param.Enabled && param.Key != "" {
+			query = append(query, url.QueryEscape(param.Key)+"="+url.QueryEscape(param.Value))
+		}
+	}
+	u.RawQuery = strings.Join(query, "&")
+
+	return u.String(), nil
+}
+
+// enabledValues returns the values of the enabled params by key
+func enabledValues(params []models.Param) map[string]string {
+	values := make(map[string]string)
+	for _, param := range params {
+		if param.Enabled && param.Key != "" {
+			values[param.Key] = param.Value
+		}
+	}
+	return values
+}
+
+// variableName returns the name of a :name or {name} path segment, or "" for a literal segment
+func variableName(segment string) string {
+	if strings.HasPrefix(segment, ":") {
+		return segment[1:]
+	}
+	// Escaped braces, as url.EscapedPath writes them
+	if name, ok := strings.CutPrefix(segment, "%7B"); ok {
+		if name, ok = strings.CutSuffix(name, "%7D"); ok {
+			return name
+		}
+	}
+	if name, ok := strings.CutPrefix(segment, "{"); ok {
+		if name, ok = strings.CutSuffix(name, "}"); ok {
+			return name
+		}
+	}
+	return ""
+}
@@ -0,0 +1,176 @@
+package profiles
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"flowpulse/pkg/database"
+	"flowpulse/pkg/models"
+)
+
+// DefaultName is the profile that uses the database in the app directory
+const DefaultName = "default"
+
+// configFile is the file in the app directory listing the profiles
+const configFile = "profiles.json"
+
+// validName restricts profile names to characters that are safe in a file name
+var validName = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+
+// Config lists the profiles and which one is used
+type Config struct {
+	Active   string           `json:"active"`
+	Profiles []models.Profile `json:"profiles"`
+}
+
+// Load reads the profile configuration. Without a configuration file only the default profile exists.
+func Load() (Config, error) {
+	config := Config{Active: DefaultName}
+
+	path, err := configPath()
+	if err != nil {
+		return config, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return config, fmt.Errorf("failed to read profiles: %w", err)
+	}
+	if err == nil {
+		if err := json.Unmarshal(data, &config); err != nil {
+			return config, fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+	}
+
+	if _, ok := config.Find(DefaultName); !ok {
+		config.Profiles = append([]models.Profile{{Name: DefaultName}}, config.Profiles...)
+	}
+	if _, ok := config.Find(config.Active); !ok {
+		config.Active = DefaultName
+	}
+	return config, nil
+}
+
+// Save writes the profile configuration
+func (c Config) Save() error {
+	path, err := configPath()
+	if err != nil {
+		return err
+	}
+
+	// Which profile is active is only stored once, not per profile
+	type storedProfile struct {
+		Name   string `json:"name"`
+		DBPath string `json:"dbPath,omitempty"`
+	}
+	stored := struct {
+		Active   string          `json:"active"`
+		Profiles []storedProfile `json:"profiles"`
+	}{Active: c.Active}
+	for _, profile := range c.Profiles {
+		stored.Profiles = append(stored.Profiles, storedProfile{Name: profile.Name, DBPath: profile.DBPath})
+	}
+	data, err := json.MarshalIndent(stored, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode profiles: %w", err)
+	}
+
+	// Write to a temporary file first so a crash can't leave a truncated configuration behind
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("failed to write profiles: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to write profiles: %w", err)
+	}
+	return nil
+}
+
+// List returns the profiles, marking the active one
+func (c Config) List() []models.Profile {
+	profiles := make([]models.Profile, len(c.Profiles))
+	for i, profile := range c.Profiles {
+		profile.Active = profile.Name == c.Active
+		profiles[i] = profile
+	}
+	return profiles
+}
+
+// Find returns the profile with the given name
+func (c Config) Find(name string) (models.Profile, bool) {
+	for _, profile := range c.Profiles {
+		if profile.Name == name {
+			profile.Active = profile.Name == c.Active
+			return profile, true
+		}
+	}
+	return models.Profile{}, false
+}
+
+// Add adds a profile, optionally with its own database path
+func (c *Config) Add(name, dbPath string) (models.Profile, error) {
+	if !validName.MatchString(name) {
+		return models.Profile{}, fmt.Errorf("profile name may only contain letters, digits, '_', '.' and '-'")
+	}
+	if _, ok := c.Find(name); ok {
+		return models.Profile{}, fmt.Errorf("profile %q already exists", name)
+	}
+
+	profile := models.Profile{Name: name, DBPath: strings.TrimSpace(dbPath)}
+	c.Profiles = append(c.Profiles, profile)
+	return profile, nil
+}
+
+// Remove removes a profile. The default and the active profile can't be removed, and the
+// profile's database is left in place.
+func (c *Config) Remove(name string) error {
+	if name == DefaultName {
+		return fmt.Errorf("the default profile can't be deleted")
+	}
+	if name == c.Active {
+		return fmt.Errorf("switch to another profile before deleting %q", name)
+	}
+	for i, profile := range c.Profiles {
+		if profile.Name == name {
+			c.Profiles = append(c.Profiles[:i], c.Profiles[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("profile %q not found", name)
+}
+
+// DBPath returns the database file of a profile. Profiles without a configured path keep
+// their database under profiles/ in the app directory; the default one uses the default database.
+func DBPath(profile models.Profile) (string, error) {
+	if profile.DBPath != "" {
+		if rest, ok := strings.CutPrefix(profile.DBPath, "~/"); ok {
+			homeDir, err := os.UserHomeDir()
+			if err != nil {
+				return "", fmt.Errorf("failed to get user home directory: %w", err)
+			}
+			return filepath.Join(homeDir, rest), nil
+		}
+		return profile.DBPath, nil
+	}
+	if profile.Name == DefaultName {
+		return database.DefaultDBPath()
+	}
+
+	appDir, err := database.AppDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(appDir, "profiles", profile.Name+".db"), nil
+}
+
+// configPath returns the path of the profile configuration file
+func configPath() (string, error) {
+	appDir, err := database.AppDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(appDir, configFile), nil
+}
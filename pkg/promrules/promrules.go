@@ -0,0 +1,237 @@
+// Package promrules renders FlowPulse alert rules as a Prometheus rule file, so teams moving to a central
+// alerting stack keep the thresholds they tuned in FlowPulse. The rules alert on the probe_success metric of
+// the Prometheus blackbox exporter probing each API's URL, and route to Alertmanager receivers by the name of
+// the notification channel or on-call rotation in their labels.
+package promrules
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"flowpulse/pkg/database"
+	"flowpulse/pkg/models"
+	"flowpulse/pkg/scheduler"
+)
+
+// GroupName is the name of the rule group holding the generated rules
+const GroupName = "flowpulse"
+
+// Rule is a Prometheus alerting rule generated from a FlowPulse alert rule
+type Rule struct {
+	Alert       string
+	Expr        string
+	For         time.Duration // 0 to fire on the first failed probe
+	Labels      [][2]string   // In the order they are written
+	Annotations [][2]string
+}
+
+// Skipped is an alert rule that has no Prometheus equivalent, with the reason why
+type Skipped struct {
+	AlertRuleID int
+	Reason      string
+}
+
+// Build converts the active alert rules to Prometheus rules. A FlowPulse rule fires after FailureThreshold
+// consecutive failures, which becomes a "for" duration of that many schedule intervals after the first one.
+// Rules of one-time schedules, inactive schedules and APIs in the trash are skipped.
+func Build(db database.Store, now time.Time) ([]Rule, []Skipped, error) {
+	alertRules, err := db.GetAllAlertRules()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var rules []Rule
+	var skipped []Skipped
+	for _, alertRule := range alertRules {
+		if !alertRule.IsActive {
+			continue
+		}
+		schedule, err := db.GetScheduleByID(alertRule.ScheduleID)
+		if err != nil {
+			return nil, nil, err
+		}
+		api, err := db.GetAPIByID(schedule.APIID)
+		if err != nil {
+			return nil, nil, err
+		}
+		if api.DeletedAt != nil {
+			skipped = append(skipped, Skipped{AlertRuleID: alertRule.ID, Reason: fmt.Sprintf("%s is in the trash", api.Name)})
+			continue
+		}
+		if !schedule.IsActive {
+			skipped = append(skipped, Skipped{AlertRuleID: alertRule.ID, Reason: fmt.Sprintf("the schedule of %s isn't active", api.Name)})
+			continue
+		}
+
+		interval, err := scheduleInterval(schedule, now)
+		if err != nil {
+			skipped = append(skipped, Skipped{AlertRuleID: alertRule.ID, Reason: fmt.Sprintf("the schedule of %s %v", api.Name, err)})
+			continue
+		}
+
+		rule := Rule{
+			Alert: alertName(api.Name),
+			Expr:  fmt.Sprintf("probe_success{instance=%s} == %d", strconv.Quote(api.URL), failingProbe(api)),
+			Labels: [][2]string{
+				{"source", "flowpulse"},
+				{"api", api.Name},
+			},
+			Annotations: [][2]string{
+				{"summary", fmt.Sprintf("%s is failing", api.Name)},
+				{"description", fmt.Sprintf("%s %s failed a check", api.Method, api.URL)},
+			},
+		}
+		if alertRule.FailureThreshold > 1 {
+			rule.For = time.Duration(alertRule.FailureThreshold-1) * interval
+			rule.Annotations[1][1] = fmt.Sprintf("%s %s failed %d consecutive checks", api.Method, api.URL, alertRule.FailureThreshold)
+		}
+
+		if alertRule.RotationID != 0 {
+			rotation, err := db.GetOnCallRotationByID(alertRule.RotationID)
+			if err != nil {
+				return nil, nil, err
+			}
+			rule.Labels = append(rule.Labels, [2]string{"rotation", rotation.Name})
+		} else if alertRule.ChannelID != 0 {
+			channel, err := db.GetNotificationChannelByID(alertRule.ChannelID)
+			if err != nil {
+				return nil, nil, err
+			}
+			rule.Labels = append(rule.Labels, [2]string{"channel", channel.Name})
+		}
+		if api.RunbookURL != "" {
+			rule.Annotations = append(rule.Annotations, [2]string{"runbook_url", api.RunbookURL})
+		}
+		rules = append(rules, rule)
+	}
+	return rules, skipped, nil
+}
+
+// Generate renders the active alert rules as a Prometheus rule file, listing the skipped ones in comments
+func Generate(db database.Store, now time.Time) (string, error) {
+	rules, skipped, err := Build(db, now)
+	if err != nil {
+		return "", err
+	}
+	return Render(rules, skipped, now), nil
+}
+
+// Render writes rules as a Prometheus rule file in YAML
+func Render(rules []Rule, skipped []Skipped, now time.Time) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Generated by FlowPulse on %s from its alert rules.\n", now.Format(time.RFC3339))
+	b.WriteString("# The rules expect the blackbox exporter to probe each API's URL as the instance label.\n")
+	for _, skip := range skipped {
+		fmt.Fprintf(&b, "# Skipped alert rule %d: %s\n", skip.AlertRuleID, skip.Reason)
+	}
+
+	b.WriteString("groups:\n")
+	fmt.Fprintf(&b, "  - name: %s\n", GroupName)
+	if len(rules) == 0 {
+		b.WriteString("    rules: []\n")
+		return b.String()
+	}
+	b.WriteString("    rules:\n")
+	for _, rule := range rules {
+		fmt.Fprintf(&b, "      - alert: %s\n", rule.Alert)
+		fmt.Fprintf(&b, "        expr: %s\n", quote(rule.Expr))
+		if rule.For > 0 {
+			fmt.Fprintf(&b, "        for: %s\n", FormatDuration(rule.For))
+		}
+		writeMap(&b, "labels", rule.Labels)
+		writeMap(&b, "annotations", rule.Annotations)
+	}
+	return b.String()
+}
+
+// writeMap writes a map of a rule with its keys in order
+func writeMap(b *strings.Builder, name string, entries [][2]string) {
+	if len(entries) == 0 {
+		return
+	}
+	fmt.Fprintf(b, "        %s:\n", name)
+	for _, entry := range entries {
+		fmt.Fprintf(b, "          %s: %s\n", entry[0], quote(entry[1]))
+	}
+}
+
+// quote writes a string as a double-quoted YAML scalar; JSON strings are valid ones
+func quote(s string) string {
+	encoded, _ := json.Marshal(s)
+	return string(encoded)
+}
+
+// FormatDuration writes a duration the way Prometheus reads it, e.g. "1h30m"
+func FormatDuration(d time.Duration) string {
+	seconds := int64(d.Round(time.Second) / time.Second)
+	if seconds <= 0 {
+		return "0s"
+	}
+	var parts []string
+	for _, unit := range []struct {
+		suffix  string
+		seconds int64
+	}{{"d", 86400}, {"h", 3600}, {"m", 60}, {"s", 1}} {
+		if seconds >= unit.seconds {
+			parts = append(parts, fmt.Sprintf("%d%s", seconds/unit.seconds, unit.suffix))
+			seconds %= unit.seconds
+		}
+	}
+	return strings.Join(parts, "")
+}
+
+// scheduleInterval returns how long a schedule waits between runs. Cron schedules use the gap between their
+// next two runs, which is exact for regular expressions and an approximation for irregular ones.
+func scheduleInterval(schedule models.Schedule, now time.Time) (time.Duration, error) {
+	switch schedule.Type {
+	case "interval":
+		seconds, err := strconv.Atoi(schedule.Expression)
+		if err != nil || seconds <= 0 {
+			return 0, fmt.Errorf("has an invalid interval")
+		}
+		return time.Duration(seconds) * time.Second, nil
+	case "cron":
+		parsed, err := scheduler.ParseCron(schedule)
+		if err != nil {
+			return 0, fmt.Errorf("has an invalid cron expression")
+		}
+		next := parsed.Next(now)
+		return parsed.Next(next).Sub(next), nil
+	case "after":
+		return 0, fmt.Errorf("runs after another schedule")
+	default:
+		return 0, fmt.Errorf("runs only once")
+	}
+}
+
+// failingProbe is the probe_success value of a failed check: 0, or 1 for APIs expected to fail
+func failingProbe(api models.API) int {
+	if api.ExpectedOutcome == models.ExpectedOutcomeFailure {
+		return 1
+	}
+	return 0
+}
+
+// alertName turns an API name into a valid Prometheus alert name, e.g. "Users API" into "FlowPulseUsersAPIDown"
+func alertName(apiName string) string {
+	var b strings.Builder
+	b.WriteString("FlowPulse")
+	upper := true
+	for _, r := range apiName {
+		if r > unicode.MaxASCII || !(unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+	b.WriteString("Down")
+	return b.String()
+}
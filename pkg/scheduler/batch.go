@@ -0,0 +1,138 @@
+package scheduler
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"flowpulse/pkg/models"
+)
+
+// batchesKept is how many finished execution batches are kept to be polled; older ones are forgotten
+const batchesKept = 50
+
+// ExecuteAPIsManually runs several APIs right away, outside their schedules, and returns the batch tracking
+// them. Unlike a single manual run, the checks go through the concurrency limits like scheduled ones, so a
+// large batch can't flood a host. Progress is sent as EventBatchProgress and can be polled with
+// ExecutionBatch. IDs given more than once are run once.
+func (s *SchedulerService) ExecuteAPIsManually(apiIDs []int) (models.ExecutionBatch, error) {
+	var apis []models.API
+	seen := make(map[int]bool)
+	for _, apiID := range apiIDs {
+		if seen[apiID] {
+			continue
+		}
+		seen[apiID] = true
+		api, err := s.db.GetAPIByID(apiID)
+		if err != nil {
+			return models.ExecutionBatch{}, fmt.Errorf("failed to get API %d: %w", apiID, err)
+		}
+		if api.DeletedAt != nil {
+			return models.ExecutionBatch{}, fmt.Errorf("API %s is in the trash", api.Name)
+		}
+		apis = append(apis, api)
+	}
+	if len(apis) == 0 {
+		return models.ExecutionBatch{}, fmt.Errorf("no APIs to run")
+	}
+
+	batch := &models.ExecutionBatch{
+		Status:    models.ExecutionBatchRunning,
+		Total:     len(apis),
+		Runs:      make([]models.BatchRun, len(apis)),
+		StartedAt: time.Now(),
+	}
+	for i, api := range apis {
+		batch.Runs[i] = models.BatchRun{APIID: api.ID, APIName: api.Name, Status: models.BatchRunQueued}
+	}
+
+	s.batchMutex.Lock()
+	s.lastBatch++
+	batch.ID = s.lastBatch
+	s.batches[batch.ID] = batch
+	s.forgetOldBatches()
+	snapshot := copyBatch(batch)
+	s.batchMutex.Unlock()
+
+	s.emit(EventBatchProgress, snapshot)
+	for i, api := range apis {
+		go s.runBatchCheck(batch, i, api)
+	}
+	return snapshot, nil
+}
+
+// runBatchCheck waits for a slot and runs the check of one API in a batch, updating its progress
+func (s *SchedulerService) runBatchCheck(batch *models.ExecutionBatch, run int, api models.API) {
+	if !s.acquireSlot(api, nil) {
+		s.updateBatch(batch, run, models.BatchRunSkipped, nil)
+		return
+	}
+	defer s.limiter.release(api)
+
+	s.updateBatch(batch, run, models.BatchRunRunning, nil)
+	executionLog := s.runShared(api, models.Schedule{APIID: api.ID}, true)
+	s.updateBatch(batch, run, executionLog.Status, &executionLog)
+}
+
+// updateBatch records the new status of a run in a batch, completing the batch once every run is done
+func (s *SchedulerService) updateBatch(batch *models.ExecutionBatch, run int, status string, executionLog *models.ExecutionLog) {
+	s.batchMutex.Lock()
+	batch.Runs[run].Status = status
+	batch.Runs[run].Log = executionLog
+	switch {
+	case status == models.BatchRunSkipped:
+		batch.Skipped++
+	case executionLog == nil:
+	case status == models.ExecutionStatusSuccess || status == models.ExecutionStatusDegraded:
+		batch.Succeeded++
+	default:
+		batch.Failed++
+	}
+	if status == models.BatchRunSkipped || executionLog != nil {
+		batch.Completed++
+	}
+	if batch.Completed == batch.Total {
+		finished := time.Now()
+		batch.Status = models.ExecutionBatchCompleted
+		batch.FinishedAt = &finished
+	}
+	snapshot := copyBatch(batch)
+	s.batchMutex.Unlock()
+
+	s.emit(EventBatchProgress, snapshot)
+}
+
+// forgetOldBatches drops the oldest finished batches beyond batchesKept; the caller holds batchMutex
+func (s *SchedulerService) forgetOldBatches() {
+	var finished []int
+	for id, batch := range s.batches {
+		if batch.Status == models.ExecutionBatchCompleted {
+			finished = append(finished, id)
+		}
+	}
+	sort.Ints(finished)
+	for _, id := range finished {
+		if len(s.batches) <= batchesKept {
+			return
+		}
+		delete(s.batches, id)
+	}
+}
+
+// ExecutionBatch returns the progress and results of a batch of manual runs
+func (s *SchedulerService) ExecutionBatch(batchID int) (models.ExecutionBatch, error) {
+	s.batchMutex.Lock()
+	defer s.batchMutex.Unlock()
+	batch, ok := s.batches[batchID]
+	if !ok {
+		return models.ExecutionBatch{}, fmt.Errorf("execution batch %d not found", batchID)
+	}
+	return copyBatch(batch), nil
+}
+
+// copyBatch copies a batch so it can be handed out while its runs go on; the caller holds batchMutex
+func copyBatch(batch *models.ExecutionBatch) models.ExecutionBatch {
+	copied := *batch
+	copied.Runs = append([]models.BatchRun(nil), batch.Runs...)
+	return copied
+}
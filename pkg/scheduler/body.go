@@ -0,0 +1,93 @@
+package scheduler
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"flowpulse/pkg/graphql"
+	"flowpulse/pkg/models"
+)
+
+// encodeBody builds the request body of an API according to its body type, along with the
+// Content-Type it implies. Raw bodies imply no Content-Type; the API's headers decide it.
+func encodeBody(api models.API) (io.Reader, string, error) {
+	switch api.BodyType {
+	case "", models.BodyTypeRaw:
+		if api.Body == "" {
+			return nil, "", nil
+		}
+		return strings.NewReader(api.Body), "", nil
+	case models.BodyTypeJSON:
+		if api.Body == "" {
+			return nil, "", nil
+		}
+		return strings.NewReader(api.Body), "application/json", nil
+	case models.BodyTypeForm:
+		form := url.Values{}
+		for _, field := range api.FormFields {
+			if field.Enabled && field.Key != "" {
+				form.Add(field.Key, field.Value)
+			}
+		}
+		return strings.NewReader(form.Encode()), "application/x-www-form-urlencoded", nil
+	case models.BodyTypeMultipart:
+		return encodeMultipart(api.FormFields)
+	case models.BodyTypeGraphQL:
+		body, err := graphql.Body(api)
+		if err != nil {
+			return nil, "", err
+		}
+		return bytes.NewReader(body), "application/json", nil
+	default:
+		return nil, "", fmt.Errorf("unknown body type %q", api.BodyType)
+	}
+}
+
+// encodeMultipart writes the enabled fields as multipart/form-data, reading file fields from disk.
+// The body is built up front so a missing file fails the request before anything is sent.
+func encodeMultipart(fields []models.FormField) (io.Reader, string, error) {
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+	for _, field := range fields {
+		if !field.Enabled || field.Key == "" {
+			continue
+		}
+		if !field.File {
+			if err := writer.WriteField(field.Key, field.Value); err != nil {
+				return nil, "", fmt.Errorf("failed to encode form field %s: %w", field.Key, err)
+			}
+			continue
+		}
+		if err := writeFormFile(writer, field); err != nil {
+			return nil, "", err
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return nil, "", fmt.Errorf("failed to encode multipart body: %w", err)
+	}
+	return &buf, writer.FormDataContentType(), nil
+}
+
+// writeFormFile adds the contents of the file a field refers to as a file part
+func writeFormFile(writer *multipart.Writer, field models.FormField) error {
+	file, err := os.Open(field.Value)
+	if err != nil {
+		return fmt.Errorf("failed to open file for form field %s: %w", field.Key, err)
+	}
+	defer file.Close()
+
+	part, err := writer.CreateFormFile(field.Key, filepath.Base(field.Value))
+	if err != nil {
+		return fmt.Errorf("failed to encode form field %s: %w", field.Key, err)
+	}
+	if _, err := io.Copy(part, file); err != nil {
+		return fmt.Errorf("failed to read file for form field %s: %w", field.Key, err)
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+package scheduler
+
+import (
+	"flowpulse/pkg/models"
+)
+
+// EvaluateBudget checks the step latencies of a collection against its latency budget.
+// Steps are expected in execution order; when the budget is exceeded the report names the step
+// during which it ran out and the step that consumed the largest share.
+func EvaluateBudget(collectionID int, budgetMs int64, steps []models.BudgetStep) models.BudgetReport {
+	report := models.BudgetReport{
+		CollectionID: collectionID,
+		BudgetMs:     budgetMs,
+		Steps:        make([]models.BudgetStep, 0, len(steps)),
+	}
+
+	var slowest int64 = -1
+	for _, step := range steps {
+		report.TotalMs += step.DurationMs
+		step.ElapsedMs = report.TotalMs
+		if budgetMs > 0 {
+			step.SharePercent = float64(step.DurationMs) / float64(budgetMs) * 100
+		}
+		if budgetMs > 0 && report.BreachingAPIID == 0 && report.TotalMs > budgetMs {
+			report.BreachingAPIID = step.APIID
+		}
+		if step.DurationMs > slowest {
+			slowest = step.DurationMs
+			report.SlowestAPIID = step.APIID
+		}
+		report.Steps = append(report.Steps, step)
+	}
+
+	report.Exceeded = budgetMs > 0 && report.TotalMs > budgetMs
+	return report
+}
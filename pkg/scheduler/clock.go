@@ -0,0 +1,70 @@
+package scheduler
+
+import (
+	"log"
+	"time"
+)
+
+const (
+	// clockCheckInterval is how often the wall clock is compared with the time that actually passed
+	clockCheckInterval = 15 * time.Second
+	// clockJumpThreshold is how far the wall clock may drift from the expected time before it counts
+	// as the system having slept or its clock having been changed
+	clockJumpThreshold = time.Minute
+)
+
+// watchClock detects system sleep and clock changes until stop is closed. Tickers measure
+// monotonic time, which stands still while the system sleeps, so the wall clock running
+// ahead of a ticker means the scheduler was suspended.
+func (s *SchedulerService) watchClock(stop <-chan struct{}) {
+	ticker := time.NewTicker(clockCheckInterval)
+	defer ticker.Stop()
+
+	// Round(0) strips the monotonic reading so Sub compares wall clock times
+	last := time.Now().Round(0)
+	for {
+		select {
+		case <-ticker.C:
+			now := time.Now().Round(0)
+			elapsed := now.Sub(last)
+			if elapsed > clockCheckInterval+clockJumpThreshold || elapsed < -clockJumpThreshold {
+				s.handleClockJump(last, now)
+			}
+			last = now
+		case <-stop:
+			return
+		}
+	}
+}
+
+// handleClockJump records the gap left by a sleep or clock change and restarts the interval jobs,
+// so each runs one fresh check instead of a stale one followed by another shortly after
+func (s *SchedulerService) handleClockJump(before, after time.Time) {
+	if after.Before(before) {
+		log.Printf("System clock moved back by %v, restarting interval jobs", before.Sub(after))
+	} else {
+		log.Printf("No checks ran for %v, likely due to system sleep or a clock change; restarting interval jobs",
+			after.Sub(before).Round(time.Second))
+		if err := s.db.RecordMonitoringGap(before, after); err != nil {
+			log.Printf("Failed to record monitoring gap: %v", err)
+		}
+	}
+
+	// No job could run in the gap, so they are only stale if they stay silent from now on
+	s.resetLastRuns()
+
+	s.intervalMutex.Lock()
+	defer s.intervalMutex.Unlock()
+	for _, job := range s.intervalJobs {
+		job.ticker.Reset(job.interval)
+		select {
+		case job.wake <- struct{}{}:
+		default: // A restart is already pending
+		}
+	}
+}
+
+// stopWatching stops watching the clock and auditing stale jobs; it is safe to call more than once
+func (s *SchedulerService) stopWatching() {
+	s.stopWatchOnce.Do(func() { close(s.stopWatchers) })
+}
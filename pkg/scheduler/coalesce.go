@@ -0,0 +1,110 @@
+package scheduler
+
+import (
+	"log"
+	"time"
+
+	"flowpulse/pkg/models"
+)
+
+// sharedExecution is a check that later triggers of the same API can join while the coalescing window is open
+type sharedExecution struct {
+	started  time.Time
+	triggers []models.ExecutionTrigger
+	done     chan struct{}       // Closed once the check has been logged
+	log      models.ExecutionLog // Set when done
+	finished bool
+}
+
+// triggerOf describes what started a check of a schedule, which has ID 0 for manual runs
+func triggerOf(schedule models.Schedule, now time.Time) models.ExecutionTrigger {
+	if schedule.ID == 0 {
+		return models.ExecutionTrigger{Source: models.TriggerSourceManual, TriggeredAt: now}
+	}
+	return models.ExecutionTrigger{Source: models.TriggerSourceSchedule, ScheduleID: schedule.ID, TriggeredAt: now}
+}
+
+// runShared checks an API, unless a check of it started within the coalescing window, in which case the trigger
+// joins that check instead. With wait, a joining trigger waits for the shared check and returns its log;
+// otherwise it returns right away with an empty log.
+func (s *SchedulerService) runShared(api models.API, schedule models.Schedule, wait bool) models.ExecutionLog {
+	shared, lead := s.shareExecution(api, schedule)
+	if !lead {
+		if !wait {
+			return models.ExecutionLog{}
+		}
+		<-shared.done
+		s.sharedMutex.Lock()
+		defer s.sharedMutex.Unlock()
+		executionLog := shared.log
+		executionLog.Triggers = append([]models.ExecutionTrigger(nil), shared.triggers...)
+		return executionLog
+	}
+
+	executionLog := s.runCheck(api, schedule, 0)
+	if shared == nil {
+		return executionLog
+	}
+
+	s.sharedMutex.Lock()
+	defer s.sharedMutex.Unlock()
+	shared.log = executionLog
+	shared.finished = true
+	close(shared.done)
+	if len(shared.triggers) > 1 {
+		executionLog.Triggers = append([]models.ExecutionTrigger(nil), shared.triggers...)
+		s.recordTriggers(shared)
+	}
+	return executionLog
+}
+
+// shareExecution joins the trigger to a check of the API started within the coalescing window, or reports that
+// it leads a check of its own. The check it leads is nil when coalescing is off.
+func (s *SchedulerService) shareExecution(api models.API, schedule models.Schedule) (*sharedExecution, bool) {
+	now := time.Now()
+	s.sharedMutex.Lock()
+	defer s.sharedMutex.Unlock()
+	if s.coalesceWindow <= 0 {
+		return nil, true
+	}
+
+	for apiID, shared := range s.shared {
+		if now.Sub(shared.started) > s.coalesceWindow && shared.finished {
+			delete(s.shared, apiID)
+		}
+	}
+	if shared, ok := s.shared[api.ID]; ok && now.Sub(shared.started) <= s.coalesceWindow {
+		shared.triggers = append(shared.triggers, triggerOf(schedule, now))
+		// A check already logged gets the trigger added to its log; one still running records them all once done
+		if shared.finished {
+			s.recordTriggers(shared)
+		}
+		return shared, false
+	}
+
+	shared := &sharedExecution{
+		started:  now,
+		triggers: []models.ExecutionTrigger{triggerOf(schedule, now)},
+		done:     make(chan struct{}),
+	}
+	s.shared[api.ID] = shared
+	return shared, true
+}
+
+// recordTriggers writes the triggers of a shared check to its log; the caller holds sharedMutex, so the
+// writes happen in the order the triggers arrived
+func (s *SchedulerService) recordTriggers(shared *sharedExecution) {
+	if shared.log.ID == 0 {
+		return // The API's log policy left the check out
+	}
+	if err := s.db.SetExecutionTriggers(shared.log.ID, shared.triggers); err != nil {
+		log.Printf("Failed to record the triggers of execution log ID %d: %v", shared.log.ID, err)
+	}
+}
+
+// SetTriggerCoalescing applies a new coalescing window to the triggers arriving from now on
+func (s *SchedulerService) SetTriggerCoalescing(coalescing models.TriggerCoalescing) {
+	s.sharedMutex.Lock()
+	defer s.sharedMutex.Unlock()
+	s.coalesceWindow = time.Duration(coalescing.WindowSeconds) * time.Second
+}
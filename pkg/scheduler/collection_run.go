@@ -0,0 +1,136 @@
+package scheduler
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"flowpulse/pkg/models"
+)
+
+// RunCollection executes every API of a collection in their stored order and records the results
+// as a collection run. Steps run one after another unless the collection allows more requests in
+// parallel, and each step after the first waits for the collection's step delay before starting.
+// Values extracted from earlier responses are available to later requests, though parallel steps
+// only see those of steps that have finished. When the collection has StopOnFailure set, no new
+// steps are started after the first failure and the remaining APIs are skipped.
+func (s *SchedulerService) RunCollection(collectionID int) (models.CollectionRun, error) {
+	collection, err := s.db.GetCollectionByID(collectionID)
+	if err != nil {
+		return models.CollectionRun{}, err
+	}
+	if collection.DeletedAt != nil {
+		return models.CollectionRun{}, fmt.Errorf("collection %s is in the trash", collection.Name)
+	}
+
+	apis, err := s.db.GetAPIsByCollectionID(collectionID)
+	if err != nil {
+		return models.CollectionRun{}, err
+	}
+	if len(apis) == 0 {
+		return models.CollectionRun{}, fmt.Errorf("collection %s has no APIs", collection.Name)
+	}
+
+	run, err := s.db.CreateCollectionRun(models.CollectionRun{
+		CollectionID: collectionID,
+		Status:       models.CollectionRunRunning,
+		Total:        len(apis),
+		StartedAt:    time.Now(),
+	})
+	if err != nil {
+		return run, err
+	}
+
+	maxParallel := collection.MaxParallel
+	if maxParallel < 1 {
+		maxParallel = 1
+	}
+	// A step waits for a free slot, so with one slot each step starts after the previous one finished
+	executions := make([]*models.ExecutionLog, len(apis))
+	slots := make(chan struct{}, maxParallel)
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	failed := false
+	for i, api := range apis {
+		slots <- struct{}{}
+		if i > 0 {
+			// An API's own delay replaces the collection's, e.g. for a step hitting a rate-limited target
+			stepDelay := collection.StepDelayMs
+			if api.StepDelayMs > 0 {
+				stepDelay = api.StepDelayMs
+			}
+			time.Sleep(time.Duration(stepDelay) * time.Millisecond)
+		}
+
+		mu.Lock()
+		stop := failed && collection.StopOnFailure
+		mu.Unlock()
+		if stop {
+			<-slots
+			break
+		}
+
+		wg.Add(1)
+		go func(i int, api models.API) {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			// Runs are not tied to a schedule, so they use no retries like manual executions
+			execution := s.runCheck(api, models.Schedule{APIID: api.ID}, run.ID)
+			mu.Lock()
+			executions[i] = &execution
+			if execution.Status == models.ExecutionStatusFailure {
+				failed = true
+			}
+			mu.Unlock()
+		}(i, api)
+	}
+	wg.Wait()
+
+	for _, execution := range executions {
+		if execution == nil {
+			run.SkippedCount++
+			continue
+		}
+		run.Executions = append(run.Executions, *execution)
+		run.DurationMs += execution.DurationMs
+
+		// Degraded answers don't stop the run; they count as passed steps
+		if execution.Status == models.ExecutionStatusFailure {
+			run.FailureCount++
+		} else {
+			run.SuccessCount++
+		}
+	}
+
+	run.Status = models.CollectionRunSuccess
+	if run.FailureCount > 0 {
+		run.Status = models.CollectionRunFailure
+	}
+	run.FinishedAt = time.Now()
+	if err := s.db.FinishCollectionRun(run); err != nil {
+		return run, err
+	}
+
+	run.Budget = RunBudget(collection, apis, run)
+	return run, nil
+}
+
+// RunBudget evaluates a collection's latency budget over the steps of one of its runs
+func RunBudget(collection models.Collection, apis []models.API, run models.CollectionRun) models.BudgetReport {
+	apiNames := make(map[int]string, len(apis))
+	for _, api := range apis {
+		apiNames[api.ID] = api.Name
+	}
+
+	steps := make([]models.BudgetStep, 0, len(run.Executions))
+	for _, execution := range run.Executions {
+		steps = append(steps, models.BudgetStep{
+			APIID:      execution.APIID,
+			APIName:    apiNames[execution.APIID],
+			LogID:      execution.ID,
+			DurationMs: execution.DurationMs,
+		})
+	}
+	return EvaluateBudget(collection.ID, collection.LatencyBudgetMs, steps)
+}
@@ -0,0 +1,249 @@
+package scheduler
+
+import (
+	"log"
+	"net"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"flowpulse/pkg/models"
+)
+
+// concurrencyLimiter bounds the scheduled checks in flight, overall, per host and to one at a time per API,
+// so short schedules can't pile up checks faster than they finish. Checks waiting for a slot queue per host,
+// and a freed slot goes to the host with the fewest checks in flight, so an API with a tiny interval can't
+// keep the checks of other hosts waiting.
+type concurrencyLimiter struct {
+	mu         sync.Mutex
+	limit      int                   // Most checks in flight at once (0 for no limit)
+	hostLimit  int                   // Most checks in flight against one host (0 for no limit)
+	running    int                   // Checks in flight
+	busy       map[int]bool          // APIs with a check in flight
+	hosts      map[string]*hostQueue // Hosts with checks in flight or waiting
+	waitingAPI map[int]int           // Checks waiting per API, to skip runs of APIs that are already queued
+}
+
+// hostQueue holds the checks in flight against a host and those waiting for a slot, oldest first
+type hostQueue struct {
+	running int
+	waiting []*slotRequest
+}
+
+// slotRequest is a check waiting for a slot, which is closed once the slot is granted
+type slotRequest struct {
+	api     models.API
+	since   time.Time
+	granted chan struct{}
+}
+
+// newConcurrencyLimiter returns a limiter with the given limits
+func newConcurrencyLimiter(concurrency models.SchedulerConcurrency) *concurrencyLimiter {
+	return &concurrencyLimiter{
+		limit:      concurrency.MaxConcurrent,
+		hostLimit:  concurrency.MaxPerHost,
+		busy:       make(map[int]bool),
+		hosts:      make(map[string]*hostQueue),
+		waitingAPI: make(map[int]int),
+	}
+}
+
+// setLimits changes how many checks may be in flight at once, granting slots the new limits leave room for
+func (l *concurrencyLimiter) setLimits(concurrency models.SchedulerConcurrency) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.limit = concurrency.MaxConcurrent
+	l.hostLimit = concurrency.MaxPerHost
+	l.dispatch()
+}
+
+// acquire waits for a slot to check the API in and returns how long it waited. When the API already has a
+// check in flight or waiting and its overlap policy is to skip, it returns false right away instead, and it
+// gives up its place in the queue and returns false once stop is closed (nil never is); otherwise the slot
+// must be released.
+func (l *concurrencyLimiter) acquire(api models.API, stop <-chan struct{}) (time.Duration, bool) {
+	l.mu.Lock()
+	if api.OverlapPolicy == models.OverlapPolicySkip && (l.busy[api.ID] || l.waitingAPI[api.ID] > 0) {
+		l.mu.Unlock()
+		return 0, false
+	}
+	request := &slotRequest{api: api, since: time.Now(), granted: make(chan struct{})}
+	host := l.host(hostOf(api))
+	host.waiting = append(host.waiting, request)
+	l.waitingAPI[api.ID]++
+	l.dispatch()
+	l.mu.Unlock()
+
+	select {
+	case <-request.granted:
+	case <-stop:
+		if !l.withdraw(request) {
+			break // The slot was granted before the request could be withdrawn
+		}
+		return 0, false
+	}
+	return time.Since(request.since), true
+}
+
+// withdraw takes a request that is still waiting out of its host's queue, reporting false when it was
+// already granted
+func (l *concurrencyLimiter) withdraw(request *slotRequest) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	select {
+	case <-request.granted:
+		return false
+	default:
+	}
+
+	host := l.host(hostOf(request.api))
+	for i, waiting := range host.waiting {
+		if waiting == request {
+			host.waiting = append(host.waiting[:i], host.waiting[i+1:]...)
+			break
+		}
+	}
+	if l.waitingAPI[request.api.ID]--; l.waitingAPI[request.api.ID] == 0 {
+		delete(l.waitingAPI, request.api.ID)
+	}
+	// Dispatching also forgets the host if this was its last request
+	l.dispatch()
+	return true
+}
+
+// release frees the slot of an API's check and grants it to a waiting check
+func (l *concurrencyLimiter) release(api models.API) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.busy, api.ID)
+	l.running--
+	host := l.host(hostOf(api))
+	host.running--
+	l.dispatch()
+}
+
+// host returns the queue of a host, creating it if needed
+func (l *concurrencyLimiter) host(name string) *hostQueue {
+	host, ok := l.hosts[name]
+	if !ok {
+		host = &hostQueue{}
+		l.hosts[name] = host
+	}
+	return host
+}
+
+// dispatch grants slots while the limits allow, each to the oldest grantable check of the host with the
+// fewest checks in flight. A check is grantable once its API has no check in flight. Must be called with mu held.
+func (l *concurrencyLimiter) dispatch() {
+	for l.limit <= 0 || l.running < l.limit {
+		var next *hostQueue
+		nextIndex := -1
+		for _, host := range l.hosts {
+			if l.hostLimit > 0 && host.running >= l.hostLimit {
+				continue
+			}
+			index := l.grantable(host)
+			if index < 0 {
+				continue
+			}
+			if next == nil || host.running < next.running ||
+				(host.running == next.running && host.waiting[index].since.Before(next.waiting[nextIndex].since)) {
+				next, nextIndex = host, index
+			}
+		}
+		if next == nil {
+			break
+		}
+
+		request := next.waiting[nextIndex]
+		next.waiting = append(next.waiting[:nextIndex], next.waiting[nextIndex+1:]...)
+		next.running++
+		l.running++
+		l.busy[request.api.ID] = true
+		if l.waitingAPI[request.api.ID]--; l.waitingAPI[request.api.ID] == 0 {
+			delete(l.waitingAPI, request.api.ID)
+		}
+		close(request.granted)
+	}
+
+	// Forget idle hosts so renamed or deleted ones don't accumulate
+	for name, host := range l.hosts {
+		if host.running == 0 && len(host.waiting) == 0 {
+			delete(l.hosts, name)
+		}
+	}
+}
+
+// grantable returns the index of the oldest check of a host whose API has no check in flight, or -1 for none
+func (l *concurrencyLimiter) grantable(host *hostQueue) int {
+	for i, request := range host.waiting {
+		if !l.busy[request.api.ID] {
+			return i
+		}
+	}
+	return -1
+}
+
+// load returns the checks in flight and waiting per host, busiest first
+func (l *concurrencyLimiter) load() []models.HostLoad {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := time.Now()
+	hosts := make([]models.HostLoad, 0, len(l.hosts))
+	for name, host := range l.hosts {
+		load := models.HostLoad{Host: name, Running: host.running, Waiting: len(host.waiting)}
+		if len(host.waiting) > 0 {
+			load.OldestWaitMs = now.Sub(host.waiting[0].since).Milliseconds()
+		}
+		hosts = append(hosts, load)
+	}
+	sort.Slice(hosts, func(i, j int) bool {
+		if hosts[i].Running+hosts[i].Waiting != hosts[j].Running+hosts[j].Waiting {
+			return hosts[i].Running+hosts[i].Waiting > hosts[j].Running+hosts[j].Waiting
+		}
+		return hosts[i].Host < hosts[j].Host
+	})
+	return hosts
+}
+
+// hostOf returns the host an API's checks go to. When the URL starts with a variable, e.g. {{baseUrl}}/users,
+// the host is only known once the variable is resolved, so the part before the path stands in for it.
+func hostOf(api models.API) string {
+	target := strings.TrimSpace(api.URL)
+	if parsed, err := url.Parse(target); err == nil && parsed.Hostname() != "" {
+		return strings.ToLower(parsed.Hostname())
+	}
+	target = checkTarget(api)
+	if host, _, ok := strings.Cut(target, "/"); ok {
+		target = host
+	}
+	if host, _, err := net.SplitHostPort(target); err == nil {
+		target = host
+	}
+	return strings.ToLower(target)
+}
+
+// acquireSlot waits for a slot for a scheduled check of the API and records how long it waited,
+// returning false when the check is to be skipped or stop was closed while it waited
+func (s *SchedulerService) acquireSlot(api models.API, stop <-chan struct{}) bool {
+	wait, ok := s.limiter.acquire(api, stop)
+	if !ok {
+		return false
+	}
+	if err := s.db.RecordSlotWait(time.Now(), wait); err != nil {
+		log.Printf("Failed to record scheduler metrics: %v", err)
+	}
+	return true
+}
+
+// SetConcurrency applies new limits to the scheduled checks started from now on, and to those waiting for a slot
+func (s *SchedulerService) SetConcurrency(concurrency models.SchedulerConcurrency) {
+	s.limiter.setLimits(concurrency)
+}
+
+// HostLoad returns the scheduled checks in flight and waiting for a slot per host, busiest first
+func (s *SchedulerService) HostLoad() []models.HostLoad {
+	return s.limiter.load()
+}
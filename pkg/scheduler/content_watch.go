@@ -0,0 +1,70 @@
+package scheduler
+
+import (
+	"log"
+
+	"flowpulse/pkg/diff"
+	"flowpulse/pkg/models"
+)
+
+// watchContent compares the response of an API that watches its content with the API's baseline. A response
+// that differs becomes the new baseline and flags the log, so each change is reported once. The first response,
+// or the first after the watch mode changed, only captures a baseline. It returns the change to record, or nil.
+func (s *SchedulerService) watchContent(api models.API, executionLog *models.ExecutionLog) *models.ContentChange {
+	if api.ContentWatch == "" || api.ExpectedOutcome == models.ExpectedOutcomeFailure {
+		return nil
+	}
+	// Only answers that came as expected are compared, so error pages and outages don't count as changes
+	if executionLog.Status != models.ExecutionStatusSuccess && executionLog.Status != models.ExecutionStatusDegraded {
+		return nil
+	}
+
+	baseline, exists, err := s.db.GetContentBaseline(api.ID)
+	if err != nil {
+		log.Printf("Failed to load content baseline of API %d: %v", api.ID, err)
+		return nil
+	}
+	fingerprint := diff.Fingerprint(executionLog.Response, api.ContentWatch)
+	if exists && baseline.Mode == api.ContentWatch && baseline.Fingerprint == fingerprint {
+		return nil
+	}
+
+	err = s.db.SetContentBaseline(models.ContentBaseline{
+		APIID:       api.ID,
+		Mode:        api.ContentWatch,
+		Fingerprint: fingerprint,
+		Body:        executionLog.Response,
+		CapturedAt:  executionLog.ExecutedAt,
+	})
+	if err != nil {
+		log.Printf("Failed to store content baseline of API %d: %v", api.ID, err)
+		return nil
+	}
+	if !exists || baseline.Mode != api.ContentWatch {
+		return nil
+	}
+
+	executionLog.ContentChanged = true
+	return &models.ContentChange{
+		APIID:               api.ID,
+		PreviousFingerprint: baseline.Fingerprint,
+		Fingerprint:         fingerprint,
+		PreviousBody:        baseline.Body,
+		Body:                executionLog.Response,
+		DetectedAt:          executionLog.ExecutedAt,
+	}
+}
+
+// recordContentChange stores a change found by watchContent along with the ID of the log that saw it,
+// which is 0 when the log wasn't stored
+func (s *SchedulerService) recordContentChange(change *models.ContentChange, executionLogID int) {
+	if change == nil {
+		return
+	}
+	change.ExecutionLogID = executionLogID
+	if _, err := s.db.CreateContentChange(*change); err != nil {
+		log.Printf("Failed to record content change of API %d: %v", change.APIID, err)
+		return
+	}
+	s.emit(EventContentChanged, *change)
+}
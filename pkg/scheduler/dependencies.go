@@ -0,0 +1,46 @@
+package scheduler
+
+import "flowpulse/pkg/models"
+
+// afterJob is an active "after" schedule with the API it checks
+type afterJob struct {
+	api      models.API
+	schedule models.Schedule
+}
+
+// runDependents fires the "after" schedules waiting for the outcome a run of a schedule ended with. Triggers
+// that joined a check started by another one have no log of their own and fire nothing.
+func (s *SchedulerService) runDependents(schedule models.Schedule, executionLog models.ExecutionLog) {
+	if schedule.ID == 0 || executionLog.Status == "" {
+		return
+	}
+
+	var fired []afterJob
+	s.afterMutex.Lock()
+	for _, job := range s.afterJobs {
+		if job.schedule.AfterID == schedule.ID && firesAfter(job.schedule.AfterOutcome, executionLog.Status) {
+			fired = append(fired, job)
+		}
+	}
+	s.afterMutex.Unlock()
+
+	for _, job := range fired {
+		go s.executeAPI(job.api, job.schedule)
+	}
+}
+
+// firesAfter reports whether a run with a status is the outcome an "after" schedule waits for
+func firesAfter(outcome, status string) bool {
+	if status == models.ExecutionStatusSkipped {
+		return false
+	}
+	succeeded := status == models.ExecutionStatusSuccess || status == models.ExecutionStatusDegraded
+	switch outcome {
+	case models.AfterOutcomeAny:
+		return true
+	case models.AfterOutcomeFailure:
+		return !succeeded
+	default:
+		return succeeded
+	}
+}
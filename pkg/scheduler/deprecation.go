@@ -0,0 +1,55 @@
+package scheduler
+
+import (
+	"log"
+	"time"
+)
+
+// deprecationAuditInterval is how often the schedules of deprecated APIs are looked for
+const deprecationAuditInterval = time.Minute
+
+// retireDeprecatedAPIs deactivates the schedules of deprecated APIs until stop is closed
+func (s *SchedulerService) retireDeprecatedAPIs(stop <-chan struct{}) {
+	ticker := time.NewTicker(deprecationAuditInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			s.retireDeprecated()
+		case <-stop:
+			return
+		}
+	}
+}
+
+// retireDeprecated stops and deactivates the active schedules of the APIs whose deprecation date has passed
+// and that are set to be disabled then. A schedule activated again afterwards is deactivated again.
+func (s *SchedulerService) retireDeprecated() {
+	apis, err := s.db.GetAPIsToRetire(time.Now())
+	if err != nil {
+		log.Printf("Failed to audit deprecated APIs: %v", err)
+		return
+	}
+
+	for _, api := range apis {
+		schedules, err := s.db.GetSchedulesByAPIID(api.ID)
+		if err != nil {
+			log.Printf("Failed to load schedules of deprecated API ID %d: %v", api.ID, err)
+			continue
+		}
+		for _, schedule := range schedules {
+			if !schedule.IsActive {
+				continue
+			}
+			// The job may not be running, so a failed stop is expected
+			s.StopJob(schedule.ID)
+			schedule.IsActive = false
+			if err := s.db.UpdateSchedule(schedule); err != nil {
+				log.Printf("Failed to deactivate schedule ID %d of deprecated API ID %d: %v", schedule.ID, api.ID, err)
+				continue
+			}
+			log.Printf("Deactivated schedule ID %d of API ID %d, deprecated after %s",
+				schedule.ID, api.ID, api.DeprecatedAfter.Format(time.DateOnly))
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"flowpulse/pkg/assertions"
+	"flowpulse/pkg/models"
+)
+
+// dnsRecordTypes are the record types DNS checks can look up
+var dnsRecordTypes = map[string]bool{"A": true, "AAAA": true, "CNAME": true, "MX": true, "NS": true, "TXT": true}
+
+// dnsExecutor looks up records of the host name in the API's URL, asking the API's DNS server if it has one. The body lists the records found,
+// one per line, and the attempt fails when none of them has the expected value.
+type dnsExecutor struct {
+	timeout time.Duration
+}
+
+func (e *dnsExecutor) SuccessCodes() assertions.StatusCodes {
+	return nil
+}
+
+func (e *dnsExecutor) Execute(ctx context.Context, check Check) (Attempt, error) {
+	host := checkTarget(check.API)
+	if host == "" {
+		return Attempt{}, fmt.Errorf("DNS checks need a host name")
+	}
+	recordType := strings.ToUpper(check.API.DNSRecordType)
+	if recordType == "" {
+		recordType = "A"
+	}
+	if !dnsRecordTypes[recordType] {
+		return Attempt{}, fmt.Errorf("unsupported DNS record type: %s", recordType)
+	}
+
+	resolver, err := resolverFor(check.API)
+	if err != nil {
+		return Attempt{}, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, e.timeout)
+	defer cancel()
+
+	start := time.Now()
+	records, err := lookupRecords(ctx, resolver, host, recordType)
+	attempt := Attempt{Body: strings.Join(records, "\n"), Duration: time.Since(start)}
+	if err == nil && len(records) == 0 {
+		err = fmt.Errorf("no %s records found for %s", recordType, host)
+	}
+	if err == nil && check.API.DNSExpected != "" && !containsRecord(records, check.API.DNSExpected) {
+		err = fmt.Errorf("no %s record of %s is %s, found %s", recordType, host, check.API.DNSExpected, strings.Join(records, ", "))
+	}
+	if err != nil {
+		attempt.Err, attempt.Phase = err, models.RequestPhaseDNS
+	}
+	return attempt, nil
+}
+
+// lookupRecords returns the values of the records of a type
+func lookupRecords(ctx context.Context, resolver *net.Resolver, host, recordType string) ([]string, error) {
+	var records []string
+	switch recordType {
+	case "A", "AAAA":
+		network := "ip4"
+		if recordType == "AAAA" {
+			network = "ip6"
+		}
+		ips, err := resolver.LookupIP(ctx, network, host)
+		if err != nil {
+			return nil, err
+		}
+		for _, ip := range ips {
+			records = append(records, ip.String())
+		}
+	case "CNAME":
+		cname, err := resolver.LookupCNAME(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, cname)
+	case "MX":
+		mxs, err := resolver.LookupMX(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+		for _, mx := range mxs {
+			records = append(records, mx.Host)
+		}
+	case "NS":
+		nss, err := resolver.LookupNS(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+		for _, ns := range nss {
+			records = append(records, ns.Host)
+		}
+	case "TXT":
+		return resolver.LookupTXT(ctx, host)
+	}
+	return records, nil
+}
+
+// containsRecord reports whether a record has the expected value, ignoring case and the trailing dot of names
+func containsRecord(records []string, expected string) bool {
+	expected = strings.TrimSuffix(strings.TrimSpace(expected), ".")
+	for _, record := range records {
+		if strings.EqualFold(strings.TrimSuffix(record, "."), expected) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,50 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"flowpulse/pkg/models"
+)
+
+// TestAPI checks an API definition right away, whether or not it was saved, and returns the execution log
+// the check would have written, with ID 0. Nothing is stored: no log, no metrics, no incidents or alerts, and
+// no variables extracted for the collection. The saved assertions of the API apply when it has an ID. The
+// request headers are always included, with secrets masked like in logs.
+func (s *SchedulerService) TestAPI(api models.API) models.ExecutionLog {
+	s.running.Add(1)
+	defer s.running.Done()
+
+	ctx, cancel := context.WithTimeout(context.Background(), executionCeiling)
+	defer cancel()
+
+	api.LogRequestHeaders = true
+	started := time.Now()
+	done := make(chan models.ExecutionLog, 1)
+	go func() {
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				done <- internalErrorLog(api, models.Schedule{}, 0, recovered)
+			}
+		}()
+		_, executionLog, _ := s.check(ctx, api, models.Schedule{APIID: api.ID}, 0, false)
+		done <- executionLog
+	}()
+
+	var executionLog models.ExecutionLog
+	select {
+	case executionLog = <-done:
+	case <-ctx.Done():
+		executionLog = models.ExecutionLog{
+			APIID:      api.ID,
+			Status:     models.ExecutionStatusFailure,
+			Error:      fmt.Sprintf("Test exceeded the hard limit of %v and was aborted", executionCeiling),
+			DurationMs: executionCeiling.Milliseconds(),
+			TimedOut:   true,
+		}
+	}
+	executionLog.ExecutedAt = started
+	s.maskSecrets(&executionLog)
+	return executionLog
+}
@@ -0,0 +1,49 @@
+package scheduler
+
+import (
+	"time"
+
+	"flowpulse/pkg/models"
+)
+
+// Events emitted as checks run and jobs change state, so the frontend can update without polling
+const (
+	EventExecutionStarted     = "execution:started"     // Sends a models.RunningExecution
+	EventExecutionCompleted   = "execution:completed"   // Sends a models.CompletedExecution
+	EventScheduleStateChanged = "schedule:stateChanged" // Sends a models.ScheduleStateChange
+	EventIngestionChanged     = "ingestion:changed"     // Sends a models.IngestionStatus when log sampling starts or stops
+	EventBatchProgress        = "batch:progress"        // Sends a models.ExecutionBatch as its runs start and finish
+	EventContentChanged       = "content:changed"       // Sends a models.ContentChange when an API's response body changes
+)
+
+// EventEmitter delivers a scheduler event. It is called from the goroutine running the check or job,
+// so it must not block.
+type EventEmitter func(name string, data interface{})
+
+// SetEventEmitter makes the scheduler send its events to the emitter; nil stops sending them
+func (s *SchedulerService) SetEventEmitter(emitter EventEmitter) {
+	s.emitterMutex.Lock()
+	defer s.emitterMutex.Unlock()
+	s.emitter = emitter
+}
+
+// emit sends an event to the emitter, if one is set, and to the event webhooks
+func (s *SchedulerService) emit(name string, data interface{}) {
+	s.eventWebhooks.Publish(name, data)
+
+	s.emitterMutex.RLock()
+	emitter := s.emitter
+	s.emitterMutex.RUnlock()
+	if emitter != nil {
+		emitter(name, data)
+	}
+}
+
+// emitScheduleState reports a schedule's job changing state
+func (s *SchedulerService) emitScheduleState(scheduleID int, state string) {
+	s.emit(EventScheduleStateChanged, models.ScheduleStateChange{
+		ScheduleID: scheduleID,
+		State:      state,
+		ChangedAt:  time.Now(),
+	})
+}
@@ -0,0 +1,163 @@
+package scheduler
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"flowpulse/pkg/assertions"
+	"flowpulse/pkg/models"
+)
+
+// Executor runs single attempts of one type of check. Retries, assertions, outcome classification
+// and logging are shared by every type and handled by the scheduler.
+type Executor interface {
+	// Execute makes one attempt at the check. An error means the check couldn't be attempted at all,
+	// e.g. because its request is invalid, and fails it without retries. Failures of the attempt
+	// itself, such as a refused connection, are reported in the Attempt so they are retried.
+	Execute(ctx context.Context, check Check) (Attempt, error)
+
+	// SuccessCodes returns the status codes an attempt succeeds with when the API doesn't set its
+	// own, or nil when attempts have no status code and succeed whenever they have no error
+	SuccessCodes() assertions.StatusCodes
+}
+
+// Check is what an executor needs to attempt a check
+type Check struct {
+	API        models.API         // API with its variables substituted
+	Assertions []models.Assertion // Assertions evaluated against the attempt
+	Auth       *models.AuthConfig // Credentials of the request, or nil for none
+}
+
+// Attempt is the outcome of a single attempt of a check
+type Attempt struct {
+	StatusCode     int
+	Headers        http.Header
+	RequestHeaders http.Header // Headers the request was sent with, for check types sending HTTP requests
+	Body           string      // Response body, or whatever the check type reports in its place
+	Duration       time.Duration
+	Err            error  // Transport error, in which case the response fields may be empty
+	Phase          string // Request phase the transport error happened in
+	FinalURL       string // URL the response came from, when redirects were followed
+	Redirects      []models.RedirectHop
+}
+
+// RegisterExecutor makes the scheduler run checks of the given type with the executor,
+// replacing any executor already registered for it
+func (s *SchedulerService) RegisterExecutor(checkType string, executor Executor) {
+	s.executorMutex.Lock()
+	defer s.executorMutex.Unlock()
+	s.executors[checkType] = executor
+}
+
+// executorFor returns the executor of a check type, HTTP when none is set
+func (s *SchedulerService) executorFor(checkType string) (Executor, error) {
+	if checkType == "" {
+		checkType = models.CheckTypeHTTP
+	}
+	s.executorMutex.RLock()
+	defer s.executorMutex.RUnlock()
+	executor, ok := s.executors[checkType]
+	if !ok {
+		return nil, fmt.Errorf("unsupported check type: %s", checkType)
+	}
+	return executor, nil
+}
+
+// registerBuiltinExecutors registers the executors of the check types FlowPulse supports
+func (s *SchedulerService) registerBuiltinExecutors() {
+	s.RegisterExecutor(models.CheckTypeHTTP, &httpExecutor{scheduler: s})
+	s.RegisterExecutor(models.CheckTypeWebSocket, &webSocketExecutor{scheduler: s})
+	s.RegisterExecutor(models.CheckTypeTCP, &tcpExecutor{timeout: s.client.Timeout})
+	s.RegisterExecutor(models.CheckTypeICMP, &icmpExecutor{timeout: s.client.Timeout})
+	s.RegisterExecutor(models.CheckTypeDNS, &dnsExecutor{timeout: s.client.Timeout})
+}
+
+// checkTarget returns the host or address an infrastructure check targets, accepting it with
+// or without a scheme such as tcp:// in front
+func checkTarget(api models.API) string {
+	target := strings.TrimSpace(api.URL)
+	if _, rest, ok := strings.Cut(target, "://"); ok {
+		target = rest
+	}
+	return strings.TrimSuffix(target, "/")
+}
+
+// httpExecutor sends the API's HTTP request
+type httpExecutor struct {
+	scheduler *SchedulerService
+}
+
+func (e *httpExecutor) SuccessCodes() assertions.StatusCodes {
+	return defaultSuccessCodes
+}
+
+// Execute sends the request and reads the response, measuring the round trip including the body
+func (e *httpExecutor) Execute(ctx context.Context, check Check) (Attempt, error) {
+	s := e.scheduler
+	req, err := s.prepareAuthorizedRequest(ctx, check)
+	if err != nil {
+		return Attempt{}, err
+	}
+
+	client, err := s.clientFor(check.API)
+	if err != nil {
+		return Attempt{}, fmt.Errorf("invalid connection settings: %w", err)
+	}
+
+	redirects := newRedirectTracker(check.API)
+	client = redirects.client(client)
+
+	requestHeaders := req.Header.Clone()
+	req, phase := traceRequestPhases(req)
+	start := time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		return Attempt{RequestHeaders: requestHeaders, Err: err, Phase: phase.get(), Duration: time.Since(start), Redirects: redirects.hops}, nil
+	}
+
+	// Read response; the client timeout also covers the body, so a slow body fails the request
+	phase.set(models.RequestPhaseBody)
+	buf := new(bytes.Buffer)
+	_, err = buf.ReadFrom(resp.Body)
+	resp.Body.Close()
+
+	// A rejected token may have been revoked early, so fetch a new one for the next attempt
+	if resp.StatusCode == http.StatusUnauthorized && check.Auth != nil {
+		s.auth.Invalidate(check.Auth.ID)
+	}
+	attempt := Attempt{
+		StatusCode:     resp.StatusCode,
+		Headers:        resp.Header,
+		RequestHeaders: requestHeaders,
+		Body:           buf.String(),
+		Duration:       time.Since(start),
+		Err:            err,
+		Redirects:      redirects.hops,
+	}
+	if len(redirects.hops) > 0 {
+		attempt.FinalURL = resp.Request.URL.String()
+	}
+	if err != nil {
+		attempt.Phase = phase.get()
+	}
+	return attempt, nil
+}
+
+// prepareAuthorizedRequest builds a fresh request for an attempt, since a body can only be read
+// once, and authenticates it with the check's credentials
+func (s *SchedulerService) prepareAuthorizedRequest(ctx context.Context, check Check) (*http.Request, error) {
+	req, err := s.prepareAPIRequest(ctx, check.API)
+	if err != nil {
+		return nil, fmt.Errorf("invalid request: %w", err)
+	}
+	if check.Auth != nil {
+		if err := s.auth.Authorize(req, *check.Auth); err != nil {
+			return nil, fmt.Errorf("authentication failed: %w", err)
+		}
+	}
+	return req, nil
+}
@@ -0,0 +1,156 @@
+package scheduler
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"time"
+
+	"flowpulse/pkg/assertions"
+	"flowpulse/pkg/models"
+)
+
+// ICMP echo message types (RFC 792)
+const (
+	icmpEchoReply   = 0
+	icmpEchoRequest = 8
+)
+
+// icmpExecutor sends an ICMP echo request to the host in the API's URL and waits for the reply.
+// The duration is the round-trip time. Only IPv4 hosts are supported.
+type icmpExecutor struct {
+	timeout time.Duration
+}
+
+func (e *icmpExecutor) SuccessCodes() assertions.StatusCodes {
+	return nil
+}
+
+func (e *icmpExecutor) Execute(ctx context.Context, check Check) (Attempt, error) {
+	host := checkTarget(check.API)
+	if host == "" {
+		return Attempt{}, fmt.Errorf("ICMP checks need a host")
+	}
+
+	resolver, err := resolverFor(check.API)
+	if err != nil {
+		return Attempt{}, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, e.timeout)
+	defer cancel()
+
+	start := time.Now()
+	ips, err := resolver.LookupIP(ctx, "ip4", host)
+	if err != nil {
+		return Attempt{Err: err, Phase: models.RequestPhaseDNS, Duration: time.Since(start)}, nil
+	}
+
+	conn, err := listenICMP()
+	if err != nil {
+		return Attempt{}, fmt.Errorf("failed to open ICMP socket: %w", err)
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+	stop := context.AfterFunc(ctx, func() { conn.SetDeadline(time.Now()) })
+	defer stop()
+
+	rtt, err := ping(conn, ips[0])
+	if err != nil {
+		return Attempt{Err: err, Phase: models.RequestPhaseConnect, Duration: time.Since(start)}, nil
+	}
+	return Attempt{Body: ips[0].String(), Duration: rtt}, nil
+}
+
+// listenICMP opens a raw ICMP socket, falling back to an unprivileged ICMP datagram socket
+// where the system offers one
+func listenICMP() (net.PacketConn, error) {
+	conn, err := net.ListenPacket("ip4:icmp", "0.0.0.0")
+	if err == nil {
+		return conn, nil
+	}
+	if conn, dgramErr := listenUnprivilegedICMP(); dgramErr == nil {
+		return conn, nil
+	}
+	return nil, err
+}
+
+// ping sends one echo request and returns the round-trip time of its reply. Replies are matched
+// by sequence number and payload, since datagram sockets replace the identifier.
+func ping(conn net.PacketConn, ip net.IP) (time.Duration, error) {
+	payload := make([]byte, 16)
+	if _, err := rand.Read(payload); err != nil {
+		return 0, fmt.Errorf("failed to generate ICMP payload: %w", err)
+	}
+	id := binary.BigEndian.Uint16(payload)
+	seq := binary.BigEndian.Uint16(payload[2:])
+	request := icmpEcho(icmpEchoRequest, id, seq, payload)
+
+	// Datagram sockets are UDP sockets to the net package and take a UDP address
+	var target net.Addr = &net.IPAddr{IP: ip}
+	if _, ok := conn.LocalAddr().(*net.UDPAddr); ok {
+		target = &net.UDPAddr{IP: ip}
+	}
+
+	start := time.Now()
+	if _, err := conn.WriteTo(request, target); err != nil {
+		return 0, err
+	}
+	buf := make([]byte, 1500)
+	for {
+		n, _, err := conn.ReadFrom(buf)
+		if err != nil {
+			return 0, err
+		}
+		reply := stripIPv4Header(buf[:n])
+		if len(reply) < 8 || reply[0] != icmpEchoReply {
+			continue
+		}
+		if binary.BigEndian.Uint16(reply[6:8]) == seq && bytes.Equal(reply[8:], payload) {
+			return time.Since(start), nil
+		}
+	}
+}
+
+// icmpEcho builds an echo message with its checksum
+func icmpEcho(messageType byte, id, seq uint16, payload []byte) []byte {
+	message := make([]byte, 8+len(payload))
+	message[0] = messageType
+	binary.BigEndian.PutUint16(message[4:], id)
+	binary.BigEndian.PutUint16(message[6:], seq)
+	copy(message[8:], payload)
+	binary.BigEndian.PutUint16(message[2:], icmpChecksum(message))
+	return message
+}
+
+// icmpChecksum computes the Internet checksum (RFC 1071)
+func icmpChecksum(data []byte) uint16 {
+	var sum uint32
+	for i := 0; i+1 < len(data); i += 2 {
+		sum += uint32(data[i])<<8 | uint32(data[i+1])
+	}
+	if len(data)%2 == 1 {
+		sum += uint32(data[len(data)-1]) << 8
+	}
+	for sum>>16 != 0 {
+		sum = sum&0xFFFF + sum>>16
+	}
+	return ^uint16(sum)
+}
+
+// stripIPv4Header removes the IP header some systems leave in front of received ICMP messages
+func stripIPv4Header(packet []byte) []byte {
+	if len(packet) < 20 || packet[0]>>4 != 4 {
+		return packet
+	}
+	headerLen := int(packet[0]&0x0F) * 4
+	if headerLen > len(packet) {
+		return packet
+	}
+	return packet[headerLen:]
+}
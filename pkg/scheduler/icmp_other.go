@@ -0,0 +1,14 @@
+//go:build !darwin && !linux
+
+package scheduler
+
+import (
+	"errors"
+	"net"
+)
+
+// listenUnprivilegedICMP reports that this platform has no unprivileged ICMP sockets, so ICMP
+// checks need the privileges to open a raw socket
+func listenUnprivilegedICMP() (net.PacketConn, error) {
+	return nil, errors.New("unprivileged ICMP sockets are not supported on this platform")
+}
@@ -0,0 +1,21 @@
+//go:build darwin || linux
+
+package scheduler
+
+import (
+	"net"
+	"os"
+	"syscall"
+)
+
+// listenUnprivilegedICMP opens an ICMP datagram socket, which needs no privileges on macOS and on
+// Linux when the user's group is within net.ipv4.ping_group_range
+func listenUnprivilegedICMP() (net.PacketConn, error) {
+	fd, err := syscall.Socket(syscall.AF_INET, syscall.SOCK_DGRAM, syscall.IPPROTO_ICMP)
+	if err != nil {
+		return nil, err
+	}
+	file := os.NewFile(uintptr(fd), "icmp")
+	defer file.Close()
+	return net.FilePacketConn(file)
+}
@@ -0,0 +1,175 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"flowpulse/pkg/models"
+)
+
+// errExecutionCancelled is the cause of the context of a check cancelled through CancelExecution
+var errExecutionCancelled = errors.New("execution cancelled")
+
+// cancelledMessage is the error logged for a cancelled check
+const cancelledMessage = "Execution was cancelled"
+
+// executionIDKey is the context key of the ID a check in flight is tracked by
+type executionIDKey struct{}
+
+// inFlightExecution is a check in flight together with the function cancelling it
+type inFlightExecution struct {
+	info   models.RunningExecution
+	cancel context.CancelCauseFunc
+	retry  *pendingRetry // Set while the check waits to retry
+}
+
+// pendingRetry is the wait of a check before a retry, which RetryNow and CancelRetry cut short
+type pendingRetry struct {
+	info   models.PendingRetry
+	decide chan bool // Receives true to retry right away, false to give up retrying
+}
+
+// trackExecution registers a check in flight, reports it started and returns the ID it can be cancelled by
+func (s *SchedulerService) trackExecution(api models.API, schedule models.Schedule, collectionRunID int, started time.Time, cancel context.CancelCauseFunc) int {
+	s.inFlightMutex.Lock()
+	s.lastInFlight++
+	info := models.RunningExecution{
+		ID:              s.lastInFlight,
+		APIID:           api.ID,
+		APIName:         api.Name,
+		ScheduleID:      schedule.ID,
+		CollectionRunID: collectionRunID,
+		StartedAt:       started,
+	}
+	s.inFlight[info.ID] = &inFlightExecution{info: info, cancel: cancel}
+	s.inFlightMutex.Unlock()
+
+	s.emit(EventExecutionStarted, info)
+	return info.ID
+}
+
+// untrackExecution forgets a check that finished
+func (s *SchedulerService) untrackExecution(executionID int) {
+	s.inFlightMutex.Lock()
+	defer s.inFlightMutex.Unlock()
+	delete(s.inFlight, executionID)
+}
+
+// cancelled reports whether a check's context was cancelled through CancelExecution
+func cancelled(ctx context.Context) bool {
+	return errors.Is(context.Cause(ctx), errExecutionCancelled)
+}
+
+// RunningExecutions returns the checks in flight, oldest first
+func (s *SchedulerService) RunningExecutions() []models.RunningExecution {
+	s.inFlightMutex.Lock()
+	running := make([]models.RunningExecution, 0, len(s.inFlight))
+	for _, execution := range s.inFlight {
+		running = append(running, execution.info)
+	}
+	s.inFlightMutex.Unlock()
+
+	sort.Slice(running, func(i, j int) bool { return running[i].ID < running[j].ID })
+	return running
+}
+
+// CancelExecution aborts a check in flight, which is logged as a failure. Retries it had left are not made.
+func (s *SchedulerService) CancelExecution(executionID int) error {
+	s.inFlightMutex.Lock()
+	execution, exists := s.inFlight[executionID]
+	s.inFlightMutex.Unlock()
+	if !exists {
+		return fmt.Errorf("execution %d is not running", executionID)
+	}
+
+	execution.cancel(errExecutionCancelled)
+	return nil
+}
+
+// waitRetry waits out the delay before a retry of a check. Checks tracked through ctx are listed by PendingRetries
+// meanwhile, and the wait can be cut short through RetryNow and CancelRetry. It reports whether to retry, which is
+// false once the retry was cancelled or ctx is done.
+func (s *SchedulerService) waitRetry(ctx context.Context, api models.API, schedule models.Schedule, attempt int, lastError string, delay time.Duration) bool {
+	executionID, _ := ctx.Value(executionIDKey{}).(int)
+	decide := make(chan bool, 1)
+
+	s.inFlightMutex.Lock()
+	execution, tracked := s.inFlight[executionID]
+	if tracked {
+		execution.retry = &pendingRetry{
+			info: models.PendingRetry{
+				ExecutionID:   executionID,
+				APIID:         api.ID,
+				APIName:       execution.info.APIName,
+				ScheduleID:    schedule.ID,
+				Attempt:       attempt,
+				MaxAttempts:   schedule.RetryCount,
+				LastError:     lastError,
+				NextAttemptAt: time.Now().Add(delay),
+			},
+			decide: decide,
+		}
+	}
+	s.inFlightMutex.Unlock()
+	if tracked {
+		defer func() {
+			s.inFlightMutex.Lock()
+			execution.retry = nil
+			s.inFlightMutex.Unlock()
+		}()
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case retry := <-decide:
+		return retry
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// PendingRetries returns the checks waiting to retry, the soonest retry first
+func (s *SchedulerService) PendingRetries() []models.PendingRetry {
+	s.inFlightMutex.Lock()
+	retries := []models.PendingRetry{}
+	for _, execution := range s.inFlight {
+		if execution.retry != nil {
+			retries = append(retries, execution.retry.info)
+		}
+	}
+	s.inFlightMutex.Unlock()
+
+	sort.Slice(retries, func(i, j int) bool { return retries[i].NextAttemptAt.Before(retries[j].NextAttemptAt) })
+	return retries
+}
+
+// RetryNow makes a check waiting to retry do so right away
+func (s *SchedulerService) RetryNow(executionID int) error {
+	return s.decideRetry(executionID, true)
+}
+
+// CancelRetry makes a check waiting to retry give up, logging the failure of its last attempt
+func (s *SchedulerService) CancelRetry(executionID int) error {
+	return s.decideRetry(executionID, false)
+}
+
+// decideRetry ends the wait of a check before a retry
+func (s *SchedulerService) decideRetry(executionID int, retry bool) error {
+	s.inFlightMutex.Lock()
+	defer s.inFlightMutex.Unlock()
+	execution, exists := s.inFlight[executionID]
+	if !exists || execution.retry == nil {
+		return fmt.Errorf("execution %d is not waiting to retry", executionID)
+	}
+
+	// The wait is over once decided, so it drops off the pending retries right away
+	execution.retry.decide <- retry
+	execution.retry = nil
+	return nil
+}
@@ -0,0 +1,156 @@
+package scheduler
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"flowpulse/pkg/models"
+)
+
+// ingestionGuard counts the logs arriving each minute and samples successes while there are too many.
+// Only a success following another success of the same API is ever left out, so failure streaks and
+// recoveries are stored as they happened and alert rules see the same sequence of outcomes.
+type ingestionGuard struct {
+	mu          sync.Mutex
+	limits      models.IngestionGuard // With the defaults applied
+	windowStart time.Time             // Start of the current minute
+	logs        int                   // Logs that arrived during the current minute
+	bytes       int64                 // Response and error bytes that arrived during the current minute
+	sampling    bool
+	since       time.Time      // When sampling started
+	skipped     int64          // Successes left out since sampling started
+	successes   int            // Successes that could have been left out since sampling started
+	lastStatus  map[int]string // Status of the latest log of each API
+}
+
+// newIngestionGuard creates an ingestion guard with the given limits
+func newIngestionGuard(limits models.IngestionGuard) *ingestionGuard {
+	guard := &ingestionGuard{lastStatus: make(map[int]string)}
+	guard.setLimits(limits)
+	return guard
+}
+
+// setLimits replaces the guard's limits, applying the defaults to those left at 0
+func (g *ingestionGuard) setLimits(limits models.IngestionGuard) {
+	if limits.MaxLogsPerMinute <= 0 {
+		limits.MaxLogsPerMinute = models.DefaultIngestionMaxLogsPerMinute
+	}
+	if limits.MaxBytesPerMinute <= 0 {
+		limits.MaxBytesPerMinute = models.DefaultIngestionMaxBytesPerMinute
+	}
+	if limits.SampleRate <= 0 {
+		limits.SampleRate = models.DefaultIngestionSampleRate
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.limits = limits
+}
+
+// admit counts an arriving log and decides whether to store it. It also reports whether sampling started or
+// stopped with this log, along with the status at that moment and the channel to alert.
+func (g *ingestionGuard) admit(executionLog models.ExecutionLog, now time.Time) (store bool, changed bool, status models.IngestionStatus, channelID int) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.rollOver(now) {
+		changed = true
+		status = g.status()
+		g.skipped = 0
+	}
+
+	g.logs++
+	g.bytes += int64(len(executionLog.Response) + len(executionLog.Error))
+	if !g.sampling && (g.logs > g.limits.MaxLogsPerMinute || g.bytes > int64(g.limits.MaxBytesPerMinute)) {
+		g.sampling = true
+		g.since = now
+		g.skipped = 0
+		g.successes = 0
+		changed = true
+		status = g.status()
+	}
+
+	previous, seen := g.lastStatus[executionLog.APIID]
+	g.lastStatus[executionLog.APIID] = executionLog.Status
+
+	// A log that saw its content change is always kept, since its alert is raised from the stored log
+	store = true
+	if g.sampling && executionLog.CollectionRunID == 0 && executionLog.Status == models.ExecutionStatusSuccess &&
+		!executionLog.ContentChanged && seen && previous == models.ExecutionStatusSuccess {
+		g.successes++
+		if g.successes%g.limits.SampleRate != 0 {
+			store = false
+			g.skipped++
+		}
+	}
+	return store, changed, status, g.limits.AlertChannelID
+}
+
+// rollOver starts a new minute once the current one has passed and stops sampling when the minute that
+// passed stayed within the limits. It reports whether sampling stopped.
+func (g *ingestionGuard) rollOver(now time.Time) bool {
+	if now.Before(g.windowStart.Add(time.Minute)) {
+		return false
+	}
+	// A gap of more than a minute means nothing arrived during the minute before this one
+	withinLimits := now.Sub(g.windowStart) >= 2*time.Minute ||
+		(g.logs <= g.limits.MaxLogsPerMinute && g.bytes <= int64(g.limits.MaxBytesPerMinute))
+
+	g.windowStart = now.Truncate(time.Minute)
+	g.logs = 0
+	g.bytes = 0
+	if g.sampling && withinLimits {
+		g.sampling = false
+		return true
+	}
+	return false
+}
+
+// status reports the guard's current state; the caller holds the lock
+func (g *ingestionGuard) status() models.IngestionStatus {
+	status := models.IngestionStatus{
+		Sampling:    g.sampling,
+		Logs:        g.logs,
+		Bytes:       g.bytes,
+		SkippedLogs: g.skipped,
+		WindowStart: g.windowStart,
+	}
+	if g.sampling {
+		since := g.since
+		status.Since = &since
+	}
+	return status
+}
+
+// admitLog applies the ingestion guard to a log about to be stored, alerting when sampling starts or stops.
+// Manual runs aren't counted and, like collection runs, are always stored.
+func (s *SchedulerService) admitLog(executionLog models.ExecutionLog) bool {
+	if executionLog.ScheduleID == 0 && executionLog.CollectionRunID == 0 {
+		return true
+	}
+
+	store, changed, status, channelID := s.ingestion.admit(executionLog, time.Now())
+	if changed {
+		if status.Sampling {
+			log.Printf("Log ingestion guard started sampling: %d logs (%d bytes) this minute", status.Logs, status.Bytes)
+		} else {
+			log.Printf("Log ingestion guard stopped sampling after leaving out %d logs", status.SkippedLogs)
+		}
+		s.emit(EventIngestionChanged, status)
+		go s.notifier.HandleIngestion(channelID, status, status.Sampling)
+	}
+	return store
+}
+
+// SetIngestionGuard applies new limits on log volume right away
+func (s *SchedulerService) SetIngestionGuard(limits models.IngestionGuard) {
+	s.ingestion.setLimits(limits)
+}
+
+// IngestionStatus returns the log volume of the current minute and whether successes are being sampled
+func (s *SchedulerService) IngestionStatus() models.IngestionStatus {
+	s.ingestion.mu.Lock()
+	defer s.ingestion.mu.Unlock()
+	return s.ingestion.status()
+}
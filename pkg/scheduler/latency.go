@@ -0,0 +1,115 @@
+package scheduler
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"flowpulse/pkg/models"
+)
+
+// latencyAuditInterval is how often the latency targets of collections are evaluated
+const latencyAuditInterval = time.Minute
+
+// defaultLatencyWindowHours is the window latency targets without their own are evaluated over
+const defaultLatencyWindowHours = 24
+
+// latencyTargetKey identifies a latency target of a collection
+type latencyTargetKey struct {
+	collectionID int
+	target       models.LatencyTarget
+}
+
+// EvaluateLatencyTargets evaluates each latency target of a collection over the executions of its APIs in
+// the target's window. Executions that don't count toward uptime, such as expected failures, are left out.
+func (s *SchedulerService) EvaluateLatencyTargets(collection models.Collection) (models.LatencyCompliance, error) {
+	now := time.Now()
+	compliance := models.LatencyCompliance{
+		CollectionID: collection.ID,
+		Compliant:    true,
+		Targets:      make([]models.LatencyTargetResult, 0, len(collection.LatencyTargets)),
+		EvaluatedAt:  now,
+	}
+	for _, target := range collection.LatencyTargets {
+		if target.WindowHours <= 0 {
+			target.WindowHours = defaultLatencyWindowHours
+		}
+		since := now.Add(-time.Duration(target.WindowHours) * time.Hour)
+		actual, executions, err := s.db.GetCollectionLatencyPercentile(collection.ID, target.Percentile, since)
+		if err != nil {
+			return compliance, fmt.Errorf("failed to evaluate p%g latency target: %w", target.Percentile, err)
+		}
+
+		result := models.LatencyTargetResult{
+			Target:     target,
+			ActualMs:   actual,
+			Executions: executions,
+			Met:        executions == 0 || actual < target.ThresholdMs,
+		}
+		compliance.Compliant = compliance.Compliant && result.Met
+		compliance.Targets = append(compliance.Targets, result)
+	}
+	return compliance, nil
+}
+
+// auditLatencyTargets evaluates the latency targets of every collection until stop is closed
+func (s *SchedulerService) auditLatencyTargets(stop <-chan struct{}) {
+	ticker := time.NewTicker(latencyAuditInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			s.auditLatency()
+		case <-stop:
+			return
+		}
+	}
+}
+
+// auditLatency alerts the latency targets that were breached since the last audit, and those met again.
+// Collections in a maintenance window are not alerted until it closes.
+func (s *SchedulerService) auditLatency() {
+	collections, err := s.db.GetAllCollections()
+	if err != nil {
+		log.Printf("Failed to audit latency targets: %v", err)
+		return
+	}
+
+	evaluated := make(map[latencyTargetKey]bool)
+	for _, collection := range collections {
+		if len(collection.LatencyTargets) == 0 {
+			continue
+		}
+		compliance, err := s.EvaluateLatencyTargets(collection)
+		if err != nil {
+			log.Printf("Failed to audit latency targets of collection ID %d: %v", collection.ID, err)
+			continue
+		}
+		_, suppressed := s.activeMaintenanceWindow(models.API{CollectionID: collection.ID})
+
+		for _, result := range compliance.Targets {
+			key := latencyTargetKey{collectionID: collection.ID, target: result.Target}
+			evaluated[key] = true
+			if suppressed || result.Met != s.latencyAlerts[key] {
+				continue
+			}
+
+			if result.Met {
+				delete(s.latencyAlerts, key)
+				log.Printf("Collection ID %d meets its p%g latency target again", collection.ID, result.Target.Percentile)
+			} else {
+				s.latencyAlerts[key] = true
+				log.Printf("Collection ID %d breached its p%g latency target of %dms: %dms",
+					collection.ID, result.Target.Percentile, result.Target.ThresholdMs, result.ActualMs)
+			}
+			s.notifier.HandleLatencyTarget(collection, result, !result.Met)
+		}
+	}
+
+	// Targets that were removed or changed are forgotten without alerting
+	for key := range s.latencyAlerts {
+		if !evaluated[key] {
+			delete(s.latencyAlerts, key)
+		}
+	}
+}
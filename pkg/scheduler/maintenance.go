@@ -0,0 +1,87 @@
+package scheduler
+
+import (
+	"log"
+	"time"
+
+	"flowpulse/pkg/models"
+)
+
+// MaintenanceWindowCovers reports whether a maintenance window is open at the given time,
+// regardless of which APIs it covers
+func MaintenanceWindowCovers(window models.MaintenanceWindow, at time.Time) (bool, error) {
+	if window.Recurrence == "" {
+		return !at.Before(window.StartsAt) && at.Before(window.EndsAt), nil
+	}
+
+	if !window.StartsAt.IsZero() && at.Before(window.StartsAt) {
+		return false, nil
+	}
+	if !window.EndsAt.IsZero() && !at.Before(window.EndsAt) {
+		return false, nil
+	}
+	parsed, err := ParseCronIn(window.Recurrence, window.Timezone)
+	if err != nil {
+		return false, err
+	}
+
+	// The window is open when one of its occurrences opened within its duration before now
+	duration := time.Duration(window.DurationMinutes) * time.Minute
+	opened := parsed.Next(at.Add(-duration))
+	return !opened.IsZero() && !opened.After(at), nil
+}
+
+// maintenanceWindowScopes reports whether a maintenance window's scope includes an API
+func maintenanceWindowScopes(window models.MaintenanceWindow, api models.API) bool {
+	switch window.Scope {
+	case models.MaintenanceScopeGlobal:
+		return true
+	case models.MaintenanceScopeAPI:
+		return window.APIID == api.ID
+	case models.MaintenanceScopeCollection:
+		return api.CollectionID != 0 && window.CollectionID == api.CollectionID
+	}
+	return false
+}
+
+// activeMaintenanceWindow returns the maintenance window covering an API right now, if any
+func (s *SchedulerService) activeMaintenanceWindow(api models.API) (models.MaintenanceWindow, bool) {
+	windows, err := s.db.GetAllMaintenanceWindows()
+	if err != nil {
+		log.Printf("Failed to load maintenance windows: %v", err)
+		return models.MaintenanceWindow{}, false
+	}
+
+	now := time.Now()
+	for _, window := range windows {
+		if !maintenanceWindowScopes(window, api) {
+			continue
+		}
+		open, err := MaintenanceWindowCovers(window, now)
+		if err != nil {
+			log.Printf("Failed to evaluate maintenance window ID %d: %v", window.ID, err)
+			continue
+		}
+		if open {
+			return window, true
+		}
+	}
+	return models.MaintenanceWindow{}, false
+}
+
+// logSkippedRun records a scheduled run that was skipped for the given reason, e.g. a maintenance window.
+// It bypasses the log policy and alert rules, since nothing was checked.
+func (s *SchedulerService) logSkippedRun(api models.API, schedule models.Schedule, reason string) {
+	skipped, err := s.db.CreateExecutionLog(models.ExecutionLog{
+		APIID:      api.ID,
+		ScheduleID: schedule.ID,
+		Status:     models.ExecutionStatusSkipped,
+		Error:      reason,
+		ExecutedAt: time.Now(),
+	})
+	if err != nil {
+		log.Printf("Failed to log skipped run of schedule ID %d: %v", schedule.ID, err)
+		return
+	}
+	s.emit(EventExecutionCompleted, models.CompletedExecution{Log: skipped})
+}
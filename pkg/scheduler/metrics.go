@@ -0,0 +1,40 @@
+package scheduler
+
+import (
+	"log"
+	"time"
+)
+
+// recordExecution counts a finished check and its retries in the scheduler metrics
+func (s *SchedulerService) recordExecution(retries int) {
+	if err := s.db.RecordSchedulerExecution(time.Now(), retries); err != nil {
+		log.Printf("Failed to record scheduler metrics: %v", err)
+	}
+}
+
+// recordQueueWait records how long a scheduled run started after it was due
+func (s *SchedulerService) recordQueueWait(due time.Time) {
+	wait := time.Since(due)
+	if wait < 0 {
+		wait = 0
+	}
+	if err := s.db.RecordSchedulerQueueWait(time.Now(), wait); err != nil {
+		log.Printf("Failed to record scheduler metrics: %v", err)
+	}
+}
+
+// recordSkippedTicks counts the interval ticks an interval job missed while its run due at the given
+// time was going. The ticker keeps one missed tick to fire right away, so only the rest are dropped.
+func (s *SchedulerService) recordSkippedTicks(job *IntervalJob, due time.Time) {
+	s.recordSkippedRuns(int(time.Since(due)/job.interval) - 1)
+}
+
+// recordSkippedRuns counts scheduled runs that never started
+func (s *SchedulerService) recordSkippedRuns(skipped int) {
+	if skipped <= 0 {
+		return
+	}
+	if err := s.db.RecordSkippedRuns(time.Now(), skipped); err != nil {
+		log.Printf("Failed to record scheduler metrics: %v", err)
+	}
+}
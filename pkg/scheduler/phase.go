@@ -0,0 +1,74 @@
+package scheduler
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptrace"
+	"sync"
+
+	"flowpulse/pkg/models"
+)
+
+// phaseTracker follows a request through its phases so a failure can be attributed to one of them
+type phaseTracker struct {
+	mu    sync.Mutex
+	phase string
+}
+
+// traceRequestPhases attaches a tracker to the request. Requests start in the connect phase,
+// which is skipped straight to sending when an idle connection is reused.
+func traceRequestPhases(req *http.Request) (*http.Request, *phaseTracker) {
+	tracker := &phaseTracker{phase: models.RequestPhaseConnect}
+	trace := &httptrace.ClientTrace{
+		DNSStart:          func(httptrace.DNSStartInfo) { tracker.set(models.RequestPhaseDNS) },
+		DNSDone:           func(httptrace.DNSDoneInfo) { tracker.set(models.RequestPhaseConnect) },
+		TLSHandshakeStart: func() { tracker.set(models.RequestPhaseTLS) },
+		TLSHandshakeDone:  func(tls.ConnectionState, error) { tracker.set(models.RequestPhaseRequest) },
+		GotConn:           func(httptrace.GotConnInfo) { tracker.set(models.RequestPhaseRequest) },
+		WroteRequest:      func(httptrace.WroteRequestInfo) { tracker.set(models.RequestPhaseHeaders) },
+	}
+	return req.WithContext(httptrace.WithClientTrace(req.Context(), trace)), tracker
+}
+
+func (t *phaseTracker) set(phase string) {
+	t.mu.Lock()
+	t.phase = phase
+	t.mu.Unlock()
+}
+
+func (t *phaseTracker) get() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.phase
+}
+
+// isTimeout reports whether a request error was caused by a deadline rather than a refused or reset connection
+func isTimeout(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// phaseDescriptions phrase each request phase for error messages
+var phaseDescriptions = map[string]string{
+	models.RequestPhaseDNS:     "resolving the host name",
+	models.RequestPhaseConnect: "connecting",
+	models.RequestPhaseTLS:     "during the TLS handshake",
+	models.RequestPhaseRequest: "sending the request",
+	models.RequestPhaseHeaders: "waiting for response headers",
+	models.RequestPhaseBody:    "reading the response body",
+}
+
+// requestErrorMessage describes a transport error together with the phase it happened in
+func requestErrorMessage(err error, phase string, timedOut bool) string {
+	if timedOut {
+		return fmt.Sprintf("Request timed out while %s: %v", phaseDescriptions[phase], err)
+	}
+	return fmt.Sprintf("Request failed while %s: %v", phaseDescriptions[phase], err)
+}
@@ -0,0 +1,84 @@
+package scheduler
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"flowpulse/pkg/models"
+)
+
+// ParseProxyURL parses a proxy's URL, which must be http, https or socks5 and name a host
+func ParseProxyURL(config models.ProxyConfig) (*url.URL, error) {
+	proxyURL, err := url.Parse(strings.TrimSpace(config.URL))
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy URL: %w", err)
+	}
+	switch proxyURL.Scheme {
+	case "http", "https", "socks5", "socks5h":
+	default:
+		return nil, fmt.Errorf("proxy URL must start with http://, https:// or socks5://")
+	}
+	if proxyURL.Host == "" {
+		return nil, fmt.Errorf("proxy URL must include a host")
+	}
+	if config.Username != "" {
+		proxyURL.User = url.UserPassword(config.Username, config.Password)
+	}
+	return proxyURL, nil
+}
+
+// proxyFunc returns the Transport.Proxy function sending requests through a proxy, except to the hosts
+// of its no-proxy list
+func proxyFunc(config models.ProxyConfig) (func(*http.Request) (*url.URL, error), error) {
+	proxyURL, err := ParseProxyURL(config)
+	if err != nil {
+		return nil, err
+	}
+	noProxy := splitNoProxy(config.NoProxy)
+	return func(req *http.Request) (*url.URL, error) {
+		if bypassesProxy(req.URL.Hostname(), noProxy) {
+			return nil, nil
+		}
+		return proxyURL, nil
+	}, nil
+}
+
+// splitNoProxy splits a comma-separated no-proxy list into lowercase entries
+func splitNoProxy(list string) []string {
+	var entries []string
+	for _, entry := range strings.Split(list, ",") {
+		if entry = strings.ToLower(strings.TrimSpace(entry)); entry != "" {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+// bypassesProxy reports whether a host matches a no-proxy entry: "*", an IP or CIDR, an exact name, or a
+// domain, which also matches its subdomains whether or not it is written with a leading "." or "*."
+func bypassesProxy(host string, noProxy []string) bool {
+	host = strings.ToLower(host)
+	ip := net.ParseIP(host)
+	for _, entry := range noProxy {
+		if entry == "*" {
+			return true
+		}
+		if ip != nil {
+			if _, network, err := net.ParseCIDR(entry); err == nil && network.Contains(ip) {
+				return true
+			}
+			if entryIP := net.ParseIP(entry); entryIP != nil && entryIP.Equal(ip) {
+				return true
+			}
+			continue
+		}
+		domain := strings.TrimPrefix(strings.TrimPrefix(entry, "*"), ".")
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,39 @@
+package scheduler
+
+import (
+	"fmt"
+	"log"
+	"runtime/debug"
+
+	"flowpulse/pkg/models"
+)
+
+// internalErrorLog is the execution log recorded when a check panicked inside FlowPulse
+func internalErrorLog(api models.API, schedule models.Schedule, collectionRunID int, recovered interface{}) models.ExecutionLog {
+	return models.ExecutionLog{
+		APIID:           api.ID,
+		ScheduleID:      schedule.ID,
+		CollectionRunID: collectionRunID,
+		Status:          models.ExecutionStatusFailure,
+		Error:           fmt.Sprintf("Internal error: %v", recovered),
+		FailurePhase:    models.RequestPhaseInternal,
+	}
+}
+
+// recoverJob stops a panic in a job callback from crashing the scheduler and records it as a
+// failed execution. It must be deferred directly by the callback.
+func (s *SchedulerService) recoverJob(api models.API, schedule models.Schedule) {
+	recovered := recover()
+	if recovered == nil {
+		return
+	}
+	log.Printf("Job for schedule ID %d panicked: %v\n%s", schedule.ID, recovered, debug.Stack())
+
+	// Logging may be what panicked, so don't let a second panic escape either
+	defer func() {
+		if again := recover(); again != nil {
+			log.Printf("Failed to record internal error for schedule ID %d: %v", schedule.ID, again)
+		}
+	}()
+	s.logExecution(api, internalErrorLog(api, schedule, 0, recovered))
+}
@@ -0,0 +1,52 @@
+package scheduler
+
+import (
+	"fmt"
+	"net/http"
+
+	"flowpulse/pkg/models"
+)
+
+// defaultMaxRedirects is how many redirects are followed when an API doesn't set its own limit,
+// the same as Go's HTTP client
+const defaultMaxRedirects = 10
+
+// redirectTracker applies an API's redirect policy to one request and records the redirects it followed
+type redirectTracker struct {
+	follow bool
+	max    int
+	hops   []models.RedirectHop
+}
+
+// newRedirectTracker returns a tracker for a request of the API
+func newRedirectTracker(api models.API) *redirectTracker {
+	tracker := &redirectTracker{follow: api.RedirectPolicy != models.RedirectPolicyNone, max: api.MaxRedirects}
+	if tracker.max <= 0 {
+		tracker.max = defaultMaxRedirects
+	}
+	return tracker
+}
+
+// client returns a copy of client sharing its transport, which redirects as the tracker's policy says
+func (t *redirectTracker) client(client *http.Client) *http.Client {
+	redirecting := *client
+	redirecting.CheckRedirect = t.checkRedirect
+	return &redirecting
+}
+
+// checkRedirect is called before following each redirect, with the requests made so far in via.
+// Without following, the 3xx response itself is the response of the check.
+func (t *redirectTracker) checkRedirect(req *http.Request, via []*http.Request) error {
+	if !t.follow {
+		return http.ErrUseLastResponse
+	}
+	hop := models.RedirectHop{URL: via[len(via)-1].URL.String()}
+	if req.Response != nil {
+		hop.StatusCode = req.Response.StatusCode
+	}
+	t.hops = append(t.hops, hop)
+	if len(via) > t.max {
+		return fmt.Errorf("stopped after %d redirects", t.max)
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"flowpulse/pkg/models"
+)
+
+// ParseDNSServer parses the DNS server of an API, an IP address with an optional port, into the address its
+// lookups are sent to. The port defaults to 53.
+func ParseDNSServer(server string) (string, error) {
+	server = strings.TrimSpace(server)
+	host, port, err := net.SplitHostPort(server)
+	if err != nil {
+		host, port = strings.TrimSuffix(strings.TrimPrefix(server, "["), "]"), "53"
+	}
+	if net.ParseIP(host) == nil {
+		return "", fmt.Errorf("DNS server must be an IP address with an optional port, e.g. 1.1.1.1 or 10.0.0.2:53")
+	}
+	if n, err := strconv.Atoi(port); err != nil || n < 1 || n > 65535 {
+		return "", fmt.Errorf("invalid DNS server port %q", port)
+	}
+	return net.JoinHostPort(host, port), nil
+}
+
+// dnsResolver returns a resolver sending every lookup to the DNS server at address, bypassing the system's
+func dnsResolver(address string) *net.Resolver {
+	return &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			var dialer net.Dialer
+			return dialer.DialContext(ctx, network, address)
+		},
+	}
+}
+
+// resolverFor returns the resolver of an API's lookups: its own DNS server's, or the system's when it has none
+func resolverFor(api models.API) (*net.Resolver, error) {
+	if strings.TrimSpace(api.DNSServer) == "" {
+		return net.DefaultResolver, nil
+	}
+	address, err := ParseDNSServer(api.DNSServer)
+	if err != nil {
+		return nil, err
+	}
+	return dnsResolver(address), nil
+}
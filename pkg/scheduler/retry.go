@@ -0,0 +1,90 @@
+package scheduler
+
+import (
+	"math"
+	"math/rand"
+	"time"
+
+	"flowpulse/pkg/models"
+)
+
+// defaultRetryMultiplier is the growth factor of exponential delays when the policy doesn't set one
+const defaultRetryMultiplier = 2
+
+// maxDuration is the longest Duration, which retry delays saturate at instead of wrapping negative
+const maxDuration = time.Duration(math.MaxInt64)
+
+// retryDelay returns how long to wait before the given retry (1 for the first) of a schedule.
+// Jitter spreads the delays so checks hammering the same endpoint don't retry in lockstep.
+func retryDelay(schedule models.Schedule, retry int) time.Duration {
+	delay := baseRetryDelay(schedule, retry)
+	if jitter := schedule.RetryPolicy.Jitter; jitter > 0 {
+		delay = addDelays(delay, scaleDelay(delay, (rand.Float64()*2-1)*jitter))
+	}
+	return capRetryDelay(schedule.RetryPolicy, delay)
+}
+
+// maxRetryWait returns the longest time a check can spend waiting between its retries
+func maxRetryWait(schedule models.Schedule) time.Duration {
+	var total time.Duration
+	for retry := 1; retry <= schedule.RetryCount; retry++ {
+		delay := baseRetryDelay(schedule, retry)
+		delay = addDelays(delay, scaleDelay(delay, schedule.RetryPolicy.Jitter))
+		total = addDelays(total, capRetryDelay(schedule.RetryPolicy, delay))
+	}
+	return total
+}
+
+// baseRetryDelay returns the delay before a retry without jitter
+func baseRetryDelay(schedule models.Schedule, retry int) time.Duration {
+	delay := secondsToDelay(schedule.FallbackDelay)
+	if schedule.RetryPolicy.Strategy != models.RetryStrategyExponential || retry <= 1 {
+		return capRetryDelay(schedule.RetryPolicy, delay)
+	}
+
+	multiplier := schedule.RetryPolicy.Multiplier
+	if multiplier <= 0 {
+		multiplier = defaultRetryMultiplier
+	}
+	// Compute in float to cap huge exponents before they overflow a Duration
+	scaled := float64(delay) * math.Pow(multiplier, float64(retry-1))
+	return capRetryDelay(schedule.RetryPolicy, floatToDelay(scaled))
+}
+
+// capRetryDelay limits a delay to the policy's maximum
+func capRetryDelay(policy models.RetryPolicy, delay time.Duration) time.Duration {
+	if maxDelay := secondsToDelay(policy.MaxDelay); maxDelay > 0 && delay > maxDelay {
+		return maxDelay
+	}
+	return delay
+}
+
+// secondsToDelay converts seconds to a Duration, saturating at maxDuration
+func secondsToDelay(seconds int) time.Duration {
+	if int64(seconds) > int64(maxDuration/time.Second) {
+		return maxDuration
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// scaleDelay multiplies a delay by a jitter factor, saturating at maxDuration
+func scaleDelay(delay time.Duration, factor float64) time.Duration {
+	return floatToDelay(factor * float64(delay))
+}
+
+// floatToDelay converts a delay computed in float to a Duration, saturating at maxDuration.
+// float64(math.MaxInt64) rounds up to 2^63, which doesn't fit, so the comparison happens before converting.
+func floatToDelay(delay float64) time.Duration {
+	if delay >= float64(maxDuration) {
+		return maxDuration
+	}
+	return time.Duration(delay)
+}
+
+// addDelays adds two delays, saturating at maxDuration. Only b may be negative, when jitter shortens a delay.
+func addDelays(a, b time.Duration) time.Duration {
+	if b > 0 && a > maxDuration-b {
+		return maxDuration
+	}
+	return a + b
+}
@@ -1,12 +1,12 @@
 package scheduler
 
 import (
-	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
-	"io"
 	"log"
 	"net/http"
+	"runtime/debug"
 	"strconv"
 	"strings"
 	"sync"
@@ -14,45 +14,136 @@ import (
 
 	"github.com/robfig/cron/v3"
 
+	"flowpulse/pkg/assertions"
+	"flowpulse/pkg/auth"
 	"flowpulse/pkg/database"
+	"flowpulse/pkg/environments"
+	"flowpulse/pkg/extraction"
 	"flowpulse/pkg/models"
+	"flowpulse/pkg/notify"
+	"flowpulse/pkg/openapi"
+	"flowpulse/pkg/params"
+	"flowpulse/pkg/secrets"
+	"flowpulse/pkg/webhooks"
 )
 
 // SchedulerService handles API execution scheduling
 type SchedulerService struct {
-	db            *database.DBService
-	cron          *cron.Cron
-	intervalJobs  map[int]*IntervalJob
-	jobEntries    map[int]cron.EntryID
-	client        *http.Client
-	intervalMutex sync.Mutex
-	cronMutex     sync.Mutex
+	db             database.Store
+	cron           *cron.Cron
+	intervalJobs   map[int]*IntervalJob
+	jobEntries     map[int]cron.EntryID
+	onceJobs       map[int]*time.Timer
+	afterJobs      map[int]afterJob          // Active "after" schedules by ID, fired by the runs they wait for
+	chained        map[int]map[string]string // Variables extracted from responses, per collection
+	client         *http.Client              // Shared by checks without their own proxy or TLS settings
+	clients        clientCache               // Clients of checks with their own proxy or TLS settings
+	environments   *environments.Service
+	secrets        *secrets.Service
+	notifier       *notify.Service
+	eventWebhooks  *webhooks.Dispatcher // Posts check results to event webhooks
+	auth           *auth.Service
+	intervalMutex  sync.Mutex
+	cronMutex      sync.Mutex
+	onceMutex      sync.Mutex
+	afterMutex     sync.Mutex
+	chainMutex     sync.Mutex
+	running        sync.WaitGroup      // Checks in flight, waited for on shutdown
+	executors      map[string]Executor // Executors of the check types, by type
+	executorMutex  sync.RWMutex
+	lastRuns       map[int]time.Time // When each schedule's job last ran, or was started if it hasn't run yet
+	lastRunMutex   sync.Mutex
+	staleAlerted   map[int]bool               // Schedules already alerted about being stale, until they run again
+	latencyAlerts  map[latencyTargetKey]bool  // Latency targets already alerted about being breached, until they are met again
+	limiter        *concurrencyLimiter        // Bounds the scheduled checks in flight
+	ingestion      *ingestionGuard            // Samples successes while too many logs arrive
+	inFlight       map[int]*inFlightExecution // Checks in flight by execution ID, to list and cancel them
+	lastInFlight   int                        // ID of the latest check put in flight
+	inFlightMutex  sync.Mutex
+	batches        map[int]*models.ExecutionBatch // Batches of manual runs by ID, see ExecuteAPIsManually
+	lastBatch      int                            // ID of the latest batch
+	batchMutex     sync.Mutex
+	shared         map[int]*sharedExecution // Latest check of each API that triggers may join, see runShared
+	coalesceWindow time.Duration            // How long after a check starts triggers of its API join it (0 for never)
+	sharedMutex    sync.Mutex
+	emitter        EventEmitter // Receives events as checks run and jobs change state
+	emitterMutex   sync.RWMutex
+	stopWatchers   chan struct{} // Closed to stop watching for clock changes, stale jobs, latency targets and deprecated APIs
+	stopWatchOnce  sync.Once
 }
 
+// shutdownTimeout bounds how long Shutdown waits for checks in flight
+const shutdownTimeout = 30 * time.Second
+
+// executionCeiling is the hard limit on a check, not counting retry delays, after which it is failed
+// even if a request hangs beyond the client timeout
+const executionCeiling = 5 * time.Minute
+
 // IntervalJob represents a job that runs at fixed intervals
 type IntervalJob struct {
 	scheduleID int
 	apiID      int
 	interval   time.Duration
+	startedAt  time.Time // When the ticker started, which every tick is a whole number of intervals after
 	ticker     *time.Ticker
-	done       chan bool
+	done       chan struct{} // Closed to stop the job, including a run still waiting for a slot
+	wake       chan struct{} // Signalled to run right away after the system slept or its clock changed
 	isRunning  bool
 }
 
 // NewSchedulerService creates a new scheduler service
-func NewSchedulerService(db *database.DBService) *SchedulerService {
+func NewSchedulerService(db database.Store) *SchedulerService {
 	cronScheduler := cron.New(cron.WithSeconds())
 	cronScheduler.Start()
+	secretStore := secrets.NewService(db)
+	concurrency, err := db.GetSchedulerConcurrency()
+	if err != nil {
+		log.Printf("Failed to load scheduler concurrency, running checks without a limit: %v", err)
+	}
+	ingestionLimits, err := db.GetIngestionGuard()
+	if err != nil {
+		log.Printf("Failed to load ingestion guard, using the default limits: %v", err)
+	}
+	coalescing, err := db.GetTriggerCoalescing()
+	if err != nil {
+		log.Printf("Failed to load trigger coalescing, running every trigger on its own: %v", err)
+	}
 
-	return &SchedulerService{
+	service := &SchedulerService{
 		db:           db,
 		cron:         cronScheduler,
 		intervalJobs: make(map[int]*IntervalJob),
 		jobEntries:   make(map[int]cron.EntryID),
+		onceJobs:     make(map[int]*time.Timer),
+		afterJobs:    make(map[int]afterJob),
+		chained:      make(map[int]map[string]string),
+		clients:      clientCache{clients: make(map[transportKey]*http.Client)},
 		client: &http.Client{
 			Timeout: 30 * time.Second,
 		},
+		environments:   environments.NewService(db, secretStore),
+		secrets:        secretStore,
+		notifier:       notify.NewService(db),
+		eventWebhooks:  webhooks.NewDispatcher(db),
+		auth:           auth.NewService(db),
+		lastRuns:       make(map[int]time.Time),
+		staleAlerted:   make(map[int]bool),
+		latencyAlerts:  make(map[latencyTargetKey]bool),
+		limiter:        newConcurrencyLimiter(concurrency),
+		ingestion:      newIngestionGuard(ingestionLimits),
+		inFlight:       make(map[int]*inFlightExecution),
+		batches:        make(map[int]*models.ExecutionBatch),
+		shared:         make(map[int]*sharedExecution),
+		coalesceWindow: time.Duration(coalescing.WindowSeconds) * time.Second,
+		executors:      make(map[string]Executor),
+		stopWatchers:   make(chan struct{}),
 	}
+	service.registerBuiltinExecutors()
+	go service.watchClock(service.stopWatchers)
+	go service.auditStaleJobs(service.stopWatchers)
+	go service.auditLatencyTargets(service.stopWatchers)
+	go service.retireDeprecatedAPIs(service.stopWatchers)
+	return service
 }
 
 // StartAllJobs starts all active jobs from the database
@@ -81,6 +172,20 @@ func (s *SchedulerService) ScheduleJob(schedule models.Schedule) error {
 			return nil // Job already scheduled
 		}
 		s.cronMutex.Unlock()
+	} else if schedule.Type == "once" {
+		s.onceMutex.Lock()
+		if _, exists := s.onceJobs[schedule.ID]; exists {
+			s.onceMutex.Unlock()
+			return nil // Job already scheduled
+		}
+		s.onceMutex.Unlock()
+	} else if schedule.Type == "after" {
+		s.afterMutex.Lock()
+		if _, exists := s.afterJobs[schedule.ID]; exists {
+			s.afterMutex.Unlock()
+			return nil // Job already scheduled
+		}
+		s.afterMutex.Unlock()
 	} else {
 		s.intervalMutex.Lock()
 		if _, exists := s.intervalJobs[schedule.ID]; exists {
@@ -97,13 +202,16 @@ func (s *SchedulerService) ScheduleJob(schedule models.Schedule) error {
 	}
 
 	if schedule.Type == "cron" {
-		// Schedule with cron
-		entryID, err := s.cron.AddFunc(schedule.Expression, func() {
-			s.executeAPI(api, schedule)
-		})
+		// Schedule with cron, in the schedule's own timezone
+		cronSchedule, err := ParseCron(schedule)
 		if err != nil {
 			return fmt.Errorf("failed to add cron job: %w", err)
 		}
+		entryID := s.cron.Schedule(cronSchedule, cron.FuncJob(func() {
+			// Cron expressions fire on whole seconds, so anything past the second is time spent waiting to start
+			s.recordQueueWait(time.Now().Truncate(time.Second))
+			s.executeAPI(api, schedule)
+		}))
 
 		s.cronMutex.Lock()
 		s.jobEntries[schedule.ID] = entryID
@@ -121,8 +229,10 @@ func (s *SchedulerService) ScheduleJob(schedule models.Schedule) error {
 			scheduleID: schedule.ID,
 			apiID:      schedule.APIID,
 			interval:   interval,
+			startedAt:  time.Now(),
 			ticker:     time.NewTicker(interval),
-			done:       make(chan bool),
+			done:       make(chan struct{}),
+			wake:       make(chan struct{}, 1),
 			isRunning:  true,
 		}
 
@@ -132,10 +242,31 @@ func (s *SchedulerService) ScheduleJob(schedule models.Schedule) error {
 
 		// Start the interval job
 		go s.runIntervalJob(job, api, schedule)
+	} else if schedule.Type == "once" {
+		// Parse the RFC3339 timestamp to run at
+		runAt, err := time.Parse(time.RFC3339, schedule.Expression)
+		if err != nil {
+			return fmt.Errorf("invalid timestamp: %w", err)
+		}
+
+		// A timestamp already in the past fires immediately, so a run missed while the app was closed still happens once
+		s.onceMutex.Lock()
+		s.onceJobs[schedule.ID] = time.AfterFunc(time.Until(runAt), func() {
+			s.runOnceJob(api, schedule)
+		})
+		s.onceMutex.Unlock()
+	} else if schedule.Type == "after" {
+		// No timer; runs of the schedule it waits for fire it, see runDependents
+		s.afterMutex.Lock()
+		s.afterJobs[schedule.ID] = afterJob{api: api, schedule: schedule}
+		s.afterMutex.Unlock()
 	} else {
 		return fmt.Errorf("unsupported schedule type: %s", schedule.Type)
 	}
 
+	// A job that never runs is stale counting from when it was scheduled
+	s.markRun(schedule.ID)
+	s.emitScheduleState(schedule.ID, models.ScheduleStateScheduled)
 	return nil
 }
 
@@ -147,6 +278,7 @@ func (s *SchedulerService) StopJob(scheduleID int) error {
 		s.cron.Remove(entryID)
 		delete(s.jobEntries, scheduleID)
 		s.cronMutex.Unlock()
+		s.emitScheduleState(scheduleID, models.ScheduleStateStopped)
 		return nil
 	}
 	s.cronMutex.Unlock()
@@ -154,14 +286,36 @@ func (s *SchedulerService) StopJob(scheduleID int) error {
 	// Try to stop interval job
 	s.intervalMutex.Lock()
 	if job, exists := s.intervalJobs[scheduleID]; exists {
-		job.done <- true
+		close(job.done)
 		job.ticker.Stop()
 		delete(s.intervalJobs, scheduleID)
 		s.intervalMutex.Unlock()
+		s.emitScheduleState(scheduleID, models.ScheduleStateStopped)
 		return nil
 	}
 	s.intervalMutex.Unlock()
 
+	// Try to stop one-time job
+	s.onceMutex.Lock()
+	if timer, exists := s.onceJobs[scheduleID]; exists {
+		timer.Stop()
+		delete(s.onceJobs, scheduleID)
+		s.onceMutex.Unlock()
+		s.emitScheduleState(scheduleID, models.ScheduleStateStopped)
+		return nil
+	}
+	s.onceMutex.Unlock()
+
+	// Try to stop "after" job
+	s.afterMutex.Lock()
+	if _, exists := s.afterJobs[scheduleID]; exists {
+		delete(s.afterJobs, scheduleID)
+		s.afterMutex.Unlock()
+		s.emitScheduleState(scheduleID, models.ScheduleStateStopped)
+		return nil
+	}
+	s.afterMutex.Unlock()
+
 	return fmt.Errorf("job not found for schedule ID: %d", scheduleID)
 }
 
@@ -178,12 +332,25 @@ func (s *SchedulerService) StopAllJobs() {
 	// Stop interval jobs
 	s.intervalMutex.Lock()
 	for scheduleID, job := range s.intervalJobs {
-		job.done <- true
+		close(job.done)
 		job.ticker.Stop()
 		delete(s.intervalJobs, scheduleID)
 	}
 	s.intervalMutex.Unlock()
 
+	// Stop one-time jobs
+	s.onceMutex.Lock()
+	for scheduleID, timer := range s.onceJobs {
+		timer.Stop()
+		delete(s.onceJobs, scheduleID)
+	}
+	s.onceMutex.Unlock()
+
+	// Stop "after" jobs
+	s.afterMutex.Lock()
+	clear(s.afterJobs)
+	s.afterMutex.Unlock()
+
 	// Stop the cron scheduler
 	s.cron.Stop()
 }
@@ -192,80 +359,480 @@ func (s *SchedulerService) StopAllJobs() {
 func (s *SchedulerService) runIntervalJob(job *IntervalJob, api models.API, schedule models.Schedule) {
 	for {
 		select {
-		case <-job.ticker.C:
-			s.executeAPI(api, schedule)
+		case due := <-job.ticker.C:
+			s.recordQueueWait(due)
+			s.executeAPIUntil(api, schedule, job.done)
+			s.recordSkippedTicks(job, due)
+		case <-job.wake:
+			// Drop a tick left over from before the sleep so only one check runs now
+			select {
+			case <-job.ticker.C:
+			default:
+			}
+			s.executeAPIUntil(api, schedule, job.done)
 		case <-job.done:
 			return
 		}
 	}
 }
 
-// executeAPI executes the API call and logs the result
+// runOnceJob executes a one-time job and marks its schedule inactive so it never runs again
+func (s *SchedulerService) runOnceJob(api models.API, schedule models.Schedule) {
+	s.onceMutex.Lock()
+	if _, exists := s.onceJobs[schedule.ID]; !exists {
+		s.onceMutex.Unlock()
+		return // Stopped before the timer fired
+	}
+	delete(s.onceJobs, schedule.ID)
+	s.onceMutex.Unlock()
+
+	s.executeAPI(api, schedule)
+
+	// Reload the schedule so edits made while it was pending aren't overwritten
+	current, err := s.db.GetScheduleByID(schedule.ID)
+	if err != nil {
+		log.Printf("Failed to load one-time schedule ID %d: %v", schedule.ID, err)
+		return
+	}
+	current.IsActive = false
+	if err := s.db.UpdateSchedule(current); err != nil {
+		log.Printf("Failed to deactivate one-time schedule ID %d: %v", schedule.ID, err)
+		return
+	}
+	s.emitScheduleState(schedule.ID, models.ScheduleStateCompleted)
+}
+
+// executeAPI executes the API call and logs the result. Scheduled runs wait for the concurrency limits
+// to leave a slot first, and triggers within the coalescing window join the check already started.
+// Once a scheduled run is done, it fires the "after" schedules waiting for its outcome.
+// It is the callback of every job, so a panic is recovered here and logged as an internal error.
 func (s *SchedulerService) executeAPI(api models.API, schedule models.Schedule) {
+	s.executeAPIUntil(api, schedule, nil)
+}
+
+// executeAPIUntil is executeAPI for a job that can be stopped while its run waits for a slot,
+// in which case the run is dropped without being logged
+func (s *SchedulerService) executeAPIUntil(api models.API, schedule models.Schedule, stop <-chan struct{}) {
+	defer s.recoverJob(api, schedule)
+	s.markRun(schedule.ID)
+	if schedule.ID == 0 {
+		s.runShared(api, schedule, false)
+		return
+	}
+	if window, skip := s.activeMaintenanceWindow(api); skip {
+		s.logSkippedRun(api, schedule, fmt.Sprintf("Skipped during maintenance window %q", window.Name))
+		return
+	}
+	if !s.acquireSlot(api, stop) {
+		select {
+		case <-stop:
+			return
+		default:
+		}
+		s.recordSkippedRuns(1)
+		s.logSkippedRun(api, schedule, "Skipped because the previous check of this API was still running or waiting to run")
+		return
+	}
+	defer s.limiter.release(api)
+	executionLog := s.runShared(api, schedule, false)
+	s.runDependents(schedule, executionLog)
+}
+
+// runCheck executes the API call, logs the result and returns the execution log.
+// The log's ID is 0 when the API's log policy skipped storing it. A check still going after
+// executionCeiling plus its retry delays is abandoned and logged as failed, so a hung request
+// can't block its job. Until then the check can be cancelled through CancelExecution.
+func (s *SchedulerService) runCheck(api models.API, schedule models.Schedule, collectionRunID int) (executionLog models.ExecutionLog) {
+	s.running.Add(1)
+	defer s.running.Done()
+
+	ceiling := executionCeiling + maxRetryWait(schedule)
+	timeoutCtx, cancel := context.WithTimeout(context.Background(), ceiling)
+	defer cancel()
+	ctx, cancelCause := context.WithCancelCause(timeoutCtx)
+	defer cancelCause(nil)
+
+	started := time.Now()
+	executionID := s.trackExecution(api, schedule, collectionRunID, started, cancelCause)
+	defer s.untrackExecution(executionID)
+	ctx = context.WithValue(ctx, executionIDKey{}, executionID)
+	defer func() {
+		s.emit(EventExecutionCompleted, models.CompletedExecution{ExecutionID: executionID, Log: executionLog})
+	}()
+
+	type result struct {
+		api     models.API
+		log     models.ExecutionLog
+		retries int
+	}
+	done := make(chan result, 1)
+	go func() {
+		// A panicking check, e.g. from a bad template, fails on its own instead of taking the app down
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				log.Printf("Check of API ID %d panicked: %v\n%s", api.ID, recovered, debug.Stack())
+				done <- result{api: api, log: internalErrorLog(api, schedule, collectionRunID, recovered)}
+			}
+		}()
+		resolved, checked, retries := s.check(ctx, api, schedule, collectionRunID, true)
+		done <- result{resolved, checked, retries}
+	}()
+
+	select {
+	case r := <-done:
+		s.recordExecution(r.retries)
+		if cancelled(ctx) {
+			// Whatever the interrupted request failed with, the reason is the cancellation
+			r.log.Status = models.ExecutionStatusFailure
+			r.log.Error = cancelledMessage
+			r.log.TimedOut = false
+		}
+		return s.logExecution(r.api, r.log)
+	case <-ctx.Done():
+		if cancelled(ctx) {
+			log.Printf("Execution of API ID %d was cancelled", api.ID)
+			s.recordExecution(0)
+			return s.logExecution(api, models.ExecutionLog{
+				APIID:           api.ID,
+				ScheduleID:      schedule.ID,
+				CollectionRunID: collectionRunID,
+				Status:          models.ExecutionStatusFailure,
+				Error:           cancelledMessage,
+				DurationMs:      time.Since(started).Milliseconds(),
+			})
+		}
+		log.Printf("Execution of API ID %d exceeded %v and was abandoned", api.ID, ceiling)
+		s.recordExecution(0)
+		return s.logExecution(api, models.ExecutionLog{
+			APIID:           api.ID,
+			ScheduleID:      schedule.ID,
+			CollectionRunID: collectionRunID,
+			Status:          models.ExecutionStatusFailure,
+			Error:           fmt.Sprintf("Execution exceeded the hard limit of %v and was aborted", ceiling),
+			DurationMs:      ceiling.Milliseconds(),
+			TimedOut:        true,
+		})
+	}
+}
+
+// check executes the API call with retries and returns the resolved API, the unlogged execution
+// log and the number of retries made. Requests and retry delays stop when ctx is done. With extract,
+// a successful response feeds the variables of the API's extractions to the rest of its collection.
+func (s *SchedulerService) check(ctx context.Context, api models.API, schedule models.Schedule, collectionRunID int, extract bool) (models.API, models.ExecutionLog, int) {
+	retries := 0
 	var statusCode int
 	var responseBody, errMsg string
+	var duration time.Duration
+	var failurePhase, finalURL string
+	var timedOut bool
+	var redirectChain []models.RedirectHop
+	status := models.ExecutionStatusFailure
+
+	// Substitute environment and chained variables into the request
+	api, err := s.environments.Resolve(api, s.chainedVariables(api.CollectionID))
+	if err != nil {
+		errMsg = fmt.Sprintf("Failed to resolve environment variables: %v", err)
+		return api, models.ExecutionLog{APIID: api.ID, ScheduleID: schedule.ID, CollectionRunID: collectionRunID, Status: models.ExecutionStatusFailure, Error: errMsg}, retries
+	}
 
-	// Prepare request
-	req, err := s.prepareAPIRequest(api)
+	// Load the assertions evaluated against each response
+	apiAssertions, err := s.db.GetAssertionsByAPIID(api.ID)
 	if err != nil {
-		errMsg = fmt.Sprintf("Failed to prepare request: %v", err)
-		s.logExecution(api.ID, schedule.ID, 0, "", errMsg)
-		return
+		errMsg = fmt.Sprintf("Failed to load assertions: %v", err)
+		return api, models.ExecutionLog{APIID: api.ID, ScheduleID: schedule.ID, CollectionRunID: collectionRunID, Status: models.ExecutionStatusFailure, Error: errMsg}, retries
+	}
+	var assertionResults []models.AssertionResult
+
+	// Load the extractions that feed variables to later requests in the collection
+	extractions, err := s.db.GetExtractionsByAPIID(api.ID)
+	if err != nil {
+		errMsg = fmt.Sprintf("Failed to load extractions: %v", err)
+		return api, models.ExecutionLog{APIID: api.ID, ScheduleID: schedule.ID, CollectionRunID: collectionRunID, Status: models.ExecutionStatusFailure, Error: errMsg}, retries
+	}
+	var responseHeaders, requestHeaders http.Header
+
+	// Load the credentials used to authenticate the request
+	authConfig, err := s.auth.ConfigForAPI(api)
+	if err != nil {
+		errMsg = fmt.Sprintf("Failed to load auth config: %v", err)
+		return api, models.ExecutionLog{APIID: api.ID, ScheduleID: schedule.ID, CollectionRunID: collectionRunID, Status: models.ExecutionStatusFailure, Error: errMsg}, retries
+	}
+
+	// Find the executor that runs checks of the API's type
+	executor, err := s.executorFor(api.CheckType)
+	if err != nil {
+		errMsg = err.Error()
+		return api, models.ExecutionLog{APIID: api.ID, ScheduleID: schedule.ID, CollectionRunID: collectionRunID, Status: models.ExecutionStatusFailure, Error: errMsg}, retries
 	}
 
-	// Execute with retry logic
+	// Parse the status codes that decide whether a response is healthy
+	codes, err := parseOutcomeCodes(api, executor.SuccessCodes())
+	if err != nil {
+		errMsg = fmt.Sprintf("Invalid status codes: %v", err)
+		return api, models.ExecutionLog{APIID: api.ID, ScheduleID: schedule.ID, CollectionRunID: collectionRunID, Status: models.ExecutionStatusFailure, Error: errMsg}, retries
+	}
+
+	// Load the OpenAPI spec responses must conform to
+	var spec *openapi.Document
+	if api.ValidateContract && api.SpecID != 0 {
+		spec, err = s.loadSpec(api.SpecID)
+		if err != nil {
+			errMsg = fmt.Sprintf("Failed to load OpenAPI spec: %v", err)
+			return api, models.ExecutionLog{APIID: api.ID, ScheduleID: schedule.ID, CollectionRunID: collectionRunID, Status: models.ExecutionStatusFailure, Error: errMsg}, retries
+		}
+	}
+
+	// Execute with retry logic, waiting between attempts as the schedule's retry policy says
 	retryCount := schedule.RetryCount
-	fallbackDelay := time.Duration(schedule.FallbackDelay) * time.Second
 
 	for attempt := 0; attempt <= retryCount; attempt++ {
 		if attempt > 0 {
-			log.Printf("Retrying API execution (attempt %d/%d) for schedule ID %d after %v delay", 
-				attempt, retryCount, schedule.ID, fallbackDelay)
-			time.Sleep(fallbackDelay)
-		}
-
-		resp, err := s.client.Do(req)
-		if err == nil {
-			// Read response
-			buf := new(bytes.Buffer)
-			buf.ReadFrom(resp.Body)
-			responseBody = buf.String()
-			resp.Body.Close()
-			statusCode = resp.StatusCode
-
-			// Break on success (2xx status code)
-			if statusCode >= 200 && statusCode < 300 {
+			retries++
+			delay := retryDelay(schedule, attempt)
+			log.Printf("Retrying API execution (attempt %d/%d) for schedule ID %d after %v delay",
+				attempt, retryCount, schedule.ID, delay)
+			if !s.waitRetry(ctx, api, schedule, attempt, errMsg, delay) {
+				if ctx.Err() != nil {
+					return api, models.ExecutionLog{APIID: api.ID, ScheduleID: schedule.ID, CollectionRunID: collectionRunID, Status: models.ExecutionStatusFailure, Error: errMsg}, retries
+				}
+				// Cancelled through CancelRetry: the last attempt's result stands
+				retries--
+				errMsg = fmt.Sprintf("Remaining retries were cancelled. Last error: %s", errMsg)
 				break
 			}
+		}
+
+		result, err := executor.Execute(ctx, Check{API: api, Assertions: apiAssertions, Auth: authConfig})
+		if err != nil {
+			errMsg = fmt.Sprintf("Failed to prepare check: %v", err)
+			return api, models.ExecutionLog{APIID: api.ID, ScheduleID: schedule.ID, CollectionRunID: collectionRunID, Status: models.ExecutionStatusFailure, Error: errMsg}, retries
+		}
+		statusCode, responseHeaders, responseBody, duration = result.StatusCode, result.Headers, result.Body, result.Duration
+		requestHeaders = result.RequestHeaders
+		finalURL, redirectChain = result.FinalURL, result.Redirects
+		err = result.Err
+		failurePhase, timedOut, errMsg = "", false, ""
+		if err != nil {
+			failurePhase, timedOut = result.Phase, isTimeout(err)
+			errMsg = requestErrorMessage(err, failurePhase, timedOut)
+		}
 
-			// If not successful and we have more retries, continue
-			if attempt < retryCount {
-				errMsg = fmt.Sprintf("API returned non-success status code: %d", statusCode)
-				continue
+		// Evaluate assertions against any response that was received
+		assertionResults = nil
+		if err == nil && (len(apiAssertions) > 0 || spec != nil) {
+			response := assertions.Response{
+				StatusCode: statusCode,
+				Headers:    responseHeaders,
+				Body:       responseBody,
+				DurationMs: duration.Milliseconds(),
+			}
+			assertionResults = assertions.Evaluate(apiAssertions, response)
+			if spec != nil {
+				assertionResults = append(assertionResults, assertions.Contract(spec, api.SpecOperation, response))
 			}
-		} else {
-			if attempt < retryCount {
-				errMsg = fmt.Sprintf("Request failed: %v", err)
-				continue
-			} else {
-				errMsg = fmt.Sprintf("All retry attempts failed. Last error: %v", err)
-				statusCode = 0
+		}
+
+		// Break once the outcome matches what the API is expected to do and all assertions hold.
+		// Degraded answers aren't retried, since the endpoint responded as designed under load.
+		outcome := codes.classify(api, statusCode, err)
+		if outcome != models.ExecutionStatusFailure && assertions.AllPassed(assertionResults) {
+			status = outcome
+			if outcome == models.ExecutionStatusDegraded {
+				errMsg = fmt.Sprintf("API returned degraded status code: %d", statusCode)
 			}
+			break
+		}
+
+		if err == nil && outcome == models.ExecutionStatusFailure {
+			errMsg = unexpectedOutcomeMessage(api, statusCode)
+		} else if err == nil {
+			errMsg = assertions.FailureMessage(assertionResults)
+		} else if attempt == retryCount && retryCount > 0 {
+			errMsg = fmt.Sprintf("All retry attempts failed. Last error: %s", errMsg)
+		}
+	}
+
+	// An abandoned check must not feed variables to the steps that already moved on
+	if extract && status == models.ExecutionStatusSuccess && ctx.Err() == nil {
+		s.extractVariables(api, extractions, responseHeaders, responseBody)
+	}
+
+	// Slow answers are degraded rather than failed, since the endpoint is available but not performing.
+	// Negative checks pass by not getting an answer, so their response time means nothing.
+	var latencyBreach string
+	if status != models.ExecutionStatusFailure && api.ExpectedOutcome != models.ExpectedOutcomeFailure {
+		var thresholdMs int
+		latencyBreach, thresholdMs = exceededLatencyThreshold(api, duration)
+		if latencyBreach != "" && status == models.ExecutionStatusSuccess {
+			status = models.ExecutionStatusDegraded
+			errMsg = fmt.Sprintf("Response took %dms, over the %s latency threshold of %dms",
+				duration.Milliseconds(), latencyBreach, thresholdMs)
+		}
+	}
+
+	executionLog := models.ExecutionLog{
+		APIID:            api.ID,
+		ScheduleID:       schedule.ID,
+		CollectionRunID:  collectionRunID,
+		StatusCode:       statusCode,
+		Status:           status,
+		Response:         responseBody,
+		Error:            errMsg,
+		DurationMs:       duration.Milliseconds(),
+		AssertionResults: assertionResults,
+		FailurePhase:     failurePhase,
+		TimedOut:         timedOut,
+		LatencyBreach:    latencyBreach,
+		FinalURL:         finalURL,
+		RedirectChain:    redirectChain,
+		ResponseHeaders:  responseHeaders,
+	}
+	if api.LogRequestHeaders {
+		executionLog.RequestHeaders = requestHeaders
+	}
+	return api, executionLog, retries
+}
+
+// loadSpec loads and parses a stored OpenAPI document
+func (s *SchedulerService) loadSpec(specID int) (*openapi.Document, error) {
+	spec, err := s.db.GetAPISpecByID(specID)
+	if err != nil {
+		return nil, err
+	}
+	return openapi.Parse([]byte(spec.Document))
+}
+
+// chainedVariables returns a copy of the variables extracted so far for a collection
+func (s *SchedulerService) chainedVariables(collectionID int) map[string]string {
+	s.chainMutex.Lock()
+	defer s.chainMutex.Unlock()
+
+	variables := make(map[string]string, len(s.chained[collectionID]))
+	for name, value := range s.chained[collectionID] {
+		variables[name] = value
+	}
+	return variables
+}
+
+// extractVariables applies an API's extractions to a successful response and makes the values
+// available to the other APIs of its collection. Chained variables are kept in memory only.
+func (s *SchedulerService) extractVariables(api models.API, extractions []models.Extraction, headers http.Header, body string) {
+	if api.CollectionID == 0 || len(extractions) == 0 {
+		return
+	}
+
+	variables, errs := extraction.Apply(extractions, headers, body)
+	for _, err := range errs {
+		log.Printf("Failed to extract variable from API %d: %v", api.ID, err)
+	}
+
+	s.chainMutex.Lock()
+	defer s.chainMutex.Unlock()
+	if s.chained[api.CollectionID] == nil {
+		s.chained[api.CollectionID] = make(map[string]string)
+	}
+	for name, value := range variables {
+		s.chained[api.CollectionID][name] = value
+	}
+}
+
+// outcomeCodes holds an API's success and degraded status codes
+type outcomeCodes struct {
+	success  assertions.StatusCodes
+	degraded assertions.StatusCodes
+}
+
+// defaultSuccessCodes is used when an API doesn't override which codes are healthy
+var defaultSuccessCodes = assertions.StatusCodes{{200, 299}}
+
+// parseOutcomeCodes parses the API's status code overrides, falling back to the success codes of
+// its check type. Without any success codes, results succeed whenever they have no error.
+func parseOutcomeCodes(api models.API, successCodes assertions.StatusCodes) (outcomeCodes, error) {
+	codes := outcomeCodes{success: successCodes}
+	var err error
+	if strings.TrimSpace(api.SuccessCodes) != "" {
+		if codes.success, err = assertions.ParseStatusCodes(api.SuccessCodes); err != nil {
+			return codes, fmt.Errorf("success codes: %w", err)
+		}
+	}
+	if strings.TrimSpace(api.DegradedCodes) != "" {
+		if codes.degraded, err = assertions.ParseStatusCodes(api.DegradedCodes); err != nil {
+			return codes, fmt.Errorf("degraded codes: %w", err)
+		}
+	}
+	return codes, nil
+}
+
+// ClassifyStatusCode returns how a check of the API scores a response with the given status code before its
+// assertions run: success, degraded or failure
+func (s *SchedulerService) ClassifyStatusCode(api models.API, statusCode int) (string, error) {
+	executor, err := s.executorFor(api.CheckType)
+	if err != nil {
+		return "", err
+	}
+	codes, err := parseOutcomeCodes(api, executor.SuccessCodes())
+	if err != nil {
+		return "", fmt.Errorf("invalid status codes: %w", err)
+	}
+	return codes.classify(api, statusCode, nil), nil
+}
+
+// classify returns the execution status of a request result for the API, before assertions.
+// Negative checks invert the usual rule: they pass when the endpoint is unreachable or rejects the
+// request, and have no degraded state. Success codes take precedence over degraded ones.
+func (c outcomeCodes) classify(api models.API, statusCode int, requestErr error) string {
+	reachedOK := requestErr == nil && (c.success == nil || c.success.Contains(statusCode))
+	if api.ExpectedOutcome == models.ExpectedOutcomeFailure {
+		if reachedOK {
+			return models.ExecutionStatusFailure
 		}
+		return models.ExecutionStatusSuccess
+	}
+	if reachedOK {
+		return models.ExecutionStatusSuccess
+	}
+	if requestErr == nil && c.degraded.Contains(statusCode) {
+		return models.ExecutionStatusDegraded
+	}
+	return models.ExecutionStatusFailure
+}
+
+// exceededLatencyThreshold returns which latency threshold of the API a response time exceeded, the critical
+// one first, along with the threshold. Nothing is returned when it exceeded none.
+func exceededLatencyThreshold(api models.API, duration time.Duration) (string, int) {
+	elapsed := duration.Milliseconds()
+	if api.LatencyCriticalMs > 0 && elapsed > int64(api.LatencyCriticalMs) {
+		return models.LatencyBreachCritical, api.LatencyCriticalMs
+	}
+	if api.LatencyWarnMs > 0 && elapsed > int64(api.LatencyWarnMs) {
+		return models.LatencyBreachWarn, api.LatencyWarnMs
 	}
+	return "", 0
+}
 
-	// Log the execution results
-	s.logExecution(api.ID, schedule.ID, statusCode, responseBody, errMsg)
+// unexpectedOutcomeMessage describes why a response didn't match the expected outcome
+func unexpectedOutcomeMessage(api models.API, statusCode int) string {
+	if api.ExpectedOutcome == models.ExpectedOutcomeFailure && statusCode == 0 {
+		return "Check succeeded but was expected to fail"
+	}
+	if api.ExpectedOutcome == models.ExpectedOutcomeFailure {
+		return fmt.Sprintf("API returned status code %d but was expected to fail", statusCode)
+	}
+	return fmt.Sprintf("API returned non-success status code: %d", statusCode)
 }
 
 // prepareAPIRequest creates an HTTP request from API configuration
-func (s *SchedulerService) prepareAPIRequest(api models.API) (*http.Request, error) {
-	var body io.Reader
-	if api.Body != "" {
-		body = strings.NewReader(api.Body)
+func (s *SchedulerService) prepareAPIRequest(ctx context.Context, api models.API) (*http.Request, error) {
+	body, contentType, err := encodeBody(api)
+	if err != nil {
+		return nil, err
 	}
 
-	req, err := http.NewRequest(api.Method, api.URL, body)
+	requestURL, err := params.BuildURL(api)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, api.Method, requestURL, body)
 	if err != nil {
 		return nil, err
 	}
@@ -282,47 +849,175 @@ func (s *SchedulerService) prepareAPIRequest(api models.API) (*http.Request, err
 		}
 	}
 
+	// A multipart body is only readable with its own boundary, so that always wins over the headers
+	if contentType != "" && (api.BodyType == models.BodyTypeMultipart || req.Header.Get("Content-Type") == "") {
+		req.Header.Set("Content-Type", contentType)
+	}
+
 	return req, nil
 }
 
-// logExecution logs the API execution results to the database
-func (s *SchedulerService) logExecution(apiID, scheduleID, statusCode int, response, errMsg string) {
-	executionLog := models.ExecutionLog{
-		APIID:      apiID,
-		ScheduleID: scheduleID,
-		StatusCode: statusCode,
-		Response:   response,
-		Error:      errMsg,
-		ExecutedAt: time.Now(),
+// logExecution logs the API execution results to the database and dispatches notifications
+func (s *SchedulerService) logExecution(api models.API, executionLog models.ExecutionLog) models.ExecutionLog {
+	executionLog.ExecutedAt = time.Now()
+	s.maskSecrets(&executionLog)
+	change := s.watchContent(api, &executionLog)
+
+	if !s.shouldStore(api, executionLog) || !s.admitLog(executionLog) {
+		s.recordContentChange(change, 0)
+		return executionLog
 	}
 
-	_, err := s.db.CreateExecutionLog(executionLog)
+	created, err := s.db.CreateExecutionLog(executionLog)
 	if err != nil {
 		log.Printf("Failed to create execution log: %v", err)
+		s.recordContentChange(change, 0)
+		return executionLog
+	}
+	s.recordContentChange(change, created.ID)
+
+	// Evaluate alert rules without holding up the job, unless a maintenance window suppresses them
+	if _, suppressed := s.activeMaintenanceWindow(api); !suppressed {
+		go s.notifier.HandleExecution(api, created)
+	}
+	return created
+}
+
+// maskSecrets hides resolved secret values that were echoed back in the response, error or assertions, or sent
+// in the request headers, along with the credentials of the request
+func (s *SchedulerService) maskSecrets(executionLog *models.ExecutionLog) {
+	executionLog.Response = s.secrets.MaskValues(executionLog.Response)
+	executionLog.Error = s.secrets.MaskValues(executionLog.Error)
+	executionLog.RequestHeaders = s.maskHeaders(executionLog.RequestHeaders)
+	executionLog.ResponseHeaders = s.maskHeaders(executionLog.ResponseHeaders)
+	for i := range executionLog.AssertionResults {
+		result := &executionLog.AssertionResults[i]
+		result.Actual = s.secrets.MaskValues(result.Actual)
+		result.Message = s.secrets.MaskValues(result.Message)
 	}
 }
 
+// credentialHeaders are the headers whose values are masked in logs whether or not they hold a known secret
+var credentialHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie"}
+
+// maskHeaders returns a copy of headers with credentials and resolved secret values masked
+func (s *SchedulerService) maskHeaders(headers map[string][]string) map[string][]string {
+	if headers == nil {
+		return nil
+	}
+	masked := make(map[string][]string, len(headers))
+	for name, values := range headers {
+		maskedValues := make([]string, len(values))
+		for i, value := range values {
+			maskedValues[i] = s.secrets.MaskValues(value)
+		}
+		masked[name] = maskedValues
+	}
+	for _, name := range credentialHeaders {
+		if values, ok := masked[http.CanonicalHeaderKey(name)]; ok {
+			for i := range values {
+				values[i] = secrets.Mask
+			}
+		}
+	}
+	return masked
+}
+
+// shouldStore applies the API's log policy to an execution by comparing it with the previous stored run of
+// the same schedule. Skipped runs are not alerted on; under "changes" repeated failures aren't stored, so
+// alert rules with a failure threshold above 1 never fire for that API.
+func (s *SchedulerService) shouldStore(api models.API, executionLog models.ExecutionLog) bool {
+	if executionLog.CollectionRunID != 0 {
+		return true // Collection runs keep every step so the run can be reviewed
+	}
+	if executionLog.ContentChanged {
+		return true // The response a content change was seen in is kept with the change
+	}
+	if api.LogPolicy != models.LogPolicyFailures && api.LogPolicy != models.LogPolicyChanges {
+		return true
+	}
+	if api.LogPolicy == models.LogPolicyFailures && executionLog.Status != models.ExecutionStatusSuccess {
+		return true
+	}
+
+	previous, err := s.db.GetRecentStatusesByScheduleID(executionLog.ScheduleID, 1)
+	if err != nil {
+		log.Printf("Failed to load previous status for schedule ID %d: %v", executionLog.ScheduleID, err)
+		return true
+	}
+	if len(previous) == 0 {
+		// The first run of a schedule only counts as a change when it fails
+		return executionLog.Status != models.ExecutionStatusSuccess
+	}
+	return previous[0] != executionLog.Status
+}
+
 // ExecuteAPIManually executes an API immediately without scheduling
 func (s *SchedulerService) ExecuteAPIManually(apiID int) error {
 	api, err := s.db.GetAPIByID(apiID)
 	if err != nil {
 		return fmt.Errorf("failed to get API: %w", err)
 	}
+	if api.DeletedAt != nil {
+		return fmt.Errorf("API %s is in the trash", api.Name)
+	}
 
 	// Create a dummy schedule for logging purposes
 	dummySchedule := models.Schedule{
-		ID:   0,
+		ID:    0,
 		APIID: apiID,
 	}
 
 	// Execute in a separate goroutine to not block
 	go s.executeAPI(api, dummySchedule)
-	
+
 	return nil
 }
 
+// RunAPI executes an API right away, outside its schedules, and waits for the logged result
+func (s *SchedulerService) RunAPI(apiID int) (models.ExecutionLog, error) {
+	api, err := s.db.GetAPIByID(apiID)
+	if err != nil {
+		return models.ExecutionLog{}, fmt.Errorf("failed to get API: %w", err)
+	}
+	if api.DeletedAt != nil {
+		return models.ExecutionLog{}, fmt.Errorf("API %s is in the trash", api.Name)
+	}
+	return s.runShared(api, models.Schedule{APIID: apiID}, true), nil
+}
+
 // Shutdown gracefully shuts down the scheduler
 func (s *SchedulerService) Shutdown() {
 	log.Println("Shutting down scheduler...")
 	s.StopAllJobs()
-} 
\ No newline at end of file
+	s.stopWatching()
+
+	// Let checks in flight store their results before the database is closed
+	done := make(chan struct{})
+	go func() {
+		s.running.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(shutdownTimeout):
+		log.Printf("Checks still running after %v, shutting down anyway", shutdownTimeout)
+	}
+}
+
+// ActiveJobs counts the jobs running on the scheduler, whichever their type
+func (s *SchedulerService) ActiveJobs() int {
+	s.cronMutex.Lock()
+	count := len(s.jobEntries)
+	s.cronMutex.Unlock()
+	s.intervalMutex.Lock()
+	count += len(s.intervalJobs)
+	s.intervalMutex.Unlock()
+	s.onceMutex.Lock()
+	count += len(s.onceJobs)
+	s.onceMutex.Unlock()
+	s.afterMutex.Lock()
+	count += len(s.afterJobs)
+	s.afterMutex.Unlock()
+	return count
+}
@@ -0,0 +1,179 @@
+package scheduler
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"flowpulse/pkg/models"
+)
+
+// staleAuditInterval is how often active schedules are audited for jobs that stopped running
+const staleAuditInterval = time.Minute
+
+// markRun records that a schedule's job ran, or was started, just now
+func (s *SchedulerService) markRun(scheduleID int) {
+	s.lastRunMutex.Lock()
+	defer s.lastRunMutex.Unlock()
+	s.lastRuns[scheduleID] = time.Now()
+}
+
+// resetLastRuns counts every job as having run just now
+func (s *SchedulerService) resetLastRuns() {
+	s.lastRunMutex.Lock()
+	defer s.lastRunMutex.Unlock()
+	now := time.Now()
+	for scheduleID := range s.lastRuns {
+		s.lastRuns[scheduleID] = now
+	}
+}
+
+// isScheduled reports whether a schedule has a cron or interval job
+func (s *SchedulerService) isScheduled(scheduleID int) bool {
+	s.cronMutex.Lock()
+	_, exists := s.jobEntries[scheduleID]
+	s.cronMutex.Unlock()
+	if exists {
+		return true
+	}
+
+	s.intervalMutex.Lock()
+	defer s.intervalMutex.Unlock()
+	_, exists = s.intervalJobs[scheduleID]
+	return exists
+}
+
+// StaleChecks returns the active cron and interval schedules whose job has no run in the given number
+// of intervals, or has no job at all, which points at a dead job. Runs are tracked in memory, so
+// this doesn't depend on which executions the log policy keeps. One-time and "after" schedules are never stale.
+func (s *SchedulerService) StaleChecks(intervals int) ([]models.StaleCheck, error) {
+	if intervals < 1 {
+		intervals = 1
+	}
+	schedules, err := s.db.GetAllActiveSchedules()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get active schedules: %w", err)
+	}
+
+	now := time.Now()
+	stale := []models.StaleCheck{}
+	for _, schedule := range schedules {
+		if schedule.Type == "once" || schedule.Type == "after" {
+			continue
+		}
+
+		s.lastRunMutex.Lock()
+		lastRun, known := s.lastRuns[schedule.ID]
+		s.lastRunMutex.Unlock()
+
+		check := models.StaleCheck{
+			ScheduleID: schedule.ID,
+			APIID:      schedule.APIID,
+			Type:       schedule.Type,
+			Expression: schedule.Expression,
+			Scheduled:  s.isScheduled(schedule.ID),
+			LastRunAt:  lastRun,
+		}
+		if check.Scheduled && known {
+			deadline, err := staleDeadline(schedule, lastRun, intervals)
+			if err != nil {
+				log.Printf("Failed to audit schedule ID %d: %v", schedule.ID, err)
+				continue
+			}
+			if !now.After(deadline) {
+				continue
+			}
+			check.OverdueSeconds = int64(now.Sub(deadline).Seconds())
+		}
+
+		if api, err := s.db.GetAPIByID(schedule.APIID); err == nil {
+			check.APIName = api.Name
+		}
+		stale = append(stale, check)
+	}
+	return stale, nil
+}
+
+// staleDeadline returns when a schedule that last ran at the given time has missed the given number of runs
+func staleDeadline(schedule models.Schedule, lastRun time.Time, intervals int) (time.Time, error) {
+	if schedule.Type == "cron" {
+		parsed, err := ParseCron(schedule)
+		if err != nil {
+			return time.Time{}, err
+		}
+		deadline := lastRun
+		for i := 0; i < intervals; i++ {
+			deadline = parsed.Next(deadline)
+		}
+		return deadline, nil
+	}
+
+	intervalSec, err := strconv.Atoi(schedule.Expression)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid interval: %w", err)
+	}
+	return lastRun.Add(time.Duration(intervals*intervalSec) * time.Second), nil
+}
+
+// auditStaleJobs looks for stale schedules until stop is closed, logging each one when it turns
+// stale and alerting it when the policy says so
+func (s *SchedulerService) auditStaleJobs(stop <-chan struct{}) {
+	ticker := time.NewTicker(staleAuditInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			s.auditStale()
+		case <-stop:
+			return
+		}
+	}
+}
+
+// auditStale reports the schedules that turned stale since the last audit
+func (s *SchedulerService) auditStale() {
+	policy, err := s.db.GetStaleCheckPolicy()
+	if err != nil {
+		log.Printf("Failed to load stale check policy: %v", err)
+		return
+	}
+	checks, err := s.StaleChecks(policy.Intervals)
+	if err != nil {
+		log.Printf("Failed to audit stale schedules: %v", err)
+		return
+	}
+
+	stale := make(map[int]bool, len(checks))
+	for _, check := range checks {
+		stale[check.ScheduleID] = true
+		if s.staleAlerted[check.ScheduleID] {
+			continue
+		}
+		s.staleAlerted[check.ScheduleID] = true
+		s.emitScheduleState(check.ScheduleID, models.ScheduleStateStale)
+
+		log.Printf("Schedule ID %d of API ID %d is stale (scheduled: %v, last run: %v)",
+			check.ScheduleID, check.APIID, check.Scheduled, check.LastRunAt)
+		if policy.Alert {
+			api, err := s.db.GetAPIByID(check.APIID)
+			if err != nil {
+				log.Printf("Failed to load API ID %d for stale alert: %v", check.APIID, err)
+				continue
+			}
+			if _, suppressed := s.activeMaintenanceWindow(api); !suppressed {
+				s.notifier.HandleStaleCheck(api, check)
+			}
+		}
+	}
+
+	// A schedule that runs again is alerted anew if it goes stale later
+	for scheduleID := range s.staleAlerted {
+		if !stale[scheduleID] {
+			delete(s.staleAlerted, scheduleID)
+			if s.isScheduled(scheduleID) {
+				s.emitScheduleState(scheduleID, models.ScheduleStateScheduled)
+			}
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package scheduler
+
+import (
+	"context"
+	"net"
+	"time"
+
+	"flowpulse/pkg/assertions"
+	"flowpulse/pkg/models"
+)
+
+// tcpExecutor connects to the host:port in the API's URL and closes the connection right away.
+// The duration is the connect latency.
+type tcpExecutor struct {
+	timeout time.Duration
+}
+
+func (e *tcpExecutor) SuccessCodes() assertions.StatusCodes {
+	return nil
+}
+
+func (e *tcpExecutor) Execute(ctx context.Context, check Check) (Attempt, error) {
+	address := checkTarget(check.API)
+	if _, _, err := net.SplitHostPort(address); err != nil {
+		return Attempt{}, err
+	}
+
+	resolver, err := resolverFor(check.API)
+	if err != nil {
+		return Attempt{}, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, e.timeout)
+	defer cancel()
+
+	dialer := net.Dialer{Resolver: resolver}
+	start := time.Now()
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	duration := time.Since(start)
+	if err != nil {
+		return Attempt{Err: err, Phase: models.RequestPhaseConnect, Duration: duration}, nil
+	}
+	conn.Close()
+	return Attempt{Body: conn.RemoteAddr().String(), Duration: duration}, nil
+}
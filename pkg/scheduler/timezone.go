@@ -0,0 +1,51 @@
+package scheduler
+
+import (
+	"fmt"
+	"time"
+	_ "time/tzdata" // Windows and minimal systems have no zoneinfo database to load timezones from
+
+	"github.com/robfig/cron/v3"
+
+	"flowpulse/pkg/models"
+)
+
+// cronParser parses cron expressions the way the scheduler's cron instance does
+var cronParser = cron.NewParser(cron.Second | cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor)
+
+// LoadTimezone resolves a schedule timezone, where an empty name means the local zone
+func LoadTimezone(name string) (*time.Location, error) {
+	if name == "" {
+		return time.Local, nil
+	}
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("unknown timezone %q", name)
+	}
+	return location, nil
+}
+
+// ParseCron parses a cron schedule's expression so that it fires in the schedule's timezone
+func ParseCron(schedule models.Schedule) (cron.Schedule, error) {
+	return ParseCronIn(schedule.Expression, schedule.Timezone)
+}
+
+// ParseCronIn parses a cron expression so that it fires in the named timezone
+func ParseCronIn(expression, timezone string) (cron.Schedule, error) {
+	parsed, err := cronParser.Parse(expression)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cron expression: %w", err)
+	}
+	if timezone == "" {
+		return parsed, nil // Local, or whatever CRON_TZ the expression itself names
+	}
+	location, err := LoadTimezone(timezone)
+	if err != nil {
+		return nil, err
+	}
+	// Descriptors such as @every have no location, only field-based expressions do
+	if spec, ok := parsed.(*cron.SpecSchedule); ok {
+		spec.Location = location
+	}
+	return parsed, nil
+}
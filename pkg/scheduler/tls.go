@@ -0,0 +1,64 @@
+package scheduler
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"strings"
+
+	"flowpulse/pkg/models"
+)
+
+// LoadTLSConfig builds the TLS configuration of an API's connections, reading its CA bundle and client
+// certificate from disk. It returns nil when the API keeps the defaults.
+func LoadTLSConfig(config models.TLSConfig) (*tls.Config, error) {
+	if config == (models.TLSConfig{}) {
+		return nil, nil
+	}
+	tlsConfig := &tls.Config{InsecureSkipVerify: config.InsecureSkipVerify}
+
+	if path := strings.TrimSpace(config.CACertPath); path != "" {
+		bundle, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA bundle: %w", err)
+		}
+		// Trust the bundle on top of the system's CAs, so other certificates in the chain still verify
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(bundle) {
+			return nil, fmt.Errorf("CA bundle %s contains no PEM certificates", path)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	certPath, keyPath := strings.TrimSpace(config.ClientCertPath), strings.TrimSpace(config.ClientKeyPath)
+	if (certPath == "") != (keyPath == "") {
+		return nil, fmt.Errorf("a client certificate needs both the certificate and the key file")
+	}
+	if certPath != "" {
+		certificate, err := tls.LoadX509KeyPair(certPath, keyPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{certificate}
+	}
+	return tlsConfig, nil
+}
+
+// tlsFilesModified returns when the files of a TLS configuration were last modified, so a transport is
+// rebuilt once they are replaced, e.g. by a renewed certificate. Missing files are reported when loading.
+func tlsFilesModified(config models.TLSConfig) [3]int64 {
+	var modified [3]int64
+	for i, path := range []string{config.CACertPath, config.ClientCertPath, config.ClientKeyPath} {
+		if path = strings.TrimSpace(path); path == "" {
+			continue
+		}
+		if info, err := os.Stat(path); err == nil {
+			modified[i] = info.ModTime().UnixNano()
+		}
+	}
+	return modified
+}
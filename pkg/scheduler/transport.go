@@ -0,0 +1,106 @@
+package scheduler
+
+import (
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"flowpulse/pkg/models"
+)
+
+// maxCachedClients bounds the clients kept for distinct transport settings; the cache is emptied when it is
+// exceeded, e.g. after settings were edited or certificates renewed many times
+const maxCachedClients = 64
+
+// transportKey identifies the settings an HTTP transport is built for
+type transportKey struct {
+	proxy       models.ProxyConfig // Proxy requests go through, unless its URL is empty
+	direct      bool               // Ignore the proxy of the environment, e.g. HTTPS_PROXY
+	tls         models.TLSConfig
+	tlsModified [3]int64 // When the TLS files were last modified
+	dnsServer   string   // Address of the DNS server host names are resolved with (empty for the system's)
+}
+
+// transportKeyFor returns the transport settings of an API's requests. The zero key stands for the
+// shared client, which uses the environment's proxy if any, verifies servers against the system's CAs and
+// resolves host names with the system's resolver.
+func (s *SchedulerService) transportKeyFor(api models.API) (transportKey, error) {
+	var key transportKey
+	switch api.ProxyMode {
+	case models.ProxyModeNone:
+		key.direct = true
+	case models.ProxyModeCustom:
+		key.proxy = api.Proxy
+	default:
+		proxy, err := s.db.GetGlobalProxy()
+		if err != nil {
+			return key, err
+		}
+		key.proxy = proxy
+	}
+	if strings.TrimSpace(key.proxy.URL) == "" {
+		key.proxy = models.ProxyConfig{}
+	}
+	key.tls = api.TLS
+	key.tlsModified = tlsFilesModified(api.TLS)
+	if strings.TrimSpace(api.DNSServer) != "" {
+		var err error
+		if key.dnsServer, err = ParseDNSServer(api.DNSServer); err != nil {
+			return key, err
+		}
+	}
+	return key, nil
+}
+
+// clientCache holds an HTTP client per distinct transport settings, so connections are reused between
+// checks with the same settings but never shared with checks verifying servers or proxying differently
+type clientCache struct {
+	clients map[transportKey]*http.Client
+	mu      sync.Mutex
+}
+
+// clientFor returns the HTTP client for an API's transport settings, building and caching it the first time
+func (s *SchedulerService) clientFor(api models.API) (*http.Client, error) {
+	key, err := s.transportKeyFor(api)
+	if err != nil {
+		return nil, err
+	}
+	if key == (transportKey{}) {
+		return s.client, nil
+	}
+
+	s.clients.mu.Lock()
+	defer s.clients.mu.Unlock()
+	if client, ok := s.clients.clients[key]; ok {
+		return client, nil
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if key.direct {
+		transport.Proxy = nil
+	} else if key.proxy.URL != "" {
+		if transport.Proxy, err = proxyFunc(key.proxy); err != nil {
+			return nil, err
+		}
+	}
+	if transport.TLSClientConfig, err = LoadTLSConfig(key.tls); err != nil {
+		return nil, err
+	}
+	if key.dnsServer != "" {
+		// Behind a proxy this only resolves the proxy's host; the proxy resolves the API's
+		dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second, Resolver: dnsResolver(key.dnsServer)}
+		transport.DialContext = dialer.DialContext
+	}
+
+	if len(s.clients.clients) >= maxCachedClients {
+		for cachedKey, client := range s.clients.clients {
+			client.CloseIdleConnections()
+			delete(s.clients.clients, cachedKey)
+		}
+	}
+	client := &http.Client{Timeout: s.client.Timeout, Transport: transport}
+	s.clients.clients[key] = client
+	return client, nil
+}
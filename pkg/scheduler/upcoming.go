@@ -0,0 +1,167 @@
+package scheduler
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"time"
+
+	"flowpulse/pkg/models"
+)
+
+// maxCalendarRuns is how many runs of a single schedule a calendar lists, so a schedule firing
+// every second doesn't flood it
+const maxCalendarRuns = 500
+
+// UpcomingRuns returns up to count times a schedule fires after the given time, ignoring runs after
+// until unless it is zero. Interval schedules that have a job tick relative to when it started,
+// otherwise as if they started at after. The schedule doesn't need to be active, so an edited
+// schedule can be previewed before it is saved.
+func (s *SchedulerService) UpcomingRuns(schedule models.Schedule, after, until time.Time, count int) ([]time.Time, error) {
+	runs := []time.Time{}
+	within := func(t time.Time) bool { return until.IsZero() || !t.After(until) }
+
+	switch schedule.Type {
+	case "cron":
+		parsed, err := ParseCron(schedule)
+		if err != nil {
+			return nil, err
+		}
+		for next := parsed.Next(after); len(runs) < count && !next.IsZero() && within(next); next = parsed.Next(next) {
+			runs = append(runs, next)
+		}
+
+	case "interval":
+		intervalSec, err := strconv.Atoi(schedule.Expression)
+		if err != nil {
+			return nil, fmt.Errorf("invalid interval: %w", err)
+		}
+		if intervalSec <= 0 {
+			return nil, fmt.Errorf("interval must be positive")
+		}
+		interval := time.Duration(intervalSec) * time.Second
+
+		anchor := after
+		s.intervalMutex.Lock()
+		if job, exists := s.intervalJobs[schedule.ID]; exists && !job.startedAt.After(after) {
+			anchor = job.startedAt
+		}
+		s.intervalMutex.Unlock()
+
+		next := anchor.Add((after.Sub(anchor)/interval + 1) * interval)
+		for ; len(runs) < count && within(next); next = next.Add(interval) {
+			runs = append(runs, next)
+		}
+
+	case "once":
+		runAt, err := time.Parse(time.RFC3339, schedule.Expression)
+		if err != nil {
+			return nil, fmt.Errorf("invalid timestamp: %w", err)
+		}
+		if count > 0 && runAt.After(after) && within(runAt) {
+			runs = append(runs, runAt)
+		}
+
+	case "after":
+		// Runs depend on the outcomes of another schedule's, so none can be foreseen
+
+	default:
+		return nil, fmt.Errorf("unsupported schedule type: %s", schedule.Type)
+	}
+
+	return runs, nil
+}
+
+// Calendar lists the runs of every active schedule between from and to, ordered by time
+func (s *SchedulerService) Calendar(from, to time.Time) (models.ScheduleCalendar, error) {
+	calendar := models.ScheduleCalendar{From: from, To: to, Runs: []models.ScheduledRun{}, Truncated: []int{}}
+
+	schedules, err := s.db.GetAllActiveSchedules()
+	if err != nil {
+		return calendar, fmt.Errorf("failed to get active schedules: %w", err)
+	}
+
+	apiNames := make(map[int]string)
+	for _, schedule := range schedules {
+		// One more than listed tells whether the schedule was cut short
+		runs, err := s.UpcomingRuns(schedule, from, to, maxCalendarRuns+1)
+		if err != nil {
+			log.Printf("Failed to list the runs of schedule ID %d: %v", schedule.ID, err)
+			continue
+		}
+		if len(runs) > maxCalendarRuns {
+			runs = runs[:maxCalendarRuns]
+			calendar.Truncated = append(calendar.Truncated, schedule.ID)
+		}
+		calendar.Runs = append(calendar.Runs, s.scheduledRuns(schedule, runs, apiNames)...)
+	}
+
+	sortScheduledRuns(calendar.Runs)
+	return calendar, nil
+}
+
+// UpcomingExecutions lists the next limit runs across every active schedule after the given time,
+// ordered by time
+func (s *SchedulerService) UpcomingExecutions(after time.Time, limit int) ([]models.ScheduledRun, error) {
+	upcoming := []models.ScheduledRun{}
+
+	schedules, err := s.db.GetAllActiveSchedules()
+	if err != nil {
+		return upcoming, fmt.Errorf("failed to get active schedules: %w", err)
+	}
+
+	apiNames := make(map[int]string)
+	for _, schedule := range schedules {
+		// No schedule can contribute more than limit runs to the next limit runs overall
+		runs, err := s.UpcomingRuns(schedule, after, time.Time{}, limit)
+		if err != nil {
+			log.Printf("Failed to list the runs of schedule ID %d: %v", schedule.ID, err)
+			continue
+		}
+		upcoming = append(upcoming, s.scheduledRuns(schedule, runs, apiNames)...)
+	}
+
+	sortScheduledRuns(upcoming)
+	if len(upcoming) > limit {
+		upcoming = upcoming[:limit]
+	}
+	return upcoming, nil
+}
+
+// scheduledRuns describes the runs of a schedule, looking up the name of its API once per API
+func (s *SchedulerService) scheduledRuns(schedule models.Schedule, runs []time.Time, apiNames map[int]string) []models.ScheduledRun {
+	if len(runs) == 0 {
+		return nil
+	}
+	name, known := apiNames[schedule.APIID]
+	if !known {
+		if api, err := s.db.GetAPIByID(schedule.APIID); err == nil {
+			name = api.Name
+		}
+		apiNames[schedule.APIID] = name
+	}
+
+	scheduled := make([]models.ScheduledRun, 0, len(runs))
+	for _, runAt := range runs {
+		scheduled = append(scheduled, models.ScheduledRun{
+			ScheduleID: schedule.ID,
+			APIID:      schedule.APIID,
+			APIName:    name,
+			Type:       schedule.Type,
+			Expression: schedule.Expression,
+			RunAt:      runAt,
+		})
+	}
+	return scheduled
+}
+
+// sortScheduledRuns orders runs by time, then by schedule
+func sortScheduledRuns(runs []models.ScheduledRun) {
+	sort.SliceStable(runs, func(i, j int) bool {
+		if !runs[i].RunAt.Equal(runs[j].RunAt) {
+			return runs[i].RunAt.Before(runs[j].RunAt)
+		}
+		return runs[i].ScheduleID < runs[j].ScheduleID
+	})
+}
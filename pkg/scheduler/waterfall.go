@@ -0,0 +1,59 @@
+package scheduler
+
+import (
+	"time"
+
+	"flowpulse/pkg/environments"
+	"flowpulse/pkg/models"
+)
+
+// RunWaterfall lays out the steps of a collection run on a timeline. A step depends on the last
+// step before it whose API extracts a variable the step refers to; extractions are keyed by API ID.
+func RunWaterfall(run models.CollectionRun, apis []models.API, extractions map[int][]models.Extraction) models.Waterfall {
+	apisByID := make(map[int]models.API, len(apis))
+	for _, api := range apis {
+		apisByID[api.ID] = api
+	}
+
+	waterfall := models.Waterfall{RunID: run.ID, StartedAt: run.StartedAt, Steps: []models.WaterfallStep{}}
+	extractedBy := make(map[string]int) // Variable name to the index of the last step extracting it
+	for i, execution := range run.Executions {
+		// Executions are logged when they finish, so the start is derived from the duration
+		started := execution.ExecutedAt.Add(-time.Duration(execution.DurationMs) * time.Millisecond)
+		offset := started.Sub(run.StartedAt).Milliseconds()
+		if offset < 0 {
+			offset = 0
+		}
+		step := models.WaterfallStep{
+			Index:         i,
+			APIID:         execution.APIID,
+			LogID:         execution.ID,
+			Status:        execution.Status,
+			StartOffsetMs: offset,
+			DurationMs:    execution.DurationMs,
+			EndOffsetMs:   offset + execution.DurationMs,
+			DependsOn:     []int{},
+		}
+
+		api, ok := apisByID[execution.APIID]
+		if ok {
+			step.APIName = api.Name
+			seen := make(map[int]bool)
+			for _, name := range environments.References(api) {
+				if dependency, ok := extractedBy[name]; ok && !seen[dependency] {
+					seen[dependency] = true
+					step.DependsOn = append(step.DependsOn, dependency)
+				}
+			}
+		}
+		for _, extraction := range extractions[execution.APIID] {
+			extractedBy[extraction.Variable] = i
+		}
+
+		if step.EndOffsetMs > waterfall.TotalMs {
+			waterfall.TotalMs = step.EndOffsetMs
+		}
+		waterfall.Steps = append(waterfall.Steps, step)
+	}
+	return waterfall
+}
@@ -0,0 +1,331 @@
+package scheduler
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"flowpulse/pkg/assertions"
+	"flowpulse/pkg/models"
+)
+
+// webSocketGUID is appended to the handshake key to compute the accept header (RFC 6455 section 1.3)
+const webSocketGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// maxWebSocketMessage bounds the size of the first message that is read
+const maxWebSocketMessage = 10 << 20
+
+// WebSocket frame opcodes
+const (
+	wsOpContinuation = 0x0
+	wsOpText         = 0x1
+	wsOpBinary       = 0x2
+	wsOpClose        = 0x8
+	wsOpPing         = 0x9
+	wsOpPong         = 0xA
+)
+
+// inspectsBody reports whether any of the assertions looks at the response body
+func inspectsBody(apiAssertions []models.Assertion) bool {
+	for _, assertion := range apiAssertions {
+		switch assertion.Type {
+		case models.AssertionJSONPath, models.AssertionBodyRegex, models.AssertionGraphQLErrors:
+			return true
+		}
+	}
+	return false
+}
+
+// webSocketExecutor opens a WebSocket connection to the API's URL with its headers, sends the body
+// as a text message when there is one, and reads the first message when it sent one or an assertion
+// inspects it. The duration is the connect latency up to the completed handshake, and the body is
+// the first message received.
+type webSocketExecutor struct {
+	scheduler *SchedulerService
+}
+
+// SuccessCodes returns the handshake status of a WebSocket server accepting the connection
+func (e *webSocketExecutor) SuccessCodes() assertions.StatusCodes {
+	return assertions.StatusCodes{{http.StatusSwitchingProtocols, http.StatusSwitchingProtocols}}
+}
+
+func (e *webSocketExecutor) Execute(ctx context.Context, check Check) (Attempt, error) {
+	req, err := e.scheduler.prepareAuthorizedRequest(ctx, check)
+	if err != nil {
+		return Attempt{}, err
+	}
+	payload := check.API.Body
+	awaitMessage := payload != "" || inspectsBody(check.Assertions)
+
+	phase := &phaseTracker{phase: models.RequestPhaseConnect}
+	start := time.Now()
+	var result Attempt
+	fail := func(err error) (Attempt, error) {
+		result.Err, result.Phase = err, phase.get()
+		if result.Duration == 0 {
+			result.Duration = time.Since(start)
+		}
+		return result, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, e.scheduler.client.Timeout)
+	defer cancel()
+	client, err := e.scheduler.clientFor(check.API)
+	if err != nil {
+		return Attempt{}, fmt.Errorf("invalid connection settings: %w", err)
+	}
+	var tlsConfig *tls.Config
+	if transport, ok := client.Transport.(*http.Transport); ok && transport.TLSClientConfig != nil {
+		tlsConfig = transport.TLSClientConfig.Clone()
+	}
+	resolver, err := resolverFor(check.API)
+	if err != nil {
+		return Attempt{}, fmt.Errorf("invalid connection settings: %w", err)
+	}
+	conn, err := dialWebSocket(ctx, req.URL, tlsConfig, resolver, phase)
+	if err != nil {
+		return fail(err)
+	}
+	defer conn.Close()
+
+	// Reads and writes give up at the deadline, or as soon as the check is abandoned
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+	stop := context.AfterFunc(ctx, func() { conn.SetDeadline(time.Now()) })
+	defer stop()
+
+	// Handshake
+	phase.set(models.RequestPhaseRequest)
+	key, err := webSocketKey()
+	if err != nil {
+		return fail(err)
+	}
+	handshake := &http.Request{
+		Method:     http.MethodGet,
+		URL:        req.URL,
+		Host:       req.URL.Host,
+		Header:     req.Header.Clone(),
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+	}
+	handshake.Header.Set("Upgrade", "websocket")
+	handshake.Header.Set("Connection", "Upgrade")
+	handshake.Header.Set("Sec-WebSocket-Key", key)
+	handshake.Header.Set("Sec-WebSocket-Version", "13")
+	result.RequestHeaders = handshake.Header.Clone()
+	if err := handshake.Write(conn); err != nil {
+		return fail(err)
+	}
+
+	phase.set(models.RequestPhaseHeaders)
+	reader := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(reader, handshake)
+	if err != nil {
+		return fail(err)
+	}
+	result.StatusCode, result.Headers = resp.StatusCode, resp.Header
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		// A rejected handshake is an ordinary HTTP response, which is classified by its status code
+		body, err := io.ReadAll(io.LimitReader(resp.Body, maxWebSocketMessage))
+		resp.Body.Close()
+		result.Body = string(body)
+		result.Duration = time.Since(start)
+		if err != nil {
+			return fail(err)
+		}
+		return result, nil
+	}
+	if resp.Header.Get("Sec-WebSocket-Accept") != webSocketAccept(key) {
+		return fail(errors.New("server sent an invalid Sec-WebSocket-Accept header"))
+	}
+	result.Duration = time.Since(start)
+
+	if payload != "" {
+		if err := writeWebSocketFrame(conn, wsOpText, []byte(payload)); err != nil {
+			return fail(err)
+		}
+	}
+	if awaitMessage {
+		phase.set(models.RequestPhaseBody)
+		message, err := readWebSocketMessage(reader, conn)
+		if err != nil {
+			return fail(err)
+		}
+		result.Body = message
+	}
+
+	// Close with 1000 (normal closure) without waiting for the server to confirm
+	writeWebSocketFrame(conn, wsOpClose, []byte{0x03, 0xE8})
+	return result, nil
+}
+
+// dialWebSocket opens the connection to a ws:// or wss:// URL, resolving its host with resolver, with TLS
+// for wss:// using the API's TLS configuration if it has one. Proxies aren't used.
+func dialWebSocket(ctx context.Context, target *url.URL, tlsConfig *tls.Config, resolver *net.Resolver, phase *phaseTracker) (net.Conn, error) {
+	secure := false
+	port := "80"
+	switch strings.ToLower(target.Scheme) {
+	case "ws", "http":
+	case "wss", "https":
+		secure, port = true, "443"
+	default:
+		return nil, fmt.Errorf("unsupported WebSocket URL scheme %q", target.Scheme)
+	}
+	if target.Port() != "" {
+		port = target.Port()
+	}
+
+	dialer := net.Dialer{Resolver: resolver}
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(target.Hostname(), port))
+	if err != nil {
+		return nil, err
+	}
+	if !secure {
+		return conn, nil
+	}
+
+	phase.set(models.RequestPhaseTLS)
+	if tlsConfig == nil {
+		tlsConfig = &tls.Config{}
+	}
+	tlsConfig.ServerName = target.Hostname()
+	tlsConn := tls.Client(conn, tlsConfig)
+	if err := tlsConn.HandshakeContext(ctx); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return tlsConn, nil
+}
+
+// webSocketKey generates the random Sec-WebSocket-Key of a handshake
+func webSocketKey() (string, error) {
+	key := make([]byte, 16)
+	if _, err := rand.Read(key); err != nil {
+		return "", fmt.Errorf("failed to generate WebSocket key: %w", err)
+	}
+	return base64.StdEncoding.EncodeToString(key), nil
+}
+
+// webSocketAccept computes the Sec-WebSocket-Accept value the server must answer a key with
+func webSocketAccept(key string) string {
+	sum := sha1.Sum([]byte(key + webSocketGUID))
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
+
+// writeWebSocketFrame writes a single final frame. Frames sent by a client must be masked.
+func writeWebSocketFrame(w io.Writer, opcode byte, payload []byte) error {
+	header := []byte{0x80 | opcode}
+	switch length := len(payload); {
+	case length < 126:
+		header = append(header, 0x80|byte(length))
+	case length <= 0xFFFF:
+		header = append(header, 0x80|126)
+		header = binary.BigEndian.AppendUint16(header, uint16(length))
+	default:
+		header = append(header, 0x80|127)
+		header = binary.BigEndian.AppendUint64(header, uint64(length))
+	}
+
+	mask := make([]byte, 4)
+	if _, err := rand.Read(mask); err != nil {
+		return fmt.Errorf("failed to generate WebSocket mask: %w", err)
+	}
+	header = append(header, mask...)
+	masked := make([]byte, len(payload))
+	for i, b := range payload {
+		masked[i] = b ^ mask[i%4]
+	}
+
+	_, err := w.Write(append(header, masked...))
+	return err
+}
+
+// readWebSocketMessage reads the next text or binary message, joining fragments and answering
+// pings while waiting. A close frame from the server ends the check with an error.
+func readWebSocketMessage(r *bufio.Reader, w io.Writer) (string, error) {
+	var message []byte
+	started := false
+	for {
+		header := make([]byte, 2)
+		if _, err := io.ReadFull(r, header); err != nil {
+			return "", err
+		}
+		final := header[0]&0x80 != 0
+		opcode := header[0] & 0x0F
+		masked := header[1]&0x80 != 0
+
+		length := uint64(header[1] & 0x7F)
+		switch length {
+		case 126:
+			extended := make([]byte, 2)
+			if _, err := io.ReadFull(r, extended); err != nil {
+				return "", err
+			}
+			length = uint64(binary.BigEndian.Uint16(extended))
+		case 127:
+			extended := make([]byte, 8)
+			if _, err := io.ReadFull(r, extended); err != nil {
+				return "", err
+			}
+			length = binary.BigEndian.Uint64(extended)
+		}
+		if length > maxWebSocketMessage || uint64(len(message))+length > maxWebSocketMessage {
+			return "", fmt.Errorf("WebSocket message exceeds %d bytes", maxWebSocketMessage)
+		}
+
+		var mask []byte
+		if masked {
+			mask = make([]byte, 4)
+			if _, err := io.ReadFull(r, mask); err != nil {
+				return "", err
+			}
+		}
+		payload := make([]byte, length)
+		if _, err := io.ReadFull(r, payload); err != nil {
+			return "", err
+		}
+		if masked {
+			for i := range payload {
+				payload[i] ^= mask[i%4]
+			}
+		}
+
+		switch opcode {
+		case wsOpPing:
+			if err := writeWebSocketFrame(w, wsOpPong, payload); err != nil {
+				return "", err
+			}
+		case wsOpPong:
+		case wsOpClose:
+			if len(payload) >= 2 {
+				return "", fmt.Errorf("connection closed by the server with code %d", binary.BigEndian.Uint16(payload))
+			}
+			return "", errors.New("connection closed by the server")
+		case wsOpText, wsOpBinary, wsOpContinuation:
+			if opcode == wsOpContinuation && !started {
+				return "", errors.New("unexpected WebSocket continuation frame")
+			}
+			started = true
+			message = append(message, payload...)
+			if final {
+				return string(message), nil
+			}
+		default:
+			return "", fmt.Errorf("unknown WebSocket opcode %d", opcode)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package secrets
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// service is the keychain service the secrets are filed under
+const service = "FlowPulse"
+
+// macKeychain stores secrets as generic passwords in the login keychain using the security tool
+type macKeychain struct{}
+
+// systemKeychain returns the login keychain when the security tool is present
+func systemKeychain() Keychain {
+	if _, err := exec.LookPath("security"); err != nil {
+		return nil
+	}
+	return macKeychain{}
+}
+
+func (macKeychain) Get(name string) (string, error) {
+	out, err := exec.Command("security", "find-generic-password", "-s", service, "-a", name, "-w").Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to read secret %q from the keychain: %w", name, err)
+	}
+	return strings.TrimSuffix(string(out), "\n"), nil
+}
+
+func (macKeychain) Set(name, value string) error {
+	// -U updates an existing item instead of failing. security only accepts the password as an argument.
+	if out, err := exec.Command("security", "add-generic-password", "-U", "-s", service, "-a", name, "-w", value).CombinedOutput(); err != nil {
+		return fmt.Errorf("%v: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+func (macKeychain) Delete(name string) error {
+	if out, err := exec.Command("security", "delete-generic-password", "-s", service, "-a", name).CombinedOutput(); err != nil {
+		return fmt.Errorf("%v: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package secrets
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// service is the attribute value the secrets are filed under
+const service = "flowpulse"
+
+// secretServiceKeychain stores secrets through the freedesktop Secret Service (GNOME Keyring,
+// KWallet) using libsecret's secret-tool
+type secretServiceKeychain struct{}
+
+// systemKeychain returns the Secret Service keychain when secret-tool is installed
+func systemKeychain() Keychain {
+	if _, err := exec.LookPath("secret-tool"); err != nil {
+		return nil
+	}
+	return secretServiceKeychain{}
+}
+
+func (secretServiceKeychain) Get(name string) (string, error) {
+	out, err := exec.Command("secret-tool", "lookup", "service", service, "account", name).Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to read secret %q from the keychain: %w", name, err)
+	}
+	return string(out), nil
+}
+
+func (secretServiceKeychain) Set(name, value string) error {
+	// The value is passed on stdin so it doesn't show up in the process list
+	cmd := exec.Command("secret-tool", "store", "--label", "FlowPulse: "+name, "service", service, "account", name)
+	cmd.Stdin = strings.NewReader(value)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%v: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+func (secretServiceKeychain) Delete(name string) error {
+	if out, err := exec.Command("secret-tool", "clear", "service", service, "account", name).CombinedOutput(); err != nil {
+		return fmt.Errorf("%v: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
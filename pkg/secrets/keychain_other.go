@@ -0,0 +1,9 @@
+//go:build !darwin && !linux
+
+package secrets
+
+// systemKeychain returns nil on platforms without a supported keychain, so secrets are
+// encrypted locally
+func systemKeychain() Keychain {
+	return nil
+}
@@ -0,0 +1,103 @@
+package secrets
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"flowpulse/pkg/database"
+)
+
+// keyFile is the name of the file in the app directory holding the local encryption key
+const keyFile = "secrets.key"
+
+// encrypt seals a value with AES-GCM, returning the nonce and ciphertext as base64
+func encrypt(value string) (string, error) {
+	gcm, err := localCipher()
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	sealed := gcm.Seal(nonce, nonce, []byte(value), nil)
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// decrypt opens a value sealed by encrypt
+func decrypt(encoded string) (string, error) {
+	gcm, err := localCipher()
+	if err != nil {
+		return "", err
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode secret: %w", err)
+	}
+	if len(sealed) < gcm.NonceSize() {
+		return "", errors.New("stored secret is too short")
+	}
+	nonce, ciphertext := sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():]
+	value, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt secret: %w", err)
+	}
+	return string(value), nil
+}
+
+// localCipher returns an AES-GCM cipher using the key stored in the app directory
+func localCipher() (cipher.AEAD, error) {
+	key, err := loadKey()
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
+
+// loadKey reads the local encryption key, generating it on first use.
+// The key is kept outside the database so a copied database alone doesn't reveal secrets.
+func loadKey() ([]byte, error) {
+	appDir, err := database.AppDir()
+	if err != nil {
+		return nil, err
+	}
+	path := filepath.Join(appDir, keyFile)
+
+	key := make([]byte, 32)
+	if _, err := io.ReadFull(rand.Reader, key); err != nil {
+		return nil, fmt.Errorf("failed to generate key: %w", err)
+	}
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err == nil {
+		defer file.Close()
+		if _, err := file.Write(key); err != nil {
+			return nil, fmt.Errorf("failed to write secrets key: %w", err)
+		}
+		return key, nil
+	}
+	if !os.IsExist(err) {
+		return nil, fmt.Errorf("failed to create secrets key: %w", err)
+	}
+
+	key, err = os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read secrets key: %w", err)
+	}
+	if len(key) != 32 {
+		return nil, fmt.Errorf("secrets key %s is corrupt", path)
+	}
+	return key, nil
+}
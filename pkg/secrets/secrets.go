@@ -0,0 +1,154 @@
+package secrets
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"sync"
+
+	"flowpulse/pkg/database"
+	"flowpulse/pkg/models"
+)
+
+// Prefix marks a variable reference as a secret, as in {{secret:name}}
+const Prefix = "secret:"
+
+// Mask replaces secret values in stored execution logs
+const Mask = "********"
+
+// minMaskLength is the shortest value that is masked; shorter values would mangle unrelated text
+const minMaskLength = 4
+
+// validName restricts secret names to characters that are safe inside a {{secret:name}} reference
+var validName = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+
+// Keychain stores secret values in the operating system's credential store
+type Keychain interface {
+	Get(name string) (string, error)
+	Set(name, value string) error
+	Delete(name string) error
+}
+
+// Service stores secrets in the OS keychain when one is available, and encrypted in the
+// database otherwise. It remembers the values it resolved so they can be masked in logs.
+type Service struct {
+	db       database.Store
+	keychain Keychain // nil when the platform has no usable keychain
+	revealed map[string]string
+	mu       sync.Mutex
+}
+
+// NewService creates a new secrets service using the platform's keychain if one is available
+func NewService(db database.Store) *Service {
+	return &Service{
+		db:       db,
+		keychain: systemKeychain(),
+		revealed: make(map[string]string),
+	}
+}
+
+// Set stores a secret, preferring the keychain and falling back to local encryption
+func (s *Service) Set(name, value string) (models.Secret, error) {
+	if !validName.MatchString(name) {
+		return models.Secret{}, fmt.Errorf("secret name may only contain letters, digits, '_', '.' and '-'")
+	}
+	if value == "" {
+		return models.Secret{}, fmt.Errorf("secret value is required")
+	}
+
+	s.forget(name)
+	if s.keychain != nil {
+		err := s.keychain.Set(name, value)
+		if err == nil {
+			return s.db.SaveSecret(name, models.SecretBackendKeychain, "")
+		}
+		log.Printf("Failed to store secret %q in the keychain, encrypting it locally instead: %v", name, err)
+	}
+
+	encrypted, err := encrypt(value)
+	if err != nil {
+		return models.Secret{}, err
+	}
+	return s.db.SaveSecret(name, models.SecretBackendLocal, encrypted)
+}
+
+// Get returns the value of a secret
+func (s *Service) Get(name string) (string, error) {
+	secret, err := s.db.GetSecretByName(name)
+	if err != nil {
+		return "", err
+	}
+
+	if secret.Backend == models.SecretBackendKeychain {
+		if s.keychain == nil {
+			return "", fmt.Errorf("secret %q is stored in a keychain that isn't available", name)
+		}
+		return s.keychain.Get(name)
+	}
+
+	encrypted, err := s.db.GetSecretValue(name)
+	if err != nil {
+		return "", err
+	}
+	return decrypt(encrypted)
+}
+
+// Delete removes a secret from the database and, if it lives there, the keychain
+func (s *Service) Delete(id int) error {
+	secret, err := s.db.GetSecretByID(id)
+	if err != nil {
+		return err
+	}
+
+	s.forget(secret.Name)
+	if secret.Backend == models.SecretBackendKeychain && s.keychain != nil {
+		if err := s.keychain.Delete(secret.Name); err != nil {
+			log.Printf("Failed to remove secret %q from the keychain: %v", secret.Name, err)
+		}
+	}
+	return s.db.DeleteSecret(id)
+}
+
+// Lookup resolves {{secret:name}} references and records the values for masking.
+// Names without the secret prefix, and unknown secrets, are not resolved.
+func (s *Service) Lookup(name string) (string, bool) {
+	if !strings.HasPrefix(name, Prefix) {
+		return "", false
+	}
+	name = strings.TrimSpace(strings.TrimPrefix(name, Prefix))
+
+	value, err := s.Get(name)
+	if err != nil {
+		log.Printf("Failed to resolve secret %q: %v", name, err)
+		return "", false
+	}
+
+	s.mu.Lock()
+	s.revealed[name] = value
+	s.mu.Unlock()
+	return value, true
+}
+
+// MaskValues replaces every secret value resolved so far with Mask
+func (s *Service) MaskValues(text string) string {
+	if text == "" {
+		return text
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, value := range s.revealed {
+		if len(value) >= minMaskLength {
+			text = strings.ReplaceAll(text, value, Mask)
+		}
+	}
+	return text
+}
+
+// forget drops a secret's remembered value once it changes or is deleted
+func (s *Service) forget(name string) {
+	s.mu.Lock()
+	delete(s.revealed, name)
+	s.mu.Unlock()
+}
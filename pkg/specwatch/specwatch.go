@@ -0,0 +1,244 @@
+package specwatch
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"flowpulse/pkg/database"
+	"flowpulse/pkg/importer"
+	"flowpulse/pkg/models"
+	"flowpulse/pkg/openapi"
+)
+
+// maxSpecSize bounds how much of a spec document is read
+const maxSpecSize = 10 << 20
+
+// checkInterval is how often the watcher looks for specs that are due
+const checkInterval = time.Minute
+
+// Service periodically re-fetches OpenAPI specs from their source URL and detects
+// operations that were added or removed compared to the APIs imported from them
+type Service struct {
+	db     database.Store
+	client *http.Client
+	stop   chan struct{}
+}
+
+// NewService creates a new spec watcher
+func NewService(db database.Store) *Service {
+	return &Service{
+		db: db,
+		client: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}
+}
+
+// Fetch downloads and parses an OpenAPI document
+func (s *Service) Fetch(url string) (string, *openapi.Document, error) {
+	resp, err := s.client.Get(url)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to fetch spec: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return "", nil, fmt.Errorf("spec URL returned %s", resp.Status)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxSpecSize))
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to read spec: %w", err)
+	}
+
+	doc, err := openapi.Parse(body)
+	if err != nil {
+		return "", nil, err
+	}
+	return string(body), doc, nil
+}
+
+// Start checks watched specs in the background until Stop is called
+func (s *Service) Start() {
+	if s.stop != nil {
+		return // Already running
+	}
+	s.stop = make(chan struct{})
+
+	go func(stop chan struct{}) {
+		ticker := time.NewTicker(checkInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				s.checkDue()
+			case <-stop:
+				return
+			}
+		}
+	}(s.stop)
+}
+
+// Stop stops the background checks
+func (s *Service) Stop() {
+	if s.stop != nil {
+		close(s.stop)
+		s.stop = nil
+	}
+}
+
+// checkDue checks every watched spec whose interval has elapsed
+func (s *Service) checkDue() {
+	specs, err := s.db.GetAllAPISpecs()
+	if err != nil {
+		log.Printf("Failed to load API specs: %v", err)
+		return
+	}
+
+	for _, spec := range specs {
+		if spec.SourceURL == "" || spec.WatchIntervalMinutes <= 0 {
+			continue
+		}
+		if time.Since(spec.LastCheckedAt) < time.Duration(spec.WatchIntervalMinutes)*time.Minute {
+			continue
+		}
+		if _, err := s.Check(spec.ID); err != nil {
+			log.Printf("Failed to check API spec %s: %v", spec.Name, err)
+		}
+	}
+}
+
+// Check re-fetches a spec, stores the latest document and records the detected changes.
+// New operations are imported straight away when the spec has AutoCreate set; everything
+// else becomes a pending proposal. It returns the spec's pending proposals.
+func (s *Service) Check(specID int) ([]models.SpecProposal, error) {
+	spec, err := s.db.GetAPISpecByID(specID)
+	if err != nil {
+		return nil, err
+	}
+	if spec.SourceURL == "" {
+		return nil, fmt.Errorf("spec %s has no source URL to watch", spec.Name)
+	}
+
+	// Record the attempt even if it fails so an unreachable URL isn't retried every minute
+	if err := s.db.MarkAPISpecChecked(spec.ID, time.Now()); err != nil {
+		return nil, err
+	}
+
+	document, doc, err := s.Fetch(spec.SourceURL)
+	if err != nil {
+		return nil, err
+	}
+	if document != spec.Document {
+		spec.Document = document
+		if _, err := s.db.UpdateAPISpec(spec); err != nil {
+			return nil, err
+		}
+	}
+
+	apis, err := s.db.GetAPIsBySpecID(spec.ID)
+	if err != nil {
+		return nil, err
+	}
+	added, removed := Diff(doc, apis)
+
+	if err := s.db.DeletePendingSpecProposals(spec.ID); err != nil {
+		return nil, err
+	}
+	for _, op := range added {
+		if spec.AutoCreate {
+			if _, err := s.createAPI(spec, doc, op); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		proposal := models.SpecProposal{SpecID: spec.ID, Kind: models.SpecProposalAdded, Operation: op.Key()}
+		if err := s.db.CreateSpecProposal(proposal); err != nil {
+			return nil, err
+		}
+	}
+	for _, api := range removed {
+		proposal := models.SpecProposal{SpecID: spec.ID, Kind: models.SpecProposalRemoved, Operation: api.SpecOperation, APIID: api.ID}
+		if err := s.db.CreateSpecProposal(proposal); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.db.GetPendingSpecProposals(spec.ID)
+}
+
+// Diff compares a spec's operations with the APIs imported from it and returns the operations
+// that have no API yet and the APIs whose operation no longer exists
+func Diff(doc *openapi.Document, apis []models.API) ([]openapi.Operation, []models.API) {
+	imported := make(map[string]bool, len(apis))
+	for _, api := range apis {
+		imported[api.SpecOperation] = true
+	}
+
+	var added []openapi.Operation
+	current := make(map[string]bool)
+	for _, op := range doc.Operations() {
+		current[op.Key()] = true
+		if !imported[op.Key()] {
+			added = append(added, op)
+		}
+	}
+
+	var removed []models.API
+	for _, api := range apis {
+		if !current[api.SpecOperation] {
+			removed = append(removed, api)
+		}
+	}
+	return added, removed
+}
+
+// Accept applies a pending proposal: an added operation is imported as a new API and
+// the API of a removed operation is deleted
+func (s *Service) Accept(proposalID int) error {
+	proposal, err := s.db.GetSpecProposalByID(proposalID)
+	if err != nil {
+		return err
+	}
+	if proposal.Status != models.SpecProposalPending {
+		return fmt.Errorf("proposal %d is already %s", proposal.ID, proposal.Status)
+	}
+
+	switch proposal.Kind {
+	case models.SpecProposalAdded:
+		spec, err := s.db.GetAPISpecByID(proposal.SpecID)
+		if err != nil {
+			return err
+		}
+		doc, err := openapi.Parse([]byte(spec.Document))
+		if err != nil {
+			return err
+		}
+		op, ok := doc.Operation(proposal.Operation)
+		if !ok {
+			return fmt.Errorf("operation %s is no longer in the spec", proposal.Operation)
+		}
+		if _, err := s.createAPI(spec, doc, op); err != nil {
+			return err
+		}
+	case models.SpecProposalRemoved:
+		if err := s.db.DeleteAPI(proposal.APIID); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unsupported proposal kind: %s", proposal.Kind)
+	}
+
+	return s.db.SetSpecProposalStatus(proposal.ID, models.SpecProposalAccepted)
+}
+
+// createAPI imports an operation of a spec as a new API in the spec's collection
+func (s *Service) createAPI(spec models.APISpec, doc *openapi.Document, op openapi.Operation) (models.API, error) {
+	api := importer.APIFromOperation(doc, op)
+	api.CollectionID = spec.CollectionID
+	api.SpecID = spec.ID
+	return s.db.CreateAPI(api)
+}
@@ -0,0 +1,194 @@
+// Package statuspage generates public status pages, as static HTML and JSON, from the uptime and
+// incident history of a chosen set of APIs.
+package statuspage
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"flowpulse/pkg/database"
+	"flowpulse/pkg/models"
+)
+
+// defaultWindowDays is the window of pages that don't set their own
+const defaultWindowDays = 30
+
+// Build gathers the content of a status page: the current status and uptime of each of its APIs and the
+// incidents opened during its window. APIs deleted since the page was set up are left out.
+func Build(db database.Store, page models.StatusPage, now time.Time) (models.StatusPageReport, error) {
+	report := models.StatusPageReport{
+		Title:       page.Title,
+		Status:      models.StatusPageOperational,
+		WindowDays:  page.WindowDays,
+		GeneratedAt: now,
+		APIs:        []models.StatusPageAPI{},
+		Incidents:   []models.StatusPageIncident{},
+	}
+	if report.Title == "" {
+		report.Title = page.Name
+	}
+	if report.WindowDays <= 0 {
+		report.WindowDays = defaultWindowDays
+	}
+	since := now.AddDate(0, 0, -report.WindowDays)
+
+	latest, err := db.GetLatestExecutionForAPIs(page.APIIDs)
+	if err != nil {
+		return report, err
+	}
+
+	for _, apiID := range page.APIIDs {
+		api, err := db.GetAPIByID(apiID)
+		if err != nil {
+			continue
+		}
+		uptime, executions, err := db.GetAPIUptime(apiID, since)
+		if err != nil {
+			return report, err
+		}
+		status := apiStatus(latest[apiID])
+		report.APIs = append(report.APIs, models.StatusPageAPI{
+			Name:       api.Name,
+			Status:     status,
+			Uptime:     uptime,
+			Executions: executions,
+		})
+		report.Status = worse(report.Status, status)
+
+		incidents, err := db.GetIncidentHistory(apiID, since)
+		if err != nil {
+			return report, err
+		}
+		for _, incident := range incidents {
+			report.Incidents = append(report.Incidents, models.StatusPageIncident{
+				APIName:         api.Name,
+				Status:          incident.Status,
+				OpenedAt:        incident.OpenedAt,
+				ResolvedAt:      incident.ResolvedAt,
+				DurationSeconds: incident.DurationSeconds,
+			})
+		}
+	}
+
+	sort.SliceStable(report.Incidents, func(i, j int) bool {
+		return report.Incidents[i].OpenedAt.After(report.Incidents[j].OpenedAt)
+	})
+	return report, nil
+}
+
+// apiStatus maps the latest execution of an API to its status on the page
+func apiStatus(execution models.ExecutionLog) string {
+	switch execution.Status {
+	case models.ExecutionStatusSuccess:
+		return models.StatusPageOperational
+	case models.ExecutionStatusDegraded:
+		return models.StatusPageDegraded
+	case models.ExecutionStatusFailure:
+		return models.StatusPageOutage
+	default:
+		return models.StatusPageUnknown
+	}
+}
+
+// worse returns the more severe of the overall status and an API's status. APIs not checked yet don't count.
+func worse(overall, status string) string {
+	severity := map[string]int{models.StatusPageOperational: 0, models.StatusPageDegraded: 1, models.StatusPageOutage: 2}
+	if rank, ok := severity[status]; ok && rank > severity[overall] {
+		return status
+	}
+	return overall
+}
+
+// RenderJSON renders a status page as indented JSON
+func RenderJSON(report models.StatusPageReport) ([]byte, error) {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode status page: %w", err)
+	}
+	return data, nil
+}
+
+// RenderHTML renders a status page as a self-contained HTML document
+func RenderHTML(report models.StatusPageReport) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := pageTemplate.Execute(&buf, report); err != nil {
+		return nil, fmt.Errorf("failed to render status page: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// Write renders a status page into dir as index.html and status.json, creating dir if needed
+func Write(report models.StatusPageReport, dir string) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create status page directory: %w", err)
+	}
+	page, err := RenderHTML(report)
+	if err != nil {
+		return err
+	}
+	data, err := RenderJSON(report)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, "index.html"), page, 0o644); err != nil {
+		return fmt.Errorf("failed to write status page: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "status.json"), data, 0o644); err != nil {
+		return fmt.Errorf("failed to write status page: %w", err)
+	}
+	return nil
+}
+
+// pageTemplate is the HTML of a status page, styled inline so the page is a single file
+var pageTemplate = template.Must(template.New("status").Funcs(template.FuncMap{
+	"uptime": func(api models.StatusPageAPI) string {
+		if api.Executions == 0 {
+			return "no data"
+		}
+		return fmt.Sprintf("%.2f%%", api.Uptime)
+	},
+	"when": func(t time.Time) string {
+		return t.UTC().Format("2006-01-02 15:04 UTC")
+	},
+	"duration": func(seconds int64) string {
+		return (time.Duration(seconds) * time.Second).String()
+	},
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}}</title>
+<style>
+body { font-family: system-ui, sans-serif; max-width: 760px; margin: 2rem auto; padding: 0 1rem; color: #1f2933; }
+.banner { padding: 1rem; border-radius: 6px; color: #fff; font-weight: 600; }
+.operational { background: #2f9e44; } .degraded { background: #f08c00; } .outage { background: #e03131; } .unknown { background: #868e96; }
+table { width: 100%; border-collapse: collapse; margin: 1rem 0; }
+td, th { text-align: left; padding: .5rem; border-bottom: 1px solid #e4e7eb; }
+.dot { display: inline-block; width: .7rem; height: .7rem; border-radius: 50%; margin-right: .4rem; }
+footer { color: #7b8794; font-size: .85rem; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<div class="banner {{.Status}}">{{if eq .Status "operational"}}All systems operational{{else if eq .Status "degraded"}}Degraded performance{{else}}Service disruption{{end}}</div>
+<h2>Services</h2>
+<table>
+<tr><th>Service</th><th>Status</th><th>Uptime ({{.WindowDays}} days)</th></tr>
+{{range .APIs}}<tr><td>{{.Name}}</td><td><span class="dot {{.Status}}"></span>{{.Status}}</td><td>{{uptime .}}</td></tr>
+{{end}}</table>
+<h2>Incidents</h2>
+{{if .Incidents}}<table>
+<tr><th>Service</th><th>Opened</th><th>Status</th><th>Duration</th></tr>
+{{range .Incidents}}<tr><td>{{.APIName}}</td><td>{{when .OpenedAt}}</td><td>{{.Status}}</td><td>{{duration .DurationSeconds}}</td></tr>
+{{end}}</table>{{else}}<p>No incidents in the last {{.WindowDays}} days.</p>{{end}}
+<footer>Generated {{when .GeneratedAt}}</footer>
+</body>
+</html>
+`))
@@ -0,0 +1,258 @@
+package webhooks
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"flowpulse/pkg/assertions"
+	"flowpulse/pkg/database"
+	"flowpulse/pkg/models"
+)
+
+// eventCompleted is the name of the event posted for a check's result, matching the scheduler's event
+const eventCompleted = "execution:completed"
+
+// Dispatcher posts check results to the event webhooks whose rules they match
+type Dispatcher struct {
+	db     database.Store
+	client *http.Client
+}
+
+// NewDispatcher creates a new event webhook dispatcher
+func NewDispatcher(db database.Store) *Dispatcher {
+	return &Dispatcher{
+		db: db,
+		client: &http.Client{
+			Timeout: 15 * time.Second,
+		},
+	}
+}
+
+// Publish receives an event from the scheduler and posts completed checks to the matching event webhooks.
+// Other events are ignored. It returns right away, delivering in the background.
+func (d *Dispatcher) Publish(event string, data interface{}) {
+	completed, ok := data.(models.CompletedExecution)
+	if !ok || event != eventCompleted {
+		return
+	}
+	go d.deliver(completed.Log)
+}
+
+// deliver posts an execution to every active event webhook whose rules it matches
+func (d *Dispatcher) deliver(execution models.ExecutionLog) {
+	webhooks, err := d.db.GetActiveEventWebhooks()
+	if err != nil {
+		log.Printf("Failed to load event webhooks: %v", err)
+		return
+	}
+	if len(webhooks) == 0 {
+		return
+	}
+
+	api, err := d.db.GetAPIByID(execution.APIID)
+	if err != nil {
+		log.Printf("Failed to load API %d for event webhooks: %v", execution.APIID, err)
+		return
+	}
+
+	var body []byte
+	for _, webhook := range webhooks {
+		if !Matches(webhook.Rules, api, execution) {
+			continue
+		}
+		if body == nil {
+			body, err = json.Marshal(models.ExecutionEvent{
+				Event:        eventCompleted,
+				APIName:      api.Name,
+				CollectionID: api.CollectionID,
+				Log:          execution,
+			})
+			if err != nil {
+				log.Printf("Failed to encode execution event: %v", err)
+				return
+			}
+		}
+		if err := d.post(webhook, body); err != nil {
+			log.Printf("Failed to post to event webhook %d: %v", webhook.ID, err)
+		}
+	}
+}
+
+// post sends an event to a webhook, signing it when the webhook has a secret
+func (d *Dispatcher) post(webhook models.EventWebhook, body []byte) error {
+	req, err := http.NewRequest(http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if webhook.Secret != "" {
+		req.Header.Set("X-FlowPulse-Signature-256", "sha256="+Sign(webhook.Secret, body))
+	}
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send event: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("event webhook returned %s", resp.Status)
+	}
+	return nil
+}
+
+// Sign returns the hex HMAC-SHA256 of a body with the secret, as sent in X-FlowPulse-Signature-256
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// ValidateRule checks that an event webhook rule can be evaluated
+func ValidateRule(rule models.EventWebhookRule) error {
+	switch rule.Field {
+	case models.EventRuleStatus:
+		switch rule.Operator {
+		case models.OperatorEquals, models.OperatorNotEquals, models.OperatorIn:
+			return nil
+		}
+	case models.EventRuleStatusCode:
+		if rule.Operator == models.OperatorIn {
+			_, err := assertions.ParseStatusCodes(rule.Value)
+			return err
+		}
+		return validateNumber(rule, true)
+	case models.EventRuleAPI, models.EventRuleCollection:
+		if rule.Operator == models.OperatorIn {
+			for _, part := range splitList(rule.Value) {
+				if _, err := strconv.Atoi(part); err != nil {
+					return fmt.Errorf("%s rule requires a list of IDs", rule.Field)
+				}
+			}
+			return nil
+		}
+		return validateNumber(rule, false)
+	case models.EventRuleDuration:
+		if rule.Operator == models.OperatorAtLeast || rule.Operator == models.OperatorAtMost {
+			return validateNumber(rule, true)
+		}
+	default:
+		return fmt.Errorf("unsupported rule field: %s", rule.Field)
+	}
+	return fmt.Errorf("unsupported operator for %s rule: %s", rule.Field, rule.Operator)
+}
+
+// validateNumber checks a rule compares with a number using equals or not_equals, or with ordered
+// also at_least or at_most
+func validateNumber(rule models.EventWebhookRule, ordered bool) error {
+	switch rule.Operator {
+	case models.OperatorEquals, models.OperatorNotEquals:
+	case models.OperatorAtLeast, models.OperatorAtMost:
+		if !ordered {
+			return fmt.Errorf("unsupported operator for %s rule: %s", rule.Field, rule.Operator)
+		}
+	default:
+		return fmt.Errorf("unsupported operator for %s rule: %s", rule.Field, rule.Operator)
+	}
+	if _, err := strconv.ParseInt(strings.TrimSpace(rule.Value), 10, 64); err != nil {
+		return fmt.Errorf("%s rule requires a number", rule.Field)
+	}
+	return nil
+}
+
+// Matches reports whether an API's execution matches every rule. Rules that can't be evaluated don't match.
+func Matches(rules []models.EventWebhookRule, api models.API, execution models.ExecutionLog) bool {
+	for _, rule := range rules {
+		if !matches(rule, api, execution) {
+			return false
+		}
+	}
+	return true
+}
+
+// matches evaluates a single rule
+func matches(rule models.EventWebhookRule, api models.API, execution models.ExecutionLog) bool {
+	switch rule.Field {
+	case models.EventRuleStatus:
+		return compareText(rule, execution.Status)
+	case models.EventRuleStatusCode:
+		if rule.Operator == models.OperatorIn {
+			codes, err := assertions.ParseStatusCodes(rule.Value)
+			return err == nil && codes.Contains(execution.StatusCode)
+		}
+		return compareNumber(rule, int64(execution.StatusCode))
+	case models.EventRuleAPI:
+		return compareNumber(rule, int64(api.ID))
+	case models.EventRuleCollection:
+		return compareNumber(rule, int64(api.CollectionID))
+	case models.EventRuleDuration:
+		return compareNumber(rule, execution.DurationMs)
+	default:
+		return false
+	}
+}
+
+// compareText applies a rule's operator to a text value
+func compareText(rule models.EventWebhookRule, actual string) bool {
+	switch rule.Operator {
+	case models.OperatorEquals:
+		return actual == strings.TrimSpace(rule.Value)
+	case models.OperatorNotEquals:
+		return actual != strings.TrimSpace(rule.Value)
+	case models.OperatorIn:
+		for _, part := range splitList(rule.Value) {
+			if actual == part {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// compareNumber applies a rule's operator to a numeric value
+func compareNumber(rule models.EventWebhookRule, actual int64) bool {
+	if rule.Operator == models.OperatorIn {
+		for _, part := range splitList(rule.Value) {
+			if value, err := strconv.ParseInt(part, 10, 64); err == nil && value == actual {
+				return true
+			}
+		}
+		return false
+	}
+
+	value, err := strconv.ParseInt(strings.TrimSpace(rule.Value), 10, 64)
+	if err != nil {
+		return false
+	}
+	switch rule.Operator {
+	case models.OperatorEquals:
+		return actual == value
+	case models.OperatorNotEquals:
+		return actual != value
+	case models.OperatorAtLeast:
+		return actual >= value
+	case models.OperatorAtMost:
+		return actual <= value
+	}
+	return false
+}
+
+// splitList splits a comma-separated rule value into its trimmed, non-empty parts
+func splitList(value string) []string {
+	var parts []string
+	for _, part := range strings.Split(value, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return parts
+}
@@ -0,0 +1,98 @@
+package webhooks
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"flowpulse/pkg/models"
+)
+
+// ErrInvalidSignature is returned when an inbound request isn't signed with the trigger's secret
+var ErrInvalidSignature = errors.New("missing or invalid webhook signature")
+
+// stripeTolerance is how old the timestamp of a Stripe-style signature may be, to reject replayed requests
+const stripeTolerance = 5 * time.Minute
+
+// GenerateSecret returns a random secret for signing a trigger's requests
+func GenerateSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// Verify checks that a request to a trigger was signed with its secret in the trigger's signature scheme:
+//   - "github": X-Hub-Signature-256 is "sha256=" and the hex HMAC-SHA256 of the body
+//   - "stripe": Stripe-Signature is "t=<unix time>,v1=<hex HMAC-SHA256 of the time, a dot and the body>",
+//     and the time is no more than five minutes away from now
+//   - "url": urlSecret, the secret the request's URL ends with, is the trigger's secret
+func Verify(trigger models.WebhookTrigger, urlSecret string, header http.Header, body []byte, now time.Time) error {
+	switch trigger.SignatureScheme {
+	case models.WebhookSignatureURL:
+		if urlSecret == "" || !hmac.Equal([]byte(urlSecret), []byte(trigger.Secret)) {
+			return ErrInvalidSignature
+		}
+		return nil
+	case models.WebhookSignatureGitHub:
+		signature, ok := strings.CutPrefix(header.Get("X-Hub-Signature-256"), "sha256=")
+		if !ok || !validMAC(trigger.Secret, body, signature) {
+			return ErrInvalidSignature
+		}
+		return nil
+	case models.WebhookSignatureStripe:
+		return verifyStripe(trigger.Secret, header.Get("Stripe-Signature"), body, now)
+	default:
+		return fmt.Errorf("unsupported signature scheme: %s", trigger.SignatureScheme)
+	}
+}
+
+// verifyStripe checks a Stripe-Signature header, which may carry several v1 signatures while secrets are rolled
+func verifyStripe(secret, header string, body []byte, now time.Time) error {
+	var timestamp string
+	var signatures []string
+	for _, part := range strings.Split(header, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch key {
+		case "t":
+			timestamp = value
+		case "v1":
+			signatures = append(signatures, value)
+		}
+	}
+
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	if age := now.Sub(time.Unix(unix, 0)); age > stripeTolerance || age < -stripeTolerance {
+		return ErrInvalidSignature
+	}
+
+	signed := append([]byte(timestamp+"."), body...)
+	for _, signature := range signatures {
+		if validMAC(secret, signed, signature) {
+			return nil
+		}
+	}
+	return ErrInvalidSignature
+}
+
+// validMAC reports whether signature is the hex HMAC-SHA256 of message with the secret, comparing in constant time
+func validMAC(secret string, message []byte, signature string) bool {
+	got, err := hex.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(message)
+	return hmac.Equal(got, mac.Sum(nil))
+}
@@ -0,0 +1,101 @@
+package workspacesync
+
+import (
+	"time"
+)
+
+// mergeSnapshots performs a three-way merge of the local and remote snapshots against the last synced base.
+// Changes made on only one side win; records changed on both sides are resolved by the newest edit,
+// and a modification always wins over a deletion on the other side.
+func mergeSnapshots(base, local, remote *Snapshot) (*Snapshot, int) {
+	merged := newSnapshot()
+	conflicts := 0
+
+	merged.Collections, conflicts = mergeRecords(base.Collections, local.Collections, remote.Collections,
+		func(r CollectionRecord) CollectionRecord { r.UpdatedAt = time.Time{}; return r },
+		func(r CollectionRecord) time.Time { return r.UpdatedAt }, conflicts)
+	merged.APIs, conflicts = mergeRecords(base.APIs, local.APIs, remote.APIs,
+		func(r APIRecord) APIRecord { r.UpdatedAt = time.Time{}; return r },
+		func(r APIRecord) time.Time { return r.UpdatedAt }, conflicts)
+	merged.Schedules, conflicts = mergeRecords(base.Schedules, local.Schedules, remote.Schedules,
+		func(r ScheduleRecord) ScheduleRecord { r.UpdatedAt = time.Time{}; return r },
+		func(r ScheduleRecord) time.Time { return r.UpdatedAt }, conflicts)
+	merged.Channels, conflicts = mergeRecords(base.Channels, local.Channels, remote.Channels,
+		func(r ChannelRecord) ChannelRecord { r.UpdatedAt = time.Time{}; return r },
+		func(r ChannelRecord) time.Time { return r.UpdatedAt }, conflicts)
+	merged.AlertRules, conflicts = mergeRecords(base.AlertRules, local.AlertRules, remote.AlertRules,
+		func(r AlertRuleRecord) AlertRuleRecord { r.UpdatedAt = time.Time{}; return r },
+		func(r AlertRuleRecord) time.Time { return r.UpdatedAt }, conflicts)
+
+	// Drop records whose parent was deleted by the merge
+	for key, record := range merged.APIs {
+		if _, ok := merged.Collections[record.CollectionKey]; record.CollectionKey != "" && !ok {
+			delete(merged.APIs, key)
+		}
+	}
+	for key, record := range merged.Schedules {
+		if _, ok := merged.APIs[record.APIKey]; !ok {
+			delete(merged.Schedules, key)
+		}
+	}
+	for key, record := range merged.AlertRules {
+		_, scheduleKept := merged.Schedules[record.ScheduleKey]
+		_, channelKept := merged.Channels[record.ChannelKey]
+		if !scheduleKept || (record.ChannelKey != "" && !channelKept) {
+			delete(merged.AlertRules, key)
+		}
+	}
+
+	return merged, conflicts
+}
+
+// mergeRecords merges one kind of record. content strips fields that don't count as a change
+// and updatedAt returns the time of the last edit used to resolve conflicts.
+func mergeRecords[T comparable](base, local, remote map[string]T, content func(T) T, updatedAt func(T) time.Time, conflicts int) (map[string]T, int) {
+	merged := make(map[string]T)
+
+	keys := make(map[string]bool)
+	for key := range local {
+		keys[key] = true
+	}
+	for key := range remote {
+		keys[key] = true
+	}
+
+	for key := range keys {
+		b, inBase := base[key]
+		l, inLocal := local[key]
+		r, inRemote := remote[key]
+
+		localChanged := !inBase || !inLocal || content(l) != content(b)
+		remoteChanged := !inBase || !inRemote || content(r) != content(b)
+
+		switch {
+		case inLocal && inRemote:
+			if content(l) == content(r) || !remoteChanged {
+				merged[key] = l
+			} else if !localChanged {
+				merged[key] = r
+			} else {
+				conflicts++
+				if updatedAt(r).After(updatedAt(l)) {
+					merged[key] = r
+				} else {
+					merged[key] = l
+				}
+			}
+		case inLocal:
+			// Missing remotely: either deleted there or created here
+			if !inBase || localChanged {
+				merged[key] = l
+			}
+		case inRemote:
+			// Missing locally: either deleted here or created there
+			if !inBase || remoteChanged {
+				merged[key] = r
+			}
+		}
+	}
+
+	return merged, conflicts
+}
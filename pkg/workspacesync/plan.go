@@ -0,0 +1,140 @@
+package workspacesync
+
+import (
+	"encoding/json"
+	"sort"
+
+	"flowpulse/pkg/models"
+)
+
+// planLocalChanges lists the changes applyToLocal would make to match the merged snapshot
+func planLocalChanges(local *localState, merged *Snapshot) models.ChangePlan {
+	plan := models.ChangePlan{Changes: []models.PlannedChange{}}
+	add := func(action, kind, name string, fields []string) {
+		plan.Changes = append(plan.Changes, models.PlannedChange{Action: action, Kind: kind, Name: name, Fields: fields})
+	}
+
+	for key, record := range local.snapshot.Collections {
+		if _, ok := merged.Collections[key]; !ok {
+			add(models.ChangeActionDelete, models.ChangeKindCollection, record.Name, nil)
+		}
+	}
+	for key, record := range merged.Collections {
+		current, ok := local.snapshot.Collections[key]
+		switch {
+		case !ok:
+			add(models.ChangeActionCreate, models.ChangeKindCollection, record.Name, nil)
+		case current != record:
+			add(models.ChangeActionUpdate, models.ChangeKindCollection, record.Name, changedFields(current, record))
+		}
+	}
+
+	for key, record := range local.snapshot.APIs {
+		if _, ok := merged.APIs[key]; !ok {
+			add(models.ChangeActionDelete, models.ChangeKindAPI, record.Name, nil)
+		}
+	}
+	for key, record := range merged.APIs {
+		current, ok := local.snapshot.APIs[key]
+		switch {
+		case !ok:
+			add(models.ChangeActionCreate, models.ChangeKindAPI, record.Name, nil)
+		case current != record:
+			add(models.ChangeActionUpdate, models.ChangeKindAPI, record.Name, changedFields(current, record))
+		}
+	}
+
+	for key, record := range local.snapshot.Schedules {
+		if _, ok := merged.Schedules[key]; !ok {
+			add(models.ChangeActionDelete, models.ChangeKindSchedule, scheduleName(local.snapshot, record), nil)
+		}
+	}
+	for key, record := range merged.Schedules {
+		current, ok := local.snapshot.Schedules[key]
+		switch {
+		case !ok:
+			add(models.ChangeActionCreate, models.ChangeKindSchedule, scheduleName(merged, record), nil)
+		case current != record:
+			add(models.ChangeActionUpdate, models.ChangeKindSchedule, scheduleName(merged, record), changedFields(current, record))
+		}
+	}
+
+	for key, record := range local.snapshot.Channels {
+		if _, ok := merged.Channels[key]; !ok {
+			add(models.ChangeActionDelete, models.ChangeKindChannel, record.Name, nil)
+		}
+	}
+	for key, record := range merged.Channels {
+		current, ok := local.snapshot.Channels[key]
+		switch {
+		case !ok:
+			add(models.ChangeActionCreate, models.ChangeKindChannel, record.Name, nil)
+		case current != record:
+			add(models.ChangeActionUpdate, models.ChangeKindChannel, record.Name, changedFields(current, record))
+		}
+	}
+
+	for key, record := range local.snapshot.AlertRules {
+		if _, ok := merged.AlertRules[key]; !ok {
+			add(models.ChangeActionDelete, models.ChangeKindAlertRule, alertRuleName(local.snapshot, record), nil)
+		}
+	}
+	for key, record := range merged.AlertRules {
+		current, ok := local.snapshot.AlertRules[key]
+		switch {
+		case !ok:
+			add(models.ChangeActionCreate, models.ChangeKindAlertRule, alertRuleName(merged, record), nil)
+		case current != record:
+			add(models.ChangeActionUpdate, models.ChangeKindAlertRule, alertRuleName(merged, record), changedFields(current, record))
+		}
+	}
+
+	// Map iteration is random, so order by kind as applied, then by name
+	kinds := map[string]int{
+		models.ChangeKindCollection: 0,
+		models.ChangeKindAPI:        1,
+		models.ChangeKindSchedule:   2,
+		models.ChangeKindChannel:    3,
+		models.ChangeKindAlertRule:  4,
+	}
+	sort.SliceStable(plan.Changes, func(i, j int) bool {
+		a, b := plan.Changes[i], plan.Changes[j]
+		if a.Kind != b.Kind {
+			return kinds[a.Kind] < kinds[b.Kind]
+		}
+		return a.Name < b.Name
+	})
+	return plan
+}
+
+// scheduleName describes a schedule by its API and expression, e.g. "Get users: interval 60"
+func scheduleName(snapshot *Snapshot, record ScheduleRecord) string {
+	return snapshot.APIs[record.APIKey].Name + ": " + record.Type + " " + record.Expression
+}
+
+// alertRuleName describes an alert rule by its schedule and channel, e.g. "Get users: interval 60 to Ops Slack"
+func alertRuleName(snapshot *Snapshot, record AlertRuleRecord) string {
+	name := scheduleName(snapshot, snapshot.Schedules[record.ScheduleKey])
+	if channel, ok := snapshot.Channels[record.ChannelKey]; ok {
+		name += " to " + channel.Name
+	}
+	return name
+}
+
+// changedFields returns the JSON names of the fields that differ between two records, apart from the edit time
+func changedFields(before, after interface{}) []string {
+	var a, b map[string]json.RawMessage
+	encodedBefore, _ := json.Marshal(before)
+	encodedAfter, _ := json.Marshal(after)
+	json.Unmarshal(encodedBefore, &a)
+	json.Unmarshal(encodedAfter, &b)
+
+	var fields []string
+	for name, value := range b {
+		if name != "updatedAt" && string(a[name]) != string(value) {
+			fields = append(fields, name)
+		}
+	}
+	sort.Strings(fields)
+	return fields
+}
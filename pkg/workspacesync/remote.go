@@ -0,0 +1,196 @@
+package workspacesync
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"flowpulse/pkg/models"
+)
+
+// Remote stores the shared workspace snapshot
+type Remote interface {
+	// Download returns the stored snapshot, or nil if nothing was uploaded yet
+	Download() ([]byte, error)
+	// Upload replaces the stored snapshot
+	Upload(data []byte) error
+}
+
+// NewRemote creates the remote described by the sync configuration
+func NewRemote(config models.SyncConfig, client *http.Client) (Remote, error) {
+	if config.Path == "" {
+		return nil, fmt.Errorf("sync path is required")
+	}
+
+	switch config.Provider {
+	case "webdav":
+		if config.Endpoint == "" {
+			return nil, fmt.Errorf("WebDAV endpoint is required")
+		}
+		return &webDAVRemote{config: config, client: client}, nil
+	case "s3":
+		if config.Bucket == "" || config.Region == "" {
+			return nil, fmt.Errorf("S3 bucket and region are required")
+		}
+		return &s3Remote{config: config, client: client}, nil
+	default:
+		return nil, fmt.Errorf("unsupported sync provider: %s", config.Provider)
+	}
+}
+
+// webDAVRemote stores the snapshot as a file in a WebDAV folder
+type webDAVRemote struct {
+	config models.SyncConfig
+	client *http.Client
+}
+
+func (r *webDAVRemote) fileURL() string {
+	return strings.TrimRight(r.config.Endpoint, "/") + "/" + strings.TrimLeft(r.config.Path, "/")
+}
+
+// Download fetches the snapshot file
+func (r *webDAVRemote) Download() ([]byte, error) {
+	req, err := http.NewRequest(http.MethodGet, r.fileURL(), nil)
+	if err != nil {
+		return nil, err
+	}
+	if r.config.Username != "" {
+		req.SetBasicAuth(r.config.Username, r.config.Password)
+	}
+	return doDownload(r.client, req)
+}
+
+// Upload writes the snapshot file
+func (r *webDAVRemote) Upload(data []byte) error {
+	req, err := http.NewRequest(http.MethodPut, r.fileURL(), bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if r.config.Username != "" {
+		req.SetBasicAuth(r.config.Username, r.config.Password)
+	}
+	return doUpload(r.client, req)
+}
+
+// s3Remote stores the snapshot as an object in an S3 (or S3-compatible) bucket
+type s3Remote struct {
+	config models.SyncConfig
+	client *http.Client
+}
+
+// objectURL uses virtual-hosted style for AWS and path style for custom endpoints such as MinIO
+func (r *s3Remote) objectURL() string {
+	key := strings.TrimLeft(r.config.Path, "/")
+	if r.config.Endpoint == "" {
+		return fmt.Sprintf("https://%s.s3.%s.amazonaws.com/%s", r.config.Bucket, r.config.Region, key)
+	}
+	return strings.TrimRight(r.config.Endpoint, "/") + "/" + r.config.Bucket + "/" + key
+}
+
+// Download fetches the snapshot object
+func (r *s3Remote) Download() ([]byte, error) {
+	req, err := http.NewRequest(http.MethodGet, r.objectURL(), nil)
+	if err != nil {
+		return nil, err
+	}
+	r.sign(req, nil)
+	return doDownload(r.client, req)
+}
+
+// Upload writes the snapshot object
+func (r *s3Remote) Upload(data []byte) error {
+	req, err := http.NewRequest(http.MethodPut, r.objectURL(), bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	r.sign(req, data)
+	return doUpload(r.client, req)
+}
+
+// sign adds an AWS Signature Version 4 Authorization header to the request
+func (r *s3Remote) sign(req *http.Request, payload []byte) {
+	now := time.Now().UTC()
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	payloadHash := sha256Hex(payload)
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	signedHeaders := "host;x-amz-content-sha256;x-amz-date"
+	canonicalHeaders := "host:" + req.URL.Host + "\n" +
+		"x-amz-content-sha256:" + payloadHash + "\n" +
+		"x-amz-date:" + amzDate + "\n"
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.Query().Encode(),
+		canonicalHeaders,
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := date + "/" + r.config.Region + "/s3/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + sha256Hex([]byte(canonicalRequest))
+
+	key := hmacSHA256([]byte("AWS4"+r.config.SecretAccessKey), date)
+	key = hmacSHA256(key, r.config.Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf(
+		"AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		r.config.AccessKeyID, scope, signedHeaders, signature,
+	))
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// doDownload executes a GET request, treating a missing file as an empty remote
+func doDownload(client *http.Client, req *http.Request) ([]byte, error) {
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download snapshot: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("failed to download snapshot: %s", resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// doUpload executes a PUT request
+func doUpload(client *http.Client, req *http.Request) error {
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to upload snapshot: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("failed to upload snapshot: %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,626 @@
+package workspacesync
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"flowpulse/pkg/database"
+	"flowpulse/pkg/models"
+	"flowpulse/pkg/secrets"
+)
+
+// Snapshot is the device-independent representation of the workspace definitions.
+// Records are keyed by UUID and reference each other by UUID instead of by autoincrement ID,
+// since IDs differ between devices. Version 1 snapshots were keyed by names instead,
+// and version 2 snapshots didn't carry notification channels and alert rules yet.
+type Snapshot struct {
+	Version     int                         `json:"version"`
+	Collections map[string]CollectionRecord `json:"collections"`
+	APIs        map[string]APIRecord        `json:"apis"`
+	Schedules   map[string]ScheduleRecord   `json:"schedules"`
+	Channels    map[string]ChannelRecord    `json:"channels"`
+	AlertRules  map[string]AlertRuleRecord  `json:"alertRules"`
+}
+
+// CollectionRecord is a synced collection
+type CollectionRecord struct {
+	ParentKey   string    `json:"parentKey,omitempty"` // UUID of the collection it is nested in (empty for top level)
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+// APIRecord is a synced API
+type APIRecord struct {
+	CollectionKey string    `json:"collectionKey"` // UUID of the API's collection (empty for none)
+	Name          string    `json:"name"`
+	Method        string    `json:"method"`
+	URL           string    `json:"url"`
+	Headers       string    `json:"headers"`
+	Body          string    `json:"body"`
+	Description   string    `json:"description"`
+	UpdatedAt     time.Time `json:"updatedAt"`
+}
+
+// ScheduleRecord is a synced schedule
+type ScheduleRecord struct {
+	APIKey        string             `json:"apiKey"` // UUID of the scheduled API
+	Type          string             `json:"type"`
+	Expression    string             `json:"expression"`
+	Timezone      string             `json:"timezone,omitempty"`
+	AfterKey      string             `json:"afterKey,omitempty"` // UUID of the schedule an "after" schedule runs after
+	AfterOutcome  string             `json:"afterOutcome,omitempty"`
+	IsActive      bool               `json:"isActive"`
+	RetryCount    int                `json:"retryCount"`
+	FallbackDelay int                `json:"fallbackDelay"`
+	RetryPolicy   models.RetryPolicy `json:"retryPolicy"`
+	UpdatedAt     time.Time          `json:"updatedAt"`
+}
+
+// ChannelRecord is a synced notification channel. Its secrets, the SMTP password and the webhook URL that
+// embeds the webhook's token, are masked, so they stay on the device they were entered on.
+type ChannelRecord struct {
+	Name      string               `json:"name"`
+	Type      string               `json:"type"`
+	Config    models.ChannelConfig `json:"config"`
+	UpdatedAt time.Time            `json:"updatedAt"`
+}
+
+// AlertRuleRecord is a synced alert rule. On-call rotations aren't synced, so a rule keeps its local rotation.
+type AlertRuleRecord struct {
+	ScheduleKey      string    `json:"scheduleKey"` // UUID of the schedule the rule watches
+	ChannelKey       string    `json:"channelKey"`  // UUID of the channel alerts go to (empty for none)
+	FailureThreshold int       `json:"failureThreshold"`
+	NotifyOnRecovery bool      `json:"notifyOnRecovery"`
+	IsActive         bool      `json:"isActive"`
+	UpdatedAt        time.Time `json:"updatedAt"`
+}
+
+// maskChannelConfig returns the settings of a channel with its secrets masked
+func maskChannelConfig(channel models.NotificationChannel) models.ChannelConfig {
+	var config models.ChannelConfig
+	if channel.Config != "" {
+		// A malformed config has nothing worth syncing
+		if err := json.Unmarshal([]byte(channel.Config), &config); err != nil {
+			return models.ChannelConfig{}
+		}
+	}
+	if config.Password != "" {
+		config.Password = secrets.Mask
+	}
+	if config.URL != "" {
+		config.URL = secrets.Mask
+	}
+	return config
+}
+
+// applyTo copies the synced fields onto a collection
+func (r CollectionRecord) applyTo(collection *models.Collection) {
+	collection.Name = r.Name
+	collection.Description = r.Description
+}
+
+// applyTo copies the synced fields onto an API
+func (r APIRecord) applyTo(api *models.API, collectionID int) {
+	api.Name = r.Name
+	api.Method = r.Method
+	api.URL = r.URL
+	api.Headers = r.Headers
+	api.Body = r.Body
+	api.Description = r.Description
+	api.CollectionID = collectionID
+}
+
+// applyTo copies the synced fields onto a schedule
+func (r ScheduleRecord) applyTo(schedule *models.Schedule, apiID int) {
+	schedule.APIID = apiID
+	schedule.Type = r.Type
+	schedule.Expression = r.Expression
+	schedule.Timezone = r.Timezone
+	schedule.AfterOutcome = r.AfterOutcome
+	schedule.IsActive = r.IsActive
+	schedule.RetryCount = r.RetryCount
+	schedule.FallbackDelay = r.FallbackDelay
+	schedule.RetryPolicy = r.RetryPolicy
+}
+
+// applyTo copies the synced fields onto a notification channel, keeping the local values of its masked secrets.
+// A channel created from a snapshot has no secrets until they are entered on this device.
+func (r ChannelRecord) applyTo(channel *models.NotificationChannel) error {
+	var current models.ChannelConfig
+	if channel.Config != "" {
+		if err := json.Unmarshal([]byte(channel.Config), &current); err != nil {
+			return fmt.Errorf("failed to parse channel config: %w", err)
+		}
+	}
+
+	config := r.Config
+	if config.Password == secrets.Mask {
+		config.Password = current.Password
+	}
+	if config.URL == secrets.Mask {
+		config.URL = current.URL
+	}
+	encoded, err := json.Marshal(config)
+	if err != nil {
+		return fmt.Errorf("failed to encode channel config: %w", err)
+	}
+
+	channel.Name = r.Name
+	channel.Type = r.Type
+	channel.Config = string(encoded)
+	return nil
+}
+
+// applyTo copies the synced fields onto an alert rule
+func (r AlertRuleRecord) applyTo(rule *models.AlertRule, scheduleID, channelID int) {
+	rule.ScheduleID = scheduleID
+	rule.ChannelID = channelID
+	rule.FailureThreshold = r.FailureThreshold
+	rule.NotifyOnRecovery = r.NotifyOnRecovery
+	rule.IsActive = r.IsActive
+}
+
+// snapshotVersion is the current format version of Snapshot
+const snapshotVersion = 3
+
+// newSnapshot creates an empty snapshot
+func newSnapshot() *Snapshot {
+	return &Snapshot{
+		Version:     snapshotVersion,
+		Collections: make(map[string]CollectionRecord),
+		APIs:        make(map[string]APIRecord),
+		Schedules:   make(map[string]ScheduleRecord),
+		Channels:    make(map[string]ChannelRecord),
+		AlertRules:  make(map[string]AlertRuleRecord),
+	}
+}
+
+// legacyAPIKey builds the version 1 key of an API record
+func legacyAPIKey(collectionKey, name string) string {
+	return collectionKey + "/" + name
+}
+
+// legacyScheduleKey builds the version 1 key of a schedule record
+func legacyScheduleKey(apiKey, scheduleType, expression string) string {
+	return apiKey + "#" + scheduleType + "#" + expression
+}
+
+// localState is a snapshot of the local database together with the local IDs of each record,
+// and the UUIDs of its records by version 1 key so older snapshots can be upgraded
+type localState struct {
+	snapshot          *Snapshot
+	collectionIDs     map[string]int
+	apiIDs            map[string]int
+	scheduleIDs       map[string]int
+	channelIDs        map[string]int
+	alertRuleIDs      map[string]int
+	legacyCollections map[string]string
+	legacyAPIs        map[string]string
+	legacySchedules   map[string]string
+}
+
+// buildLocalState reads the workspace definitions from the database
+func buildLocalState(db database.Store) (*localState, error) {
+	state := &localState{
+		snapshot:          newSnapshot(),
+		collectionIDs:     make(map[string]int),
+		apiIDs:            make(map[string]int),
+		scheduleIDs:       make(map[string]int),
+		channelIDs:        make(map[string]int),
+		alertRuleIDs:      make(map[string]int),
+		legacyCollections: make(map[string]string),
+		legacyAPIs:        make(map[string]string),
+		legacySchedules:   make(map[string]string),
+	}
+
+	collections, err := db.GetAllCollections()
+	if err != nil {
+		return nil, err
+	}
+	collectionKeys := make(map[int]string)
+	collectionNames := make(map[int]string)
+	for _, collection := range collections {
+		collectionKeys[collection.ID] = collection.UUID
+		collectionNames[collection.ID] = collection.Name
+		state.legacyCollections[collection.Name] = collection.UUID
+		state.collectionIDs[collection.UUID] = collection.ID
+		state.snapshot.Collections[collection.UUID] = CollectionRecord{
+			Name:        collection.Name,
+			Description: collection.Description,
+			UpdatedAt:   collection.UpdatedAt,
+		}
+	}
+	// Parents are keyed once every collection's key is known
+	for _, collection := range collections {
+		if collection.ParentID != 0 {
+			record := state.snapshot.Collections[collection.UUID]
+			record.ParentKey = collectionKeys[collection.ParentID]
+			state.snapshot.Collections[collection.UUID] = record
+		}
+	}
+
+	apis, err := db.GetAllAPIs()
+	if err != nil {
+		return nil, err
+	}
+	apiKeys := make(map[int]string)
+	legacyAPIKeys := make(map[int]string)
+	for _, api := range apis {
+		collectionKey := collectionKeys[api.CollectionID]
+		key := api.UUID
+		apiKeys[api.ID] = key
+		legacyAPIKeys[api.ID] = legacyAPIKey(collectionNames[api.CollectionID], api.Name)
+		state.legacyAPIs[legacyAPIKeys[api.ID]] = key
+		state.apiIDs[key] = api.ID
+		state.snapshot.APIs[key] = APIRecord{
+			CollectionKey: collectionKey,
+			Name:          api.Name,
+			Method:        api.Method,
+			URL:           api.URL,
+			Headers:       api.Headers,
+			Body:          api.Body,
+			Description:   api.Description,
+			UpdatedAt:     api.UpdatedAt,
+		}
+	}
+
+	schedules, err := db.GetAllSchedules()
+	if err != nil {
+		return nil, err
+	}
+	scheduleKeys := make(map[int]string)
+	for _, schedule := range schedules {
+		apiKey, ok := apiKeys[schedule.APIID]
+		if !ok {
+			continue
+		}
+		key := schedule.UUID
+		scheduleKeys[schedule.ID] = key
+		state.legacySchedules[legacyScheduleKey(legacyAPIKeys[schedule.APIID], schedule.Type, schedule.Expression)] = key
+		state.scheduleIDs[key] = schedule.ID
+		state.snapshot.Schedules[key] = ScheduleRecord{
+			APIKey:        apiKey,
+			Type:          schedule.Type,
+			Expression:    schedule.Expression,
+			Timezone:      schedule.Timezone,
+			AfterOutcome:  schedule.AfterOutcome,
+			IsActive:      schedule.IsActive,
+			RetryCount:    schedule.RetryCount,
+			FallbackDelay: schedule.FallbackDelay,
+			RetryPolicy:   schedule.RetryPolicy,
+			UpdatedAt:     schedule.UpdatedAt,
+		}
+	}
+	// Links between schedules are keyed once every schedule's key is known
+	for _, schedule := range schedules {
+		if record, ok := state.snapshot.Schedules[schedule.UUID]; ok && schedule.AfterID != 0 {
+			record.AfterKey = scheduleKeys[schedule.AfterID]
+			state.snapshot.Schedules[schedule.UUID] = record
+		}
+	}
+
+	channels, err := db.GetAllNotificationChannels()
+	if err != nil {
+		return nil, err
+	}
+	channelKeys := make(map[int]string)
+	for _, channel := range channels {
+		channelKeys[channel.ID] = channel.UUID
+		state.channelIDs[channel.UUID] = channel.ID
+		state.snapshot.Channels[channel.UUID] = ChannelRecord{
+			Name:      channel.Name,
+			Type:      channel.Type,
+			Config:    maskChannelConfig(channel),
+			UpdatedAt: channel.UpdatedAt,
+		}
+	}
+
+	rules, err := db.GetAllAlertRules()
+	if err != nil {
+		return nil, err
+	}
+	for _, rule := range rules {
+		scheduleKey, ok := scheduleKeys[rule.ScheduleID]
+		if !ok {
+			continue
+		}
+		state.alertRuleIDs[rule.UUID] = rule.ID
+		state.snapshot.AlertRules[rule.UUID] = AlertRuleRecord{
+			ScheduleKey:      scheduleKey,
+			ChannelKey:       channelKeys[rule.ChannelID],
+			FailureThreshold: rule.FailureThreshold,
+			NotifyOnRecovery: rule.NotifyOnRecovery,
+			IsActive:         rule.IsActive,
+			UpdatedAt:        rule.UpdatedAt,
+		}
+	}
+
+	return state, nil
+}
+
+// applyToLocal makes the local database match the merged snapshot and returns the IDs of changed schedules
+func applyToLocal(db database.Store, local *localState, merged *Snapshot, result *models.SyncResult) ([]int, error) {
+	var changedSchedules []int
+
+	// Deletions run children first so schedules never point at a missing API
+	for key, id := range local.alertRuleIDs {
+		if _, ok := merged.AlertRules[key]; !ok {
+			if err := db.DeleteAlertRule(id); err != nil {
+				return changedSchedules, err
+			}
+			result.Deleted++
+		}
+	}
+	for key, id := range local.scheduleIDs {
+		if _, ok := merged.Schedules[key]; !ok {
+			if err := db.DeleteSchedule(id); err != nil {
+				return changedSchedules, err
+			}
+			changedSchedules = append(changedSchedules, id)
+			result.Deleted++
+		}
+	}
+	for key, id := range local.apiIDs {
+		if _, ok := merged.APIs[key]; !ok {
+			if err := db.DeleteAPI(id); err != nil {
+				return changedSchedules, err
+			}
+			result.Deleted++
+		}
+	}
+	for key, id := range local.collectionIDs {
+		if _, ok := merged.Collections[key]; !ok {
+			if err := db.DeleteCollection(id); err != nil {
+				return changedSchedules, err
+			}
+			result.Deleted++
+		}
+	}
+	for key, id := range local.channelIDs {
+		if _, ok := merged.Channels[key]; !ok {
+			if err := db.DeleteNotificationChannel(id); err != nil {
+				return changedSchedules, err
+			}
+			result.Deleted++
+		}
+	}
+
+	// Creations and updates run parents first so children can resolve their local IDs
+	for key, record := range merged.Collections {
+		if id, ok := local.collectionIDs[key]; ok {
+			if local.snapshot.Collections[key] == record {
+				continue
+			}
+			// Overlay the synced fields so local-only settings are kept
+			collection, err := db.GetCollectionByID(id)
+			if err != nil {
+				return changedSchedules, err
+			}
+			record.applyTo(&collection)
+			if _, err := db.UpdateCollection(collection); err != nil {
+				return changedSchedules, err
+			}
+			result.Updated++
+			continue
+		}
+		collection := models.Collection{UUID: key}
+		record.applyTo(&collection)
+		created, err := db.CreateCollection(collection)
+		if err != nil {
+			return changedSchedules, err
+		}
+		local.collectionIDs[key] = created.ID
+		result.Created++
+	}
+	// Nesting waits until every collection exists. Moves that would nest a collection in itself, which edits
+	// merged from two devices can ask for, are left out so the local nesting is kept.
+	for key, record := range merged.Collections {
+		existing, ok := local.snapshot.Collections[key]
+		if (ok && existing.ParentKey == record.ParentKey) || (!ok && record.ParentKey == "") {
+			continue
+		}
+		if err := db.MoveCollection(local.collectionIDs[key], local.collectionIDs[record.ParentKey]); err != nil {
+			log.Printf("Sync kept collection %s where it was: %v", record.Name, err)
+		}
+	}
+
+	for key, record := range merged.APIs {
+		collectionID := local.collectionIDs[record.CollectionKey]
+		if id, ok := local.apiIDs[key]; ok {
+			if local.snapshot.APIs[key] == record {
+				continue
+			}
+			api, err := db.GetAPIByID(id)
+			if err != nil {
+				return changedSchedules, err
+			}
+			record.applyTo(&api, collectionID)
+			if _, err := db.UpdateAPI(api); err != nil {
+				return changedSchedules, err
+			}
+			result.Updated++
+			continue
+		}
+		api := models.API{UUID: key}
+		record.applyTo(&api, collectionID)
+		created, err := db.CreateAPI(api)
+		if err != nil {
+			return changedSchedules, err
+		}
+		local.apiIDs[key] = created.ID
+		result.Created++
+	}
+
+	for key, record := range merged.Schedules {
+		apiID, ok := local.apiIDs[record.APIKey]
+		if !ok {
+			return changedSchedules, fmt.Errorf("schedule %s references unknown API %s", key, record.APIKey)
+		}
+		if id, ok := local.scheduleIDs[key]; ok {
+			if local.snapshot.Schedules[key] == record {
+				continue
+			}
+			schedule, err := db.GetScheduleByID(id)
+			if err != nil {
+				return changedSchedules, err
+			}
+			record.applyTo(&schedule, apiID)
+			if err := db.UpdateSchedule(schedule); err != nil {
+				return changedSchedules, err
+			}
+			changedSchedules = append(changedSchedules, id)
+			result.Updated++
+			continue
+		}
+		schedule := models.Schedule{UUID: key}
+		record.applyTo(&schedule, apiID)
+		created, err := db.CreateSchedule(schedule)
+		if err != nil {
+			return changedSchedules, err
+		}
+		local.scheduleIDs[key] = created.ID
+		changedSchedules = append(changedSchedules, created.ID)
+		result.Created++
+	}
+	// Links between schedules wait until every schedule exists, since one may run after a schedule created later
+	for key, record := range merged.Schedules {
+		existing, ok := local.snapshot.Schedules[key]
+		if (ok && existing.AfterKey == record.AfterKey) || (!ok && record.AfterKey == "") {
+			continue
+		}
+		schedule, err := db.GetScheduleByID(local.scheduleIDs[key])
+		if err != nil {
+			return changedSchedules, err
+		}
+		schedule.AfterID = local.scheduleIDs[record.AfterKey]
+		if err := db.UpdateSchedule(schedule); err != nil {
+			return changedSchedules, err
+		}
+		changedSchedules = append(changedSchedules, schedule.ID)
+	}
+
+	for key, record := range merged.Channels {
+		if id, ok := local.channelIDs[key]; ok {
+			if local.snapshot.Channels[key] == record {
+				continue
+			}
+			channel, err := db.GetNotificationChannelByID(id)
+			if err != nil {
+				return changedSchedules, err
+			}
+			if err := record.applyTo(&channel); err != nil {
+				return changedSchedules, err
+			}
+			if _, err := db.UpdateNotificationChannel(channel); err != nil {
+				return changedSchedules, err
+			}
+			result.Updated++
+			continue
+		}
+		channel := models.NotificationChannel{UUID: key}
+		if err := record.applyTo(&channel); err != nil {
+			return changedSchedules, err
+		}
+		created, err := db.CreateNotificationChannel(channel)
+		if err != nil {
+			return changedSchedules, err
+		}
+		local.channelIDs[key] = created.ID
+		result.Created++
+	}
+
+	for key, record := range merged.AlertRules {
+		scheduleID, ok := local.scheduleIDs[record.ScheduleKey]
+		if !ok {
+			return changedSchedules, fmt.Errorf("alert rule %s references unknown schedule %s", key, record.ScheduleKey)
+		}
+		channelID := local.channelIDs[record.ChannelKey]
+		if id, ok := local.alertRuleIDs[key]; ok {
+			if local.snapshot.AlertRules[key] == record {
+				continue
+			}
+			rule, err := db.GetAlertRuleByID(id)
+			if err != nil {
+				return changedSchedules, err
+			}
+			record.applyTo(&rule, scheduleID, channelID)
+			if _, err := db.UpdateAlertRule(rule); err != nil {
+				return changedSchedules, err
+			}
+			result.Updated++
+			continue
+		}
+		rule := models.AlertRule{UUID: key}
+		record.applyTo(&rule, scheduleID, channelID)
+		if _, err := db.CreateAlertRule(rule); err != nil {
+			return changedSchedules, err
+		}
+		result.Created++
+	}
+
+	return changedSchedules, nil
+}
+
+// upgrader converts version 1 snapshots, keyed by names, to UUID keys. Records that exist locally
+// take their local UUID; others get a new UUID that is shared between the snapshots it upgrades,
+// so the same record in the base and the remote snapshot still matches up.
+type upgrader struct {
+	local       *localState
+	collections map[string]string
+	apis        map[string]string
+	schedules   map[string]string
+}
+
+func newUpgrader(local *localState) *upgrader {
+	return &upgrader{
+		local:       local,
+		collections: make(map[string]string),
+		apis:        make(map[string]string),
+		schedules:   make(map[string]string),
+	}
+}
+
+// uuidFor returns the UUID of a version 1 key, preferring the local record's
+func uuidFor(key string, localUUIDs, assigned map[string]string) string {
+	if uuid, ok := localUUIDs[key]; ok {
+		return uuid
+	}
+	if uuid, ok := assigned[key]; ok {
+		return uuid
+	}
+	uuid := database.NewUUID()
+	assigned[key] = uuid
+	return uuid
+}
+
+// upgrade converts the snapshot in place when it predates UUID keys
+func (u *upgrader) upgrade(snapshot *Snapshot) {
+	if snapshot.Version >= 2 {
+		return
+	}
+
+	collections := make(map[string]CollectionRecord, len(snapshot.Collections))
+	for key, record := range snapshot.Collections {
+		collections[uuidFor(key, u.local.legacyCollections, u.collections)] = record
+	}
+	apis := make(map[string]APIRecord, len(snapshot.APIs))
+	for key, record := range snapshot.APIs {
+		if record.CollectionKey != "" {
+			record.CollectionKey = uuidFor(record.CollectionKey, u.local.legacyCollections, u.collections)
+		}
+		apis[uuidFor(key, u.local.legacyAPIs, u.apis)] = record
+	}
+	schedules := make(map[string]ScheduleRecord, len(snapshot.Schedules))
+	for key, record := range snapshot.Schedules {
+		record.APIKey = uuidFor(record.APIKey, u.local.legacyAPIs, u.apis)
+		schedules[uuidFor(key, u.local.legacySchedules, u.schedules)] = record
+	}
+
+	snapshot.Version = snapshotVersion
+	snapshot.Collections = collections
+	snapshot.APIs = apis
+	snapshot.Schedules = schedules
+}
@@ -0,0 +1,145 @@
+package workspacesync
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"flowpulse/pkg/database"
+	"flowpulse/pkg/models"
+)
+
+// SyncService syncs the workspace definitions (APIs, collections, schedules, notification channels and alert rules,
+// not logs) with a remote
+type SyncService struct {
+	db     database.Store
+	client *http.Client
+}
+
+// NewSyncService creates a new sync service
+func NewSyncService(db database.Store) *SyncService {
+	return &SyncService{
+		db: db,
+		client: &http.Client{
+			Timeout: 60 * time.Second,
+		},
+	}
+}
+
+// Sync merges local and remote changes, applies the result locally and uploads it.
+// It returns the IDs of local schedules that were created, updated or deleted so their jobs can be refreshed.
+func (s *SyncService) Sync() (models.SyncResult, []int, error) {
+	var result models.SyncResult
+
+	state, err := s.merge()
+	if err != nil {
+		return result, nil, err
+	}
+	result.Conflicts = state.conflicts
+
+	changedSchedules, err := applyToLocal(s.db, state.local, state.merged, &result)
+	if err != nil {
+		return result, changedSchedules, fmt.Errorf("failed to apply synced changes: %w", err)
+	}
+
+	// Re-read the database so the uploaded snapshot carries the local timestamps
+	final, err := buildLocalState(s.db)
+	if err != nil {
+		return result, changedSchedules, fmt.Errorf("failed to read local workspace: %w", err)
+	}
+	data, err := json.Marshal(final.snapshot)
+	if err != nil {
+		return result, changedSchedules, fmt.Errorf("failed to encode snapshot: %w", err)
+	}
+	if err := state.remote.Upload(data); err != nil {
+		return result, changedSchedules, err
+	}
+
+	syncedAt := time.Now()
+	if err := s.db.SaveSyncBaseSnapshot(string(data), syncedAt); err != nil {
+		return result, changedSchedules, err
+	}
+	result.SyncedAt = syncedAt.Format(time.RFC3339)
+
+	return result, changedSchedules, nil
+}
+
+// Preview merges local and remote changes like Sync, but only returns the changes it would make
+// to the local workspace without applying them or uploading anything
+func (s *SyncService) Preview() (models.ChangePlan, error) {
+	state, err := s.merge()
+	if err != nil {
+		return models.ChangePlan{}, err
+	}
+	plan := planLocalChanges(state.local, state.merged)
+	plan.Conflicts = state.conflicts
+	return plan, nil
+}
+
+// mergeState is the outcome of merging the local workspace with the remote
+type mergeState struct {
+	remote    Remote
+	local     *localState
+	merged    *Snapshot
+	conflicts int
+}
+
+// merge downloads the remote snapshot and merges it with the local workspace against the last synced base
+func (s *SyncService) merge() (*mergeState, error) {
+	config, err := s.db.GetSyncConfig()
+	if err != nil {
+		return nil, err
+	}
+	remote, err := NewRemote(config, s.client)
+	if err != nil {
+		return nil, err
+	}
+
+	base, err := s.loadBase()
+	if err != nil {
+		return nil, err
+	}
+
+	local, err := buildLocalState(s.db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read local workspace: %w", err)
+	}
+
+	remoteSnapshot := newSnapshot()
+	data, err := remote.Download()
+	if err != nil {
+		return nil, err
+	}
+	if data != nil {
+		if err := json.Unmarshal(data, remoteSnapshot); err != nil {
+			return nil, fmt.Errorf("failed to parse remote snapshot: %w", err)
+		}
+		if remoteSnapshot.Version > snapshotVersion {
+			return nil, fmt.Errorf("remote snapshot version %d is newer than supported version %d", remoteSnapshot.Version, snapshotVersion)
+		}
+	}
+
+	// Snapshots written before records had UUIDs are converted so they match the local records
+	upgrader := newUpgrader(local)
+	upgrader.upgrade(base)
+	upgrader.upgrade(remoteSnapshot)
+
+	merged, conflicts := mergeSnapshots(base, local.snapshot, remoteSnapshot)
+	return &mergeState{remote: remote, local: local, merged: merged, conflicts: conflicts}, nil
+}
+
+// loadBase loads the snapshot of the last successful sync
+func (s *SyncService) loadBase() (*Snapshot, error) {
+	base := newSnapshot()
+	data, err := s.db.GetSyncBaseSnapshot()
+	if err != nil {
+		return nil, err
+	}
+	if data != "" {
+		if err := json.Unmarshal([]byte(data), base); err != nil {
+			return nil, fmt.Errorf("failed to parse sync base snapshot: %w", err)
+		}
+	}
+	return base, nil
+}
@@ -0,0 +1,962 @@
+package main
+
+import (
+	"context"
+	"crypto/subtle"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"flowpulse/pkg/backup"
+	"flowpulse/pkg/models"
+	"flowpulse/pkg/statuspage"
+	"flowpulse/pkg/webhooks"
+)
+
+// startRESTServer serves the REST API on host:port, stopping any server already running
+func (a *App) startRESTServer(host string, port int, token string) error {
+	if token == "" && !isLoopback(host) {
+		return fmt.Errorf("a token is required to serve the REST API on %s", host)
+	}
+
+	a.stopRESTServer()
+
+	addr := net.JoinHostPort(host, strconv.Itoa(port))
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+
+	server := &http.Server{Handler: a.restHandler(token)}
+	go func() {
+		if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Printf("REST API stopped: %v", err)
+		}
+	}()
+	log.Printf("REST API listening on %s", addr)
+
+	a.restMutex.Lock()
+	a.restServer = server
+	a.restMutex.Unlock()
+	return nil
+}
+
+// stopRESTServer stops the REST server if it is running, letting requests in progress finish
+func (a *App) stopRESTServer() {
+	a.restMutex.Lock()
+	server := a.restServer
+	a.restServer = nil
+	a.restMutex.Unlock()
+	if server == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := server.Shutdown(ctx); err != nil {
+		log.Printf("Failed to stop REST API: %v", err)
+	}
+}
+
+// isLoopback reports whether a host only accepts connections from this machine
+func isLoopback(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// restHandler serves the REST API used by external tooling and CI to drive FlowPulse.
+// Every endpoint except /health, the webhook triggers and the public status pages requires the token as a
+// bearer token when one is set. Webhook triggers authenticate each request by its signature instead.
+func (a *App) restHandler(token string) http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("GET /health", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+	})
+
+	// APIs
+	mux.HandleFunc("GET /apis", func(w http.ResponseWriter, r *http.Request) {
+		respond(w, r)(a.GetAllAPIs())
+	})
+	mux.HandleFunc("GET /apis/deprecated", func(w http.ResponseWriter, r *http.Request) {
+		respond(w, r)(a.GetDeprecationReport())
+	})
+	mux.HandleFunc("GET /apis/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if id, ok := pathID(w, r); ok {
+			respond(w, r)(a.GetAPIByID(id))
+		}
+	})
+	mux.HandleFunc("POST /apis", func(w http.ResponseWriter, r *http.Request) {
+		var api models.API
+		if decodeJSON(w, r, &api) {
+			respond(w, r)(a.CreateAPI(api))
+		}
+	})
+	mux.HandleFunc("PUT /apis/{id}", func(w http.ResponseWriter, r *http.Request) {
+		var api models.API
+		if id, ok := pathID(w, r); ok && decodeJSON(w, r, &api) {
+			api.ID = id
+			respond(w, r)(a.UpdateAPI(api))
+		}
+	})
+	mux.HandleFunc("DELETE /apis/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if id, ok := pathID(w, r); ok {
+			respondEmpty(w, r, a.DeleteAPI(id))
+		}
+	})
+	mux.HandleFunc("POST /apis/{id}/duplicate", func(w http.ResponseWriter, r *http.Request) {
+		if id, ok := pathID(w, r); ok {
+			respond(w, r)(a.DuplicateAPI(id))
+		}
+	})
+	mux.HandleFunc("POST /apis/{id}/restore", func(w http.ResponseWriter, r *http.Request) {
+		if id, ok := pathID(w, r); ok {
+			respondEmpty(w, r, a.RestoreAPI(id))
+		}
+	})
+	mux.HandleFunc("POST /apis/test", func(w http.ResponseWriter, r *http.Request) {
+		var api models.API
+		if decodeJSON(w, r, &api) {
+			respond(w, r)(a.TestAPI(api))
+		}
+	})
+	mux.HandleFunc("POST /apis/{id}/execute", func(w http.ResponseWriter, r *http.Request) {
+		if id, ok := pathID(w, r); ok {
+			respondEmpty(w, r, a.ExecuteAPIManually(id))
+		}
+	})
+	mux.HandleFunc("GET /apis/{id}/executions", func(w http.ResponseWriter, r *http.Request) {
+		id, ok := pathID(w, r)
+		if !ok {
+			return
+		}
+		if limit, ok := queryInt(w, r, "limit", 20); ok {
+			respond(w, r)(a.GetExecutionLogsByAPIID(id, limit))
+		}
+	})
+	mux.HandleFunc("GET /apis/{id}/analytics", func(w http.ResponseWriter, r *http.Request) {
+		if id, ok := pathID(w, r); ok {
+			respond(w, r)(a.GetAPIAnalytics(id))
+		}
+	})
+	mux.HandleFunc("GET /apis/{id}/timeseries", func(w http.ResponseWriter, r *http.Request) {
+		id, ok := pathID(w, r)
+		if !ok {
+			return
+		}
+		bucket := r.URL.Query().Get("bucket")
+		if bucket == "" {
+			bucket = models.TimeSeriesBucketHour
+		}
+		to, ok := queryTime(w, r, "to", time.Now())
+		if !ok {
+			return
+		}
+		if from, ok := queryTime(w, r, "from", to.Add(-24*time.Hour)); ok {
+			respond(w, r)(a.GetAPITimeSeries(id, bucket, from, to))
+		}
+	})
+	mux.HandleFunc("GET /apis/{id}/latency-histogram", func(w http.ResponseWriter, r *http.Request) {
+		id, ok := pathID(w, r)
+		if !ok {
+			return
+		}
+		to, ok := queryTime(w, r, "to", time.Now())
+		if !ok {
+			return
+		}
+		if from, ok := queryTime(w, r, "from", to.Add(-30*24*time.Hour)); ok {
+			respond(w, r)(a.GetLatencyHistogram(id, from, to))
+		}
+	})
+	mux.HandleFunc("GET /apis/{id}/performance", func(w http.ResponseWriter, r *http.Request) {
+		id, ok := pathID(w, r)
+		if !ok {
+			return
+		}
+		if hours, ok := queryInt(w, r, "hours", 24); ok {
+			respond(w, r)(a.GetPerformanceBreakdown(id, hours))
+		}
+	})
+	mux.HandleFunc("GET /apis/{id}/content-baseline", func(w http.ResponseWriter, r *http.Request) {
+		if id, ok := pathID(w, r); ok {
+			respond(w, r)(a.GetContentBaseline(id))
+		}
+	})
+	mux.HandleFunc("DELETE /apis/{id}/content-baseline", func(w http.ResponseWriter, r *http.Request) {
+		if id, ok := pathID(w, r); ok {
+			respondEmpty(w, r, a.ResetContentBaseline(id))
+		}
+	})
+	mux.HandleFunc("GET /apis/{id}/content-changes", func(w http.ResponseWriter, r *http.Request) {
+		id, ok := pathID(w, r)
+		if !ok {
+			return
+		}
+		if limit, ok := queryInt(w, r, "limit", 20); ok {
+			respond(w, r)(a.GetContentChanges(id, limit))
+		}
+	})
+	mux.HandleFunc("GET /apis/{id}/schedules", func(w http.ResponseWriter, r *http.Request) {
+		if id, ok := pathID(w, r); ok {
+			respond(w, r)(a.GetSchedulesByAPIID(id))
+		}
+	})
+	mux.HandleFunc("GET /apis/{id}/incidents", func(w http.ResponseWriter, r *http.Request) {
+		if id, ok := pathID(w, r); ok {
+			respond(w, r)(a.GetIncidentsByAPIID(id))
+		}
+	})
+	mux.HandleFunc("GET /apis/{id}/expected-failures", func(w http.ResponseWriter, r *http.Request) {
+		if id, ok := pathID(w, r); ok {
+			respond(w, r)(a.GetExpectedFailureWindows(id))
+		}
+	})
+	mux.HandleFunc("GET /apis/{id}/variables", func(w http.ResponseWriter, r *http.Request) {
+		if id, ok := pathID(w, r); ok {
+			respond(w, r)(a.GetResolvedVariables(id))
+		}
+	})
+	mux.HandleFunc("POST /apis/{id}/snooze", func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Hours int `json:"hours"`
+		}
+		if id, ok := pathID(w, r); ok && decodeJSON(w, r, &body) {
+			respondEmpty(w, r, a.SnoozeAlerts(id, body.Hours))
+		}
+	})
+	mux.HandleFunc("DELETE /apis/{id}/snooze", func(w http.ResponseWriter, r *http.Request) {
+		if id, ok := pathID(w, r); ok {
+			respondEmpty(w, r, a.UnsnoozeAlerts(id))
+		}
+	})
+
+	// Trash
+	mux.HandleFunc("GET /trash", func(w http.ResponseWriter, r *http.Request) {
+		respond(w, r)(a.GetTrash())
+	})
+	mux.HandleFunc("DELETE /trash", func(w http.ResponseWriter, r *http.Request) {
+		respondEmpty(w, r, a.EmptyTrash())
+	})
+
+	// Collections
+	mux.HandleFunc("GET /collections", func(w http.ResponseWriter, r *http.Request) {
+		respond(w, r)(a.GetAllCollections())
+	})
+	mux.HandleFunc("GET /collections/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if id, ok := pathID(w, r); ok {
+			respond(w, r)(a.GetCollectionByID(id))
+		}
+	})
+	mux.HandleFunc("POST /collections", func(w http.ResponseWriter, r *http.Request) {
+		var collection models.Collection
+		if decodeJSON(w, r, &collection) {
+			respond(w, r)(a.CreateCollection(collection))
+		}
+	})
+	mux.HandleFunc("PUT /collections/{id}", func(w http.ResponseWriter, r *http.Request) {
+		var collection models.Collection
+		if id, ok := pathID(w, r); ok && decodeJSON(w, r, &collection) {
+			collection.ID = id
+			respond(w, r)(a.UpdateCollection(collection))
+		}
+	})
+	mux.HandleFunc("DELETE /collections/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if id, ok := pathID(w, r); ok {
+			respondEmpty(w, r, a.DeleteCollection(id))
+		}
+	})
+	mux.HandleFunc("POST /collections/{id}/duplicate", func(w http.ResponseWriter, r *http.Request) {
+		if id, ok := pathID(w, r); ok {
+			respond(w, r)(a.DuplicateCollection(id, r.URL.Query().Get("schedules") == "true"))
+		}
+	})
+	mux.HandleFunc("GET /collections/tree", func(w http.ResponseWriter, r *http.Request) {
+		respond(w, r)(a.GetCollectionTree())
+	})
+	mux.HandleFunc("POST /collections/{id}/move", func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			ParentID int `json:"parentId"`
+		}
+		if id, ok := pathID(w, r); ok && decodeJSON(w, r, &body) {
+			respondEmpty(w, r, a.MoveCollection(id, body.ParentID))
+		}
+	})
+	mux.HandleFunc("POST /collections/{id}/restore", func(w http.ResponseWriter, r *http.Request) {
+		if id, ok := pathID(w, r); ok {
+			respondEmpty(w, r, a.RestoreCollection(id))
+		}
+	})
+	mux.HandleFunc("GET /collections/{id}/apis", func(w http.ResponseWriter, r *http.Request) {
+		if id, ok := pathID(w, r); ok {
+			respond(w, r)(a.GetAPIsByCollectionID(id))
+		}
+	})
+	mux.HandleFunc("PUT /collections/{id}/order", func(w http.ResponseWriter, r *http.Request) {
+		var apiIDs []int
+		if id, ok := pathID(w, r); ok && decodeJSON(w, r, &apiIDs) {
+			respondEmpty(w, r, a.SetCollectionOrder(id, apiIDs))
+		}
+	})
+	mux.HandleFunc("POST /collections/{id}/run", func(w http.ResponseWriter, r *http.Request) {
+		if id, ok := pathID(w, r); ok {
+			respond(w, r)(a.RunCollection(id))
+		}
+	})
+	mux.HandleFunc("GET /collections/{id}/runs", func(w http.ResponseWriter, r *http.Request) {
+		id, ok := pathID(w, r)
+		if !ok {
+			return
+		}
+		if limit, ok := queryInt(w, r, "limit", 20); ok {
+			respond(w, r)(a.GetCollectionRuns(id, limit))
+		}
+	})
+	mux.HandleFunc("GET /collections/{id}/latency-compliance", func(w http.ResponseWriter, r *http.Request) {
+		if id, ok := pathID(w, r); ok {
+			respond(w, r)(a.GetCollectionLatencyCompliance(id))
+		}
+	})
+	mux.HandleFunc("GET /collection-runs/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if id, ok := pathID(w, r); ok {
+			respond(w, r)(a.GetCollectionRun(id))
+		}
+	})
+	mux.HandleFunc("GET /collection-runs/{id}/waterfall", func(w http.ResponseWriter, r *http.Request) {
+		if id, ok := pathID(w, r); ok {
+			respond(w, r)(a.GetCollectionRunWaterfall(id))
+		}
+	})
+
+	// Schedules
+	mux.HandleFunc("GET /schedules", func(w http.ResponseWriter, r *http.Request) {
+		respond(w, r)(a.GetAllSchedules())
+	})
+	mux.HandleFunc("GET /schedules/calendar", func(w http.ResponseWriter, r *http.Request) {
+		from, ok := queryTime(w, r, "from", time.Now())
+		if !ok {
+			return
+		}
+		if to, ok := queryTime(w, r, "to", from.AddDate(0, 0, 7)); ok {
+			respond(w, r)(a.GetScheduleCalendar(from, to))
+		}
+	})
+	mux.HandleFunc("GET /schedules/upcoming", func(w http.ResponseWriter, r *http.Request) {
+		if limit, ok := queryInt(w, r, "limit", 20); ok {
+			respond(w, r)(a.GetUpcomingExecutions(limit))
+		}
+	})
+	mux.HandleFunc("GET /schedules/{id}/upcoming", func(w http.ResponseWriter, r *http.Request) {
+		id, ok := pathID(w, r)
+		if !ok {
+			return
+		}
+		if count, ok := queryInt(w, r, "count", 10); ok {
+			respond(w, r)(a.GetUpcomingRuns(id, count))
+		}
+	})
+	mux.HandleFunc("GET /schedules/stale", func(w http.ResponseWriter, r *http.Request) {
+		respond(w, r)(a.GetStaleSchedules())
+	})
+	mux.HandleFunc("GET /schedules/graph", func(w http.ResponseWriter, r *http.Request) {
+		respond(w, r)(a.GetScheduleGraph())
+	})
+	mux.HandleFunc("POST /schedules", func(w http.ResponseWriter, r *http.Request) {
+		var schedule models.Schedule
+		if decodeJSON(w, r, &schedule) {
+			respond(w, r)(a.CreateSchedule(schedule))
+		}
+	})
+	mux.HandleFunc("PUT /schedules/{id}", func(w http.ResponseWriter, r *http.Request) {
+		var schedule models.Schedule
+		if id, ok := pathID(w, r); ok && decodeJSON(w, r, &schedule) {
+			schedule.ID = id
+			respondEmpty(w, r, a.UpdateSchedule(schedule))
+		}
+	})
+	mux.HandleFunc("DELETE /schedules/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if id, ok := pathID(w, r); ok {
+			respondEmpty(w, r, a.DeleteSchedule(id))
+		}
+	})
+	mux.HandleFunc("POST /schedules/{id}/toggle", func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Active bool `json:"active"`
+		}
+		if id, ok := pathID(w, r); ok && decodeJSON(w, r, &body) {
+			respondEmpty(w, r, a.ToggleSchedule(id, body.Active))
+		}
+	})
+
+	// Workspace backups
+	mux.HandleFunc("GET /backup", func(w http.ResponseWriter, r *http.Request) {
+		respond(w, r)(a.backupWorkspace(r.URL.Query().Get("logs") == "true"))
+	})
+	mux.HandleFunc("POST /backup/restore", func(w http.ResponseWriter, r *http.Request) {
+		workspace, err := backup.Decode(r.Body)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		respond(w, r)(a.restoreWorkspace(workspace))
+	})
+
+	// Workspace report and cleanups
+	mux.HandleFunc("GET /workspace/report", func(w http.ResponseWriter, r *http.Request) {
+		respond(w, r)(a.GetWorkspaceReport())
+	})
+	mux.HandleFunc("POST /workspace/cleanup/{action}", func(w http.ResponseWriter, r *http.Request) {
+		respond(w, r)(a.RunCleanupAction(r.PathValue("action")))
+	})
+
+	// Search
+	mux.HandleFunc("GET /search", func(w http.ResponseWriter, r *http.Request) {
+		respond(w, r)(a.Search(r.URL.Query().Get("q")))
+	})
+
+	// Logs and analytics
+	mux.HandleFunc("GET /executions", func(w http.ResponseWriter, r *http.Request) {
+		if limit, ok := queryInt(w, r, "limit", 20); ok {
+			respond(w, r)(a.GetRecentExecutions(limit))
+		}
+	})
+	mux.HandleFunc("POST /executions/search", func(w http.ResponseWriter, r *http.Request) {
+		var filter models.LogFilter
+		if limit, ok := queryInt(w, r, "limit", 100); ok && decodeJSON(w, r, &filter) {
+			respond(w, r)(a.SearchExecutionLogs(filter, limit))
+		}
+	})
+	mux.HandleFunc("GET /executions/{id}/response", func(w http.ResponseWriter, r *http.Request) {
+		if id, ok := pathID(w, r); ok {
+			respond(w, r)(a.GetExecutionResponseBody(id))
+		}
+	})
+	mux.HandleFunc("GET /executions/{id}/query", func(w http.ResponseWriter, r *http.Request) {
+		if id, ok := pathID(w, r); ok {
+			respond(w, r)(a.QueryExecutionResponse(id, r.URL.Query().Get("path")))
+		}
+	})
+	mux.HandleFunc("POST /executions/{id}/annotations", func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Note string `json:"note"`
+		}
+		if id, ok := pathID(w, r); ok && decodeJSON(w, r, &body) {
+			respond(w, r)(a.AnnotateExecution(id, body.Note))
+		}
+	})
+	mux.HandleFunc("POST /execution-batches", func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			APIIDs []int `json:"apiIds"`
+		}
+		if decodeJSON(w, r, &body) {
+			respond(w, r)(a.ExecuteAPIsManually(body.APIIDs))
+		}
+	})
+	mux.HandleFunc("GET /execution-batches/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if id, ok := pathID(w, r); ok {
+			respond(w, r)(a.GetExecutionBatch(id))
+		}
+	})
+	mux.HandleFunc("GET /executions/running", func(w http.ResponseWriter, r *http.Request) {
+		respond(w, r)(a.GetRunningExecutions(), nil)
+	})
+	mux.HandleFunc("POST /executions/running/{id}/cancel", func(w http.ResponseWriter, r *http.Request) {
+		if id, ok := pathID(w, r); ok {
+			respondEmpty(w, r, a.CancelExecution(id))
+		}
+	})
+	mux.HandleFunc("GET /executions/retries", func(w http.ResponseWriter, r *http.Request) {
+		respond(w, r)(a.GetPendingRetries(), nil)
+	})
+	mux.HandleFunc("POST /executions/running/{id}/retry-now", func(w http.ResponseWriter, r *http.Request) {
+		if id, ok := pathID(w, r); ok {
+			respondEmpty(w, r, a.RetryNow(id))
+		}
+	})
+	mux.HandleFunc("POST /executions/running/{id}/cancel-retry", func(w http.ResponseWriter, r *http.Request) {
+		if id, ok := pathID(w, r); ok {
+			respondEmpty(w, r, a.CancelRetry(id))
+		}
+	})
+	mux.HandleFunc("GET /analytics", func(w http.ResponseWriter, r *http.Request) {
+		respond(w, r)(a.GetOverallAnalytics())
+	})
+	mux.HandleFunc("GET /analytics/scheduler", func(w http.ResponseWriter, r *http.Request) {
+		if hours, ok := queryInt(w, r, "hours", 24); ok {
+			respond(w, r)(a.GetSchedulerMetrics(hours))
+		}
+	})
+
+	// Incidents
+	mux.HandleFunc("GET /incidents", func(w http.ResponseWriter, r *http.Request) {
+		respond(w, r)(a.GetOpenIncidents())
+	})
+	mux.HandleFunc("GET /incidents/history", func(w http.ResponseWriter, r *http.Request) {
+		if apiID, ok := queryInt(w, r, "apiId", 0); ok {
+			if hours, ok := queryInt(w, r, "hours", 24*30); ok {
+				respond(w, r)(a.GetIncidentHistory(apiID, hours))
+			}
+		}
+	})
+	mux.HandleFunc("GET /incidents/stats", func(w http.ResponseWriter, r *http.Request) {
+		if apiID, ok := queryInt(w, r, "apiId", 0); ok {
+			if hours, ok := queryInt(w, r, "hours", 24*30); ok {
+				respond(w, r)(a.GetIncidentStats(apiID, hours))
+			}
+		}
+	})
+	mux.HandleFunc("GET /incidents/{id}/events", func(w http.ResponseWriter, r *http.Request) {
+		if id, ok := pathID(w, r); ok {
+			respond(w, r)(a.GetIncidentEvents(id))
+		}
+	})
+	mux.HandleFunc("POST /incidents/{id}/acknowledge", func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Note string `json:"note"`
+		}
+		if id, ok := pathID(w, r); ok && decodeJSON(w, r, &body) {
+			respondEmpty(w, r, a.AcknowledgeIncident(id, body.Note))
+		}
+	})
+
+	mux.HandleFunc("POST /incidents/{id}/annotations", func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Note string `json:"note"`
+		}
+		if id, ok := pathID(w, r); ok && decodeJSON(w, r, &body) {
+			respond(w, r)(a.AnnotateIncident(id, body.Note))
+		}
+	})
+
+	// Expected failure windows
+	mux.HandleFunc("POST /expected-failures", func(w http.ResponseWriter, r *http.Request) {
+		var window models.ExpectedFailureWindow
+		if decodeJSON(w, r, &window) {
+			respond(w, r)(a.CreateExpectedFailureWindow(window))
+		}
+	})
+	mux.HandleFunc("PUT /expected-failures/{id}", func(w http.ResponseWriter, r *http.Request) {
+		var window models.ExpectedFailureWindow
+		if id, ok := pathID(w, r); ok && decodeJSON(w, r, &window) {
+			window.ID = id
+			respond(w, r)(a.UpdateExpectedFailureWindow(window))
+		}
+	})
+	mux.HandleFunc("DELETE /expected-failures/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if id, ok := pathID(w, r); ok {
+			respondEmpty(w, r, a.DeleteExpectedFailureWindow(id))
+		}
+	})
+
+	// Maintenance windows
+	mux.HandleFunc("GET /maintenance-windows", func(w http.ResponseWriter, r *http.Request) {
+		respond(w, r)(a.GetAllMaintenanceWindows())
+	})
+	mux.HandleFunc("POST /maintenance-windows", func(w http.ResponseWriter, r *http.Request) {
+		var window models.MaintenanceWindow
+		if decodeJSON(w, r, &window) {
+			respond(w, r)(a.CreateMaintenanceWindow(window))
+		}
+	})
+	mux.HandleFunc("PUT /maintenance-windows/{id}", func(w http.ResponseWriter, r *http.Request) {
+		var window models.MaintenanceWindow
+		if id, ok := pathID(w, r); ok && decodeJSON(w, r, &window) {
+			window.ID = id
+			respond(w, r)(a.UpdateMaintenanceWindow(window))
+		}
+	})
+	mux.HandleFunc("DELETE /maintenance-windows/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if id, ok := pathID(w, r); ok {
+			respondEmpty(w, r, a.DeleteMaintenanceWindow(id))
+		}
+	})
+
+	// Saved log filters
+	mux.HandleFunc("GET /log-filters", func(w http.ResponseWriter, r *http.Request) {
+		respond(w, r)(a.GetSavedLogFilters())
+	})
+	mux.HandleFunc("GET /log-filters/{id}/executions", func(w http.ResponseWriter, r *http.Request) {
+		id, ok := pathID(w, r)
+		if !ok {
+			return
+		}
+		if limit, ok := queryInt(w, r, "limit", 100); ok {
+			respond(w, r)(a.SearchExecutionLogsWithSavedFilter(id, limit))
+		}
+	})
+	mux.HandleFunc("POST /log-filters", func(w http.ResponseWriter, r *http.Request) {
+		var saved models.SavedLogFilter
+		if decodeJSON(w, r, &saved) {
+			respond(w, r)(a.CreateSavedLogFilter(saved))
+		}
+	})
+	mux.HandleFunc("PUT /log-filters/{id}", func(w http.ResponseWriter, r *http.Request) {
+		var saved models.SavedLogFilter
+		if id, ok := pathID(w, r); ok && decodeJSON(w, r, &saved) {
+			saved.ID = id
+			respond(w, r)(a.UpdateSavedLogFilter(saved))
+		}
+	})
+	mux.HandleFunc("DELETE /log-filters/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if id, ok := pathID(w, r); ok {
+			respondEmpty(w, r, a.DeleteSavedLogFilter(id))
+		}
+	})
+
+	// Webhook triggers
+	fireWebhook := func(w http.ResponseWriter, r *http.Request) {
+		id, ok := pathID(w, r)
+		if !ok {
+			return
+		}
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxWebhookBody))
+		if err != nil {
+			writeError(w, http.StatusRequestEntityTooLarge, "webhook body is too large")
+			return
+		}
+		if err := a.fireWebhookTrigger(id, r.PathValue("secret"), r.Header, body); err != nil {
+			status := errorStatus(r, err)
+			if errors.Is(err, webhooks.ErrInvalidSignature) {
+				status = http.StatusUnauthorized
+			}
+			writeError(w, status, err.Error())
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}
+	mux.HandleFunc("POST /hooks/{id}", fireWebhook)
+	mux.HandleFunc("POST /hooks/{id}/{secret}", fireWebhook)
+	mux.HandleFunc("GET /webhook-triggers", func(w http.ResponseWriter, r *http.Request) {
+		respond(w, r)(a.GetWebhookTriggers())
+	})
+	mux.HandleFunc("POST /webhook-triggers", func(w http.ResponseWriter, r *http.Request) {
+		var trigger models.WebhookTrigger
+		if decodeJSON(w, r, &trigger) {
+			respond(w, r)(a.CreateWebhookTrigger(trigger))
+		}
+	})
+	mux.HandleFunc("PUT /webhook-triggers/{id}", func(w http.ResponseWriter, r *http.Request) {
+		var trigger models.WebhookTrigger
+		if id, ok := pathID(w, r); ok && decodeJSON(w, r, &trigger) {
+			trigger.ID = id
+			respond(w, r)(a.UpdateWebhookTrigger(trigger))
+		}
+	})
+	mux.HandleFunc("DELETE /webhook-triggers/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if id, ok := pathID(w, r); ok {
+			respondEmpty(w, r, a.DeleteWebhookTrigger(id))
+		}
+	})
+
+	// Event webhooks
+	mux.HandleFunc("GET /event-webhooks", func(w http.ResponseWriter, r *http.Request) {
+		respond(w, r)(a.GetEventWebhooks())
+	})
+	mux.HandleFunc("POST /event-webhooks", func(w http.ResponseWriter, r *http.Request) {
+		var webhook models.EventWebhook
+		if decodeJSON(w, r, &webhook) {
+			respond(w, r)(a.CreateEventWebhook(webhook))
+		}
+	})
+	mux.HandleFunc("PUT /event-webhooks/{id}", func(w http.ResponseWriter, r *http.Request) {
+		var webhook models.EventWebhook
+		if id, ok := pathID(w, r); ok && decodeJSON(w, r, &webhook) {
+			webhook.ID = id
+			respond(w, r)(a.UpdateEventWebhook(webhook))
+		}
+	})
+	mux.HandleFunc("DELETE /event-webhooks/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if id, ok := pathID(w, r); ok {
+			respondEmpty(w, r, a.DeleteEventWebhook(id))
+		}
+	})
+
+	// Prometheus rule file generated from the alert rules
+	mux.HandleFunc("GET /alert-rules/prometheus", func(w http.ResponseWriter, r *http.Request) {
+		rules, err := a.GetPrometheusRules()
+		if err != nil {
+			writeError(w, errorStatus(r, err), err.Error())
+			return
+		}
+		w.Header().Set("Content-Type", "application/yaml; charset=utf-8")
+		w.Write([]byte(rules))
+	})
+
+	// Status pages
+	mux.HandleFunc("GET /status/{slug}", func(w http.ResponseWriter, r *http.Request) {
+		report, err := a.publicStatusPage(r.PathValue("slug"))
+		if err != nil {
+			writeError(w, errorStatus(r, err), err.Error())
+			return
+		}
+		page, err := statuspage.RenderHTML(report)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write(page)
+	})
+	mux.HandleFunc("GET /status/{slug}/status.json", func(w http.ResponseWriter, r *http.Request) {
+		respond(w, r)(a.publicStatusPage(r.PathValue("slug")))
+	})
+	mux.HandleFunc("GET /status-pages", func(w http.ResponseWriter, r *http.Request) {
+		respond(w, r)(a.GetStatusPages())
+	})
+	mux.HandleFunc("POST /status-pages", func(w http.ResponseWriter, r *http.Request) {
+		var page models.StatusPage
+		if decodeJSON(w, r, &page) {
+			respond(w, r)(a.CreateStatusPage(page))
+		}
+	})
+	mux.HandleFunc("PUT /status-pages/{id}", func(w http.ResponseWriter, r *http.Request) {
+		var page models.StatusPage
+		if id, ok := pathID(w, r); ok && decodeJSON(w, r, &page) {
+			page.ID = id
+			respond(w, r)(a.UpdateStatusPage(page))
+		}
+	})
+	mux.HandleFunc("DELETE /status-pages/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if id, ok := pathID(w, r); ok {
+			respondEmpty(w, r, a.DeleteStatusPage(id))
+		}
+	})
+	mux.HandleFunc("GET /status-pages/{id}/report", func(w http.ResponseWriter, r *http.Request) {
+		if id, ok := pathID(w, r); ok {
+			respond(w, r)(a.GenerateStatusPage(id))
+		}
+	})
+	mux.HandleFunc("POST /status-pages/{id}/export", func(w http.ResponseWriter, r *http.Request) {
+		if id, ok := pathID(w, r); ok {
+			dir, err := a.ExportStatusPage(id)
+			respond(w, r)(map[string]string{"outputDir": dir}, err)
+		}
+	})
+
+	// Tags
+	mux.HandleFunc("GET /tags", func(w http.ResponseWriter, r *http.Request) {
+		respond(w, r)(a.GetTags())
+	})
+	mux.HandleFunc("POST /tags", func(w http.ResponseWriter, r *http.Request) {
+		var tag models.Tag
+		if decodeJSON(w, r, &tag) {
+			respond(w, r)(a.CreateTag(tag))
+		}
+	})
+	mux.HandleFunc("PUT /tags/{id}", func(w http.ResponseWriter, r *http.Request) {
+		var tag models.Tag
+		if id, ok := pathID(w, r); ok && decodeJSON(w, r, &tag) {
+			tag.ID = id
+			respond(w, r)(a.UpdateTag(tag))
+		}
+	})
+	mux.HandleFunc("DELETE /tags/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if id, ok := pathID(w, r); ok {
+			respondEmpty(w, r, a.DeleteTag(id))
+		}
+	})
+	mux.HandleFunc("GET /tags/{id}/apis", func(w http.ResponseWriter, r *http.Request) {
+		if id, ok := pathID(w, r); ok {
+			respond(w, r)(a.GetAPIsByTag(id))
+		}
+	})
+	mux.HandleFunc("GET /tags/{id}/schedules", func(w http.ResponseWriter, r *http.Request) {
+		if id, ok := pathID(w, r); ok {
+			respond(w, r)(a.GetSchedulesByTag(id))
+		}
+	})
+	mux.HandleFunc("GET /tags/{id}/executions", func(w http.ResponseWriter, r *http.Request) {
+		if id, ok := pathID(w, r); ok {
+			if limit, ok := queryInt(w, r, "limit", 100); ok {
+				respond(w, r)(a.GetExecutionLogsByTag(id, limit))
+			}
+		}
+	})
+	mux.HandleFunc("GET /apis/{id}/tags", func(w http.ResponseWriter, r *http.Request) {
+		if id, ok := pathID(w, r); ok {
+			respond(w, r)(a.GetAPITags(id))
+		}
+	})
+	mux.HandleFunc("PUT /apis/{id}/tags", func(w http.ResponseWriter, r *http.Request) {
+		var tagIDs []int
+		if id, ok := pathID(w, r); ok && decodeJSON(w, r, &tagIDs) {
+			respondEmpty(w, r, a.SetAPITags(id, tagIDs))
+		}
+	})
+	mux.HandleFunc("GET /schedules/{id}/tags", func(w http.ResponseWriter, r *http.Request) {
+		if id, ok := pathID(w, r); ok {
+			respond(w, r)(a.GetScheduleTags(id))
+		}
+	})
+	mux.HandleFunc("PUT /schedules/{id}/tags", func(w http.ResponseWriter, r *http.Request) {
+		var tagIDs []int
+		if id, ok := pathID(w, r); ok && decodeJSON(w, r, &tagIDs) {
+			respondEmpty(w, r, a.SetScheduleTags(id, tagIDs))
+		}
+	})
+
+	// Dashboards
+	mux.HandleFunc("GET /dashboards", func(w http.ResponseWriter, r *http.Request) {
+		respond(w, r)(a.GetAllDashboards())
+	})
+	mux.HandleFunc("GET /dashboards/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if id, ok := pathID(w, r); ok {
+			respond(w, r)(a.GetDashboardByID(id))
+		}
+	})
+	mux.HandleFunc("POST /dashboards", func(w http.ResponseWriter, r *http.Request) {
+		var dashboard models.Dashboard
+		if decodeJSON(w, r, &dashboard) {
+			respond(w, r)(a.CreateDashboard(dashboard))
+		}
+	})
+	mux.HandleFunc("PUT /dashboards/{id}", func(w http.ResponseWriter, r *http.Request) {
+		var dashboard models.Dashboard
+		if id, ok := pathID(w, r); ok && decodeJSON(w, r, &dashboard) {
+			dashboard.ID = id
+			respond(w, r)(a.UpdateDashboard(dashboard))
+		}
+	})
+	mux.HandleFunc("DELETE /dashboards/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if id, ok := pathID(w, r); ok {
+			respondEmpty(w, r, a.DeleteDashboard(id))
+		}
+	})
+
+	// Annotations
+	mux.HandleFunc("PUT /annotations/{id}", func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Note string `json:"note"`
+		}
+		if id, ok := pathID(w, r); ok && decodeJSON(w, r, &body) {
+			respond(w, r)(a.UpdateAnnotation(id, body.Note))
+		}
+	})
+	mux.HandleFunc("DELETE /annotations/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if id, ok := pathID(w, r); ok {
+			respondEmpty(w, r, a.DeleteAnnotation(id))
+		}
+	})
+
+	return requireToken(token, mux)
+}
+
+// maxWebhookBody bounds the body of an inbound webhook request, which is read whole to verify its signature
+const maxWebhookBody = 1 << 20
+
+// requireToken rejects requests without the bearer token, except health checks, webhook triggers and status pages.
+// An empty token disables authentication, which is only allowed on loopback addresses.
+func requireToken(token string, next http.Handler) http.Handler {
+	expected := []byte("Bearer " + token)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if token != "" && r.URL.Path != "/health" && !strings.HasPrefix(r.URL.Path, "/hooks/") && !strings.HasPrefix(r.URL.Path, "/status/") &&
+			subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), expected) != 1 {
+			writeError(w, http.StatusUnauthorized, "missing or invalid token")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// respond returns a function writing an App method's result as JSON, or its error
+func respond(w http.ResponseWriter, r *http.Request) func(interface{}, error) {
+	return func(result interface{}, err error) {
+		if err != nil {
+			writeError(w, errorStatus(r, err), err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, result)
+	}
+}
+
+// respondEmpty answers 204 No Content for App methods that only return an error
+func respondEmpty(w http.ResponseWriter, r *http.Request, err error) {
+	if err != nil {
+		writeError(w, errorStatus(r, err), err.Error())
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// errorStatus maps an App error to a status code. Missing rows are 404s; other errors of
+// requests that change something are most often rejected input, so they are reported as 400s.
+func errorStatus(r *http.Request, err error) int {
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return http.StatusNotFound
+	case r.Method == http.MethodGet:
+		return http.StatusInternalServerError
+	default:
+		return http.StatusBadRequest
+	}
+}
+
+// pathID parses the {id} path parameter, answering 400 when it isn't a number
+func pathID(w http.ResponseWriter, r *http.Request) (int, bool) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid ID %q", r.PathValue("id")))
+		return 0, false
+	}
+	return id, true
+}
+
+// queryInt parses a positive integer query parameter, falling back to a default when it's missing
+func queryInt(w http.ResponseWriter, r *http.Request, name string, fallback int) (int, bool) {
+	raw := r.URL.Query().Get(name)
+	if raw == "" {
+		return fallback, true
+	}
+	value, err := strconv.Atoi(raw)
+	if err != nil || value <= 0 {
+		writeError(w, http.StatusBadRequest, name+" must be a positive integer")
+		return 0, false
+	}
+	return value, true
+}
+
+// queryTime reads an optional RFC3339 query parameter, answering 400 when it isn't a valid timestamp
+func queryTime(w http.ResponseWriter, r *http.Request, name string, fallback time.Time) (time.Time, bool) {
+	raw := r.URL.Query().Get(name)
+	if raw == "" {
+		return fallback, true
+	}
+	value, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, name+" must be an RFC3339 timestamp")
+		return time.Time{}, false
+	}
+	return value, true
+}
+
+// decodeJSON decodes the request body, answering 400 when it isn't valid JSON
+func decodeJSON(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid request body: %v", err))
+		return false
+	}
+	return true
+}
+
+// writeJSON encodes a response body as JSON
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+// writeError writes an error response as {"error": message}
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"error": message})
+}
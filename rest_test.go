@@ -0,0 +1,18 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestRESTHandlerRegistersRoutes builds the REST handler, which panics when two routes conflict
+func TestRESTHandlerRegistersRoutes(t *testing.T) {
+	handler := (&App{}).restHandler("x")
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/health", nil))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("GET /health returned %d, want %d", recorder.Code, http.StatusOK)
+	}
+}